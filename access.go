@@ -0,0 +1,118 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+type userRecordAccess struct {
+	RecordId      string
+	HasEditAccess bool
+}
+
+type UserInfo struct {
+	UserId         string `json:"user_id"`
+	OrganizationId string `json:"organization_id"`
+	Username       string `json:"preferred_username"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	Locale         string `json:"locale"`
+	Timezone       string `json:"zoneinfo"`
+}
+
+// UserRecordAccess queries accept at most 200 record ids
+const userRecordAccessBatchSizeMax = 200
+
+var recordIdPattern = regexp.MustCompile(`^[a-zA-Z0-9]{15}([a-zA-Z0-9]{3})?$`)
+
+func getUserId(auth *authentication) (string, error) {
+	// the identity url returned during authentication ends with the user id
+	if auth.Id != "" {
+		parts := strings.Split(strings.TrimSuffix(auth.Id, "/"), "/")
+		return parts[len(parts)-1], nil
+	}
+
+	info, err := doGetUserInfo(auth)
+	if err != nil {
+		return "", err
+	}
+	if info.UserId == "" {
+		return "", errors.New("unable to determine current user")
+	}
+	return info.UserId, nil
+}
+
+func doGetUserInfo(auth *authentication) (UserInfo, error) {
+	instanceUrl, accessToken := auth.session()
+	req, err := http.NewRequest(http.MethodGet, instanceUrl+"/services/oauth2/userinfo", nil)
+	if err != nil {
+		return UserInfo{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := auth.config.client().Do(req)
+	if err != nil {
+		return UserInfo{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return UserInfo{}, errors.New(resp.Status + ": unable to determine current user")
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return UserInfo{}, err
+	}
+	info := UserInfo{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return UserInfo{}, err
+	}
+	return info, nil
+}
+
+// compares record ids by their case sensitive 15 character form
+func shortRecordId(recordId string) string {
+	if len(recordId) > 15 {
+		return recordId[:15]
+	}
+	return recordId
+}
+
+func doCanUpdate(auth *authentication, recordIds []string) ([]string, error) {
+	for _, recordId := range recordIds {
+		if !recordIdPattern.MatchString(recordId) {
+			return nil, fmt.Errorf("invalid salesforce id: %s", recordId)
+		}
+	}
+	userId, err := getUserId(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	editable := map[string]bool{}
+	for i := 0; i < len(recordIds); i += userRecordAccessBatchSizeMax {
+		batch := recordIds[i:min(i+userRecordAccessBatchSizeMax, len(recordIds))]
+		query := "SELECT RecordId, HasEditAccess FROM UserRecordAccess WHERE UserId = '" + userId +
+			"' AND RecordId IN ('" + strings.Join(batch, "','") + "')"
+		access := []userRecordAccess{}
+		if err := performQuery(auth, query, &access, dmlOptions{}); err != nil {
+			return nil, err
+		}
+		for _, record := range access {
+			if record.HasEditAccess {
+				editable[shortRecordId(record.RecordId)] = true
+			}
+		}
+	}
+
+	inaccessibleIds := []string{}
+	for _, recordId := range recordIds {
+		if !editable[shortRecordId(recordId)] {
+			inaccessibleIds = append(inaccessibleIds, recordId)
+		}
+	}
+	return inaccessibleIds, nil
+}
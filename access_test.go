@@ -0,0 +1,186 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_getUserId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/oauth2/userinfo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(UserInfo{UserId: "005000000000002"})
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusUnauthorized)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "identity_url",
+			auth: &authentication{
+				Id: "https://login.salesforce.com/id/00D000000000001/005000000000001",
+			},
+			want:    "005000000000001",
+			wantErr: false,
+		},
+		{
+			name: "userinfo",
+			auth: &authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "accesstokenvalue",
+			},
+			want:    "005000000000002",
+			wantErr: false,
+		},
+		{
+			name:    "userinfo_fail",
+			auth:    &badSfAuth,
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getUserId(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getUserId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getUserId() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doGetUserInfo(t *testing.T) {
+	server, sfAuth := setupTestServer(map[string]any{"user_id": "005000000000001", "organization_id": "00D000000000001"}, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusUnauthorized)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    UserInfo
+		wantErr bool
+	}{
+		{
+			name:    "get_user_info",
+			auth:    &sfAuth,
+			want:    UserInfo{UserId: "005000000000001", OrganizationId: "00D000000000001"},
+			wantErr: false,
+		},
+		{
+			name:    "unauthorized",
+			auth:    &badSfAuth,
+			want:    UserInfo{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doGetUserInfo(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doGetUserInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetUserInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doCanUpdate(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		resp := queryResponse{
+			TotalSize: 2,
+			Done:      true,
+			Records: []map[string]any{
+				{"RecordId": "001000000000001AAA", "HasEditAccess": true},
+				{"RecordId": "001000000000002AAA", "HasEditAccess": false},
+			},
+		}
+		body, _ := json.Marshal(resp)
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		Id:          "https://login.salesforce.com/id/00D000000000001/005000000000001",
+	}
+
+	type args struct {
+		recordIds []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "some_inaccessible",
+			args: args{
+				recordIds: []string{"001000000000001", "001000000000002AAA", "001000000000003AAA"},
+			},
+			want:    []string{"001000000000002AAA", "001000000000003AAA"},
+			wantErr: false,
+		},
+		{
+			name: "invalid_id",
+			args: args{
+				recordIds: []string{"001' OR Id != '"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doCanUpdate(&sfAuth, tt.args.recordIds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doCanUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doCanUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	queries = nil
+	recordIds := make([]string, 201)
+	for i := range recordIds {
+		recordIds[i] = "001000000000001"
+	}
+	if _, err := doCanUpdate(&sfAuth, recordIds); err != nil {
+		t.Fatalf("doCanUpdate() error = %v", err)
+	}
+	if len(queries) != 2 {
+		t.Errorf("doCanUpdate() made %d queries, want 2", len(queries))
+	}
+	if !strings.Contains(queries[0], "UserId = '005000000000001'") {
+		t.Errorf("doCanUpdate() query = %v", queries[0])
+	}
+}
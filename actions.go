@@ -0,0 +1,66 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+type actionRequest struct {
+	Inputs []any `json:"inputs"`
+}
+
+type actionResult struct {
+	ActionName   string         `json:"actionName"`
+	Errors       []actionError  `json:"errors"`
+	IsSuccess    bool           `json:"isSuccess"`
+	OutputValues map[string]any `json:"outputValues"`
+}
+
+type actionError struct {
+	StatusCode string `json:"statusCode"`
+	Message    string `json:"message"`
+}
+
+// invokes a standard invocable action, such as customNotificationAction, with one or more inputs.
+// actions respond with 200 even when an input fails, so failed results are returned as an error
+func doInvokeStandardAction(auth *authentication, action string, inputs []any) ([]actionResult, error) {
+	body, err := json.Marshal(actionRequest{Inputs: inputs})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/actions/standard/" + action,
+		content: jsonType,
+		body:    string(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var results []actionResult
+	if err := json.Unmarshal(respBody, &results); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.IsSuccess {
+			continue
+		}
+		if len(result.Errors) == 0 {
+			errs = append(errs, errors.New(action+" failed"))
+		}
+		for _, actionErr := range result.Errors {
+			errs = append(errs, errors.New(action+" failed: "+actionErr.StatusCode+": "+actionErr.Message))
+		}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package salesforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_doInvokeStandardAction(t *testing.T) {
+	successResults := []actionResult{{ActionName: "customNotificationAction", IsSuccess: true}}
+	server, sfAuth := setupTestServer(successResults, http.StatusOK)
+	defer server.Close()
+
+	failedResults := []actionResult{
+		{ActionName: "customNotificationAction", IsSuccess: true},
+		{ActionName: "customNotificationAction", IsSuccess: false, Errors: []actionError{{StatusCode: "INVALID_ID_FIELD", Message: "invalid recipient"}}},
+	}
+	failedServer, failedAuth := setupTestServer(failedResults, http.StatusOK)
+	defer failedServer.Close()
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name        string
+		auth        *authentication
+		wantResults int
+		wantErr     bool
+		wantMessage string
+	}{
+		{
+			name:        "success",
+			auth:        &sfAuth,
+			wantResults: 1,
+		},
+		{
+			name:        "failed_input",
+			auth:        &failedAuth,
+			wantResults: 2,
+			wantErr:     true,
+			wantMessage: "customNotificationAction failed: INVALID_ID_FIELD: invalid recipient",
+		},
+		{
+			name:    "bad_request",
+			auth:    &badAuth,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doInvokeStandardAction(tt.auth, "customNotificationAction", []any{map[string]any{}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doInvokeStandardAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantMessage != "" && err.Error() != tt.wantMessage {
+				t.Errorf("doInvokeStandardAction() error = %v, want %v", err, tt.wantMessage)
+			}
+			if len(got) != tt.wantResults {
+				t.Errorf("doInvokeStandardAction() returned %d results, want %d", len(got), tt.wantResults)
+			}
+		})
+	}
+}
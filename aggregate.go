@@ -0,0 +1,87 @@
+package salesforce
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// one row of an aggregate query, keyed by alias, grouped field name, or exprN for unaliased aggregates
+type AggregateResult map[string]any
+
+func (result AggregateResult) Int(key string) int {
+	return int(result.Float(key))
+}
+
+func (result AggregateResult) Float(key string) float64 {
+	switch v := result[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+func (result AggregateResult) String(key string) string {
+	switch v := result[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func performAggregateQuery(auth *authentication, query string, result any, options dmlOptions) error {
+	records, err := fetchQueryRecords(auth, query, options)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		delete(record, "attributes")
+	}
+	if t := reflect.TypeOf(result); t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice {
+		resolveAggregateFieldTags(t.Elem().Elem(), records)
+		resolveFieldTags(t.Elem().Elem(), records)
+	}
+
+	return mapstructure.Decode(records, result)
+}
+
+// grouped relationship fields such as Account.Name are returned flattened to their last name,
+// so tags with relationship paths are matched against that name instead
+func resolveAggregateFieldTags(t reflect.Type, records []map[string]any) {
+	t, ok := structType(t)
+	if !ok {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		options := parseFieldTag(field)
+		if !strings.Contains(options.name, relationshipSeparator) {
+			continue
+		}
+		path := strings.Split(options.name, relationshipSeparator)
+		for _, record := range records {
+			if _, ok := lookupRelationshipField(record, path); ok {
+				continue
+			}
+			key, ok := findKey(record, path[len(path)-1])
+			if !ok {
+				continue
+			}
+			value := record[key]
+			delete(record, key)
+			record[mapstructureKey(field)] = resolveFieldValue(field.Type, options, value)
+		}
+	}
+}
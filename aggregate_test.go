@@ -0,0 +1,74 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_performAggregateQuery(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 2,
+		Done:      true,
+		Records: []map[string]any{
+			{"attributes": map[string]any{"type": "AggregateResult"}, "LeadSource": "Web", "Name": "Stark Industries", "expr0": 3, "total": 1500.5},
+			{"attributes": map[string]any{"type": "AggregateResult"}, "LeadSource": nil, "Name": "Wayne Enterprises", "expr0": 1, "total": 200},
+		},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	type opportunitySummary struct {
+		LeadSource  string
+		AccountName string  `sf:"Account.Name"`
+		Count       int     `sf:"expr0"`
+		Total       float64 `sf:"total"`
+	}
+
+	t.Run("decode_struct", func(t *testing.T) {
+		got := []opportunitySummary{}
+		if err := performAggregateQuery(&sfAuth, "SELECT LeadSource, Account.Name, COUNT(Id), SUM(Amount) total FROM Opportunity GROUP BY LeadSource, Account.Name", &got, dmlOptions{}); err != nil {
+			t.Fatalf("performAggregateQuery() error = %v", err)
+		}
+		want := []opportunitySummary{
+			{LeadSource: "Web", AccountName: "Stark Industries", Count: 3, Total: 1500.5},
+			{AccountName: "Wayne Enterprises", Count: 1, Total: 200},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("performAggregateQuery() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("decode_aggregate_results", func(t *testing.T) {
+		got := []AggregateResult{}
+		if err := performAggregateQuery(&sfAuth, "SELECT LeadSource, COUNT(Id) FROM Opportunity GROUP BY LeadSource", &got, dmlOptions{}); err != nil {
+			t.Fatalf("performAggregateQuery() error = %v", err)
+		}
+		if len(got) != 2 || got[0].Int("expr0") != 3 || got[0].String("LeadSource") != "Web" || got[0].Float("total") != 1500.5 {
+			t.Errorf("performAggregateQuery() = %v", got)
+		}
+		if _, ok := got[0]["attributes"]; ok {
+			t.Errorf("performAggregateQuery() kept attributes: %v", got[0])
+		}
+	})
+
+	t.Run("bad_request", func(t *testing.T) {
+		got := []AggregateResult{}
+		if err := performAggregateQuery(&badSfAuth, "SELECT COUNT(Id) FROM Opportunity", &got, dmlOptions{}); err == nil {
+			t.Errorf("performAggregateQuery() expected error")
+		}
+	})
+}
+
+func TestAggregateResult_accessors(t *testing.T) {
+	result := AggregateResult{"expr0": float64(42), "avg": "2.5", "name": "Hot", "missing": nil}
+	if result.Int("expr0") != 42 || result.Float("avg") != 2.5 || result.String("name") != "Hot" {
+		t.Errorf("AggregateResult accessors = %v, %v, %v", result.Int("expr0"), result.Float("avg"), result.String("name"))
+	}
+	if result.String("expr0") != "42" || result.String("missing") != "" || result.Int("unknown") != 0 {
+		t.Errorf("AggregateResult accessors returned unexpected values for %v", result)
+	}
+}
@@ -0,0 +1,44 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type ExecuteAnonymousResult struct {
+	Line                int    `json:"line"`
+	Column              int    `json:"column"`
+	Compiled            bool   `json:"compiled"`
+	Success             bool   `json:"success"`
+	CompileProblem      string `json:"compileProblem"`
+	ExceptionMessage    string `json:"exceptionMessage"`
+	ExceptionStackTrace string `json:"exceptionStackTrace"`
+}
+
+func doExecuteAnonymousApex(auth *authentication, code string) (ExecuteAnonymousResult, error) {
+	if code == "" {
+		return ExecuteAnonymousResult{}, errors.New("apex code is required")
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     "/tooling/executeAnonymous/?anonymousBody=" + url.QueryEscape(code),
+		content: jsonType,
+	})
+	if err != nil {
+		return ExecuteAnonymousResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ExecuteAnonymousResult{}, err
+	}
+	result := ExecuteAnonymousResult{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return ExecuteAnonymousResult{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_doExecuteAnonymousApex(t *testing.T) {
+	success := ExecuteAnonymousResult{
+		Line:     -1,
+		Column:   -1,
+		Compiled: true,
+		Success:  true,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/tooling/executeAnonymous/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("anonymousBody") != "System.debug('hi & bye');" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := json.Marshal(success)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	compileFailure := ExecuteAnonymousResult{
+		Line:           1,
+		Column:         8,
+		CompileProblem: "Unexpected token '('.",
+	}
+	compileServer, compileAuth := setupTestServer(compileFailure, http.StatusOK)
+	defer compileServer.Close()
+
+	exception := ExecuteAnonymousResult{
+		Line:                1,
+		Column:              1,
+		Compiled:            true,
+		ExceptionMessage:    "System.NullPointerException: Attempt to de-reference a null object",
+		ExceptionStackTrace: "AnonymousBlock: line 1, column 1",
+	}
+	exceptionServer, exceptionAuth := setupTestServer(exception, http.StatusOK)
+	defer exceptionServer.Close()
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	type args struct {
+		auth *authentication
+		code string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    ExecuteAnonymousResult
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				auth: &sfAuth,
+				code: "System.debug('hi & bye');",
+			},
+			want:    success,
+			wantErr: false,
+		},
+		{
+			name: "compile_failure",
+			args: args{
+				auth: &compileAuth,
+				code: "System.debug(;",
+			},
+			want:    compileFailure,
+			wantErr: false,
+		},
+		{
+			name: "exception",
+			args: args{
+				auth: &exceptionAuth,
+				code: "Account a; a.Name = 'x';",
+			},
+			want:    exception,
+			wantErr: false,
+		},
+		{
+			name: "empty_code",
+			args: args{
+				auth: &sfAuth,
+				code: "",
+			},
+			want:    ExecuteAnonymousResult{},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth: &badAuth,
+				code: "System.debug('hi');",
+			},
+			want:    ExecuteAnonymousResult{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doExecuteAnonymousApex(tt.args.auth, tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doExecuteAnonymousApex() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doExecuteAnonymousApex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
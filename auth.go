@@ -15,15 +15,18 @@ import (
 )
 
 type authentication struct {
-	AccessToken string `json:"access_token"`
-	InstanceUrl string `json:"instance_url"`
-	Id          string `json:"id"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
-	IssuedAt    string `json:"issued_at"`
-	Signature   string `json:"signature"`
-	grantType   string
-	creds       Creds
+	AccessToken  string `json:"access_token"`
+	InstanceUrl  string `json:"instance_url"`
+	Id           string `json:"id"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	IssuedAt     string `json:"issued_at"`
+	Signature    string `json:"signature"`
+	RefreshToken string `json:"refresh_token"`
+	grantType    string
+	creds        Creds
+	config       *configuration
+	lock         *sessionLock
 }
 
 type Creds struct {
@@ -35,10 +38,18 @@ type Creds struct {
 	ConsumerSecret string
 	ConsumerRSAPem string
 	AccessToken    string
+	Environment    string
 }
 
 const JwtExpirationTime = 5 * time.Minute
 
+const (
+	EnvironmentProduction = "production"
+	EnvironmentSandbox    = "sandbox"
+	productionLoginUrl    = "https://login.salesforce.com"
+	sandboxLoginUrl       = "https://test.salesforce.com"
+)
+
 const (
 	grantTypeUsernamePassword  = "password"
 	grantTypeClientCredentials = "client_credentials"
@@ -53,6 +64,51 @@ func validateAuth(sf Salesforce) error {
 	return nil
 }
 
+// adds a missing https scheme and removes trailing slashes so that endpoints can be appended
+func normalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return "", nil
+	}
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	parsedUrl, err := url.Parse(domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain: %w", err)
+	}
+	if (parsedUrl.Scheme != "https" && parsedUrl.Scheme != "http") || parsedUrl.Host == "" {
+		return "", errors.New("invalid domain: " + domain)
+	}
+	if strings.Trim(parsedUrl.Path, "/") != "" || parsedUrl.RawQuery != "" {
+		return "", errors.New("invalid domain, expected only a scheme and host: " + domain)
+	}
+	return parsedUrl.Scheme + "://" + parsedUrl.Host, nil
+}
+
+// the login servers return the org's instance url after authenticating, so a my domain url is optional
+func resolveDomain(creds Creds) (string, error) {
+	domain, err := normalizeDomain(creds.Domain)
+	if err != nil || domain != "" {
+		return domain, err
+	}
+
+	switch creds.Environment {
+	case "":
+		return "", nil
+	case EnvironmentProduction:
+		domain = productionLoginUrl
+	case EnvironmentSandbox:
+		domain = sandboxLoginUrl
+	default:
+		return "", fmt.Errorf("invalid environment: %s, expected %s or %s", creds.Environment, EnvironmentProduction, EnvironmentSandbox)
+	}
+	if creds.AccessToken != "" && creds.ConsumerKey == "" {
+		return "", errors.New("authenticating with an access token requires the instance url as the domain")
+	}
+	return domain, nil
+}
+
 func validateSession(auth authentication) error {
 	if err := validateAuth(Salesforce{auth: &auth}); err != nil {
 		return err
@@ -69,33 +125,58 @@ func validateSession(auth authentication) error {
 	return nil
 }
 
+// requests that fail with the same expired token wait for one refresh, and are retried with its token
+func refreshExpiredSession(auth *authentication, expiredToken string) error {
+	lock := auth.sessionLock()
+	lock.refresh.Lock()
+	defer lock.refresh.Unlock()
+	if _, accessToken := auth.session(); accessToken != expiredToken {
+		return nil
+	}
+	return refreshSession(auth)
+}
+
 func refreshSession(auth *authentication) error {
+	if auth.config.hasTokenProvider() {
+		return refreshFromTokenProvider(auth)
+	}
 	var refreshedAuth *authentication
 	var err error
+	instanceUrl, _ := auth.session()
 
 	switch grantType := auth.grantType; grantType {
 	case grantTypeClientCredentials:
 		refreshedAuth, err = clientCredentialsFlow(
-			auth.InstanceUrl,
+			instanceUrl,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerSecret,
+			auth.config,
 		)
 	case grantTypeUsernamePassword:
 		refreshedAuth, err = usernamePasswordFlow(
-			auth.InstanceUrl,
+			instanceUrl,
 			auth.creds.Username,
 			auth.creds.Password,
 			auth.creds.SecurityToken,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerSecret,
+			auth.config,
 		)
 	case grantTypeJWT:
 		refreshedAuth, err = jwtFlow(
-			auth.InstanceUrl,
+			instanceUrl,
 			auth.creds.Username,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerRSAPem,
 			JwtExpirationTime,
+			auth.config,
+		)
+	case grantTypeDevice, grantTypeAuthorizationCode:
+		refreshedAuth, err = refreshTokenFlow(
+			instanceUrl,
+			auth.creds.ConsumerKey,
+			auth.RefreshToken,
+			auth.config,
 		)
 	default:
 		return errors.New("invalid session, unable to refresh session")
@@ -109,6 +190,9 @@ func refreshSession(auth *authentication) error {
 		return errors.New("missing refresh auth")
 	}
 
+	lock := auth.sessionLock()
+	lock.Lock()
+	defer lock.Unlock()
 	auth.AccessToken = refreshedAuth.AccessToken
 	auth.IssuedAt = refreshedAuth.IssuedAt
 	auth.Signature = refreshedAuth.Signature
@@ -117,8 +201,8 @@ func refreshSession(auth *authentication) error {
 	return nil
 }
 
-func doAuth(url string, body *strings.Reader) (*authentication, error) {
-	resp, err := http.Post(url, "application/x-www-form-urlencoded", body)
+func doAuth(url string, body *strings.Reader, config *configuration) (*authentication, error) {
+	resp, err := config.client().Post(url, formType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -131,17 +215,18 @@ func doAuth(url string, body *strings.Reader) (*authentication, error) {
 		return nil, err
 	}
 
-	auth := &authentication{}
+	auth := &authentication{lock: &sessionLock{}}
 	jsonError := json.Unmarshal(respBody, &auth)
 	if jsonError != nil {
 		return nil, jsonError
 	}
 
 	defer resp.Body.Close()
+	auth.config = config
 	return auth, nil
 }
 
-func usernamePasswordFlow(domain string, username string, password string, securityToken string, consumerKey string, consumerSecret string) (*authentication, error) {
+func usernamePasswordFlow(domain string, username string, password string, securityToken string, consumerKey string, consumerSecret string, config *configuration) (*authentication, error) {
 	payload := url.Values{
 		"grant_type":    {grantTypeUsernamePassword},
 		"client_id":     {consumerKey},
@@ -151,7 +236,7 @@ func usernamePasswordFlow(domain string, username string, password string, secur
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := doAuth(domain+endpoint, body, config)
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +244,7 @@ func usernamePasswordFlow(domain string, username string, password string, secur
 	return auth, nil
 }
 
-func clientCredentialsFlow(domain string, consumerKey string, consumerSecret string) (*authentication, error) {
+func clientCredentialsFlow(domain string, consumerKey string, consumerSecret string, config *configuration) (*authentication, error) {
 	payload := url.Values{
 		"grant_type":    {grantTypeClientCredentials},
 		"client_id":     {consumerKey},
@@ -167,7 +252,7 @@ func clientCredentialsFlow(domain string, consumerKey string, consumerSecret str
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := doAuth(domain+endpoint, body, config)
 	if err != nil {
 		return nil, err
 	}
@@ -175,16 +260,20 @@ func clientCredentialsFlow(domain string, consumerKey string, consumerSecret str
 	return auth, nil
 }
 
-func setAccessToken(domain string, accessToken string) (*authentication, error) {
-	auth := &authentication{InstanceUrl: domain, AccessToken: accessToken}
-	if err := validateSession(*auth); err != nil {
+func setAccessToken(domain string, accessToken string, config *configuration) (*authentication, error) {
+	auth := &authentication{InstanceUrl: domain, AccessToken: accessToken, config: config, lock: &sessionLock{}}
+	if config == nil || !config.skipSessionValidation {
+		if err := validateSession(*auth); err != nil {
+			return nil, err
+		}
+	} else if err := validateAuth(Salesforce{auth: auth}); err != nil {
 		return nil, err
 	}
 	auth.grantType = grantTypeAccessToken
 	return auth, nil
 }
 
-func jwtFlow(domain string, username string, consumerKey string, consumerRSAPem string, expirationTime time.Duration) (*authentication, error) {
+func jwtFlow(domain string, username string, consumerKey string, consumerRSAPem string, expirationTime time.Duration, config *configuration) (*authentication, error) {
 	audience := domain
 	if strings.Contains(audience, "test.salesforce") || strings.Contains(audience, "sandbox") {
 		audience = "https://test.salesforce.com"
@@ -213,7 +302,7 @@ func jwtFlow(domain string, username string, consumerKey string, consumerRSAPem
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := doAuth(domain+endpoint, body, config)
 	if err != nil {
 		return nil, err
 	}
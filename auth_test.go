@@ -43,6 +43,106 @@ func Test_validateAuth(t *testing.T) {
 	}
 }
 
+func Test_normalizeDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "already_normalized",
+			domain:  "https://example.my.salesforce.com",
+			want:    "https://example.my.salesforce.com",
+			wantErr: false,
+		},
+		{
+			name:    "missing_scheme_and_trailing_slash",
+			domain:  " example.my.salesforce.com/ ",
+			want:    "https://example.my.salesforce.com",
+			wantErr: false,
+		},
+		{
+			name:    "empty",
+			domain:  "",
+			want:    "",
+			wantErr: false,
+		},
+		{
+			name:    "unsupported_scheme",
+			domain:  "ftp://example.my.salesforce.com",
+			wantErr: true,
+		},
+		{
+			name:    "path",
+			domain:  "https://example.my.salesforce.com/services/data",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("normalizeDomain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("normalizeDomain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolveDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		creds   Creds
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "domain_takes_precedence",
+			creds:   Creds{Domain: "example.my.salesforce.com", Environment: EnvironmentSandbox},
+			want:    "https://example.my.salesforce.com",
+			wantErr: false,
+		},
+		{
+			name:    "production",
+			creds:   Creds{Environment: EnvironmentProduction, ConsumerKey: "key"},
+			want:    "https://login.salesforce.com",
+			wantErr: false,
+		},
+		{
+			name:    "sandbox",
+			creds:   Creds{Environment: EnvironmentSandbox, ConsumerKey: "key"},
+			want:    "https://test.salesforce.com",
+			wantErr: false,
+		},
+		{
+			name:    "invalid_environment",
+			creds:   Creds{Environment: "staging"},
+			wantErr: true,
+		},
+		{
+			name:    "access_token_without_instance_url",
+			creds:   Creds{Environment: EnvironmentProduction, AccessToken: "token"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveDomain(tt.creds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveDomain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveDomain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_usernamePasswordFlow(t *testing.T) {
 	auth := authentication{
 		AccessToken: "1234",
@@ -51,6 +151,7 @@ func Test_usernamePasswordFlow(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeUsernamePassword,
+		lock:        &sessionLock{},
 	}
 	server, _ := setupTestServer(auth, http.StatusOK)
 	defer server.Close()
@@ -101,7 +202,7 @@ func Test_usernamePasswordFlow(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := usernamePasswordFlow(tt.args.domain, tt.args.username, tt.args.password, tt.args.securityToken, tt.args.consumerKey, tt.args.consumerSecret)
+			got, err := usernamePasswordFlow(tt.args.domain, tt.args.username, tt.args.password, tt.args.securityToken, tt.args.consumerKey, tt.args.consumerSecret, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("loginPassword() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -121,6 +222,7 @@ func Test_clientCredentialsFlow(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeClientCredentials,
+		lock:        &sessionLock{},
 	}
 	server, _ := setupTestServer(auth, http.StatusOK)
 	defer server.Close()
@@ -162,7 +264,7 @@ func Test_clientCredentialsFlow(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := clientCredentialsFlow(tt.args.domain, tt.args.consumerKey, tt.args.consumerSecret)
+			got, err := clientCredentialsFlow(tt.args.domain, tt.args.consumerKey, tt.args.consumerSecret, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("clientCredentialsFlow() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -225,7 +327,7 @@ func Test_setAccessToken(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := setAccessToken(tt.args.domain, tt.args.accessToken)
+			got, err := setAccessToken(tt.args.domain, tt.args.accessToken, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("setAccessToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -345,6 +447,7 @@ func Test_jwtFlow(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeJWT,
+		lock:        &sessionLock{},
 	}
 	server, _ := setupTestServer(auth, http.StatusOK)
 	defer server.Close()
@@ -391,7 +494,7 @@ func Test_jwtFlow(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := jwtFlow(tt.args.domain, tt.args.username, tt.args.consumerKey, tt.args.consumerRSAPem, 1*time.Minute)
+			got, err := jwtFlow(tt.args.domain, tt.args.username, tt.args.consumerKey, tt.args.consumerRSAPem, 1*time.Minute, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("jwtFlow() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,137 @@
+package benchmarks
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/k-capehart/go-salesforce/v2"
+)
+
+// Benchmarks run against an in-process mock server unless SF_BENCH_INSTANCE_URL and
+// SF_BENCH_ACCESS_TOKEN point at a scratch org. Never point them at production.
+//
+//	SF_BENCH_RECORDS      number of records per operation (default 100000)
+//	SF_BENCH_SOBJECT      sObject to upsert and export (default Account)
+//	SF_BENCH_EXTERNAL_ID  external id field used for upserts (default ExternalId__c)
+
+const defaultRecords = 100000
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func numberOfRecords(b *testing.B) int {
+	n, err := strconv.Atoi(getEnv("SF_BENCH_RECORDS", strconv.Itoa(defaultRecords)))
+	if err != nil || n < 1 {
+		b.Fatalf("invalid SF_BENCH_RECORDS: %s", os.Getenv("SF_BENCH_RECORDS"))
+	}
+	return n
+}
+
+func setup(b *testing.B, queryRecords int) *salesforce.Salesforce {
+	instanceUrl := os.Getenv("SF_BENCH_INSTANCE_URL")
+	accessToken := os.Getenv("SF_BENCH_ACCESS_TOKEN")
+	if instanceUrl == "" || accessToken == "" {
+		server := newMockServer(queryRecords)
+		b.Cleanup(server.Close)
+		instanceUrl, accessToken = server.URL, "accesstokenvalue"
+	}
+	sf, err := salesforce.NewFromToken(instanceUrl, accessToken, salesforce.WithoutSessionValidation())
+	if err != nil {
+		b.Fatal(err)
+	}
+	return sf
+}
+
+func records(n int, externalIdField string) []map[string]any {
+	records := make([]map[string]any, n)
+	for i := range records {
+		records[i] = map[string]any{
+			"Name":          "Benchmark Account " + strconv.Itoa(i),
+			externalIdField: "bench-" + strconv.Itoa(i),
+		}
+	}
+	return records
+}
+
+func reportThroughput(b *testing.B, n int) {
+	b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "records/s")
+}
+
+func BenchmarkUpsertCollection(b *testing.B) {
+	n := numberOfRecords(b)
+	sf := setup(b, 0)
+	sObject := getEnv("SF_BENCH_SOBJECT", "Account")
+	externalId := getEnv("SF_BENCH_EXTERNAL_ID", "ExternalId__c")
+	data := records(n, externalId)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sf.UpsertCollection(sObject, externalId, data, 200); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportThroughput(b, n)
+}
+
+func BenchmarkUpsertComposite(b *testing.B) {
+	n := numberOfRecords(b)
+	sf := setup(b, 0)
+	sObject := getEnv("SF_BENCH_SOBJECT", "Account")
+	externalId := getEnv("SF_BENCH_EXTERNAL_ID", "ExternalId__c")
+	data := records(n, externalId)
+	// a single composite request holds at most 25 subrequests of 200 records
+	const recordsPerRequest = 25 * 200
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for start := 0; start < len(data); start += recordsPerRequest {
+			batch := data[start:min(start+recordsPerRequest, len(data))]
+			if _, err := sf.UpsertComposite(sObject, externalId, batch, 200, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	reportThroughput(b, n)
+}
+
+func BenchmarkUpsertBulk(b *testing.B) {
+	n := numberOfRecords(b)
+	sf := setup(b, 0)
+	sObject := getEnv("SF_BENCH_SOBJECT", "Account")
+	externalId := getEnv("SF_BENCH_EXTERNAL_ID", "ExternalId__c")
+	data := records(n, externalId)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// measures job creation and upload; processing time on the org is not included
+		if _, err := sf.UpsertBulk(sObject, externalId, data, 10000, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportThroughput(b, n)
+}
+
+func BenchmarkQueryBulkExport(b *testing.B) {
+	n := numberOfRecords(b)
+	sf := setup(b, n)
+	sObject := getEnv("SF_BENCH_SOBJECT", "Account")
+	filePath := filepath.Join(b.TempDir(), "export.csv")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := sf.QueryBulkExport("SELECT Id, Name FROM "+sObject, filePath); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportThroughput(b, n)
+}
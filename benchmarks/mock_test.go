@@ -0,0 +1,100 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+type mockResult struct {
+	Id      string `json:"id"`
+	Success bool   `json:"success"`
+	Errors  []any  `json:"errors"`
+}
+
+type mockCollection struct {
+	Records []map[string]any `json:"records"`
+}
+
+type mockCompositeRequest struct {
+	CompositeRequest []struct {
+		Body        mockCollection `json:"body"`
+		ReferenceId string         `json:"referenceId"`
+	} `json:"compositeRequest"`
+}
+
+type mockCompositeResult struct {
+	Body           []mockResult `json:"body"`
+	HttpStatusCode int          `json:"httpStatusCode"`
+	ReferenceId    string       `json:"referenceId"`
+}
+
+func mockResults(n int) []mockResult {
+	results := make([]mockResult, n)
+	for i := range results {
+		results[i] = mockResult{Id: "001000000000000AAA", Success: true, Errors: []any{}}
+	}
+	return results
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	data, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// newMockServer emulates the REST, composite, and Bulk v2 endpoints used by the benchmarks
+// queryRecords controls how many rows bulk query jobs return
+func newMockServer(queryRecords int) *httptest.Server {
+	var jobs atomic.Int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.Contains(path, "/composite/sobjects"):
+			collection := mockCollection{}
+			if err := json.NewDecoder(r.Body).Decode(&collection); err != nil {
+				writeJSON(w, http.StatusBadRequest, nil)
+				return
+			}
+			writeJSON(w, http.StatusOK, mockResults(len(collection.Records)))
+		case strings.HasSuffix(path, "/composite"):
+			compReq := mockCompositeRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&compReq); err != nil {
+				writeJSON(w, http.StatusBadRequest, nil)
+				return
+			}
+			results := []mockCompositeResult{}
+			for _, subReq := range compReq.CompositeRequest {
+				results = append(results, mockCompositeResult{
+					Body:           mockResults(len(subReq.Body.Records)),
+					HttpStatusCode: http.StatusOK,
+					ReferenceId:    subReq.ReferenceId,
+				})
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"compositeResponse": results})
+		case strings.HasSuffix(path, "/jobs/ingest") || strings.HasSuffix(path, "/jobs/query"):
+			id := fmt.Sprintf("750%015d", jobs.Add(1))
+			writeJSON(w, http.StatusOK, map[string]any{"id": id, "state": "Open"})
+		case strings.HasSuffix(path, "/batches"):
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasSuffix(path, "/results"):
+			var csv strings.Builder
+			csv.WriteString("Id,Name\n")
+			for i := 0; i < queryRecords; i++ {
+				csv.WriteString("001000000000000AAA,Account " + strconv.Itoa(i) + "\n")
+			}
+			w.Header().Set("Sforce-Locator", "null")
+			w.Header().Set("Sforce-Numberofrecords", strconv.Itoa(queryRecords))
+			_, _ = w.Write([]byte(csv.String()))
+		case strings.Contains(path, "/jobs/"):
+			writeJSON(w, http.StatusOK, map[string]any{"id": path[strings.LastIndex(path, "/")+1:], "state": "JobComplete"})
+		default:
+			writeJSON(w, http.StatusOK, map[string]any{})
+		}
+	}))
+}
@@ -0,0 +1,126 @@
+package salesforce
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	soqlDateFormat     = "2006-01-02"
+	soqlDateTimeFormat = "2006-01-02T15:04:05Z"
+)
+
+var soqlStringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"\b", `\b`,
+	"\f", `\f`,
+)
+
+// replaces each :name in the query with its formatted value, leaving string literals untouched
+func bindQueryParams(query string, params map[string]any) (string, error) {
+	var builder strings.Builder
+	inLiteral := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case inLiteral:
+			builder.WriteByte(c)
+			if c == '\\' && i+1 < len(query) {
+				i++
+				builder.WriteByte(query[i])
+			} else if c == '\'' {
+				inLiteral = false
+			}
+		case c == '\'':
+			inLiteral = true
+			builder.WriteByte(c)
+		case c == ':' && i+1 < len(query) && isBindNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isBindNamePart(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, ok := params[name]
+			if !ok {
+				return "", errors.New("missing value for bind variable: " + name)
+			}
+			formatted, err := formatSoqlValue(value)
+			if err != nil {
+				return "", errors.New("invalid value for bind variable " + name + ": " + err.Error())
+			}
+			builder.WriteString(formatted)
+			i = end - 1
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	if inLiteral {
+		return "", errors.New("unterminated string literal in query")
+	}
+	return builder.String(), nil
+}
+
+func isBindNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isBindNamePart(c byte) bool {
+	return isBindNameStart(c) || (c >= '0' && c <= '9')
+}
+
+func formatSoqlValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case time.Time:
+		return v.UTC().Format(soqlDateTimeFormat), nil
+	case Date:
+		return v.String(), nil
+	case Datetime:
+		return time.Time(v).UTC().Format(soqlDateTimeFormat), nil
+	case Time:
+		return v.String(), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return "'" + soqlStringEscaper.Replace(rv.String()) + "'", nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "null", nil
+		}
+		return formatSoqlValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		// lists are bound for use in IN and NOT IN clauses, which can't be empty
+		if rv.Len() == 0 {
+			return "", errors.New("list must not be empty")
+		}
+		values := make([]string, rv.Len())
+		for i := range values {
+			formatted, err := formatSoqlValue(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			values[i] = formatted
+		}
+		return "(" + strings.Join(values, ",") + ")", nil
+	}
+	return "", errors.New("unsupported type " + rv.Type().String())
+}
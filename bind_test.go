@@ -0,0 +1,120 @@
+package salesforce
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_bindQueryParams(t *testing.T) {
+	closeDate := time.Date(2024, time.March, 5, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	tests := []struct {
+		name    string
+		query   string
+		params  map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "string_and_number",
+			query:  "SELECT Id FROM Account WHERE Name = :name AND NumberOfEmployees > :size",
+			params: map[string]any{"name": "Stark Industries", "size": 100},
+			want:   "SELECT Id FROM Account WHERE Name = 'Stark Industries' AND NumberOfEmployees > 100",
+		},
+		{
+			name:   "escape_injection",
+			query:  "SELECT Id FROM Contact WHERE LastName = :lastName",
+			params: map[string]any{"lastName": `O'Brien' OR Name != '\`},
+			want:   `SELECT Id FROM Contact WHERE LastName = 'O\'Brien\' OR Name != \'\\'`,
+		},
+		{
+			name:   "dates",
+			query:  "SELECT Id FROM Opportunity WHERE CloseDate = :closeDate AND CreatedDate > :created",
+			params: map[string]any{"closeDate": Date(closeDate), "created": closeDate},
+			want:   "SELECT Id FROM Opportunity WHERE CloseDate = 2024-03-05 AND CreatedDate > 2024-03-05T14:30:00Z",
+		},
+		{
+			name:   "in_clause",
+			query:  "SELECT Id FROM Account WHERE Id IN :ids AND Rating IN :ratings",
+			params: map[string]any{"ids": []string{"001A", "001B"}, "ratings": [1]string{"Hot"}},
+			want:   "SELECT Id FROM Account WHERE Id IN ('001A','001B') AND Rating IN ('Hot')",
+		},
+		{
+			name:   "null_bool_and_float",
+			query:  "SELECT Id FROM Account WHERE ParentId = :parent AND IsDeleted = :deleted AND AnnualRevenue > :revenue",
+			params: map[string]any{"parent": nil, "deleted": false, "revenue": 1.5},
+			want:   "SELECT Id FROM Account WHERE ParentId = null AND IsDeleted = false AND AnnualRevenue > 1.5",
+		},
+		{
+			name:   "ignore_literals_and_date_literals",
+			query:  "SELECT Id FROM Account WHERE Name = 'a :name \\' :name' AND CreatedDate = LAST_N_DAYS:7 AND Site = :name",
+			params: map[string]any{"name": "x"},
+			want:   "SELECT Id FROM Account WHERE Name = 'a :name \\' :name' AND CreatedDate = LAST_N_DAYS:7 AND Site = 'x'",
+		},
+		{
+			name:    "missing_param",
+			query:   "SELECT Id FROM Account WHERE Name = :name",
+			params:  map[string]any{},
+			wantErr: true,
+		},
+		{
+			name:    "empty_list",
+			query:   "SELECT Id FROM Account WHERE Id IN :ids",
+			params:  map[string]any{"ids": []string{}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported_type",
+			query:   "SELECT Id FROM Account WHERE Name = :name",
+			params:  map[string]any{"name": map[string]string{}},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated_literal",
+			query:   "SELECT Id FROM Account WHERE Name = 'abc",
+			params:  nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bindQueryParams(tt.query, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bindQueryParams() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("bindQueryParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatSoqlValue(t *testing.T) {
+	type rating string
+	name := "Wayne Enterprises"
+	var nilPointer *string
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "named_string", value: rating("Hot"), want: "'Hot'"},
+		{name: "pointer", value: &name, want: "'Wayne Enterprises'"},
+		{name: "nil_pointer", value: nilPointer, want: "null"},
+		{name: "unsigned", value: uint16(7), want: "7"},
+		{name: "newline", value: "a\nb", want: `'a\nb'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatSoqlValue(tt.value)
+			if err != nil {
+				t.Fatalf("formatSoqlValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("formatSoqlValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
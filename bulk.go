@@ -9,9 +9,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
@@ -20,25 +26,106 @@ type bulkJobCreationRequest struct {
 	Object              string `json:"object"`
 	Operation           string `json:"operation"`
 	ExternalIdFieldName string `json:"externalIdFieldName"`
+	ColumnDelimiter     string `json:"columnDelimiter,omitempty"`
+	ContentType         string `json:"contentType,omitempty"`
+	LineEnding          string `json:"lineEnding,omitempty"`
+	ConcurrencyMode     string `json:"concurrencyMode,omitempty"`
 }
 
+type BulkJobOptions struct {
+	ColumnDelimiter      string
+	ContentType          string
+	LineEnding           string
+	ConcurrencyMode      string
+	IgnoreDeletedRecords bool
+	HardDelete           bool
+	Progress             ProgressFunc
+	Plan                 *BulkLoadPlan
+	ExplicitNulls        bool
+}
+
+type BulkQueryOptions struct {
+	ChunkSize       int
+	Progress        ProgressFunc
+	Columns         []string
+	ColumnHeaders   map[string]string
+	ColumnDelimiter string
+	ContentType     string
+	LineEnding      string
+	Compress        bool
+	Format          string
+	QueryAll        bool
+}
+
+type BulkJobProgress struct {
+	JobId            string
+	State            BulkJobState
+	RecordsProcessed int
+	RecordsFailed    int
+	Elapsed          time.Duration
+}
+
+// called on each poll of a bulk job while waiting for it to finish
+type ProgressFunc func(progress BulkJobProgress)
+
 type bulkQueryJobCreationRequest struct {
-	Operation string `json:"operation"`
-	Query     string `json:"query"`
+	Operation       string `json:"operation"`
+	Query           string `json:"query"`
+	ColumnDelimiter string `json:"columnDelimiter,omitempty"`
+	ContentType     string `json:"contentType,omitempty"`
+	LineEnding      string `json:"lineEnding,omitempty"`
 }
 
 type bulkJob struct {
-	Id    string `json:"id"`
-	State string `json:"state"`
+	Id    string       `json:"id"`
+	State BulkJobState `json:"state"`
 }
 
 type BulkJobResults struct {
-	Id                  string `json:"id"`
-	State               string `json:"state"`
-	NumberRecordsFailed int    `json:"numberRecordsFailed"`
-	ErrorMessage        string `json:"errorMessage"`
-	SuccessfulRecords   []map[string]any
-	FailedRecords       []map[string]any
+	Id                      string       `json:"id"`
+	State                   BulkJobState `json:"state"`
+	NumberRecordsProcessed  int          `json:"numberRecordsProcessed"`
+	NumberRecordsFailed     int          `json:"numberRecordsFailed"`
+	ErrorMessage            string       `json:"errorMessage"`
+	ColumnDelimiter         string       `json:"columnDelimiter,omitempty"`
+	LineEnding              string       `json:"lineEnding,omitempty"`
+	ConcurrencyMode         string       `json:"concurrencyMode,omitempty"`
+	Retries                 int          `json:"retries"`
+	TotalProcessingTime     int          `json:"totalProcessingTime"`
+	ApiActiveProcessingTime int          `json:"apiActiveProcessingTime"`
+	ApexProcessingTime      int          `json:"apexProcessingTime"`
+	SuccessfulRecords       []map[string]any
+	FailedRecords           []map[string]any
+	UnprocessedRecords      []map[string]any
+}
+
+type BulkJobSummary struct {
+	Id                     string       `json:"id"`
+	Operation              string       `json:"operation"`
+	Object                 string       `json:"object"`
+	CreatedById            string       `json:"createdById"`
+	CreatedDate            string       `json:"createdDate"`
+	SystemModstamp         string       `json:"systemModstamp"`
+	State                  BulkJobState `json:"state"`
+	ConcurrencyMode        string       `json:"concurrencyMode"`
+	ContentType            string       `json:"contentType"`
+	ApiVersion             float64      `json:"apiVersion"`
+	JobType                string       `json:"jobType"`
+	LineEnding             string       `json:"lineEnding"`
+	ColumnDelimiter        string       `json:"columnDelimiter"`
+	NumberRecordsProcessed int          `json:"numberRecordsProcessed"`
+}
+
+type BulkJobFilter struct {
+	JobType             string
+	ConcurrencyMode     string
+	IsPkChunkingEnabled bool
+}
+
+type allJobsResponse struct {
+	Done           bool             `json:"done"`
+	NextRecordsUrl string           `json:"nextRecordsUrl"`
+	Records        []BulkJobSummary `json:"records"`
 }
 
 type bulkJobQueryResults struct {
@@ -48,24 +135,49 @@ type bulkJobQueryResults struct {
 }
 
 const (
-	jobStateAborted        = "Aborted"
-	jobStateUploadComplete = "UploadComplete"
-	jobStateJobComplete    = "JobComplete"
-	jobStateFailed         = "Failed"
-	jobStateOpen           = "Open"
-	insertOperation        = "insert"
-	updateOperation        = "update"
-	upsertOperation        = "upsert"
-	deleteOperation        = "delete"
-	ingestJobType          = "ingest"
-	queryJobType           = "query"
-	failedResults          = "failedResults"
-	successfulResults      = "successfulResults"
+	insertOperation       = "insert"
+	updateOperation       = "update"
+	upsertOperation       = "upsert"
+	deleteOperation       = "delete"
+	hardDeleteOperation   = "hardDelete"
+	ingestJobType         = "ingest"
+	queryJobType          = "query"
+	queryAllOperation     = "queryAll"
+	numberOfRecordsHeader = "Sforce-NumberOfRecords"
+	locatorHeader         = "Sforce-Locator"
+	failedResults         = "failedResults"
+	successfulResults     = "successfulResults"
+	unprocessedRecords    = "unprocessedrecords"
+	bulkErrorField        = "sf__Error"
+	bulkCreatedField      = "sf__Created"
+)
+
+const (
+	ColumnDelimiterBackquote = "BACKQUOTE"
+	ColumnDelimiterCaret     = "CARET"
+	ColumnDelimiterComma     = "COMMA"
+	ColumnDelimiterPipe      = "PIPE"
+	ColumnDelimiterSemicolon = "SEMICOLON"
+	ColumnDelimiterTab       = "TAB"
+	LineEndingLF             = "LF"
+	LineEndingCRLF           = "CRLF"
+	ContentTypeCSV           = "CSV"
+	ConcurrencyModeParallel  = "Parallel"
+	ConcurrencyModeSerial    = "Serial"
 )
 
+var columnDelimiters = map[string]rune{
+	ColumnDelimiterBackquote: '`',
+	ColumnDelimiterCaret:     '^',
+	ColumnDelimiterComma:     ',',
+	ColumnDelimiterPipe:      '|',
+	ColumnDelimiterSemicolon: ';',
+	ColumnDelimiterTab:       '\t',
+}
+
 var appFs = afero.NewOsFs() // afero.Fs type is a wrapper around os functions, allowing us to mock it in tests
 
-func updateJobState(job bulkJob, state string, auth *authentication) error {
+func updateJobState(job bulkJob, state BulkJobState, auth *authentication) error {
 	job.State = state
 	body, _ := json.Marshal(job)
 	_, err := doRequest(auth, requestPayload{
@@ -81,8 +193,69 @@ func updateJobState(job bulkJob, state string, auth *authentication) error {
 	return nil
 }
 
+func deleteBulkJob(auth *authentication, bulkJobId string) error {
+	_, err := doRequest(auth, requestPayload{
+		method:  http.MethodDelete,
+		uri:     "/jobs/ingest/" + bulkJobId,
+		content: jsonType,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getAllJobs(auth *authentication, jobType string, filter BulkJobFilter) ([]BulkJobSummary, error) {
+	params := url.Values{}
+	if filter.JobType != "" {
+		params.Set("jobType", filter.JobType)
+	}
+	if filter.ConcurrencyMode != "" {
+		params.Set("concurrencyMode", filter.ConcurrencyMode)
+	}
+	if filter.IsPkChunkingEnabled {
+		params.Set("isPkChunkingEnabled", "true")
+	}
+	uri := "/jobs/" + jobType
+	if len(params) > 0 {
+		uri = uri + "?" + params.Encode()
+	}
+
+	jobs := []BulkJobSummary{}
+	for uri != "" {
+		resp, err := doRequest(auth, requestPayload{
+			method:  http.MethodGet,
+			uri:     uri,
+			content: jsonType,
+		})
+		if err != nil {
+			return jobs, err
+		}
+
+		respBody, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return jobs, readErr
+		}
+
+		page := &allJobsResponse{}
+		jsonError := json.Unmarshal(respBody, page)
+		if jsonError != nil {
+			return jobs, jsonError
+		}
+		jobs = append(jobs, page.Records...)
+
+		uri = ""
+		if !page.Done && page.NextRecordsUrl != "" {
+			uri = strings.TrimPrefix(page.NextRecordsUrl, "/services/data/"+apiVersion)
+		}
+	}
+
+	return jobs, nil
+}
+
 func createBulkJob(auth *authentication, jobType string, body []byte) (bulkJob, error) {
-	resp, err := doRequest(auth, requestPayload{
+	resp, err := doBulkJobCreationRequest(auth, requestPayload{
 		method:  http.MethodPost,
 		uri:     "/jobs/" + jobType,
 		content: jsonType,
@@ -107,19 +280,27 @@ func createBulkJob(auth *authentication, jobType string, body []byte) (bulkJob,
 }
 
 func uploadJobData(auth *authentication, data string, bulkJob bulkJob) error {
+	return streamJobData(auth, func(w io.Writer) error {
+		_, err := io.WriteString(w, data)
+		return err
+	}, bulkJob)
+}
+
+// the data is written straight to the request body as it is uploaded
+func streamJobData(auth *authentication, write func(w io.Writer) error, bulkJob bulkJob) error {
 	_, uploadDataErr := doRequest(auth, requestPayload{
 		method:  http.MethodPut,
 		uri:     "/jobs/ingest/" + bulkJob.Id + "/batches",
 		content: csvType,
-		body:    data,
+		stream:  write,
 	})
 	if uploadDataErr != nil {
-		if err := updateJobState(bulkJob, jobStateAborted, auth); err != nil {
+		if err := updateJobState(bulkJob, BulkJobStateAborted, auth); err != nil {
 			return err
 		}
 		return uploadDataErr
 	}
-	stateErr := updateJobState(bulkJob, jobStateUploadComplete, auth)
+	stateErr := updateJobState(bulkJob, BulkJobStateUploadComplete, auth)
 	if stateErr != nil {
 		return stateErr
 	}
@@ -127,11 +308,12 @@ func uploadJobData(auth *authentication, data string, bulkJob bulkJob) error {
 	return nil
 }
 
-func getJobResults(auth *authentication, jobType string, bulkJobId string) (BulkJobResults, error) {
+func getJobResults(auth *authentication, jobType string, bulkJobId string, polling bool) (BulkJobResults, error) {
 	resp, err := doRequest(auth, requestPayload{
 		method:  http.MethodGet,
 		uri:     "/jobs/" + jobType + "/" + bulkJobId,
 		content: jsonType,
+		polling: polling,
 	})
 	if err != nil {
 		return BulkJobResults{}, err
@@ -152,20 +334,121 @@ func getJobResults(auth *authentication, jobType string, bulkJobId string) (Bulk
 }
 
 func getJobRecordResults(auth *authentication, bulkJobResults BulkJobResults) (BulkJobResults, error) {
-	successfulRecords, err := getBulkJobRecords(auth, bulkJobResults.Id, successfulResults)
+	successfulRecords, err := getBulkJobRecords(auth, bulkJobResults.Id, successfulResults, bulkJobResults.ColumnDelimiter)
 	if err != nil {
 		return bulkJobResults, fmt.Errorf("failed to get SuccessfulRecords: %w", err)
 	}
 	bulkJobResults.SuccessfulRecords = successfulRecords
-	failedRecords, err := getBulkJobRecords(auth, bulkJobResults.Id, failedResults)
+	failedRecords, err := getBulkJobRecords(auth, bulkJobResults.Id, failedResults, bulkJobResults.ColumnDelimiter)
 	if err != nil {
 		return bulkJobResults, fmt.Errorf("failed to get FailedRecords: %w", err)
 	}
 	bulkJobResults.FailedRecords = failedRecords
+	// only jobs that stopped early have records that were never attempted
+	if bulkJobResults.State.IsTerminal() && !bulkJobResults.State.Succeeded() {
+		unprocessed, err := getBulkJobRecords(auth, bulkJobResults.Id, unprocessedRecords, bulkJobResults.ColumnDelimiter)
+		if err != nil {
+			return bulkJobResults, fmt.Errorf("failed to get UnprocessedRecords: %w", err)
+		}
+		bulkJobResults.UnprocessedRecords = unprocessed
+	}
 	return bulkJobResults, err
 }
 
-func getBulkJobRecords(auth *authentication, bulkJobId string, resultType string) ([]map[string]any, error) {
+// date, datetime, and time formats used by salesforce in csv results
+var salesforceTimeLayouts = []string{
+	"2006-01-02T15:04:05.000Z0700",
+	time.RFC3339Nano,
+	"2006-01-02",
+	"15:04:05.000Z",
+}
+
+func stringToSalesforceTimeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || !timeTypes[to] {
+		return data, nil
+	}
+	parsed, err := parseSalesforceTime(data.(string))
+	if err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(parsed).Convert(to).Interface(), nil
+}
+
+// bulk results are csv, so every value is a string until it is weakly decoded into the target type
+func decodeBulkRecords(records []map[string]any, target any) error {
+	copies := make([]map[string]any, len(records))
+	for i, record := range records {
+		copies[i] = make(map[string]any, len(record))
+		for key, value := range record {
+			copies[i][key] = value
+		}
+	}
+	if t := reflect.TypeOf(target); t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice {
+		resolveFieldTags(t.Elem().Elem(), copies)
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       stringToSalesforceTimeHook,
+		WeaklyTypedInput: true,
+		Result:           target,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(copies)
+}
+
+// the columns salesforce adds to bulk job results; embed it in a struct with `mapstructure:",squash"` to decode them
+// alongside the record's fields
+type BulkRecordResult struct {
+	Id string `mapstructure:"sf__Id"`
+	// for upserts, whether the record was inserted rather than updated
+	Created bool   `mapstructure:"sf__Created"`
+	Error   string `mapstructure:"sf__Error"`
+}
+
+// the id and created flag of each successful record, such as to tell the inserts of an upsert job from its updates
+func (results BulkJobResults) SuccessfulRecordResults() ([]BulkRecordResult, error) {
+	recordResults := []BulkRecordResult{}
+	if err := decodeBulkRecords(results.SuccessfulRecords, &recordResults); err != nil {
+		return nil, err
+	}
+	return recordResults, nil
+}
+
+func (results BulkJobResults) DecodeSuccessfulRecords(target any) error {
+	return decodeBulkRecords(results.SuccessfulRecords, target)
+}
+
+func (results BulkJobResults) DecodeFailedRecords(target any) error {
+	return decodeBulkRecords(results.FailedRecords, target)
+}
+
+func (results BulkJobResults) DecodeUnprocessedRecords(target any) error {
+	return decodeBulkRecords(results.UnprocessedRecords, target)
+}
+
+func ignoreDeletedRecords(bulkJobResults BulkJobResults) BulkJobResults {
+	var failedRecords []map[string]any
+	for _, record := range bulkJobResults.FailedRecords {
+		sfError, _ := record[bulkErrorField].(string)
+		if !strings.HasPrefix(sfError, entityIsDeletedError) {
+			failedRecords = append(failedRecords, record)
+			continue
+		}
+		delete(record, bulkErrorField)
+		record[bulkCreatedField] = "false"
+		bulkJobResults.SuccessfulRecords = append(bulkJobResults.SuccessfulRecords, record)
+		bulkJobResults.NumberRecordsFailed--
+	}
+	bulkJobResults.FailedRecords = failedRecords
+	return bulkJobResults
+}
+
+func getBulkJobRecords(auth *authentication, bulkJobId string, resultType string, columnDelimiter string) ([]map[string]any, error) {
+	delimiter, err := getColumnDelimiter(columnDelimiter)
+	if err != nil {
+		return nil, err
+	}
 	resp, err := doRequest(auth, requestPayload{
 		method:  http.MethodGet,
 		uri:     "/jobs/ingest/" + bulkJobId + "/" + resultType,
@@ -175,6 +458,7 @@ func getBulkJobRecords(auth *authentication, bulkJobId string, resultType string
 		return nil, err
 	}
 	reader := csv.NewReader(resp.Body)
+	reader.Comma = delimiter
 	results, err := csvToMap(*reader)
 	if err != nil {
 		return nil, err
@@ -183,61 +467,210 @@ func getBulkJobRecords(auth *authentication, bulkJobId string, resultType string
 	return results, nil
 }
 
-func waitForJobResultsAsync(auth *authentication, bulkJobId string, jobType string, interval time.Duration, c chan error) {
-	err := wait.PollUntilContextTimeout(context.Background(), interval, time.Minute, false, func(context.Context) (bool, error) {
-		bulkJob, reqErr := getJobResults(auth, jobType, bulkJobId)
-		if reqErr != nil {
-			return true, reqErr
+func doGetJobResults(auth *authentication, bulkJobId string, options BulkJobOptions) (BulkJobResults, error) {
+	job, err := getJobResults(auth, ingestJobType, bulkJobId, false)
+	if err != nil {
+		return BulkJobResults{}, err
+	}
+
+	if job.State.IsTerminal() {
+		job, err = getJobRecordResults(auth, job)
+		if err != nil {
+			return job, err
+		}
+		if options.IgnoreDeletedRecords {
+			job = ignoreDeletedRecords(job)
+		}
+	}
+
+	return job, nil
+}
+
+func doGetJobResultsInto(auth *authentication, bulkJobId string, successful any, failed any, options BulkJobOptions) (BulkJobResults, error) {
+	job, err := doGetJobResults(auth, bulkJobId, options)
+	if err != nil {
+		return job, err
+	}
+	if successful != nil {
+		if err := job.DecodeSuccessfulRecords(successful); err != nil {
+			return job, fmt.Errorf("failed to decode SuccessfulRecords: %w", err)
 		}
-		return isBulkJobDone(bulkJob)
+	}
+	if failed != nil {
+		if err := job.DecodeFailedRecords(failed); err != nil {
+			return job, fmt.Errorf("failed to decode FailedRecords: %w", err)
+		}
+	}
+	return job, nil
+}
+
+// streams a result set to a file so that large jobs don't have to fit in memory
+func saveBulkJobRecords(auth *authentication, bulkJobId string, resultType string, filePath string) error {
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     "/jobs/ingest/" + bulkJobId + "/" + resultType,
+		content: csvType,
 	})
-	c <- err
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	file, fileErr := appFs.Create(filePath)
+	if fileErr != nil {
+		return fileErr
+	}
+	defer file.Close()
+
+	_, copyErr := io.Copy(file, resp.Body)
+	return copyErr
+}
+
+func doSaveJobRecordResults(auth *authentication, bulkJobId string, successPath string, failedPath string, unprocessedPath string) error {
+	resultFiles := []struct {
+		resultType string
+		filePath   string
+	}{
+		{successfulResults, successPath},
+		{failedResults, failedPath},
+		{unprocessedRecords, unprocessedPath},
+	}
+	for _, resultFile := range resultFiles {
+		if resultFile.filePath == "" {
+			continue
+		}
+		if err := saveBulkJobRecords(auth, bulkJobId, resultFile.resultType, resultFile.filePath); err != nil {
+			return fmt.Errorf("failed to save %s: %w", resultFile.resultType, err)
+		}
+	}
+	return nil
+}
+
+func waitForJobResultsAsync(auth *authentication, bulkJobId string, jobType string, interval time.Duration, progress ProgressFunc, c chan error) {
+	c <- waitForJobResults(auth, bulkJobId, jobType, interval, progress)
 }
 
-func waitForJobResults(auth *authentication, bulkJobId string, jobType string, interval time.Duration) error {
+func waitForJobResults(auth *authentication, bulkJobId string, jobType string, interval time.Duration, progress ProgressFunc) error {
+	start := time.Now()
+	var lastJob BulkJobResults
 	err := wait.PollUntilContextTimeout(context.Background(), interval, time.Minute, false, func(context.Context) (bool, error) {
-		bulkJob, reqErr := getJobResults(auth, jobType, bulkJobId)
+		bulkJob, reqErr := getJobResults(auth, jobType, bulkJobId, true)
 		if reqErr != nil {
 			return true, reqErr
 		}
-		return isBulkJobDone(bulkJob)
+		lastJob = bulkJob
+		done, jobErr := isBulkJobDone(bulkJob)
+		if done {
+			recordBulkJobTelemetry(auth, TelemetryBulkJob{
+				JobId:            bulkJobId,
+				JobType:          jobType,
+				State:            bulkJob.State,
+				RecordsProcessed: bulkJob.NumberRecordsProcessed,
+				RecordsFailed:    bulkJob.NumberRecordsFailed,
+				Duration:         time.Since(start),
+				Err:              jobErr,
+			})
+		}
+		if progress != nil {
+			progress(BulkJobProgress{
+				JobId:            bulkJobId,
+				State:            bulkJob.State,
+				RecordsProcessed: bulkJob.NumberRecordsProcessed,
+				RecordsFailed:    bulkJob.NumberRecordsFailed,
+				Elapsed:          time.Since(start),
+			})
+		}
+		return done, jobErr
 	})
+	if err != nil && wait.Interrupted(err) {
+		return newBulkJobTimeoutError(lastJob, bulkJobId, err)
+	}
 	return err
 }
 
 func isBulkJobDone(bulkJob BulkJobResults) (bool, error) {
-	if bulkJob.State == jobStateJobComplete || bulkJob.State == jobStateFailed {
-		if bulkJob.ErrorMessage != "" {
-			return true, errors.New(bulkJob.ErrorMessage)
-		}
-		return true, nil
+	if !bulkJob.State.IsTerminal() {
+		return false, nil
 	}
-	if bulkJob.State == jobStateAborted {
-		return true, errors.New("bulk job aborted")
+	if jobErr := jobLevelError(bulkJob); jobErr != nil {
+		return true, jobErr
 	}
-	return false, nil
+	return true, nil
 }
 
-func getQueryJobResults(auth *authentication, bulkJobId string, locator string) (bulkJobQueryResults, error) {
+func queryJobResultsUri(bulkJobId string, locator string, maxRecords int) string {
 	uri := "/jobs/query/" + bulkJobId + "/results"
+	params := url.Values{}
 	if locator != "" {
-		uri = uri + "/?locator=" + locator
+		params.Set("locator", locator)
 	}
+	if maxRecords > 0 {
+		params.Set("maxRecords", strconv.Itoa(maxRecords))
+	}
+	if len(params) > 0 {
+		uri = uri + "/?" + params.Encode()
+	}
+	return uri
+}
+
+type QueryResultsHeaderError struct {
+	JobId  string
+	Header string
+	Value  string
+}
+
+func (e *QueryResultsHeaderError) Error() string {
+	if e.Value == "" {
+		return "bulk query job " + e.JobId + ": missing " + e.Header + " header"
+	}
+	return "bulk query job " + e.JobId + ": invalid " + e.Header + " header: " + e.Value
+}
+
+// a missing locator would make the first page look like the last, so both headers are required.
+// the last page has a locator of "null" or an empty locator, which are returned as an empty locator
+func parseQueryResultsHeaders(bulkJobId string, header http.Header) (int, string, error) {
+	if len(header.Values(numberOfRecordsHeader)) == 0 {
+		return 0, "", &QueryResultsHeaderError{JobId: bulkJobId, Header: numberOfRecordsHeader}
+	}
+	count := header.Get(numberOfRecordsHeader)
+	numberOfRecords, err := strconv.Atoi(count)
+	if err != nil || numberOfRecords < 0 {
+		return 0, "", &QueryResultsHeaderError{JobId: bulkJobId, Header: numberOfRecordsHeader, Value: count}
+	}
+	if len(header.Values(locatorHeader)) == 0 {
+		return 0, "", &QueryResultsHeaderError{JobId: bulkJobId, Header: locatorHeader}
+	}
+	locator := header.Get(locatorHeader)
+	if locator == "null" {
+		locator = ""
+	}
+	return numberOfRecords, locator, nil
+}
+
+// results are returned in the column delimiter and line ending the job was created with
+func getQueryJobResults(auth *authentication, bulkJobId string, locator string, options BulkQueryOptions) (bulkJobQueryResults, error) {
+	delimiter, err := getColumnDelimiter(options.ColumnDelimiter)
+	if err != nil {
+		return bulkJobQueryResults{}, err
+	}
+	uri := queryJobResultsUri(bulkJobId, locator, options.ChunkSize)
 	resp, err := doRequest(auth, requestPayload{method: http.MethodGet, uri: uri, content: jsonType})
 	if err != nil {
 		return bulkJobQueryResults{}, err
 	}
 
+	defer resp.Body.Close()
+
+	numberOfRecords, locator, headerErr := parseQueryResultsHeaders(bulkJobId, resp.Header)
+	if headerErr != nil {
+		return bulkJobQueryResults{}, headerErr
+	}
 	reader := csv.NewReader(resp.Body)
+	reader.Comma = delimiter
 	records, readErr := reader.ReadAll()
 	if readErr != nil {
 		return bulkJobQueryResults{}, readErr
 	}
-	numberOfRecords, _ := strconv.Atoi(resp.Header["Sforce-Numberofrecords"][0])
-	locator = ""
-	if resp.Header["Sforce-Locator"][0] != "null" {
-		locator = resp.Header["Sforce-Locator"][0]
-	}
 
 	queryResults := bulkJobQueryResults{
 		NumberOfRecords: numberOfRecords,
@@ -248,47 +681,246 @@ func getQueryJobResults(auth *authentication, bulkJobId string, locator string)
 	return queryResults, nil
 }
 
-func collectQueryResults(auth *authentication, bulkJobId string) ([][]string, error) {
-	queryResults, resultsErr := getQueryJobResults(auth, bulkJobId, "")
+// passes each page of results to write as it is downloaded, so that large result sets don't have to fit in memory
+func collectQueryResults(auth *authentication, bulkJobId string, options BulkQueryOptions, write func(records [][]string) error) error {
+	queryResults, resultsErr := getQueryJobResults(auth, bulkJobId, "", options)
 	if resultsErr != nil {
-		return nil, resultsErr
+		return resultsErr
+	}
+	if err := write(queryResults.Data); err != nil {
+		return err
 	}
-	records := queryResults.Data
 	for queryResults.Locator != "" {
-		queryResults, resultsErr = getQueryJobResults(auth, bulkJobId, queryResults.Locator)
+		queryResults, resultsErr = getQueryJobResults(auth, bulkJobId, queryResults.Locator, options)
 		if resultsErr != nil {
-			return nil, resultsErr
+			return resultsErr
+		}
+		if len(queryResults.Data) == 0 {
+			continue
+		}
+		if err := write(queryResults.Data[1:]); err != nil { // don't include headers in subsequent batches
+			return err
+		}
+	}
+	return nil
+}
+
+func getColumnDelimiter(columnDelimiter string) (rune, error) {
+	if columnDelimiter == "" {
+		return ',', nil
+	}
+	delimiter, ok := columnDelimiters[columnDelimiter]
+	if !ok {
+		return 0, errors.New("invalid column delimiter: " + columnDelimiter)
+	}
+	return delimiter, nil
+}
+
+func newBulkQueryOptions(opts ...BulkQueryOptions) (BulkQueryOptions, error) {
+	options := BulkQueryOptions{}
+	for _, opt := range opts {
+		if opt.ChunkSize != 0 {
+			options.ChunkSize = opt.ChunkSize
+		}
+		if opt.Progress != nil {
+			options.Progress = opt.Progress
+		}
+		if opt.Columns != nil {
+			options.Columns = opt.Columns
+		}
+		if opt.ColumnHeaders != nil {
+			options.ColumnHeaders = opt.ColumnHeaders
+		}
+		if opt.ColumnDelimiter != "" {
+			options.ColumnDelimiter = opt.ColumnDelimiter
 		}
-		records = append(records, queryResults.Data[1:]...) // don't include headers in subsequent batches
+		if opt.ContentType != "" {
+			options.ContentType = opt.ContentType
+		}
+		if opt.LineEnding != "" {
+			options.LineEnding = opt.LineEnding
+		}
+		options.Compress = options.Compress || opt.Compress
+		options.QueryAll = options.QueryAll || opt.QueryAll
+		if opt.Format != "" {
+			options.Format = opt.Format
+		}
+	}
+	if options.ChunkSize < 0 {
+		return BulkQueryOptions{}, errors.New("chunk size must not be negative")
+	}
+	if _, err := getColumnDelimiter(options.ColumnDelimiter); err != nil {
+		return BulkQueryOptions{}, err
 	}
-	return records, nil
+	if options.LineEnding != "" && options.LineEnding != LineEndingLF && options.LineEnding != LineEndingCRLF {
+		return BulkQueryOptions{}, errors.New("invalid line ending: " + options.LineEnding)
+	}
+	if err := validateExportOptions(options); err != nil {
+		return BulkQueryOptions{}, err
+	}
+	return options, nil
 }
 
-func mapsToCSV(maps []map[string]any) (string, error) {
+func newBulkJobOptions(opts ...BulkJobOptions) (BulkJobOptions, error) {
+	options := BulkJobOptions{}
+	for _, opt := range opts {
+		if opt.ColumnDelimiter != "" {
+			options.ColumnDelimiter = opt.ColumnDelimiter
+		}
+		if opt.ContentType != "" {
+			options.ContentType = opt.ContentType
+		}
+		if opt.LineEnding != "" {
+			options.LineEnding = opt.LineEnding
+		}
+		if opt.ConcurrencyMode != "" {
+			options.ConcurrencyMode = opt.ConcurrencyMode
+		}
+		options.IgnoreDeletedRecords = options.IgnoreDeletedRecords || opt.IgnoreDeletedRecords
+		options.HardDelete = options.HardDelete || opt.HardDelete
+		if opt.Progress != nil {
+			options.Progress = opt.Progress
+		}
+		if opt.Plan != nil {
+			options.Plan = opt.Plan
+		}
+		options.ExplicitNulls = options.ExplicitNulls || opt.ExplicitNulls
+	}
+
+	if _, err := getColumnDelimiter(options.ColumnDelimiter); err != nil {
+		return BulkJobOptions{}, err
+	}
+	if options.ContentType != "" && options.ContentType != ContentTypeCSV {
+		return BulkJobOptions{}, errors.New("invalid content type: " + options.ContentType)
+	}
+	if options.LineEnding != "" && options.LineEnding != LineEndingLF && options.LineEnding != LineEndingCRLF {
+		return BulkJobOptions{}, errors.New("invalid line ending: " + options.LineEnding)
+	}
+	if options.ConcurrencyMode != "" && options.ConcurrencyMode != ConcurrencyModeParallel && options.ConcurrencyMode != ConcurrencyModeSerial {
+		return BulkJobOptions{}, errors.New("invalid concurrency mode: " + options.ConcurrencyMode)
+	}
+	return options, nil
+}
+
+// IgnoreDeletedRecords changes how results are read, so it is rejected rather than silently ignored when creating jobs
+func newBulkIngestJobOptions(opts ...BulkJobOptions) (BulkJobOptions, error) {
+	options, err := newBulkJobOptions(opts...)
+	if err != nil {
+		return BulkJobOptions{}, err
+	}
+	if options.IgnoreDeletedRecords {
+		return BulkJobOptions{}, errors.New("IgnoreDeletedRecords only applies to GetJobResults and GetJobResultsInto")
+	}
+	return options, nil
+}
+
+// hard deleted records skip the recycle bin, which requires the Bulk API Hard Delete permission
+func bulkDeleteOperation(options BulkJobOptions) string {
+	if options.HardDelete {
+		return hardDeleteOperation
+	}
+	return deleteOperation
+}
+
+func newCSVWriter(w io.Writer, options BulkJobOptions) (*csv.Writer, error) {
+	delimiter, err := getColumnDelimiter(options.ColumnDelimiter)
+	if err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	writer.UseCRLF = options.LineEnding == LineEndingCRLF
+	return writer, nil
+}
+
+// bulk ingest clears a field whose value is #N/A, while an empty value leaves it unchanged on update
+const bulkNullValue = "#N/A"
+
+// datetimes are sent in utc with milliseconds, the format salesforce uses in bulk results
+const bulkDateTimeFormat = "2006-01-02T15:04:05.000Z"
+
+func formatCSVValue(value any, options BulkJobOptions) string {
+	switch v := indirectValue(value).(type) {
+	case nil:
+		if options.ExplicitNulls {
+			return bulkNullValue
+		}
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return formatCSVValue(nil, options)
+		}
+		return v.UTC().Format(bulkDateTimeFormat)
+	case Date:
+		if time.Time(v).IsZero() {
+			return formatCSVValue(nil, options)
+		}
+		return v.String()
+	case Datetime:
+		return formatCSVValue(time.Time(v), options)
+	case Time:
+		if time.Time(v).IsZero() {
+			return formatCSVValue(nil, options)
+		}
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// nested records, such as relationships referenced by external id, become columns like Account.External_Id__c
+func flattenCSVRecord(prefix string, record map[string]any, flat map[string]any) {
+	for key, value := range record {
+		if key == "attributes" {
+			continue
+		}
+		if nested, ok := indirectValue(value).(map[string]any); ok {
+			flattenCSVRecord(prefix+key+relationshipSeparator, nested, flat)
+			continue
+		}
+		flat[prefix+key] = value
+	}
+}
+
+func mapsToCSV(maps []map[string]any, options BulkJobOptions) (string, error) {
 	var buf bytes.Buffer
-	w := csv.NewWriter(&buf)
-	var headers []string
+	w, err := newCSVWriter(&buf, options)
+	if err != nil {
+		return "", err
+	}
+	// every record's fields are included, since records may leave out fields that others set
+	rows := make([]map[string]any, len(maps))
+	columns := map[string]bool{}
+	for i, m := range maps {
+		rows[i] = map[string]any{}
+		flattenCSVRecord("", m, rows[i])
+		for column := range rows[i] {
+			columns[column] = true
+		}
+	}
+	headers := make([]string, 0, len(columns))
+	for column := range columns {
+		headers = append(headers, column)
+	}
+	sort.Strings(headers)
 
 	if len(maps) > 0 {
-		headers = make([]string, 0, len(maps[0]))
-		for header := range maps[0] {
-			headers = append(headers, header)
-		}
 		err := w.Write(headers)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	for _, m := range maps {
+	for _, m := range rows {
 		row := make([]string, 0, len(headers))
 		for _, header := range headers {
-			val := m[header]
-			if val == nil {
+			// fields a record leaves out are left unchanged, even with explicit nulls
+			value, ok := m[header]
+			if !ok {
 				row = append(row, "")
-			} else {
-				row = append(row, fmt.Sprintf("%v", val))
+				continue
 			}
+			row = append(row, formatCSVValue(value, options))
 		}
 		err := w.Write(row)
 		if err != nil {
@@ -297,7 +929,7 @@ func mapsToCSV(maps []map[string]any) (string, error) {
 	}
 
 	w.Flush()
-	err := w.Error()
+	err = w.Error()
 	if err != nil {
 		return "", err
 	}
@@ -324,46 +956,15 @@ func csvToMap(reader csv.Reader) ([]map[string]any, error) {
 	return nil, nil
 }
 
-func readCSVFile(filePath string) ([][]string, error) {
-	file, fileErr := appFs.Open(filePath)
-	if fileErr != nil {
-		return nil, fileErr
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, readErr := reader.ReadAll()
-	if readErr != nil {
-		return nil, readErr
-	}
-
-	return records, nil
-}
-
-func writeCSVFile(filePath string, data [][]string) error {
-	file, fileErr := appFs.Create(filePath)
-	if fileErr != nil {
-		return fileErr
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	if writer == nil {
-		return errors.New("error writing csv file")
-	}
-	defer writer.Flush()
-	if err := writer.WriteAll(data); err != nil {
-		return errors.New("error writing csv file")
-	}
-
-	return nil
-}
-
-func constructBulkJobRequest(auth *authentication, sObjectName string, operation string, fieldName string) (bulkJob, error) {
+func constructBulkJobRequest(auth *authentication, sObjectName string, operation string, fieldName string, options BulkJobOptions) (bulkJob, error) {
 	jobReq := bulkJobCreationRequest{
 		Object:              sObjectName,
 		Operation:           operation,
 		ExternalIdFieldName: fieldName,
+		ColumnDelimiter:     options.ColumnDelimiter,
+		ContentType:         options.ContentType,
+		LineEnding:          options.LineEnding,
+		ConcurrencyMode:     options.ConcurrencyMode,
 	}
 	body, _ := json.Marshal(jobReq)
 
@@ -371,7 +972,7 @@ func constructBulkJobRequest(auth *authentication, sObjectName string, operation
 	if jobCreationErr != nil {
 		return bulkJob{}, jobCreationErr
 	}
-	if job.Id == "" || job.State != jobStateOpen {
+	if job.Id == "" || job.State != BulkJobStateOpen {
 		newErr := errors.New("error creating bulk data job: id does not exist or job closed prematurely")
 		return job, newErr
 	}
@@ -379,142 +980,190 @@ func constructBulkJobRequest(auth *authentication, sObjectName string, operation
 	return job, nil
 }
 
-func doBulkJob(auth *authentication, sObjectName string, fieldName string, operation string, records any, batchSize int, waitForResults bool) ([]string, error) {
-	recordMap, err := convertToSliceOfMaps(records)
-	if err != nil {
-		return []string{}, err
+// throttle profiles rely on jobs being created in order, so they disable parallel submission
+func bulkJobWorkers(auth *authentication, profile ThrottleProfile) int {
+	if profile.MaxParallelJobs > 0 || profile.InterBatchDelay > 0 {
+		return 1
 	}
+	return auth.config.workers()
+}
 
-	var jobErrors error
-	var jobIds []string
-	for len(recordMap) > 0 {
-		var batch []map[string]any
-		var remaining []map[string]any
-		if len(recordMap) > batchSize {
-			batch, remaining = recordMap[:batchSize], recordMap[batchSize:]
-		} else {
-			batch = recordMap
+// removes ids of jobs that were never created
+func compactJobIds(jobIds []string) []string {
+	var createdIds []string
+	for _, id := range jobIds {
+		if id != "" {
+			createdIds = append(createdIds, id)
 		}
-		recordMap = remaining
+	}
+	return createdIds
+}
 
-		job, constructJobErr := constructBulkJobRequest(auth, sObjectName, operation, fieldName)
-		if constructJobErr != nil {
-			return jobIds, constructJobErr
-		}
-		jobIds = append(jobIds, job.Id)
+func doBulkJob(auth *authentication, sObjectName string, fieldName string, operation string, records any, batchSize int, waitForResults bool, options BulkJobOptions) ([]string, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.ExplicitNulls)
+	if err != nil {
+		return []string{}, err
+	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return []string{}, err
+	}
+	profile := auth.config.throttleProfile(sObjectName)
+	plan := newBulkLoadPlan(options.Plan, sObjectName, operation, fieldName, len(recordMap), profile.batchSize(batchSize))
 
-		data, convertErr := mapsToCSV(batch)
-		if convertErr != nil {
-			return jobIds, convertErr
-		}
+	return submitBulkLoadPlan(auth, plan, recordBatchData(recordMap, options), false, waitForResults, options)
+}
 
-		uploadErr := uploadJobData(auth, data, job)
-		if uploadErr != nil {
-			return jobIds, uploadErr
-		}
+func doBulkJobWithFile(auth *authentication, sObjectName string, fieldName string, operation string, filePath string, batchSize int, waitForResults bool, options BulkJobOptions) ([]string, error) {
+	file, err := readBulkFile(auth, sObjectName, filePath, options)
+	if err != nil {
+		return nil, err
 	}
-
-	if waitForResults {
-		c := make(chan error, len(jobIds))
-		for _, id := range jobIds {
-			go waitForJobResultsAsync(auth, id, ingestJobType, (time.Second / 2), c)
-		}
-		jobErrors = <-c
+	profile := auth.config.throttleProfile(sObjectName)
+	plan := newBulkLoadPlan(options.Plan, sObjectName, operation, fieldName, len(file.offsets), profile.batchSize(batchSize))
+	if err := file.splitBatches(plan, bulkUploadLimit); err != nil {
+		return nil, err
 	}
 
-	return jobIds, jobErrors
+	// upload failures are reported without stopping the remaining batches
+	return submitBulkLoadPlan(auth, plan, file.batchData(options), true, waitForResults, options)
 }
 
-func doBulkJobWithFile(auth *authentication, sObjectName string, fieldName string, operation string, filePath string, batchSize int, waitForResults bool) ([]string, error) {
-	var jobErrors error
-	var jobIds []string
+func readBulkFile(auth *authentication, sObjectName string, filePath string, options BulkJobOptions) (*bulkFile, error) {
+	file, err := scanBulkFile(filePath, options)
+	if err != nil {
+		return nil, err
+	}
 
-	records, readErr := readCSVFile(filePath)
-	if readErr != nil {
-		return jobIds, readErr
+	headerRecord := make(map[string]any, len(file.headers))
+	for _, header := range file.headers {
+		headerRecord[header] = nil
 	}
+	if err := validateRecordFields(auth, sObjectName, []map[string]any{headerRecord}); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
 
-	headers := records[0]
-	records = records[1:]
-	for len(records) > 0 {
-		var batch [][]string
-		var remaining [][]string
-		if len(records) > batchSize {
-			batch, remaining = records[:batchSize], records[batchSize:]
-		} else {
-			batch = records
+func recordBatchData(recordMap []map[string]any, options BulkJobOptions) func(batch BulkLoadBatch, w io.Writer) error {
+	return func(batch BulkLoadBatch, w io.Writer) error {
+		data, err := mapsToCSV(recordMap[batch.Index:batch.Index+batch.Size], options)
+		if err != nil {
+			return err
 		}
-		records = remaining
+		_, err = io.WriteString(w, data)
+		return err
+	}
+}
 
-		job, constructJobErr := constructBulkJobRequest(auth, sObjectName, operation, fieldName)
-		if constructJobErr != nil {
-			jobErrors = errors.Join(jobErrors, constructJobErr)
-			break
+// creates a job for every batch that hasn't been uploaded yet, recording the job ids and statuses in the plan
+func submitBulkLoadPlan(auth *authentication, plan *BulkLoadPlan, batchData func(batch BulkLoadBatch, w io.Writer) error, continueOnUploadError bool, waitForResults bool, options BulkJobOptions) ([]string, error) {
+	profile := auth.config.throttleProfile(plan.SObjectName)
+	var pending []int
+	for i, batch := range plan.Batches {
+		if batch.Status != BulkBatchUploaded {
+			pending = append(pending, i)
+			plan.Batches[i].Status = BulkBatchPending
 		}
-		jobIds = append(jobIds, job.Id)
+	}
 
-		var buf bytes.Buffer
-		w := csv.NewWriter(&buf)
-		batch = append([][]string{headers}, batch...)
-		if err := w.WriteAll(batch); err != nil {
-			jobErrors = errors.Join(jobErrors, err)
-			break
+	// batches are updated by concurrent uploads, while throttling reads the job ids of earlier batches
+	var mu sync.Mutex
+	setBatch := func(i int, jobId string, status string, err error) error {
+		mu.Lock()
+		defer mu.Unlock()
+		plan.Batches[i].JobId = jobId
+		plan.Batches[i].Status = status
+		plan.Batches[i].Error = ""
+		if err != nil {
+			plan.Batches[i].Error = err.Error()
 		}
-		w.Flush()
-		writeErr := w.Error()
-		if writeErr != nil {
-			jobErrors = errors.Join(jobErrors, writeErr)
-			break
+		return err
+	}
+
+	jobIds := make([]string, len(pending))
+	uploadErrors := make([]error, len(pending))
+	err := runConcurrently(len(pending), bulkJobWorkers(auth, profile), func(k int) error {
+		i := pending[k]
+		mu.Lock()
+		batch := plan.Batches[i]
+		previous := plan.jobIdsBefore(i)
+		mu.Unlock()
+		if throttleErr := throttleBulkJob(auth, profile, previous); throttleErr != nil {
+			return setBatch(i, batch.JobId, BulkBatchFailed, throttleErr)
+		}
+		release := auth.config.acquireBulkJobSlot()
+		defer release()
+		job, constructJobErr := constructBulkJobRequest(auth, plan.SObjectName, plan.Operation, plan.ExternalIdFieldName, options)
+		if constructJobErr != nil {
+			return setBatch(i, batch.JobId, BulkBatchFailed, constructJobErr)
 		}
+		jobIds[k] = job.Id
 
-		uploadErr := uploadJobData(auth, buf.String(), job)
-		if uploadErr != nil {
-			jobErrors = errors.Join(jobErrors, uploadErr)
+		write := func(w io.Writer) error {
+			return batchData(batch, w)
+		}
+		if uploadErr := streamJobData(auth, write, job); uploadErr != nil {
+			if continueOnUploadError {
+				uploadErrors[k] = setBatch(i, job.Id, BulkBatchFailed, uploadErr)
+				return nil
+			}
+			return setBatch(i, job.Id, BulkBatchFailed, uploadErr)
 		}
+		return setBatch(i, job.Id, BulkBatchUploaded, nil)
+	})
+	jobIds = compactJobIds(jobIds)
+	jobErrors := errors.Join(err, errors.Join(uploadErrors...))
+	if jobErrors != nil && !continueOnUploadError {
+		return jobIds, jobErrors
 	}
 
 	if waitForResults {
 		c := make(chan error, len(jobIds))
 		for _, id := range jobIds {
-			go waitForJobResultsAsync(auth, id, ingestJobType, (time.Second / 2), c)
+			go waitForJobResultsAsync(auth, id, ingestJobType, (time.Second / 2), options.Progress, c)
 		}
-		jobErrors = <-c
+		jobErrors = errors.Join(jobErrors, <-c)
 	}
 
 	return jobIds, jobErrors
 }
 
-func doQueryBulk(auth *authentication, filePath string, query string) error {
+// queryAll includes deleted and archived records in the results
+func createBulkQueryJob(auth *authentication, query string, options BulkQueryOptions) (bulkJob, error) {
 	queryJobReq := bulkQueryJobCreationRequest{
-		Operation: queryJobType,
-		Query:     query,
+		Operation:       queryJobType,
+		Query:           query,
+		ColumnDelimiter: options.ColumnDelimiter,
+		ContentType:     options.ContentType,
+		LineEnding:      options.LineEnding,
+	}
+	if options.QueryAll {
+		queryJobReq.Operation = queryAllOperation
 	}
 	body, jsonErr := json.Marshal(queryJobReq)
 	if jsonErr != nil {
-		return jsonErr
+		return bulkJob{}, jsonErr
 	}
 
 	job, jobCreationErr := createBulkJob(auth, queryJobType, body)
 	if jobCreationErr != nil {
-		return jobCreationErr
+		return bulkJob{}, jobCreationErr
 	}
 	if job.Id == "" {
-		newErr := errors.New("error creating bulk query job")
-		return newErr
+		return bulkJob{}, errors.New("error creating bulk query job")
+	}
+	return job, nil
+}
+
+func doQueryBulk(auth *authentication, filePath string, query string, options BulkQueryOptions) error {
+	job, jobCreationErr := createBulkQueryJob(auth, query, options)
+	if jobCreationErr != nil {
+		return jobCreationErr
 	}
 
-	pollErr := waitForJobResults(auth, job.Id, queryJobType, (time.Second / 2))
+	pollErr := waitForJobResults(auth, job.Id, queryJobType, (time.Second / 2), options.Progress)
 	if pollErr != nil {
 		return pollErr
 	}
-	records, reqErr := collectQueryResults(auth, job.Id)
-	if reqErr != nil {
-		return reqErr
-	}
-	writeErr := writeCSVFile(filePath, records)
-	if writeErr != nil {
-		return writeErr
-	}
-
-	return nil
+	return exportQueryResults(auth, job.Id, filePath, options)
 }
@@ -3,6 +3,8 @@ package salesforce
 import (
 	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -16,7 +18,7 @@ import (
 func Test_createBulkJob(t *testing.T) {
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -101,12 +103,112 @@ func Test_createBulkJob(t *testing.T) {
 	}
 }
 
+func Test_getAllJobs(t *testing.T) {
+	firstPage := allJobsResponse{
+		Done:           false,
+		NextRecordsUrl: "/services/data/" + apiVersion + "/jobs/ingest?queryLocator=01gD",
+		Records: []BulkJobSummary{
+			{Id: "1", State: BulkJobStateJobComplete, Operation: insertOperation, Object: "Account"},
+		},
+	}
+	secondPage := allJobsResponse{
+		Done: true,
+		Records: []BulkJobSummary{
+			{Id: "2", State: BulkJobStateOpen, Operation: updateOperation, Object: "Contact"},
+		},
+	}
+	firstPageBody, _ := json.Marshal(firstPage)
+	secondPageBody, _ := json.Marshal(secondPage)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("queryLocator") == "01gD" {
+			if _, err := w.Write(secondPageBody); err != nil {
+				t.Fatal(err.Error())
+			}
+		} else {
+			if r.URL.Query().Get("jobType") != "V2Ingest" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if _, err := w.Write(firstPageBody); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+	}))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	defer server.Close()
+
+	badRespServer, badRespSfAuth := setupTestServer("1", http.StatusOK)
+	defer badRespServer.Close()
+
+	type args struct {
+		auth    *authentication
+		jobType string
+		filter  BulkJobFilter
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []BulkJobSummary
+		wantErr bool
+	}{
+		{
+			name: "get_all_jobs_paginated",
+			args: args{
+				auth:    &sfAuth,
+				jobType: ingestJobType,
+				filter:  BulkJobFilter{JobType: "V2Ingest"},
+			},
+			want:    append(firstPage.Records, secondPage.Records...),
+			wantErr: false,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:    &sfAuth,
+				jobType: ingestJobType,
+				filter:  BulkJobFilter{},
+			},
+			want:    []BulkJobSummary{},
+			wantErr: true,
+		},
+		{
+			name: "bad_response",
+			args: args{
+				auth:    &badRespSfAuth,
+				jobType: queryJobType,
+				filter:  BulkJobFilter{},
+			},
+			want:    []BulkJobSummary{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getAllJobs(tt.args.auth, tt.args.jobType, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getAllJobs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getAllJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_getJobResults(t *testing.T) {
 	jobResults := BulkJobResults{
-		Id:                  "1234",
-		State:               jobStateOpen,
-		NumberRecordsFailed: 0,
-		ErrorMessage:        "",
+		Id:                      "1234",
+		State:                   BulkJobStateOpen,
+		NumberRecordsFailed:     0,
+		ErrorMessage:            "",
+		Retries:                 1,
+		TotalProcessingTime:     1200,
+		ApiActiveProcessingTime: 900,
+		ApexProcessingTime:      300,
 	}
 	server, sfAuth := setupTestServer(jobResults, http.StatusOK)
 	defer server.Close()
@@ -159,7 +261,7 @@ func Test_getJobResults(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getJobResults(tt.args.auth, tt.args.jobType, tt.args.bulkJobId)
+			got, err := getJobResults(tt.args.auth, tt.args.jobType, tt.args.bulkJobId, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getJobResults() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -171,6 +273,176 @@ func Test_getJobResults(t *testing.T) {
 	}
 }
 
+func Test_createBulkQueryJob(t *testing.T) {
+	var got bulkQueryJobCreationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/jobs/query" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Error(err.Error())
+		}
+		if _, err := w.Write([]byte(`{"id": "1234", "state": "UploadComplete"}`)); err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badAuth := setupTestServer(bulkJob{}, http.StatusOK)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		options BulkQueryOptions
+		want    bulkQueryJobCreationRequest
+		wantErr bool
+	}{
+		{
+			name:    "query",
+			auth:    &sfAuth,
+			options: BulkQueryOptions{},
+			want:    bulkQueryJobCreationRequest{Operation: queryJobType, Query: "SELECT Id FROM Account"},
+			wantErr: false,
+		},
+		{
+			name:    "query_all_with_format",
+			auth:    &sfAuth,
+			options: BulkQueryOptions{QueryAll: true, ColumnDelimiter: ColumnDelimiterTab, ContentType: "CSV", LineEnding: LineEndingCRLF},
+			want: bulkQueryJobCreationRequest{
+				Operation:       queryAllOperation,
+				Query:           "SELECT Id FROM Account",
+				ColumnDelimiter: ColumnDelimiterTab,
+				ContentType:     "CSV",
+				LineEnding:      LineEndingCRLF,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing_job_id",
+			auth:    &badAuth,
+			options: BulkQueryOptions{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = bulkQueryJobCreationRequest{}
+			job, err := createBulkQueryJob(tt.auth, "SELECT Id FROM Account", tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createBulkQueryJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if job.Id != "1234" {
+				t.Errorf("createBulkQueryJob() = %v, want id 1234", job)
+			}
+			if got != tt.want {
+				t.Errorf("createBulkQueryJob() sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseQueryResultsHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		wantCount   int
+		wantLocator string
+		wantErr     *QueryResultsHeaderError
+	}{
+		{
+			name:        "next_page",
+			header:      http.Header{"Sforce-Numberofrecords": {"50000"}, "Sforce-Locator": {"MTAwMDA"}},
+			wantCount:   50000,
+			wantLocator: "MTAwMDA",
+		},
+		{
+			name:        "last_page",
+			header:      http.Header{"Sforce-Numberofrecords": {"10"}, "Sforce-Locator": {"null"}},
+			wantCount:   10,
+			wantLocator: "",
+		},
+		{
+			name:        "non_canonical_header_names",
+			header:      http.Header{"Sforce-NumberOfRecords": {"10"}, "Sforce-Locator": {"null"}},
+			wantCount:   10,
+			wantLocator: "",
+		},
+		{
+			name:    "missing_locator",
+			header:  http.Header{"Sforce-Numberofrecords": {"10"}},
+			wantErr: &QueryResultsHeaderError{JobId: "1234", Header: locatorHeader},
+		},
+		{
+			name:    "missing_number_of_records",
+			header:  http.Header{"Sforce-Locator": {"null"}},
+			wantErr: &QueryResultsHeaderError{JobId: "1234", Header: numberOfRecordsHeader},
+		},
+		{
+			name:    "invalid_number_of_records",
+			header:  http.Header{"Sforce-Numberofrecords": {"ten"}, "Sforce-Locator": {"null"}},
+			wantErr: &QueryResultsHeaderError{JobId: "1234", Header: numberOfRecordsHeader, Value: "ten"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, values := range tt.header {
+				for _, value := range values {
+					header.Add(key, value)
+				}
+			}
+			count, locator, err := parseQueryResultsHeaders("1234", header)
+			if tt.wantErr != nil {
+				var headerErr *QueryResultsHeaderError
+				if !errors.As(err, &headerErr) || !reflect.DeepEqual(headerErr, tt.wantErr) {
+					t.Errorf("parseQueryResultsHeaders() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQueryResultsHeaders() error = %v", err)
+			}
+			if count != tt.wantCount || locator != tt.wantLocator {
+				t.Errorf("parseQueryResultsHeaders() = %v, %v, want %v, %v", count, locator, tt.wantCount, tt.wantLocator)
+			}
+		})
+	}
+}
+
+func Test_getQueryJobResults_delimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Sforce-Numberofrecords", "1")
+		w.Header().Add("Sforce-Locator", "null")
+		if _, err := w.Write([]byte("Id\tName\r\n001\tStark Industries\r\n")); err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := getQueryJobResults(&sfAuth, "1234", "", BulkQueryOptions{ColumnDelimiter: ColumnDelimiterTab, LineEnding: LineEndingCRLF})
+	if err != nil {
+		t.Fatalf("getQueryJobResults() error = %v", err)
+	}
+	want := [][]string{{"Id", "Name"}, {"001", "Stark Industries"}}
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("getQueryJobResults() = %v, want %v", got.Data, want)
+	}
+}
+
 func Test_isBulkJobDone(t *testing.T) {
 	type args struct {
 		bulkJob BulkJobResults
@@ -186,7 +458,7 @@ func Test_isBulkJobDone(t *testing.T) {
 			args: args{
 				bulkJob: BulkJobResults{
 					Id:                  "1234",
-					State:               jobStateJobComplete,
+					State:               BulkJobStateJobComplete,
 					NumberRecordsFailed: 0,
 					ErrorMessage:        "",
 				},
@@ -199,7 +471,7 @@ func Test_isBulkJobDone(t *testing.T) {
 			args: args{
 				bulkJob: BulkJobResults{
 					Id:                  "1234",
-					State:               jobStateOpen,
+					State:               BulkJobStateOpen,
 					NumberRecordsFailed: 0,
 					ErrorMessage:        "",
 				},
@@ -212,7 +484,7 @@ func Test_isBulkJobDone(t *testing.T) {
 			args: args{
 				bulkJob: BulkJobResults{
 					Id:                  "1234",
-					State:               jobStateAborted,
+					State:               BulkJobStateAborted,
 					NumberRecordsFailed: 0,
 					ErrorMessage:        "",
 				},
@@ -225,7 +497,7 @@ func Test_isBulkJobDone(t *testing.T) {
 			args: args{
 				bulkJob: BulkJobResults{
 					Id:                  "1234",
-					State:               jobStateFailed,
+					State:               BulkJobStateFailed,
 					NumberRecordsFailed: 1,
 					ErrorMessage:        "example error",
 				},
@@ -303,7 +575,7 @@ func Test_getQueryJobResults(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getQueryJobResults(tt.args.auth, tt.args.bulkJobId, tt.args.locator)
+			got, err := getQueryJobResults(tt.args.auth, tt.args.bulkJobId, tt.args.locator, BulkQueryOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getQueryJobResults() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -316,8 +588,10 @@ func Test_getQueryJobResults(t *testing.T) {
 }
 
 func Test_mapsToCSV(t *testing.T) {
+	name := "Wayne Enterprises"
 	type args struct {
-		maps []map[string]any
+		maps    []map[string]any
+		options BulkJobOptions
 	}
 	tests := []struct {
 		name    string
@@ -352,10 +626,101 @@ func Test_mapsToCSV(t *testing.T) {
 			want:    "key\n\n",
 			wantErr: false,
 		},
+		{
+			name: "convert_map_to_csv_string_explicit_nulls",
+			args: args{
+				maps: []map[string]any{
+					{
+						"key": nil,
+					},
+				},
+				options: BulkJobOptions{ExplicitNulls: true},
+			},
+			want:    "key\n#N/A\n",
+			wantErr: false,
+		},
+		{
+			name: "convert_map_to_csv_string_times",
+			args: args{
+				maps: []map[string]any{
+					{
+						"key": time.Date(2024, 1, 31, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60)),
+					},
+					{
+						"key": Date(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)),
+					},
+					{
+						"key": time.Time{},
+					},
+				},
+			},
+			want:    "key\n2024-01-31T14:30:00.000Z\n2024-01-31\n\n",
+			wantErr: false,
+		},
+		{
+			name: "convert_map_to_csv_string_pipe_delimiter",
+			args: args{
+				maps: []map[string]any{
+					{
+						"key": "a,b",
+					},
+				},
+				options: BulkJobOptions{ColumnDelimiter: ColumnDelimiterPipe},
+			},
+			want:    "key\na,b\n",
+			wantErr: false,
+		},
+		{
+			name: "convert_map_to_csv_string_pointers_and_relationships",
+			args: args{
+				maps: []map[string]any{
+					{
+						"Name":      &name,
+						"Phone":     (*string)(nil),
+						"Parent":    map[string]any{"External_Id__c": "acme-1", "attributes": map[string]any{"type": "Account"}},
+						"Owner":     &map[string]any{"Alias": "bwayne"},
+						"Employees": 10,
+					},
+					{
+						"Name":        "Stark Industries",
+						"Description": nil,
+					},
+				},
+				options: BulkJobOptions{ExplicitNulls: true},
+			},
+			want:    "Description,Employees,Name,Owner.Alias,Parent.External_Id__c,Phone\n,10,Wayne Enterprises,bwayne,acme-1,#N/A\n#N/A,,Stark Industries,,,\n",
+			wantErr: false,
+		},
+		{
+			name: "convert_map_to_csv_string_crlf",
+			args: args{
+				maps: []map[string]any{
+					{
+						"key": "val",
+					},
+				},
+				options: BulkJobOptions{LineEnding: LineEndingCRLF},
+			},
+			want:    "key\r\nval\r\n",
+			wantErr: false,
+		},
+		{
+			name: "invalid_column_delimiter",
+			args: args{
+				maps: []map[string]any{
+					{
+						"key": "val",
+					},
+				},
+				options: BulkJobOptions{ColumnDelimiter: "COLON"},
+			},
+			want:    "",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := mapsToCSV(tt.args.maps)
+			got, err := mapsToCSV(tt.args.maps, tt.args.options)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("mapsToCSV() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -367,17 +732,84 @@ func Test_mapsToCSV(t *testing.T) {
 	}
 }
 
+func Test_newBulkJobOptions(t *testing.T) {
+	type args struct {
+		opts []BulkJobOptions
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    BulkJobOptions
+		wantErr bool
+	}{
+		{
+			name:    "no_options",
+			args:    args{},
+			want:    BulkJobOptions{},
+			wantErr: false,
+		},
+		{
+			name: "merge_options",
+			args: args{opts: []BulkJobOptions{
+				{ColumnDelimiter: ColumnDelimiterPipe, LineEnding: LineEndingLF},
+				{LineEnding: LineEndingCRLF, ContentType: ContentTypeCSV, ConcurrencyMode: ConcurrencyModeSerial, ExplicitNulls: true, HardDelete: true},
+			}},
+			want: BulkJobOptions{
+				ColumnDelimiter: ColumnDelimiterPipe,
+				ContentType:     ContentTypeCSV,
+				LineEnding:      LineEndingCRLF,
+				ConcurrencyMode: ConcurrencyModeSerial,
+				ExplicitNulls:   true,
+				HardDelete:      true,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid_column_delimiter",
+			args:    args{opts: []BulkJobOptions{{ColumnDelimiter: "COLON"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_content_type",
+			args:    args{opts: []BulkJobOptions{{ContentType: "JSON"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_line_ending",
+			args:    args{opts: []BulkJobOptions{{LineEnding: "CR"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_concurrency_mode",
+			args:    args{opts: []BulkJobOptions{{ConcurrencyMode: "Fast"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newBulkJobOptions(tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newBulkJobOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newBulkJobOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_constructBulkJobRequest(t *testing.T) {
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
 
 	badJob := bulkJob{
 		Id:    "1234",
-		State: jobStateAborted,
+		State: BulkJobStateAborted,
 	}
 	badJobByte, _ := json.Marshal(badJob)
 	badJobServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -453,7 +885,7 @@ func Test_constructBulkJobRequest(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := constructBulkJobRequest(tt.args.auth, tt.args.sObjectName, tt.args.operation, tt.args.fieldName)
+			got, err := constructBulkJobRequest(tt.args.auth, tt.args.sObjectName, tt.args.operation, tt.args.fieldName, BulkJobOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("constructBulkJobRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -472,13 +904,13 @@ func Test_doBulkJob(t *testing.T) {
 	}
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	jobBody, _ := json.Marshal(job)
 
 	jobResults := BulkJobResults{
 		Id:                  "1234",
-		State:               jobStateJobComplete,
+		State:               BulkJobStateJobComplete,
 		NumberRecordsFailed: 0,
 		ErrorMessage:        "",
 	}
@@ -582,6 +1014,28 @@ func Test_doBulkJob(t *testing.T) {
 			want:    []string{job.Id, job.Id},
 			wantErr: false,
 		},
+		{
+			name: "bulk_upsert_maps",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+				fieldName:   "externalId",
+				operation:   upsertOperation,
+				records: []map[string]any{
+					{
+						"externalId": "acc1",
+						"Name":       "test account 1",
+					},
+					{
+						"externalId": "acc2",
+					},
+				},
+				batchSize:      1,
+				waitForResults: false,
+			},
+			want:    []string{job.Id, job.Id},
+			wantErr: false,
+		},
 		{
 			name: "bad_request",
 			args: args{
@@ -653,7 +1107,7 @@ func Test_doBulkJob(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doBulkJob(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.operation, tt.args.records, tt.args.batchSize, tt.args.waitForResults)
+			got, err := doBulkJob(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.operation, tt.args.records, tt.args.batchSize, tt.args.waitForResults, BulkJobOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doBulkJob() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -668,7 +1122,7 @@ func Test_doBulkJob(t *testing.T) {
 func Test_waitForJobResultsAsync(t *testing.T) {
 	jobResults := BulkJobResults{
 		Id:    "1234",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	server, sfAuth := setupTestServer(jobResults, http.StatusOK)
 	defer server.Close()
@@ -724,7 +1178,7 @@ func Test_waitForJobResultsAsync(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			go waitForJobResultsAsync(tt.args.auth, tt.args.bulkJobId, tt.args.jobType, tt.args.interval, tt.args.c)
+			go waitForJobResultsAsync(tt.args.auth, tt.args.bulkJobId, tt.args.jobType, tt.args.interval, nil, tt.args.c)
 			err := <-tt.args.c
 			if (err != nil) != tt.wantErr {
 				t.Errorf("waitForJobResult() error = %v, wantErr %v", err, tt.wantErr)
@@ -736,7 +1190,7 @@ func Test_waitForJobResultsAsync(t *testing.T) {
 func Test_waitForJobResults(t *testing.T) {
 	jobResults := BulkJobResults{
 		Id:    "1234",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	server, sfAuth := setupTestServer(jobResults, http.StatusOK)
 	defer server.Close()
@@ -789,7 +1243,7 @@ func Test_waitForJobResults(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := waitForJobResults(tt.args.auth, tt.args.bulkJobId, tt.args.jobType, tt.args.interval)
+			err := waitForJobResults(tt.args.auth, tt.args.bulkJobId, tt.args.jobType, tt.args.interval, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("waitForQueryResults() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -797,6 +1251,52 @@ func Test_waitForJobResults(t *testing.T) {
 	}
 }
 
+func Test_waitForJobResults_progress(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		jobResults := BulkJobResults{
+			Id:                     "1234",
+			State:                  BulkJobStateUploadComplete,
+			NumberRecordsProcessed: 100,
+			NumberRecordsFailed:    1,
+		}
+		if polls > 1 {
+			jobResults.State = BulkJobStateJobComplete
+			jobResults.NumberRecordsProcessed = 200
+		}
+		body, _ := json.Marshal(jobResults)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	var got []BulkJobProgress
+	progress := func(p BulkJobProgress) {
+		got = append(got, p)
+	}
+	if err := waitForJobResults(&sfAuth, "1234", ingestJobType, time.Nanosecond, progress); err != nil {
+		t.Fatalf("waitForJobResults() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("progress called %d times, want 2", len(got))
+	}
+	if got[0].JobId != "1234" || got[0].State != BulkJobStateUploadComplete || got[0].RecordsProcessed != 100 || got[0].RecordsFailed != 1 {
+		t.Errorf("first progress = %+v", got[0])
+	}
+	if got[1].State != BulkJobStateJobComplete || got[1].RecordsProcessed != 200 {
+		t.Errorf("last progress = %+v", got[1])
+	}
+	if got[1].Elapsed < got[0].Elapsed {
+		t.Errorf("elapsed went backwards: %v then %v", got[0].Elapsed, got[1].Elapsed)
+	}
+}
+
 func Test_collectQueryResults(t *testing.T) {
 	csvData := `"col"` + "\n" + `"row"`
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -849,7 +1349,11 @@ func Test_collectQueryResults(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := collectQueryResults(tt.args.auth, tt.args.bulkJobId)
+			var got [][]string
+			err := collectQueryResults(tt.args.auth, tt.args.bulkJobId, BulkQueryOptions{}, func(records [][]string) error {
+				got = append(got, records...)
+				return nil
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("collectQueryResults() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -946,123 +1450,76 @@ func Test_uploadJobData(t *testing.T) {
 	}
 }
 
-func Test_readCSVFile(t *testing.T) {
-	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
-	if err := appFs.MkdirAll("data", 0755); err != nil {
-		t.Fatalf("error creating directory in virtual file system")
-	}
-	if err := afero.WriteFile(appFs, "data/data.csv", []byte("123"), 0644); err != nil {
-		t.Fatalf("error creating file in virtual file system")
-	}
-
-	type args struct {
-		filePath string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    [][]string
-		wantErr bool
-	}{
-		{
-			name: "read file successfully",
-			args: args{
-				filePath: "data/data.csv",
-			},
-			want:    [][]string{{"123"}},
-			wantErr: false,
-		},
-		{
-			name: "read file failure",
-			args: args{
-				filePath: "data/does_not_exist.csv",
-			},
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := readCSVFile(tt.args.filePath)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("readCSVFile() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("readCSVFile() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func Test_writeCSVFile(t *testing.T) {
-	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+func Test_updateJobState(t *testing.T) {
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
 
 	type args struct {
-		filePath string
-		data     [][]string
+		job   bulkJob
+		state BulkJobState
+		auth  *authentication
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    [][]string
 		wantErr bool
 	}{
 		{
-			name: "write file successfully",
+			name: "bad_request",
 			args: args{
-				filePath: "data/export.csv",
-				data:     [][]string{{"123"}},
+				job:   bulkJob{},
+				state: "",
+				auth:  &badSfAuth,
 			},
-			want:    [][]string{{"123"}},
-			wantErr: false,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := writeCSVFile(tt.args.filePath, tt.args.data); (err != nil) != tt.wantErr {
-				t.Errorf("writeCSVFile() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			got, err := readCSVFile("data/export.csv")
-			if err != nil {
-				t.Error(err.Error())
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("writeCSVFile() = %v, want %v", got, tt.want)
+			if err := updateJobState(tt.args.job, tt.args.state, tt.args.auth); (err != nil) != tt.wantErr {
+				t.Errorf("updateJobState() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func Test_updateJobState(t *testing.T) {
+func Test_deleteBulkJob(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusNoContent)
+	defer server.Close()
+
 	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
 	defer badServer.Close()
 
 	type args struct {
-		job   bulkJob
-		state string
-		auth  *authentication
+		auth      *authentication
+		bulkJobId string
 	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
+		{
+			name: "delete_job",
+			args: args{
+				auth:      &sfAuth,
+				bulkJobId: "1234",
+			},
+			wantErr: false,
+		},
 		{
 			name: "bad_request",
 			args: args{
-				job:   bulkJob{},
-				state: "",
-				auth:  &badSfAuth,
+				auth:      &badSfAuth,
+				bulkJobId: "1234",
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := updateJobState(tt.args.job, tt.args.state, tt.args.auth); (err != nil) != tt.wantErr {
-				t.Errorf("updateJobState() error = %v, wantErr %v", err, tt.wantErr)
+			if err := deleteBulkJob(tt.args.auth, tt.args.bulkJobId); (err != nil) != tt.wantErr {
+				t.Errorf("deleteBulkJob() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -1079,13 +1536,13 @@ func Test_doBulkJobWithFile(t *testing.T) {
 
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	jobBody, _ := json.Marshal(job)
 
 	jobResults := BulkJobResults{
 		Id:                  "1234",
-		State:               jobStateJobComplete,
+		State:               BulkJobStateJobComplete,
 		NumberRecordsFailed: 0,
 		ErrorMessage:        "",
 	}
@@ -1217,7 +1674,7 @@ func Test_doBulkJobWithFile(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doBulkJobWithFile(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.operation, tt.args.filePath, tt.args.batchSize, tt.args.waitForResults)
+			got, err := doBulkJobWithFile(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.operation, tt.args.filePath, tt.args.batchSize, tt.args.waitForResults, BulkJobOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doBulkJobWithFile() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1232,12 +1689,12 @@ func Test_doBulkJobWithFile(t *testing.T) {
 func Test_doQueryBulk(t *testing.T) {
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	jobCreationRespBody, _ := json.Marshal(job)
 	badJob := bulkJob{
 		Id:    "",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	badJobCreationRespBody, _ := json.Marshal(badJob)
 
@@ -1302,7 +1759,7 @@ func Test_doQueryBulk(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := doQueryBulk(tt.args.auth, tt.args.filePath, tt.args.query); (err != nil) != tt.wantErr {
+			if err := doQueryBulk(tt.args.auth, tt.args.filePath, tt.args.query, BulkQueryOptions{}); (err != nil) != tt.wantErr {
 				t.Errorf("doQueryBulk() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -1367,6 +1824,27 @@ func Test_getJobRecordResults(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "failed_job_includes_unprocessed_records",
+			args: args{
+				auth:           &sfAuth,
+				bulkJobResults: BulkJobResults{Id: "1234", State: BulkJobStateFailed},
+			},
+			want: BulkJobResults{
+				Id:    "1234",
+				State: BulkJobStateFailed,
+				FailedRecords: []map[string]any{{
+					"name": "test account",
+				}},
+				SuccessfulRecords: []map[string]any{{
+					"name": "test account",
+				}},
+				UnprocessedRecords: []map[string]any{{
+					"name": "test account",
+				}},
+			},
+			wantErr: false,
+		},
 		{
 			name: "failed_to_get_successful_records",
 			args: args{
@@ -1405,6 +1883,225 @@ func Test_getJobRecordResults(t *testing.T) {
 	}
 }
 
+func TestBulkJobResults_SuccessfulRecordResults(t *testing.T) {
+	results := BulkJobResults{
+		SuccessfulRecords: []map[string]any{
+			{"sf__Id": "003A", "sf__Created": "true", "External_Id__c": "A", "LastName": "Stark"},
+			{"sf__Id": "003B", "sf__Created": "false", "External_Id__c": "B", "LastName": "Rogers"},
+		},
+	}
+	got, err := results.SuccessfulRecordResults()
+	if err != nil {
+		t.Fatalf("SuccessfulRecordResults() error = %v", err)
+	}
+	want := []BulkRecordResult{{Id: "003A", Created: true}, {Id: "003B", Created: false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SuccessfulRecordResults() = %v, want %v", got, want)
+	}
+
+	type contactResult struct {
+		BulkRecordResult `mapstructure:",squash"`
+		ExternalId       string `sf:"External_Id__c"`
+		LastName         string
+	}
+	contacts := []contactResult{}
+	if err := results.DecodeSuccessfulRecords(&contacts); err != nil {
+		t.Fatalf("DecodeSuccessfulRecords() error = %v", err)
+	}
+	wantContacts := []contactResult{
+		{BulkRecordResult: BulkRecordResult{Id: "003A", Created: true}, ExternalId: "A", LastName: "Stark"},
+		{BulkRecordResult: BulkRecordResult{Id: "003B"}, ExternalId: "B", LastName: "Rogers"},
+	}
+	if !reflect.DeepEqual(contacts, wantContacts) {
+		t.Errorf("DecodeSuccessfulRecords() = %v, want %v", contacts, wantContacts)
+	}
+}
+
+func TestBulkJobResults_DecodeRecords(t *testing.T) {
+	type contact struct {
+		Id        string `mapstructure:"sf__Id"`
+		Created   bool   `mapstructure:"sf__Created"`
+		LastName  string
+		Employees int    `sf:"NumberOfEmployees"`
+		Error     string `mapstructure:"sf__Error"`
+	}
+	results := BulkJobResults{
+		SuccessfulRecords: []map[string]any{
+			{"sf__Id": "003A", "sf__Created": "true", "LastName": "Stark", "NumberOfEmployees": "12"},
+		},
+		FailedRecords: []map[string]any{
+			{"sf__Id": "", "sf__Error": "REQUIRED_FIELD_MISSING:Required fields are missing: [LastName]:LastName --", "LastName": "", "NumberOfEmployees": ""},
+		},
+		UnprocessedRecords: []map[string]any{
+			{"LastName": "Rogers", "NumberOfEmployees": "1"},
+		},
+	}
+
+	successful := []contact{}
+	if err := results.DecodeSuccessfulRecords(&successful); err != nil {
+		t.Fatalf("DecodeSuccessfulRecords() error = %v", err)
+	}
+	wantSuccessful := []contact{{Id: "003A", Created: true, LastName: "Stark", Employees: 12}}
+	if !reflect.DeepEqual(successful, wantSuccessful) {
+		t.Errorf("DecodeSuccessfulRecords() = %v, want %v", successful, wantSuccessful)
+	}
+
+	failed := []contact{}
+	if err := results.DecodeFailedRecords(&failed); err != nil {
+		t.Fatalf("DecodeFailedRecords() error = %v", err)
+	}
+	wantFailed := []contact{{Error: "REQUIRED_FIELD_MISSING:Required fields are missing: [LastName]:LastName --"}}
+	if !reflect.DeepEqual(failed, wantFailed) {
+		t.Errorf("DecodeFailedRecords() = %v, want %v", failed, wantFailed)
+	}
+
+	unprocessed := []contact{}
+	if err := results.DecodeUnprocessedRecords(&unprocessed); err != nil {
+		t.Fatalf("DecodeUnprocessedRecords() error = %v", err)
+	}
+	wantUnprocessed := []contact{{LastName: "Rogers", Employees: 1}}
+	if !reflect.DeepEqual(unprocessed, wantUnprocessed) {
+		t.Errorf("DecodeUnprocessedRecords() = %v, want %v", unprocessed, wantUnprocessed)
+	}
+
+	if _, ok := results.SuccessfulRecords[0]["NumberOfEmployees"]; !ok {
+		t.Errorf("DecodeSuccessfulRecords() modified the original records")
+	}
+	if err := results.DecodeSuccessfulRecords(&[]struct{ LastName int }{}); err == nil {
+		t.Errorf("DecodeSuccessfulRecords() expected a conversion error")
+	}
+}
+
+func Test_stringToSalesforceTimeHook(t *testing.T) {
+	timeType := reflect.TypeOf(time.Time{})
+	tests := []struct {
+		name    string
+		data    string
+		want    any
+		wantErr bool
+	}{
+		{
+			name:    "datetime",
+			data:    "2024-03-01T15:04:05.000+0000",
+			want:    time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC),
+			wantErr: false,
+		},
+		{
+			name:    "rfc3339",
+			data:    "2024-03-01T15:04:05Z",
+			want:    time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC),
+			wantErr: false,
+		},
+		{
+			name:    "date",
+			data:    "2024-03-01",
+			want:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: false,
+		},
+		{
+			name:    "empty",
+			data:    "",
+			want:    time.Time{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid",
+			data:    "yesterday",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stringToSalesforceTimeHook(reflect.TypeOf(""), timeType, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("stringToSalesforceTimeHook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.(time.Time).Equal(tt.want.(time.Time)) {
+				t.Errorf("stringToSalesforceTimeHook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, _ := stringToSalesforceTimeHook(reflect.TypeOf(""), reflect.TypeOf(""), "2024-03-01"); got != "2024-03-01" {
+		t.Errorf("stringToSalesforceTimeHook() converted a string field: %v", got)
+	}
+}
+
+func Test_doGetJobResultsInto(t *testing.T) {
+	type opportunity struct {
+		Id        string    `mapstructure:"sf__Id"`
+		Error     string    `mapstructure:"sf__Error"`
+		Amount    float64   `sf:"Amount"`
+		IsWon     bool      `sf:"IsWon"`
+		CloseDate time.Time `sf:"CloseDate"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		switch {
+		case strings.HasSuffix(r.URL.Path, successfulResults):
+			body = []byte("sf__Id,sf__Created,Amount,IsWon,CloseDate\n006A,true,1500.50,true,2024-03-01\n")
+		case strings.HasSuffix(r.URL.Path, failedResults):
+			body = []byte("sf__Id,sf__Error,Amount,IsWon,CloseDate\n,INVALID_FIELD,abc,false,\n")
+		default:
+			body, _ = json.Marshal(BulkJobResults{Id: "1234", State: BulkJobStateJobComplete})
+		}
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	successful := []opportunity{}
+	if _, err := doGetJobResultsInto(&sfAuth, "1234", &successful, nil, BulkJobOptions{}); err != nil {
+		t.Fatalf("doGetJobResultsInto() error = %v", err)
+	}
+	want := []opportunity{{Id: "006A", Amount: 1500.50, IsWon: true, CloseDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}}
+	if !reflect.DeepEqual(successful, want) {
+		t.Errorf("doGetJobResultsInto() = %v, want %v", successful, want)
+	}
+
+	// failed records keep the values that were submitted, which may not convert
+	failed := []opportunity{}
+	if _, err := doGetJobResultsInto(&sfAuth, "1234", nil, &failed, BulkJobOptions{}); err == nil {
+		t.Errorf("doGetJobResultsInto() expected a conversion error")
+	}
+}
+
+func Test_ignoreDeletedRecords(t *testing.T) {
+	jobResults := BulkJobResults{
+		Id:                  "1234",
+		State:               BulkJobStateJobComplete,
+		NumberRecordsFailed: 2,
+		SuccessfulRecords: []map[string]any{
+			{"sf__Id": "001A", "sf__Created": "false", "Id": "001A"},
+		},
+		FailedRecords: []map[string]any{
+			{"sf__Id": "", "sf__Error": "ENTITY_IS_DELETED:entity is deleted:--", "Id": "001B"},
+			{"sf__Id": "", "sf__Error": "INVALID_CROSS_REFERENCE_KEY:invalid cross reference id:--", "Id": "001C"},
+		},
+	}
+	want := BulkJobResults{
+		Id:                  "1234",
+		State:               BulkJobStateJobComplete,
+		NumberRecordsFailed: 1,
+		SuccessfulRecords: []map[string]any{
+			{"sf__Id": "001A", "sf__Created": "false", "Id": "001A"},
+			{"sf__Id": "", "sf__Created": "false", "Id": "001B"},
+		},
+		FailedRecords: []map[string]any{
+			{"sf__Id": "", "sf__Error": "INVALID_CROSS_REFERENCE_KEY:invalid cross reference id:--", "Id": "001C"},
+		},
+	}
+	if got := ignoreDeletedRecords(jobResults); !reflect.DeepEqual(got, want) {
+		t.Errorf("ignoreDeletedRecords() = %v, want %v", got, want)
+	}
+}
+
 func Test_getBulkJobRecords(t *testing.T) {
 	csvData := `"name"` + "\n" + `"test account"`
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1478,7 +2175,7 @@ func Test_getBulkJobRecords(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getBulkJobRecords(tt.args.auth, tt.args.bulkJobId, tt.args.resultType)
+			got, err := getBulkJobRecords(tt.args.auth, tt.args.bulkJobId, tt.args.resultType, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getBulkJobRecords() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1540,3 +2237,211 @@ func Test_csvToMap(t *testing.T) {
 		})
 	}
 }
+
+func Test_doSaveJobRecordResults(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resultType := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if _, err := w.Write([]byte("\"sf__Id\",\"result\"\n\"001\",\"" + resultType + "\"\n")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	if err := doSaveJobRecordResults(&sfAuth, "1234", "success.csv", "", "unprocessed.csv"); err != nil {
+		t.Fatalf("doSaveJobRecordResults() error = %v", err)
+	}
+	wantFiles := map[string]string{
+		"success.csv":     successfulResults,
+		"unprocessed.csv": unprocessedRecords,
+	}
+	for filePath, resultType := range wantFiles {
+		data, err := afero.ReadFile(appFs, filePath)
+		if err != nil {
+			t.Fatalf("doSaveJobRecordResults() did not write %s: %v", filePath, err)
+		}
+		want := "\"sf__Id\",\"result\"\n\"001\",\"" + resultType + "\"\n"
+		if string(data) != want {
+			t.Errorf("doSaveJobRecordResults() %s = %q, want %q", filePath, data, want)
+		}
+	}
+	if exists, _ := afero.Exists(appFs, "failed.csv"); exists {
+		t.Errorf("doSaveJobRecordResults() wrote a file for an empty path")
+	}
+
+	if err := doSaveJobRecordResults(&badSfAuth, "1234", "", "failed.csv", ""); err == nil {
+		t.Errorf("doSaveJobRecordResults() expected an error")
+	}
+}
+
+func Test_queryJobResultsUri(t *testing.T) {
+	type args struct {
+		bulkJobId  string
+		locator    string
+		maxRecords int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "first_page",
+			args: args{bulkJobId: "1234"},
+			want: "/jobs/query/1234/results",
+		},
+		{
+			name: "locator",
+			args: args{bulkJobId: "1234", locator: "abc"},
+			want: "/jobs/query/1234/results/?locator=abc",
+		},
+		{
+			name: "locator_and_chunk_size",
+			args: args{bulkJobId: "1234", locator: "abc", maxRecords: 50000},
+			want: "/jobs/query/1234/results/?locator=abc&maxRecords=50000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryJobResultsUri(tt.args.bulkJobId, tt.args.locator, tt.args.maxRecords); got != tt.want {
+				t.Errorf("queryJobResultsUri() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newBulkQueryOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []BulkQueryOptions
+		want    BulkQueryOptions
+		wantErr bool
+	}{
+		{
+			name:    "no_options",
+			opts:    nil,
+			want:    BulkQueryOptions{},
+			wantErr: false,
+		},
+		{
+			name:    "chunk_size",
+			opts:    []BulkQueryOptions{{ChunkSize: 100}, {ChunkSize: 50000}},
+			want:    BulkQueryOptions{ChunkSize: 50000},
+			wantErr: false,
+		},
+		{
+			name:    "negative_chunk_size",
+			opts:    []BulkQueryOptions{{ChunkSize: -1}},
+			want:    BulkQueryOptions{},
+			wantErr: true,
+		},
+		{
+			name: "export_shape",
+			opts: []BulkQueryOptions{
+				{Columns: []string{"Id"}, ColumnDelimiter: ColumnDelimiterTab},
+				{ColumnHeaders: map[string]string{"Id": "id"}, LineEnding: LineEndingCRLF},
+			},
+			want: BulkQueryOptions{
+				Columns:         []string{"Id"},
+				ColumnHeaders:   map[string]string{"Id": "id"},
+				ColumnDelimiter: ColumnDelimiterTab,
+				LineEnding:      LineEndingCRLF,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid_delimiter",
+			opts:    []BulkQueryOptions{{ColumnDelimiter: "COLON"}},
+			want:    BulkQueryOptions{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_line_ending",
+			opts:    []BulkQueryOptions{{LineEnding: "CR"}},
+			want:    BulkQueryOptions{},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate_columns",
+			opts:    []BulkQueryOptions{{Columns: []string{"Id", "Id"}}},
+			want:    BulkQueryOptions{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newBulkQueryOptions(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newBulkQueryOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newBulkQueryOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_collectQueryResults_chunkSize(t *testing.T) {
+	var requestUris []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestUris = append(requestUris, r.URL.RequestURI())
+		if r.URL.Query().Get("locator") == "" {
+			w.Header().Add("Sforce-Locator", "abc")
+		} else {
+			w.Header().Add("Sforce-Locator", "null")
+		}
+		w.Header().Add("Sforce-Numberofrecords", "1")
+		if _, err := w.Write([]byte("Id\n001\n")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	var got [][]string
+	err := collectQueryResults(&sfAuth, "1234", BulkQueryOptions{ChunkSize: 1}, func(records [][]string) error {
+		got = append(got, records...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("collectQueryResults() error = %v", err)
+	}
+	want := [][]string{{"Id"}, {"001"}, {"001"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectQueryResults() = %v, want %v", got, want)
+	}
+	wantUris := []string{
+		"/services/data/" + apiVersion + "/jobs/query/1234/results/?maxRecords=1",
+		"/services/data/" + apiVersion + "/jobs/query/1234/results/?locator=abc&maxRecords=1",
+	}
+	if !reflect.DeepEqual(requestUris, wantUris) {
+		t.Errorf("collectQueryResults() requested %v, want %v", requestUris, wantUris)
+	}
+}
+
+func Test_newBulkIngestJobOptions(t *testing.T) {
+	if _, err := newBulkIngestJobOptions(BulkJobOptions{HardDelete: true}); err != nil {
+		t.Errorf("newBulkIngestJobOptions() error = %v", err)
+	}
+	if _, err := newBulkIngestJobOptions(BulkJobOptions{IgnoreDeletedRecords: true}); err == nil {
+		t.Errorf("newBulkIngestJobOptions() expected error for IgnoreDeletedRecords")
+	}
+
+	server, sfAuth := setupTestServer(bulkJob{Id: "1234", State: BulkJobStateOpen}, http.StatusOK)
+	defer server.Close()
+	sf := &Salesforce{auth: &sfAuth}
+	records := []map[string]any{{"Id": "001"}}
+	if _, err := sf.DeleteBulk("Account", records, 1, false, BulkJobOptions{IgnoreDeletedRecords: true}); err == nil {
+		t.Errorf("DeleteBulk() expected error for IgnoreDeletedRecords")
+	}
+}
@@ -0,0 +1,82 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const bulkAsyncRoute = "/services/async/"
+
+// an internal batch of a bulk ingest job, as salesforce split the uploaded data; times are in milliseconds
+type BulkJobBatch struct {
+	Id                      string `json:"id" xml:"id"`
+	JobId                   string `json:"jobId" xml:"jobId"`
+	State                   string `json:"state" xml:"state"`
+	StateMessage            string `json:"stateMessage" xml:"stateMessage"`
+	CreatedDate             string `json:"createdDate" xml:"createdDate"`
+	SystemModstamp          string `json:"systemModstamp" xml:"systemModstamp"`
+	NumberRecordsProcessed  int    `json:"numberRecordsProcessed" xml:"numberRecordsProcessed"`
+	NumberRecordsFailed     int    `json:"numberRecordsFailed" xml:"numberRecordsFailed"`
+	TotalProcessingTime     int    `json:"totalProcessingTime" xml:"totalProcessingTime"`
+	ApiActiveProcessingTime int    `json:"apiActiveProcessingTime" xml:"apiActiveProcessingTime"`
+	ApexProcessingTime      int    `json:"apexProcessingTime" xml:"apexProcessingTime"`
+}
+
+type bulkJobBatchList struct {
+	Batches []BulkJobBatch `json:"batchInfo" xml:"batchInfo"`
+}
+
+// bulk api 2.0 doesn't list the batches of a job, so they come from the bulk api 1.0 endpoint, which answers in
+// json or xml depending on the job's content type
+func doGetJobBatches(auth *authentication, bulkJobId string) ([]BulkJobBatch, error) {
+	if bulkJobId == "" {
+		return nil, errors.New("bulk job id is required")
+	}
+	route := bulkAsyncRoute + strings.TrimPrefix(apiVersion, "v") + "/job/" + bulkJobId + "/batch"
+	instanceUrl, accessToken := auth.session()
+	req, err := http.NewRequest(http.MethodGet, instanceUrl+route, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-SFDC-Session", accessToken)
+	req.Header.Set("User-Agent", "go-salesforce")
+	req.Header.Set("Accept", jsonType)
+	if err := auth.config.limiter(false).wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	req, endTelemetry := startRequestTelemetry(auth, req, TelemetryRequest{
+		Operation:  "GET async/job/batch",
+		Method:     http.MethodGet,
+		Path:       route,
+		ApiVersion: apiVersion,
+	})
+	resp, err := auth.config.client().Do(req)
+	endTelemetry(resp, err)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 300 {
+		return nil, errors.New(resp.Status + ": " + string(respBody))
+	}
+
+	batches := bulkJobBatchList{}
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		err = json.Unmarshal(respBody, &batches)
+	} else {
+		err = xml.Unmarshal(respBody, &batches)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return batches.Batches, nil
+}
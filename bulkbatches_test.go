@@ -0,0 +1,94 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_doGetJobBatches(t *testing.T) {
+	want := []BulkJobBatch{
+		{
+			Id:                      "751abc",
+			JobId:                   "750abc",
+			State:                   "Completed",
+			NumberRecordsProcessed:  10000,
+			TotalProcessingTime:     5000,
+			ApiActiveProcessingTime: 4000,
+			ApexProcessingTime:      1000,
+		},
+		{
+			Id:                     "751def",
+			JobId:                  "750abc",
+			State:                  "Failed",
+			StateMessage:           "InvalidBatch : Field name not found : Nmae",
+			NumberRecordsProcessed: 0,
+		},
+	}
+	jsonBody := `{"batchInfo":[` +
+		`{"id":"751abc","jobId":"750abc","state":"Completed","numberRecordsProcessed":10000,"totalProcessingTime":5000,"apiActiveProcessingTime":4000,"apexProcessingTime":1000},` +
+		`{"id":"751def","jobId":"750abc","state":"Failed","stateMessage":"InvalidBatch : Field name not found : Nmae","numberRecordsProcessed":0}]}`
+	xmlBody := `<?xml version="1.0" encoding="UTF-8"?>
+<batchInfoList xmlns="http://www.force.com/2009/06/asyncapi/dataload">
+ <batchInfo><id>751abc</id><jobId>750abc</jobId><state>Completed</state><numberRecordsProcessed>10000</numberRecordsProcessed>` +
+		`<totalProcessingTime>5000</totalProcessingTime><apiActiveProcessingTime>4000</apiActiveProcessingTime><apexProcessingTime>1000</apexProcessingTime></batchInfo>
+ <batchInfo><id>751def</id><jobId>750abc</jobId><state>Failed</state><stateMessage>InvalidBatch : Field name not found : Nmae</stateMessage><numberRecordsProcessed>0</numberRecordsProcessed></batchInfo>
+</batchInfoList>`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		want        []BulkJobBatch
+		wantErr     bool
+	}{
+		{name: "json", contentType: jsonType, body: jsonBody, status: http.StatusOK, want: want},
+		{name: "xml", contentType: "application/xml", body: xmlBody, status: http.StatusOK, want: want},
+		{name: "bad_request", contentType: "application/xml", body: "<error/>", status: http.StatusBadRequest, wantErr: true},
+		{name: "bad_response", contentType: jsonType, body: "1", status: http.StatusOK, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != bulkAsyncRoute+strings.TrimPrefix(apiVersion, "v")+"/job/750abc/batch" || r.Header.Get("X-SFDC-Session") != "accesstokenvalue" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				if _, err := w.Write([]byte(tt.body)); err != nil {
+					panic(err.Error())
+				}
+			}))
+			defer server.Close()
+			sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+			got, err := doGetJobBatches(&sfAuth, "750abc")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doGetJobBatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetJobBatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := doGetJobBatches(&authentication{}, ""); err == nil {
+		t.Errorf("doGetJobBatches() expected error without a job id")
+	}
+}
+
+func TestSalesforce_GetJobBatches(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+	sf := &Salesforce{auth: &sfAuth}
+	if _, err := sf.GetJobBatches(""); err == nil {
+		t.Errorf("GetJobBatches() expected error without a job id")
+	}
+	if _, err := (&Salesforce{}).GetJobBatches("750abc"); err == nil {
+		t.Errorf("GetJobBatches() expected validation error")
+	}
+}
@@ -0,0 +1,151 @@
+package salesforce
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// the most csv data salesforce accepts in a single bulk upload, a var so tests can lower it
+var bulkUploadLimit int64 = 150 * 1000 * 1000
+
+// where each record of a csv file starts and how large it is once encoded for upload, so batches can be
+// streamed from the file without reading it into memory
+type bulkFile struct {
+	path       string
+	headers    []string
+	offsets    []int64
+	sizes      []int64
+	headerSize int64
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func scanBulkFile(filePath string, options BulkJobOptions) (*bulkFile, error) {
+	delimiter, err := getColumnDelimiter(options.ColumnDelimiter)
+	if err != nil {
+		return nil, err
+	}
+	file, err := appFs.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.ReuseRecord = true
+	counter := &countingWriter{}
+	writer, err := newCSVWriter(counter, options)
+	if err != nil {
+		return nil, err
+	}
+	encodedSize := func(row []string) (int64, error) {
+		before := counter.n
+		if err := writer.Write(row); err != nil {
+			return 0, err
+		}
+		writer.Flush()
+		return counter.n - before, writer.Error()
+	}
+
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv file is empty: " + filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	scanned := &bulkFile{path: filePath, headers: append([]string{}, headers...)}
+	if scanned.headerSize, err = encodedSize(headers); err != nil {
+		return nil, err
+	}
+	for {
+		offset := reader.InputOffset()
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		size, err := encodedSize(row)
+		if err != nil {
+			return nil, err
+		}
+		scanned.offsets = append(scanned.offsets, offset)
+		scanned.sizes = append(scanned.sizes, size)
+	}
+	return scanned, nil
+}
+
+// splits batches that would be larger than the upload limit, keeping records in order
+func (file *bulkFile) splitBatches(plan *BulkLoadPlan, limit int64) error {
+	batches := []BulkLoadBatch{}
+	for _, batch := range plan.Batches {
+		start := batch.Index
+		size := file.headerSize
+		for i := batch.Index; i < batch.Index+batch.Size; i++ {
+			if file.headerSize+file.sizes[i] > limit {
+				return errors.New("record " + strconv.Itoa(i+1) + " of " + file.path + " is larger than the bulk upload limit")
+			}
+			if size+file.sizes[i] > limit {
+				batches = append(batches, BulkLoadBatch{Index: start, Size: i - start, Status: BulkBatchPending})
+				start = i
+				size = file.headerSize
+			}
+			size += file.sizes[i]
+		}
+		batches = append(batches, BulkLoadBatch{Index: start, Size: batch.Index + batch.Size - start, Status: BulkBatchPending})
+	}
+	plan.Batches = batches
+	return nil
+}
+
+// seeks to the batch's first record, so each upload only reads its own records
+func (file *bulkFile) batchData(options BulkJobOptions) func(batch BulkLoadBatch, w io.Writer) error {
+	return func(batch BulkLoadBatch, w io.Writer) error {
+		delimiter, err := getColumnDelimiter(options.ColumnDelimiter)
+		if err != nil {
+			return err
+		}
+		f, err := appFs.Open(file.path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := f.Seek(file.offsets[batch.Index], io.SeekStart); err != nil {
+			return err
+		}
+
+		reader := csv.NewReader(f)
+		reader.Comma = delimiter
+		reader.ReuseRecord = true
+		writer, err := newCSVWriter(w, options)
+		if err != nil {
+			return err
+		}
+		if err := writer.Write(file.headers); err != nil {
+			return err
+		}
+		for i := 0; i < batch.Size; i++ {
+			row, err := reader.Read()
+			if err != nil {
+				return err
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+}
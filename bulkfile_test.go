@@ -0,0 +1,182 @@
+package salesforce
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func Test_scanBulkFile(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	if err := appFs.MkdirAll("data", 0755); err != nil {
+		t.Fatalf("error creating directory in virtual file system")
+	}
+	files := map[string]string{
+		"data/data.csv":      "123",
+		"data/pipe.csv":      "a|b\n1|2",
+		"data/multiline.csv": "Name,Description\nAcme,\"first\nsecond\"\nGlobex,plain\n",
+		"data/empty.csv":     "",
+		"data/ragged.csv":    "a,b\n1\n",
+	}
+	for path, content := range files {
+		if err := afero.WriteFile(appFs, path, []byte(content), 0644); err != nil {
+			t.Fatalf("error creating file in virtual file system")
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		options BulkJobOptions
+		want    *bulkFile
+		wantErr bool
+	}{
+		{
+			name: "headers_only",
+			path: "data/data.csv",
+			want: &bulkFile{path: "data/data.csv", headers: []string{"123"}, headerSize: 4},
+		},
+		{
+			name:    "pipe_delimited",
+			path:    "data/pipe.csv",
+			options: BulkJobOptions{ColumnDelimiter: ColumnDelimiterPipe},
+			want:    &bulkFile{path: "data/pipe.csv", headers: []string{"a", "b"}, offsets: []int64{4}, sizes: []int64{4}, headerSize: 4},
+		},
+		{
+			name: "multiline_field",
+			path: "data/multiline.csv",
+			want: &bulkFile{
+				path:       "data/multiline.csv",
+				headers:    []string{"Name", "Description"},
+				offsets:    []int64{17, 37},
+				sizes:      []int64{20, 13},
+				headerSize: 17,
+			},
+		},
+		{
+			name:    "crlf_sizes",
+			path:    "data/pipe.csv",
+			options: BulkJobOptions{ColumnDelimiter: ColumnDelimiterPipe, LineEnding: LineEndingCRLF},
+			want:    &bulkFile{path: "data/pipe.csv", headers: []string{"a", "b"}, offsets: []int64{4}, sizes: []int64{5}, headerSize: 5},
+		},
+		{
+			name:    "invalid_column_delimiter",
+			path:    "data/data.csv",
+			options: BulkJobOptions{ColumnDelimiter: "COLON"},
+			wantErr: true,
+		},
+		{
+			name:    "empty_file",
+			path:    "data/empty.csv",
+			wantErr: true,
+		},
+		{
+			name:    "wrong_number_of_fields",
+			path:    "data/ragged.csv",
+			wantErr: true,
+		},
+		{
+			name:    "missing_file",
+			path:    "data/does_not_exist.csv",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scanBulkFile(tt.path, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scanBulkFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanBulkFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_bulkFile_splitBatches(t *testing.T) {
+	file := &bulkFile{path: "data.csv", headerSize: 10, sizes: []int64{20, 20, 20, 50, 5}}
+	plan := newBulkLoadPlan(nil, "Account", insertOperation, "", 5, 4)
+	if err := file.splitBatches(plan, 60); err != nil {
+		t.Fatalf("splitBatches() error = %v", err)
+	}
+	want := []BulkLoadBatch{
+		{Index: 0, Size: 2, Status: BulkBatchPending},
+		{Index: 2, Size: 1, Status: BulkBatchPending},
+		{Index: 3, Size: 1, Status: BulkBatchPending},
+		{Index: 4, Size: 1, Status: BulkBatchPending},
+	}
+	if !reflect.DeepEqual(plan.Batches, want) {
+		t.Errorf("splitBatches() = %v, want %v", plan.Batches, want)
+	}
+
+	plan = newBulkLoadPlan(nil, "Account", insertOperation, "", 5, 4)
+	if err := file.splitBatches(plan, 55); err == nil {
+		t.Errorf("splitBatches() expected error for a record larger than the limit")
+	}
+}
+
+func Test_doBulkJobWithFile_streaming(t *testing.T) {
+	appFs = afero.NewMemMapFs()
+	data := "Name,Description\nAcme,\"first\nsecond\"\nGlobex,plain\nInitech,\"quoted \"\"name\"\"\"\n"
+	if err := afero.WriteFile(appFs, "accounts.csv", []byte(data), 0644); err != nil {
+		t.Fatalf("error creating file in virtual file system")
+	}
+
+	var mu sync.Mutex
+	uploads := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/batches") {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			uploads = append(uploads, string(body))
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if _, err := w.Write([]byte(`{"id": "7500000000001", "state": "Open"}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	limit := bulkUploadLimit
+	bulkUploadLimit = 45
+	defer func() { bulkUploadLimit = limit }()
+
+	jobIds, err := doBulkJobWithFile(&sfAuth, "Account", "", insertOperation, "accounts.csv", 10000, false, BulkJobOptions{})
+	if err != nil {
+		t.Fatalf("doBulkJobWithFile() error = %v", err)
+	}
+	want := []string{
+		"Name,Description\nAcme,\"first\nsecond\"\n",
+		"Name,Description\nGlobex,plain\n",
+		"Name,Description\nInitech,\"quoted \"\"name\"\"\"\n",
+	}
+	if len(jobIds) != len(want) || !reflect.DeepEqual(uploads, want) {
+		t.Errorf("doBulkJobWithFile() uploaded %q, want %q", uploads, want)
+	}
+}
+
+func Test_streamJobData_writeError(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+
+	err := streamJobData(&sfAuth, func(w io.Writer) error {
+		if _, err := w.Write(bytes.Repeat([]byte("a"), 1024)); err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}, bulkJob{})
+	if err == nil {
+		t.Errorf("streamJobData() expected error when writing the data fails")
+	}
+}
@@ -0,0 +1,63 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// returned when bulk job creation is still throttled with 429 after every retry
+var ErrBulkJobLimit = errors.New("bulk job limit exceeded")
+
+const maxBulkJobLimitAttempts = 6
+
+// doubled after each throttled attempt, unless salesforce sends a Retry-After header
+var bulkJobLimitDelay = 5 * time.Second
+
+// queues ingest jobs so no more than n are being created and uploaded by this client at once.
+// it has no effect unless WithConcurrency allows more than n workers, and it doesn't count jobs
+// that are still processing or that were opened by other clients
+func WithMaxOpenBulkJobs(n int) Option {
+	return func(config *configuration) error {
+		if n < 1 {
+			return errors.New("max open bulk jobs must be at least 1")
+		}
+		config.openBulkJobs = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// blocks until a slot is free, returning the function that releases it once the job is uploaded
+func (config *configuration) acquireBulkJobSlot() func() {
+	if config == nil || config.openBulkJobs == nil {
+		return func() {}
+	}
+	config.openBulkJobs <- struct{}{}
+	return func() { <-config.openBulkJobs }
+}
+
+// bulk endpoints respond 429 when too many jobs are being created or processed at once
+func doBulkJobCreationRequest(auth *authentication, payload requestPayload) (*http.Response, error) {
+	delay := bulkJobLimitDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := doRequest(auth, payload)
+		if err == nil || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		if attempt >= maxBulkJobLimitAttempts {
+			return resp, fmt.Errorf("%w: %w", ErrBulkJobLimit, err)
+		}
+		wait := delay
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); retryAfter > 0 {
+			wait = retryAfter
+		}
+		recordRetryTelemetry(auth, TelemetryRetry{
+			Operation: newTelemetryRequest(payload).Operation,
+			Reason:    retryReasonBulkJobLimit,
+			Attempt:   attempt,
+		})
+		time.Sleep(wait)
+		delay *= 2
+	}
+}
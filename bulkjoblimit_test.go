@@ -0,0 +1,178 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithMaxOpenBulkJobs(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		wantErr bool
+	}{
+		{name: "valid", n: 2, wantErr: false},
+		{name: "zero", n: 0, wantErr: true},
+		{name: "negative", n: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newConfiguration(WithMaxOpenBulkJobs(tt.n))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithMaxOpenBulkJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cap(config.openBulkJobs) != tt.n {
+				t.Errorf("WithMaxOpenBulkJobs() = %v, want %v", cap(config.openBulkJobs), tt.n)
+			}
+		})
+	}
+}
+
+func Test_doBulkJobCreationRequest(t *testing.T) {
+	defaultDelay := bulkJobLimitDelay
+	bulkJobLimitDelay = time.Millisecond
+	defer func() { bulkJobLimitDelay = defaultDelay }()
+
+	jobBody, _ := json.Marshal(bulkJob{Id: "1234", State: BulkJobStateOpen})
+	throttledBody := `[{"errorCode":"REQUEST_LIMIT_EXCEEDED","message":"ConcurrentRequests"}]`
+
+	tests := []struct {
+		name         string
+		throttled    int
+		status       int
+		wantRequests int
+		wantErr      error
+	}{
+		{name: "no_throttling", throttled: 0, wantRequests: 1},
+		{name: "throttled_then_created", throttled: 2, wantRequests: 3},
+		{name: "always_throttled", throttled: maxBulkJobLimitAttempts, wantRequests: maxBulkJobLimitAttempts, wantErr: ErrBulkJobLimit},
+		{name: "other_error_not_retried", throttled: 1, status: http.StatusBadRequest, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tt.throttled {
+					status := http.StatusTooManyRequests
+					if tt.status != 0 {
+						status = tt.status
+					}
+					w.WriteHeader(status)
+					if _, err := w.Write([]byte(throttledBody)); err != nil {
+						t.Fatal(err.Error())
+					}
+					return
+				}
+				if _, err := w.Write(jobBody); err != nil {
+					t.Fatal(err.Error())
+				}
+			}))
+			defer server.Close()
+			sfAuth := authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "accesstokenvalue",
+			}
+
+			_, err := createBulkJob(&sfAuth, ingestJobType, []byte("{}"))
+			if requests != tt.wantRequests {
+				t.Errorf("createBulkJob() sent %d requests, want %d", requests, tt.wantRequests)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("createBulkJob() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && tt.status == 0 && err != nil {
+				t.Errorf("createBulkJob() unexpected error = %v", err)
+			}
+			if tt.status != 0 && err == nil {
+				t.Errorf("createBulkJob() expected error")
+			}
+		})
+	}
+}
+
+func Test_doBulkJobCreationRequest_RetryAfter(t *testing.T) {
+	jobBody, _ := json.Marshal(bulkJob{Id: "1234", State: BulkJobStateOpen})
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			if _, err := w.Write([]byte(`[]`)); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		if _, err := w.Write(jobBody); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	start := time.Now()
+	if _, err := createBulkJob(&sfAuth, ingestJobType, []byte("{}")); err != nil {
+		t.Fatalf("createBulkJob() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("createBulkJob() retried after %v, want at least Retry-After", elapsed)
+	}
+}
+
+func Test_submitBulkLoadPlan_maxOpenJobs(t *testing.T) {
+	var mu sync.Mutex
+	open, maxOpen, created := 0, 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost:
+			created++
+			open++
+			maxOpen = max(maxOpen, open)
+			body, _ := json.Marshal(bulkJob{Id: "job" + string(rune('0'+created)), State: BulkJobStateOpen})
+			if _, err := w.Write(body); err != nil {
+				t.Fatal(err.Error())
+			}
+		case strings.HasSuffix(r.URL.Path, "/batches"):
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPatch:
+			open--
+			if _, err := w.Write([]byte(`{}`)); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+	}))
+	defer server.Close()
+
+	config, err := newConfiguration(WithConcurrency(4), WithMaxOpenBulkJobs(1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+	records := []map[string]any{{"Name": "a"}, {"Name": "b"}, {"Name": "c"}, {"Name": "d"}}
+	jobIds, err := doBulkJob(&sfAuth, "Account", "", insertOperation, records, 1, false, BulkJobOptions{})
+	if err != nil {
+		t.Fatalf("doBulkJob() error = %v", err)
+	}
+	if len(jobIds) != 4 {
+		t.Errorf("doBulkJob() created %d jobs, want 4", len(jobIds))
+	}
+	if maxOpen != 1 {
+		t.Errorf("doBulkJob() had %d jobs open at once, want 1", maxOpen)
+	}
+}
@@ -0,0 +1,129 @@
+package salesforce
+
+import (
+	"errors"
+	"strconv"
+)
+
+const (
+	BulkBatchPending  = "Pending"
+	BulkBatchUploaded = "Uploaded"
+	BulkBatchFailed   = "Failed"
+)
+
+// records the bulk job created for each batch of a bulk load, so a failed load can be resumed with ResumeBulkLoad
+// plans can be serialized with encoding/json and resumed by another process
+type BulkLoadPlan struct {
+	SObjectName         string          `json:"sObjectName"`
+	Operation           string          `json:"operation"`
+	ExternalIdFieldName string          `json:"externalIdFieldName,omitempty"`
+	Batches             []BulkLoadBatch `json:"batches"`
+}
+
+// a batch of Size input records starting at Index
+type BulkLoadBatch struct {
+	Index  int    `json:"index"`
+	Size   int    `json:"size"`
+	JobId  string `json:"jobId,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// fills in the plan passed through BulkJobOptions, or a new plan when there is none
+func newBulkLoadPlan(plan *BulkLoadPlan, sObjectName string, operation string, fieldName string, total int, batchSize int) *BulkLoadPlan {
+	if plan == nil {
+		plan = &BulkLoadPlan{}
+	}
+	*plan = BulkLoadPlan{
+		SObjectName:         sObjectName,
+		Operation:           operation,
+		ExternalIdFieldName: fieldName,
+		Batches:             []BulkLoadBatch{},
+	}
+	for index := 0; index < total; index += batchSize {
+		plan.Batches = append(plan.Batches, BulkLoadBatch{
+			Index:  index,
+			Size:   min(batchSize, total-index),
+			Status: BulkBatchPending,
+		})
+	}
+	return plan
+}
+
+// true once every batch has been uploaded, which doesn't mean that the jobs have finished processing
+func (plan *BulkLoadPlan) Done() bool {
+	for _, batch := range plan.Batches {
+		if batch.Status != BulkBatchUploaded {
+			return false
+		}
+	}
+	return true
+}
+
+func (plan *BulkLoadPlan) JobIds() []string {
+	var jobIds []string
+	for _, batch := range plan.Batches {
+		if batch.Status == BulkBatchUploaded {
+			jobIds = append(jobIds, batch.JobId)
+		}
+	}
+	return jobIds
+}
+
+// throttling waits on the jobs of earlier batches, by position
+func (plan *BulkLoadPlan) jobIdsBefore(i int) []string {
+	jobIds := make([]string, i)
+	for j, batch := range plan.Batches[:i] {
+		jobIds[j] = batch.JobId
+	}
+	return jobIds
+}
+
+func (plan *BulkLoadPlan) validate(total int) error {
+	if plan == nil || len(plan.Batches) == 0 {
+		return errors.New("bulk load plan has no batches")
+	}
+	if plan.SObjectName == "" || plan.Operation == "" {
+		return errors.New("bulk load plan is missing the sObject name or operation")
+	}
+	index := 0
+	for _, batch := range plan.Batches {
+		if batch.Index != index || batch.Size < 1 {
+			return errors.New("bulk load plan batches must cover the records in order")
+		}
+		index += batch.Size
+	}
+	if index != total {
+		return errors.New("bulk load plan covers " + strconv.Itoa(index) + " records, got " + strconv.Itoa(total))
+	}
+	return nil
+}
+
+// records must be the same records, in the same order, that the plan was created with
+func doResumeBulkLoad(auth *authentication, plan *BulkLoadPlan, records any, waitForResults bool, options BulkJobOptions) ([]string, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.ExplicitNulls)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.validate(len(recordMap)); err != nil {
+		return nil, err
+	}
+	if err := validateRecordFields(auth, plan.SObjectName, recordMap); err != nil {
+		return nil, err
+	}
+	return submitBulkLoadPlan(auth, plan, recordBatchData(recordMap, options), false, waitForResults, options)
+}
+
+func doResumeBulkLoadFile(auth *authentication, plan *BulkLoadPlan, filePath string, waitForResults bool, options BulkJobOptions) ([]string, error) {
+	if plan == nil {
+		return nil, errors.New("bulk load plan has no batches")
+	}
+	file, err := readBulkFile(auth, plan.SObjectName, filePath, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.validate(len(file.offsets)); err != nil {
+		return nil, err
+	}
+	return submitBulkLoadPlan(auth, plan, file.batchData(options), true, waitForResults, options)
+}
@@ -0,0 +1,136 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// creates jobs with increasing ids, failing uploads for the jobs in failUploads
+func setupBulkLoadTestServer(t *testing.T, failUploads map[string]bool) (*httptest.Server, authentication) {
+	var mu sync.Mutex
+	jobs := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body any
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/ingest"):
+			jobs++
+			body = bulkJob{Id: "job" + strconv.Itoa(jobs), State: BulkJobStateOpen}
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/batches"):
+			jobId := strings.Split(r.URL.Path, "/")[6]
+			if failUploads[jobId] {
+				w.WriteHeader(http.StatusBadRequest)
+				body = []SalesforceErrorMessage{{Message: "upload failed"}}
+			} else {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		default:
+			body = bulkJob{State: BulkJobStateUploadComplete}
+		}
+		respBody, _ := json.Marshal(body)
+		if _, err := w.Write(respBody); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	return server, authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+}
+
+func Test_doResumeBulkLoad(t *testing.T) {
+	server, sfAuth := setupBulkLoadTestServer(t, map[string]bool{"job2": true})
+	defer server.Close()
+
+	records := []map[string]any{}
+	for i := 0; i < 5; i++ {
+		records = append(records, map[string]any{"Name": strconv.Itoa(i)})
+	}
+	plan := &BulkLoadPlan{}
+	_, err := doBulkJob(&sfAuth, "Account", "", insertOperation, records, 2, false, BulkJobOptions{Plan: plan})
+	if err == nil {
+		t.Fatal("doBulkJob() expected an upload error")
+	}
+	want := &BulkLoadPlan{
+		SObjectName: "Account",
+		Operation:   insertOperation,
+		Batches: []BulkLoadBatch{
+			{Index: 0, Size: 2, JobId: "job1", Status: BulkBatchUploaded},
+			{Index: 2, Size: 2, JobId: "job2", Status: BulkBatchFailed, Error: plan.Batches[1].Error},
+			{Index: 4, Size: 1, Status: BulkBatchPending},
+		},
+	}
+	if !reflect.DeepEqual(plan, want) || plan.Batches[1].Error == "" {
+		t.Fatalf("doBulkJob() plan = %+v, want %+v", plan, want)
+	}
+	if plan.Done() || !reflect.DeepEqual(plan.JobIds(), []string{"job1"}) {
+		t.Errorf("plan Done() = %v, JobIds() = %v", plan.Done(), plan.JobIds())
+	}
+
+	// plans are saved and resumed as json
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resumed := &BulkLoadPlan{}
+	if err := json.Unmarshal(data, resumed); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := doResumeBulkLoad(&sfAuth, resumed, records[:4], false, BulkJobOptions{}); err == nil {
+		t.Errorf("doResumeBulkLoad() expected error for records that don't match the plan")
+	}
+	jobIds, err := doResumeBulkLoad(&sfAuth, resumed, records, false, BulkJobOptions{})
+	if err != nil {
+		t.Fatalf("doResumeBulkLoad() error = %v", err)
+	}
+	if !reflect.DeepEqual(jobIds, []string{"job3", "job4"}) {
+		t.Errorf("doResumeBulkLoad() = %v, want [job3 job4]", jobIds)
+	}
+	if !resumed.Done() || !reflect.DeepEqual(resumed.JobIds(), []string{"job1", "job3", "job4"}) {
+		t.Errorf("resumed plan = %+v", resumed)
+	}
+
+	if _, err := doResumeBulkLoad(&sfAuth, &BulkLoadPlan{}, records, false, BulkJobOptions{}); err == nil {
+		t.Errorf("doResumeBulkLoad() expected error for an empty plan")
+	}
+}
+
+func Test_doResumeBulkLoadFile(t *testing.T) {
+	server, sfAuth := setupBulkLoadTestServer(t, map[string]bool{"job1": true})
+	defer server.Close()
+
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	filePath := "data/accounts.csv"
+	if err := afero.WriteFile(appFs, filePath, []byte("Name\nStark Industries\nWayne Enterprises\nAcme\n"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	plan := &BulkLoadPlan{}
+	jobIds, err := doBulkJobWithFile(&sfAuth, "Account", "", insertOperation, filePath, 2, false, BulkJobOptions{Plan: plan})
+	if err == nil {
+		t.Fatal("doBulkJobWithFile() expected an upload error")
+	}
+	// file uploads continue after a failed batch
+	if !reflect.DeepEqual(jobIds, []string{"job1", "job2"}) {
+		t.Errorf("doBulkJobWithFile() = %v, want [job1 job2]", jobIds)
+	}
+	if plan.Batches[0].Status != BulkBatchFailed || plan.Batches[1].Status != BulkBatchUploaded {
+		t.Fatalf("doBulkJobWithFile() plan = %+v", plan)
+	}
+
+	jobIds, err = doResumeBulkLoadFile(&sfAuth, plan, filePath, false, BulkJobOptions{})
+	if err != nil {
+		t.Fatalf("doResumeBulkLoadFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(jobIds, []string{"job3"}) || !plan.Done() {
+		t.Errorf("doResumeBulkLoadFile() = %v, plan = %+v", jobIds, plan)
+	}
+}
@@ -0,0 +1,112 @@
+package salesforce
+
+import (
+	"strconv"
+)
+
+type BulkJobState string
+
+const (
+	BulkJobStateOpen           BulkJobState = "Open"
+	BulkJobStateUploadComplete BulkJobState = "UploadComplete"
+	BulkJobStateInProgress     BulkJobState = "InProgress"
+	BulkJobStateJobComplete    BulkJobState = "JobComplete"
+	BulkJobStateFailed         BulkJobState = "Failed"
+	BulkJobStateAborted        BulkJobState = "Aborted"
+)
+
+// jobs in a terminal state are finished and won't change state again
+func (state BulkJobState) IsTerminal() bool {
+	return state == BulkJobStateJobComplete || state == BulkJobStateFailed || state == BulkJobStateAborted
+}
+
+// the job processed every record, though individual records may still have failed
+func (state BulkJobState) Succeeded() bool {
+	return state == BulkJobStateJobComplete
+}
+
+type BulkJobErrorKind string
+
+const (
+	// the job completed, but some records failed and can be found in FailedRecords
+	BulkJobErrorRecords BulkJobErrorKind = "records"
+	// the job failed or was aborted, so records may not have been processed
+	BulkJobErrorJob BulkJobErrorKind = "job"
+	// the job didn't finish before we stopped waiting for it, and may still be running
+	BulkJobErrorTimeout BulkJobErrorKind = "timeout"
+)
+
+type BulkJobError struct {
+	JobId         string
+	State         BulkJobState
+	Kind          BulkJobErrorKind
+	Message       string
+	RecordsFailed int
+	Err           error
+}
+
+func (e *BulkJobError) Error() string {
+	switch e.Kind {
+	case BulkJobErrorRecords:
+		return "bulk job " + e.JobId + " completed with " + strconv.Itoa(e.RecordsFailed) + " failed records"
+	case BulkJobErrorTimeout:
+		if e.Err != nil {
+			return "timed out waiting for bulk job " + e.JobId + ": " + e.Err.Error()
+		}
+		return "timed out waiting for bulk job " + e.JobId
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.State == BulkJobStateAborted {
+		return "bulk job aborted"
+	}
+	return "bulk job " + e.JobId + " " + string(e.State)
+}
+
+func (e *BulkJobError) Unwrap() error {
+	return e.Err
+}
+
+// classifies the outcome of a finished job, returning nil if it hasn't finished or every record succeeded
+func (results BulkJobResults) Err() error {
+	if jobErr := jobLevelError(results); jobErr != nil {
+		return jobErr
+	}
+	if results.State.Succeeded() && results.NumberRecordsFailed > 0 {
+		return &BulkJobError{
+			JobId:         results.Id,
+			State:         results.State,
+			Kind:          BulkJobErrorRecords,
+			RecordsFailed: results.NumberRecordsFailed,
+		}
+	}
+	return nil
+}
+
+// failed and aborted jobs, and completed jobs that report an error message, failed as a whole rather than record by record
+func jobLevelError(results BulkJobResults) *BulkJobError {
+	if !results.State.IsTerminal() {
+		return nil
+	}
+	if results.State.Succeeded() && results.ErrorMessage == "" {
+		return nil
+	}
+	return &BulkJobError{
+		JobId:         results.Id,
+		State:         results.State,
+		Kind:          BulkJobErrorJob,
+		Message:       results.ErrorMessage,
+		RecordsFailed: results.NumberRecordsFailed,
+	}
+}
+
+func newBulkJobTimeoutError(results BulkJobResults, bulkJobId string, err error) *BulkJobError {
+	return &BulkJobError{
+		JobId:         bulkJobId,
+		State:         results.State,
+		Kind:          BulkJobErrorTimeout,
+		RecordsFailed: results.NumberRecordsFailed,
+		Err:           err,
+	}
+}
@@ -0,0 +1,128 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBulkJobState(t *testing.T) {
+	tests := []struct {
+		state         BulkJobState
+		wantTerminal  bool
+		wantSucceeded bool
+	}{
+		{state: BulkJobStateOpen, wantTerminal: false, wantSucceeded: false},
+		{state: BulkJobStateUploadComplete, wantTerminal: false, wantSucceeded: false},
+		{state: BulkJobStateInProgress, wantTerminal: false, wantSucceeded: false},
+		{state: BulkJobStateJobComplete, wantTerminal: true, wantSucceeded: true},
+		{state: BulkJobStateFailed, wantTerminal: true, wantSucceeded: false},
+		{state: BulkJobStateAborted, wantTerminal: true, wantSucceeded: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			if got := tt.state.IsTerminal(); got != tt.wantTerminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.wantTerminal)
+			}
+			if got := tt.state.Succeeded(); got != tt.wantSucceeded {
+				t.Errorf("Succeeded() = %v, want %v", got, tt.wantSucceeded)
+			}
+		})
+	}
+}
+
+func TestBulkJobResults_Err(t *testing.T) {
+	tests := []struct {
+		name        string
+		results     BulkJobResults
+		wantKind    BulkJobErrorKind
+		wantMessage string
+	}{
+		{
+			name:    "in_progress",
+			results: BulkJobResults{Id: "1234", State: BulkJobStateInProgress, NumberRecordsFailed: 1},
+		},
+		{
+			name:    "complete",
+			results: BulkJobResults{Id: "1234", State: BulkJobStateJobComplete},
+		},
+		{
+			name:        "complete_with_failed_records",
+			results:     BulkJobResults{Id: "1234", State: BulkJobStateJobComplete, NumberRecordsFailed: 2},
+			wantKind:    BulkJobErrorRecords,
+			wantMessage: "bulk job 1234 completed with 2 failed records",
+		},
+		{
+			name:        "complete_with_error_message",
+			results:     BulkJobResults{Id: "1234", State: BulkJobStateJobComplete, ErrorMessage: "example error"},
+			wantKind:    BulkJobErrorJob,
+			wantMessage: "example error",
+		},
+		{
+			name:        "failed",
+			results:     BulkJobResults{Id: "1234", State: BulkJobStateFailed, ErrorMessage: "InvalidBatch : Field name not found"},
+			wantKind:    BulkJobErrorJob,
+			wantMessage: "InvalidBatch : Field name not found",
+		},
+		{
+			name:        "failed_without_message",
+			results:     BulkJobResults{Id: "1234", State: BulkJobStateFailed},
+			wantKind:    BulkJobErrorJob,
+			wantMessage: "bulk job 1234 Failed",
+		},
+		{
+			name:        "aborted",
+			results:     BulkJobResults{Id: "1234", State: BulkJobStateAborted},
+			wantKind:    BulkJobErrorJob,
+			wantMessage: "bulk job aborted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.results.Err()
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			var jobErr *BulkJobError
+			if !errors.As(err, &jobErr) {
+				t.Fatalf("Err() = %v, want *BulkJobError", err)
+			}
+			if jobErr.Kind != tt.wantKind {
+				t.Errorf("Err() kind = %v, want %v", jobErr.Kind, tt.wantKind)
+			}
+			if jobErr.Error() != tt.wantMessage {
+				t.Errorf("Err() = %v, want %v", jobErr.Error(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func Test_isBulkJobDone_errorKind(t *testing.T) {
+	done, err := isBulkJobDone(BulkJobResults{Id: "1234", State: BulkJobStateJobComplete, NumberRecordsFailed: 1})
+	if !done || err != nil {
+		t.Errorf("isBulkJobDone() = %v, %v, want true, nil", done, err)
+	}
+
+	done, err = isBulkJobDone(BulkJobResults{Id: "1234", State: BulkJobStateFailed})
+	var jobErr *BulkJobError
+	if !done || !errors.As(err, &jobErr) || jobErr.Kind != BulkJobErrorJob {
+		t.Errorf("isBulkJobDone() = %v, %v, want true, job error", done, err)
+	}
+}
+
+func Test_newBulkJobTimeoutError(t *testing.T) {
+	err := newBulkJobTimeoutError(BulkJobResults{Id: "1234", State: BulkJobStateInProgress}, "1234", context.DeadlineExceeded)
+	if err.Kind != BulkJobErrorTimeout || err.State != BulkJobStateInProgress {
+		t.Errorf("newBulkJobTimeoutError() = %+v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("newBulkJobTimeoutError() should wrap %v", context.DeadlineExceeded)
+	}
+	want := "timed out waiting for bulk job 1234: context deadline exceeded"
+	if err.Error() != want {
+		t.Errorf("newBulkJobTimeoutError() = %v, want %v", err.Error(), want)
+	}
+}
@@ -0,0 +1,53 @@
+package salesforce
+
+type Capabilities struct {
+	AuthFlow          string
+	CanRefreshSession bool
+	APIVersion        string
+	CustomHTTPClient  bool
+	SessionValidation bool
+	Compression       bool
+}
+
+const (
+	AuthFlowUsernamePassword  = "username_password"
+	AuthFlowClientCredentials = "client_credentials"
+	AuthFlowJWT               = "jwt"
+	AuthFlowAccessToken       = "access_token"
+	AuthFlowDevice            = "device"
+	AuthFlowAuthorizationCode = "authorization_code"
+	AuthFlowTokenProvider     = "token_provider"
+)
+
+var authFlows = map[string]string{
+	grantTypeUsernamePassword:  AuthFlowUsernamePassword,
+	grantTypeClientCredentials: AuthFlowClientCredentials,
+	grantTypeJWT:               AuthFlowJWT,
+	grantTypeAccessToken:       AuthFlowAccessToken,
+	grantTypeDevice:            AuthFlowDevice,
+	grantTypeAuthorizationCode: AuthFlowAuthorizationCode,
+	grantTypeTokenProvider:     AuthFlowTokenProvider,
+}
+
+func getCapabilities(auth *authentication) Capabilities {
+	capabilities := Capabilities{
+		APIVersion:        apiVersion,
+		SessionValidation: true,
+	}
+	if auth == nil {
+		return capabilities
+	}
+	capabilities.AuthFlow = authFlows[auth.grantType]
+	// access tokens can't be refreshed because no credentials are kept
+	capabilities.CanRefreshSession = auth.grantType == grantTypeUsernamePassword ||
+		auth.grantType == grantTypeClientCredentials ||
+		auth.grantType == grantTypeJWT ||
+		((auth.grantType == grantTypeDevice || auth.grantType == grantTypeAuthorizationCode) && auth.RefreshToken != "") ||
+		auth.config.hasTokenProvider()
+	if auth.config != nil {
+		capabilities.CustomHTTPClient = auth.config.customHTTPClient
+		capabilities.SessionValidation = !auth.config.skipSessionValidation
+		capabilities.Compression = auth.config.compression
+	}
+	return capabilities
+}
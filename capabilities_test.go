@@ -0,0 +1,154 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_getCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		auth *authentication
+		want Capabilities
+	}{
+		{
+			name: "no_auth",
+			auth: nil,
+			want: Capabilities{
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+			},
+		},
+		{
+			name: "client_credentials",
+			auth: &authentication{
+				grantType: grantTypeClientCredentials,
+				config:    &configuration{},
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowClientCredentials,
+				CanRefreshSession: true,
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+			},
+		},
+		{
+			name: "access_token_with_options",
+			auth: &authentication{
+				grantType: grantTypeAccessToken,
+				config: &configuration{
+					httpClient:            &http.Client{},
+					customHTTPClient:      true,
+					skipSessionValidation: true,
+				},
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowAccessToken,
+				CanRefreshSession: false,
+				APIVersion:        apiVersion,
+				CustomHTTPClient:  true,
+				SessionValidation: false,
+			},
+		},
+		{
+			name: "device_without_refresh_token",
+			auth: &authentication{
+				grantType: grantTypeDevice,
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowDevice,
+				CanRefreshSession: false,
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+			},
+		},
+		{
+			name: "authorization_code_with_refresh_token",
+			auth: &authentication{
+				grantType:    grantTypeAuthorizationCode,
+				RefreshToken: "refresh",
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowAuthorizationCode,
+				CanRefreshSession: true,
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+			},
+		},
+		{
+			name: "jwt_without_config",
+			auth: &authentication{
+				grantType: grantTypeJWT,
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowJWT,
+				CanRefreshSession: true,
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+			},
+		},
+		{
+			name: "compression",
+			auth: &authentication{
+				grantType: grantTypeJWT,
+				config:    &configuration{compression: true},
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowJWT,
+				CanRefreshSession: true,
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+				Compression:       true,
+			},
+		},
+		{
+			name: "token_provider",
+			auth: &authentication{
+				grantType: grantTypeAccessToken,
+				config: &configuration{
+					tokenProvider: func(ctx context.Context) (string, string, error) { return "token", "", nil },
+				},
+			},
+			want: Capabilities{
+				AuthFlow:          AuthFlowAccessToken,
+				CanRefreshSession: true,
+				APIVersion:        apiVersion,
+				SessionValidation: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getCapabilities(tt.auth); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getCapabilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getCapabilities_customHTTPClient(t *testing.T) {
+	middleware := func(next http.RoundTripper) http.RoundTripper { return next }
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "default", opts: nil, want: false},
+		{name: "transport_options", opts: []Option{WithMiddleware(middleware), WithHighThroughputTransport()}, want: false},
+		{name: "with_http_client", opts: []Option{WithHTTPClient(&http.Client{}), WithMiddleware(middleware)}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newConfiguration(tt.opts...)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			got := getCapabilities(&authentication{grantType: grantTypeAccessToken, config: config})
+			if got.CustomHTTPClient != tt.want {
+				t.Errorf("getCapabilities() CustomHTTPClient = %v, want %v", got.CustomHTTPClient, tt.want)
+			}
+		})
+	}
+}
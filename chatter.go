@@ -0,0 +1,242 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+type FeedElement struct {
+	Id              string
+	FeedElementType string
+	SubjectId       string
+	ActorId         string
+	Text            string
+	CreatedDate     string
+}
+
+type FeedComment struct {
+	Id            string
+	FeedElementId string
+	UserId        string
+	Text          string
+	CreatedDate   string
+}
+
+type CustomNotification struct {
+	NotificationTypeId string
+	RecipientIds       []string
+	Title              string
+	Body               string
+	TargetId           string
+}
+
+type messageSegment struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+	Id   string `json:"id,omitempty"`
+}
+
+type messageBody struct {
+	MessageSegments []messageSegment `json:"messageSegments"`
+}
+
+type feedElementInput struct {
+	Body            messageBody `json:"body"`
+	FeedElementType string      `json:"feedElementType"`
+	SubjectId       string      `json:"subjectId"`
+}
+
+type feedCommentInput struct {
+	Body messageBody `json:"body"`
+}
+
+type chatterReference struct {
+	Id string `json:"id"`
+}
+
+type chatterText struct {
+	Text string `json:"text"`
+}
+
+type feedElementResponse struct {
+	Id              string           `json:"id"`
+	FeedElementType string           `json:"feedElementType"`
+	Parent          chatterReference `json:"parent"`
+	Actor           chatterReference `json:"actor"`
+	Body            chatterText      `json:"body"`
+	CreatedDate     string           `json:"createdDate"`
+}
+
+type feedCommentResponse struct {
+	Id          string           `json:"id"`
+	FeedElement chatterReference `json:"feedElement"`
+	User        chatterReference `json:"user"`
+	Body        chatterText      `json:"body"`
+	CreatedDate string           `json:"createdDate"`
+}
+
+type feedElementPage struct {
+	Elements    []feedElementResponse `json:"elements"`
+	NextPageUrl string                `json:"nextPageUrl"`
+}
+
+type customNotificationInput struct {
+	CustomNotifTypeId string   `json:"customNotifTypeId"`
+	RecipientIds      []string `json:"recipientIds"`
+	Title             string   `json:"title"`
+	Body              string   `json:"body"`
+	TargetId          string   `json:"targetId,omitempty"`
+}
+
+const (
+	chatterUri             = "/chatter"
+	feedItemType           = "FeedItem"
+	textSegment            = "Text"
+	mentionSegment         = "Mention"
+	customNotificationType = "customNotificationAction"
+)
+
+// mentions are added after the text, each separated by a space, and notify the mentioned users or groups
+func newMessageBody(text string, mentions []string) messageBody {
+	body := messageBody{MessageSegments: []messageSegment{{Type: textSegment, Text: text}}}
+	for _, id := range mentions {
+		body.MessageSegments = append(body.MessageSegments,
+			messageSegment{Type: textSegment, Text: " "},
+			messageSegment{Type: mentionSegment, Id: id},
+		)
+	}
+	return body
+}
+
+func (element feedElementResponse) toFeedElement() FeedElement {
+	return FeedElement{
+		Id:              element.Id,
+		FeedElementType: element.FeedElementType,
+		SubjectId:       element.Parent.Id,
+		ActorId:         element.Actor.Id,
+		Text:            element.Body.Text,
+		CreatedDate:     element.CreatedDate,
+	}
+}
+
+func doChatterRequest(auth *authentication, method string, uri string, input any, result any) error {
+	payload := requestPayload{
+		method:  method,
+		uri:     chatterUri + uri,
+		content: jsonType,
+	}
+	if input != nil {
+		body, err := json.Marshal(input)
+		if err != nil {
+			return err
+		}
+		payload.body = string(body)
+	}
+	resp, err := doRequest(auth, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
+
+func doPostFeedElement(auth *authentication, subjectId string, text string, mentions []string) (FeedElement, error) {
+	if subjectId == "" {
+		return FeedElement{}, errors.New("subject id is required")
+	}
+	if text == "" && len(mentions) == 0 {
+		return FeedElement{}, errors.New("text or mentions are required")
+	}
+	input := feedElementInput{
+		Body:            newMessageBody(text, mentions),
+		FeedElementType: feedItemType,
+		SubjectId:       subjectId,
+	}
+	var element feedElementResponse
+	if err := doChatterRequest(auth, http.MethodPost, "/feed-elements", input, &element); err != nil {
+		return FeedElement{}, err
+	}
+	return element.toFeedElement(), nil
+}
+
+func doPostFeedComment(auth *authentication, feedElementId string, text string, mentions []string) (FeedComment, error) {
+	if feedElementId == "" {
+		return FeedComment{}, errors.New("feed element id is required")
+	}
+	if text == "" && len(mentions) == 0 {
+		return FeedComment{}, errors.New("text or mentions are required")
+	}
+	input := feedCommentInput{Body: newMessageBody(text, mentions)}
+	var comment feedCommentResponse
+	uri := "/feed-elements/" + url.PathEscape(feedElementId) + "/capabilities/comments/items"
+	if err := doChatterRequest(auth, http.MethodPost, uri, input, &comment); err != nil {
+		return FeedComment{}, err
+	}
+	return FeedComment{
+		Id:            comment.Id,
+		FeedElementId: comment.FeedElement.Id,
+		UserId:        comment.User.Id,
+		Text:          comment.Body.Text,
+		CreatedDate:   comment.CreatedDate,
+	}, nil
+}
+
+func doGetFeedElements(auth *authentication, subjectId string) ([]FeedElement, error) {
+	if subjectId == "" {
+		return nil, errors.New("subject id is required")
+	}
+	elements := []FeedElement{}
+	paginator, err := newPaginator(auth, chatterUri+"/feeds/record/"+url.PathEscape(subjectId)+"/feed-elements", dmlOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for paginator.Next() {
+		var page feedElementPage
+		if err := paginator.Decode(&page); err != nil {
+			return nil, err
+		}
+		for _, element := range page.Elements {
+			elements = append(elements, element.toFeedElement())
+		}
+	}
+	if err := paginator.Error(); err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+func doDeleteFeedElement(auth *authentication, feedElementId string) error {
+	if feedElementId == "" {
+		return errors.New("feed element id is required")
+	}
+	return doChatterRequest(auth, http.MethodDelete, "/feed-elements/"+url.PathEscape(feedElementId), nil, nil)
+}
+
+func doSendCustomNotification(auth *authentication, notification CustomNotification) error {
+	if notification.NotificationTypeId == "" {
+		return errors.New("notification type id is required")
+	}
+	if len(notification.RecipientIds) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+	input := customNotificationInput{
+		CustomNotifTypeId: notification.NotificationTypeId,
+		RecipientIds:      notification.RecipientIds,
+		Title:             notification.Title,
+		Body:              notification.Body,
+		TargetId:          notification.TargetId,
+	}
+	_, err := doInvokeStandardAction(auth, customNotificationType, []any{input})
+	return err
+}
@@ -0,0 +1,283 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_newMessageBody(t *testing.T) {
+	got := newMessageBody("Deployment finished", []string{"005000000000001", "0F9000000000001"})
+	want := messageBody{MessageSegments: []messageSegment{
+		{Type: textSegment, Text: "Deployment finished"},
+		{Type: textSegment, Text: " "},
+		{Type: mentionSegment, Id: "005000000000001"},
+		{Type: textSegment, Text: " "},
+		{Type: mentionSegment, Id: "0F9000000000001"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newMessageBody() = %v, want %v", got, want)
+	}
+}
+
+func Test_doPostFeedElement(t *testing.T) {
+	var gotBody feedElementInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/"+apiVersion+"/chatter/feed-elements" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotBody); err != nil {
+			panic(err.Error())
+		}
+		w.WriteHeader(http.StatusCreated)
+		resp := `{"id": "0D5000000000001", "feedElementType": "FeedItem", "parent": {"id": "001000000000001"}, "actor": {"id": "005000000000001"}, "body": {"text": "Sync complete @Tony Stark"}, "createdDate": "2024-01-31T14:30:00.000Z"}`
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	type args struct {
+		auth      *authentication
+		subjectId string
+		text      string
+		mentions  []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    FeedElement
+		wantErr bool
+	}{
+		{
+			name: "post_with_mention",
+			args: args{
+				auth:      &sfAuth,
+				subjectId: "001000000000001",
+				text:      "Sync complete",
+				mentions:  []string{"005000000000002"},
+			},
+			want: FeedElement{
+				Id:              "0D5000000000001",
+				FeedElementType: "FeedItem",
+				SubjectId:       "001000000000001",
+				ActorId:         "005000000000001",
+				Text:            "Sync complete @Tony Stark",
+				CreatedDate:     "2024-01-31T14:30:00.000Z",
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing_subject",
+			args: args{
+				auth: &sfAuth,
+				text: "Sync complete",
+			},
+			want:    FeedElement{},
+			wantErr: true,
+		},
+		{
+			name: "missing_text",
+			args: args{
+				auth:      &sfAuth,
+				subjectId: "001000000000001",
+			},
+			want:    FeedElement{},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:      &badAuth,
+				subjectId: "001000000000001",
+				text:      "Sync complete",
+			},
+			want:    FeedElement{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doPostFeedElement(tt.args.auth, tt.args.subjectId, tt.args.text, tt.args.mentions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doPostFeedElement() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doPostFeedElement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	wantBody := feedElementInput{
+		Body:            newMessageBody("Sync complete", []string{"005000000000002"}),
+		FeedElementType: feedItemType,
+		SubjectId:       "001000000000001",
+	}
+	if !reflect.DeepEqual(gotBody, wantBody) {
+		t.Errorf("doPostFeedElement() body = %v, want %v", gotBody, wantBody)
+	}
+}
+
+func Test_doPostFeedComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/"+apiVersion+"/chatter/feed-elements/0D5000000000001/capabilities/comments/items" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		resp := `{"id": "0D7000000000001", "feedElement": {"id": "0D5000000000001"}, "user": {"id": "005000000000001"}, "body": {"text": "Retrying"}, "createdDate": "2024-01-31T14:31:00.000Z"}`
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := doPostFeedComment(&sfAuth, "0D5000000000001", "Retrying", nil)
+	if err != nil {
+		t.Fatalf("doPostFeedComment() error = %v", err)
+	}
+	want := FeedComment{
+		Id:            "0D7000000000001",
+		FeedElementId: "0D5000000000001",
+		UserId:        "005000000000001",
+		Text:          "Retrying",
+		CreatedDate:   "2024-01-31T14:31:00.000Z",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doPostFeedComment() = %v, want %v", got, want)
+	}
+
+	if _, err := doPostFeedComment(&sfAuth, "", "Retrying", nil); err == nil {
+		t.Errorf("doPostFeedComment() expected error for missing feed element id")
+	}
+}
+
+func Test_doGetFeedElements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp string
+		switch r.URL.Path {
+		case "/services/data/" + apiVersion + "/chatter/feeds/record/001000000000001/feed-elements":
+			if r.URL.Query().Get("page") == "2" {
+				resp = `{"elements": [{"id": "0D5000000000002", "feedElementType": "FeedItem"}], "nextPageUrl": null}`
+			} else {
+				resp = `{"elements": [{"id": "0D5000000000001", "feedElementType": "FeedItem"}], "nextPageUrl": "/services/data/` + apiVersion + `/chatter/feeds/record/001000000000001/feed-elements?page=2"}`
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := doGetFeedElements(&sfAuth, "001000000000001")
+	if err != nil {
+		t.Fatalf("doGetFeedElements() error = %v", err)
+	}
+	want := []FeedElement{
+		{Id: "0D5000000000001", FeedElementType: "FeedItem"},
+		{Id: "0D5000000000002", FeedElementType: "FeedItem"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doGetFeedElements() = %v, want %v", got, want)
+	}
+
+	if _, err := doGetFeedElements(&sfAuth, "001000000000002"); err == nil {
+		t.Errorf("doGetFeedElements() expected error for unknown record")
+	}
+}
+
+func Test_doDeleteFeedElement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/services/data/"+apiVersion+"/chatter/feed-elements/0D5000000000001" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	if err := doDeleteFeedElement(&sfAuth, "0D5000000000001"); err != nil {
+		t.Errorf("doDeleteFeedElement() error = %v", err)
+	}
+	if err := doDeleteFeedElement(&sfAuth, ""); err == nil {
+		t.Errorf("doDeleteFeedElement() expected error for missing feed element id")
+	}
+}
+
+func Test_doSendCustomNotification(t *testing.T) {
+	var gotBody actionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/actions/standard/customNotificationAction" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotBody); err != nil {
+			panic(err.Error())
+		}
+		if _, err := w.Write([]byte(`[{"actionName": "customNotificationAction", "errors": null, "isSuccess": true, "outputValues": {"SuccessMessage": "Your custom notification is processed successfully."}}]`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	notification := CustomNotification{
+		NotificationTypeId: "0ML000000000001",
+		RecipientIds:       []string{"005000000000001"},
+		Title:              "Sync failed",
+		Body:               "3 records failed to sync",
+		TargetId:           "001000000000001",
+	}
+	if err := doSendCustomNotification(&sfAuth, notification); err != nil {
+		t.Fatalf("doSendCustomNotification() error = %v", err)
+	}
+	wantInput := map[string]any{
+		"customNotifTypeId": "0ML000000000001",
+		"recipientIds":      []any{"005000000000001"},
+		"title":             "Sync failed",
+		"body":              "3 records failed to sync",
+		"targetId":          "001000000000001",
+	}
+	if len(gotBody.Inputs) != 1 || !reflect.DeepEqual(gotBody.Inputs[0], wantInput) {
+		t.Errorf("doSendCustomNotification() inputs = %v, want %v", gotBody.Inputs, wantInput)
+	}
+
+	if err := doSendCustomNotification(&sfAuth, CustomNotification{NotificationTypeId: "0ML000000000001"}); err == nil {
+		t.Errorf("doSendCustomNotification() expected error for missing recipients")
+	}
+	if err := doSendCustomNotification(&sfAuth, CustomNotification{RecipientIds: []string{"005000000000001"}}); err == nil {
+		t.Errorf("doSendCustomNotification() expected error for missing notification type")
+	}
+}
@@ -0,0 +1,67 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// encodes and decodes the json of query, retrieve, DML, and composite requests, such as to swap in a faster
+// json library; Unmarshal must not keep a reference to data after it returns, since the buffer is reused
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func WithCodec(codec Codec) Option {
+	return func(config *configuration) error {
+		if codec == nil {
+			return errors.New("codec is nil")
+		}
+		config.jsonCodec = codec
+		return nil
+	}
+}
+
+func (config *configuration) codec() Codec {
+	if config == nil || config.jsonCodec == nil {
+		return jsonCodec{}
+	}
+	return config.jsonCodec
+}
+
+// larger buffers are left for the garbage collector, so one huge response doesn't stay in the pool
+const maxPooledBufferSize = 4 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// reads a response body into a pooled buffer, which is reused once the body has been decoded
+func decodeBody(codec Codec, body io.Reader, v any) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}
+	}()
+	if _, err := buf.ReadFrom(body); err != nil {
+		return err
+	}
+	return codec.Unmarshal(buf.Bytes(), v)
+}
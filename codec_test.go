@@ -0,0 +1,88 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type countingCodec struct {
+	marshals   *atomic.Int64
+	unmarshals *atomic.Int64
+}
+
+func (codec countingCodec) Marshal(v any) ([]byte, error) {
+	codec.marshals.Add(1)
+	return json.Marshal(v)
+}
+
+func (codec countingCodec) Unmarshal(data []byte, v any) error {
+	codec.unmarshals.Add(1)
+	return json.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	codec := countingCodec{marshals: &atomic.Int64{}, unmarshals: &atomic.Int64{}}
+	config, err := newConfiguration(WithCodec(codec))
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+
+	queryServer, queryAuth := setupTestServer(queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "123abc"}},
+	}, http.StatusOK)
+	defer queryServer.Close()
+	queryAuth.config = config
+	records := []map[string]any{}
+	if err := performQuery(&queryAuth, "SELECT Id FROM Account", &records, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	if len(records) != 1 || codec.unmarshals.Load() != 1 {
+		t.Errorf("query decoded %v with %d codec calls, want 1 record and 1 call", records, codec.unmarshals.Load())
+	}
+
+	dmlServer, dmlAuth := setupTestServer([]SalesforceResult{{Id: "123abc", Success: true}}, http.StatusOK)
+	defer dmlServer.Close()
+	dmlAuth.config = config
+	results, err := doInsertCollection(&dmlAuth, "Account", []map[string]any{{"Name": "test"}}, 200, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doInsertCollection() error = %v", err)
+	}
+	if len(results.Results) != 1 || codec.marshals.Load() != 1 || codec.unmarshals.Load() != 2 {
+		t.Errorf("insert used the codec for %d marshals and %d unmarshals", codec.marshals.Load(), codec.unmarshals.Load())
+	}
+
+	oneServer, oneAuth := setupTestServer(SalesforceResult{Id: "123abc", Success: true}, http.StatusCreated)
+	defer oneServer.Close()
+	oneAuth.config = config
+	result, err := doInsertOne(&oneAuth, "Account", map[string]any{"Name": "test"}, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doInsertOne() error = %v", err)
+	}
+	if result.Id != "123abc" || codec.marshals.Load() != 2 || codec.unmarshals.Load() != 3 {
+		t.Errorf("insert one used the codec for %d marshals and %d unmarshals", codec.marshals.Load(), codec.unmarshals.Load())
+	}
+
+	if _, err := newConfiguration(WithCodec(nil)); err == nil {
+		t.Errorf("WithCodec() expected error for nil codec")
+	}
+}
+
+func Test_decodeBody(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		got := map[string]string{}
+		if err := decodeBody(jsonCodec{}, strings.NewReader(`{"Name":"test`+strings.Repeat("a", i)+`"}`), &got); err != nil {
+			t.Fatalf("decodeBody() error = %v", err)
+		}
+		if want := "test" + strings.Repeat("a", i); got["Name"] != want {
+			t.Errorf("decodeBody() = %v, want Name %s", got, want)
+		}
+	}
+	if err := decodeBody(jsonCodec{}, strings.NewReader("not json"), &map[string]any{}); err == nil {
+		t.Errorf("decodeBody() expected error for invalid json")
+	}
+}
@@ -0,0 +1,127 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// returned in a BatchError when salesforce rejects a whole collection request, such as for an unknown field or
+// a value that can't be parsed, instead of failing individual records
+type CollectionError struct {
+	Errors []SalesforceErrorMessage
+	// the fields named by the errors, or the field being parsed when the request couldn't be parsed
+	Fields []string
+	// index of the offending record in the records passed in, or -1 when the error doesn't point to one
+	RecordIndex int
+}
+
+func (e *CollectionError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, sfError := range e.Errors {
+		messages[i] = sfError.ErrorCode + ": " + sfError.Message
+	}
+	message := strings.Join(messages, "; ")
+	if e.RecordIndex >= 0 {
+		message += " (record " + strconv.Itoa(e.RecordIndex)
+		if len(e.Fields) > 0 {
+			message += ", fields " + strings.Join(e.Fields, ", ")
+		}
+		message += ")"
+	} else if len(e.Fields) > 0 {
+		message += " (fields " + strings.Join(e.Fields, ", ") + ")"
+	}
+	return message
+}
+
+var (
+	// JSON_PARSER_ERROR messages end with the position the parser stopped at
+	parserPositionPattern = regexp.MustCompile(`at \[line:(\d+), column:(\d+)\]`)
+	// INVALID_FIELD messages name the field, such as "No such column 'Nmae' on sobject of type Account"
+	noSuchColumnPattern = regexp.MustCompile(`No such column '([^']+)'`)
+	jsonKeyPattern      = regexp.MustCompile(`"((?:[^"\\]|\\.)+)"\s*:`)
+)
+
+// errors for rejected requests carry the response body, which is parsed to find the records and fields at fault;
+// offset is the index of the batch's first record, and any other error is returned as is
+func newCollectionError(err error, body []byte, records []map[string]any, offset int, codec Codec) error {
+	var sfErrors []SalesforceErrorMessage
+	if json.Unmarshal([]byte(err.Error()), &sfErrors) != nil || len(sfErrors) == 0 {
+		return err
+	}
+	collectionErr := &CollectionError{Errors: sfErrors, RecordIndex: -1}
+	for _, sfError := range sfErrors {
+		collectionErr.Fields = appendField(collectionErr.Fields, sfError.Fields...)
+		if match := noSuchColumnPattern.FindStringSubmatch(sfError.Message); match != nil {
+			collectionErr.Fields = appendField(collectionErr.Fields, match[1])
+			if i := recordWithField(records, match[1]); i >= 0 && collectionErr.RecordIndex < 0 {
+				collectionErr.RecordIndex = offset + i
+			}
+		}
+		if match := parserPositionPattern.FindStringSubmatch(sfError.Message); match != nil && match[1] == "1" {
+			column, _ := strconv.Atoi(match[2])
+			if i, field := recordAtPosition(body, records, column-1, codec); i >= 0 {
+				collectionErr.RecordIndex = offset + i
+				if field != "" {
+					collectionErr.Fields = appendField(collectionErr.Fields, field)
+				}
+			}
+		}
+	}
+	return collectionErr
+}
+
+func appendField(fields []string, names ...string) []string {
+	for _, name := range names {
+		if name != "" && !containsFold(fields, name) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordWithField(records []map[string]any, field string) int {
+	for i, record := range records {
+		if _, ok := findKey(record, field); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// finds the record whose encoding spans position in the request body, and the last field before position;
+// the records are encoded again to find where each one starts, giving up if they don't line up with the body
+func recordAtPosition(body []byte, records []map[string]any, position int, codec Codec) (int, string) {
+	start := bytes.Index(body, []byte(`"records":[`))
+	if start < 0 || position < 0 || position >= len(body) {
+		return -1, ""
+	}
+	start += len(`"records":[`)
+	for i, record := range records {
+		encoded, err := codec.Marshal(record)
+		if err != nil || !bytes.HasPrefix(body[start:], encoded) {
+			return -1, ""
+		}
+		end := start + len(encoded)
+		if position < end+1 {
+			matches := jsonKeyPattern.FindAllSubmatch(body[start:min(position+1, end)], -1)
+			if len(matches) == 0 {
+				return i, ""
+			}
+			return i, string(matches[len(matches)-1][1])
+		}
+		start = end + 1
+	}
+	return -1, ""
+}
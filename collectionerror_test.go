@@ -0,0 +1,132 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// rejects collections containing "abc" as unparseable and "Nmae" as an unknown field, like salesforce
+func setupCollectionErrorServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var sfErrors []SalesforceErrorMessage
+		if i := bytes.Index(body, []byte(`"abc"`)); i >= 0 {
+			column := strconv.Itoa(i + len(`"abc"`) + 1)
+			sfErrors = []SalesforceErrorMessage{{
+				Message:   "Cannot deserialize instance of int from VALUE_STRING value abc or request may be missing a required field at [line:1, column:" + column + "]",
+				ErrorCode: "JSON_PARSER_ERROR",
+			}}
+		} else if bytes.Contains(body, []byte(`"Nmae"`)) {
+			sfErrors = []SalesforceErrorMessage{{
+				Message:   "No such column 'Nmae' on sobject of type Account",
+				ErrorCode: "INVALID_FIELD",
+			}}
+		}
+		if sfErrors != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			if err := json.NewEncoder(w).Encode(sfErrors); err != nil {
+				panic(err.Error())
+			}
+			return
+		}
+		collection := sObjectCollection{}
+		if err := json.Unmarshal(body, &collection); err != nil {
+			panic(err.Error())
+		}
+		results := make([]SalesforceResult, len(collection.Records))
+		for i := range results {
+			results[i] = SalesforceResult{Id: "001" + strconv.Itoa(i), Success: true}
+		}
+		if err := json.NewEncoder(w).Encode(results); err != nil {
+			panic(err.Error())
+		}
+	}))
+}
+
+func Test_doInsertCollection_collectionError(t *testing.T) {
+	server := setupCollectionErrorServer()
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	tests := []struct {
+		name    string
+		records []map[string]any
+		want    *CollectionError
+	}{
+		{
+			name: "parser_error",
+			records: []map[string]any{
+				{"Name": "a", "NumberOfEmployees": 1},
+				{"Name": "b", "NumberOfEmployees": 2},
+				{"Name": "c", "NumberOfEmployees": 3},
+				{"Name": "d", "NumberOfEmployees": "abc"},
+			},
+			want: &CollectionError{Fields: []string{"NumberOfEmployees"}, RecordIndex: 3},
+		},
+		{
+			name: "invalid_field",
+			records: []map[string]any{
+				{"Name": "a"},
+				{"Name": "b"},
+				{"Name": "c"},
+				{"Nmae": "d"},
+			},
+			want: &CollectionError{Fields: []string{"Nmae"}, RecordIndex: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := doInsertCollection(&sfAuth, "Account", tt.records, 2, dmlOptions{})
+			var multiErr *MultiError
+			if !errors.As(err, &multiErr) || len(multiErr.Errors) != 1 || multiErr.Errors[0].Batch != 1 {
+				t.Fatalf("doInsertCollection() error = %v, want the second batch to fail", err)
+			}
+			var collectionErr *CollectionError
+			if !errors.As(err, &collectionErr) {
+				t.Fatalf("doInsertCollection() error = %v, want a CollectionError", err)
+			}
+			if !reflect.DeepEqual(collectionErr.Fields, tt.want.Fields) || collectionErr.RecordIndex != tt.want.RecordIndex {
+				t.Errorf("CollectionError = fields %v, record %d, want fields %v, record %d",
+					collectionErr.Fields, collectionErr.RecordIndex, tt.want.Fields, tt.want.RecordIndex)
+			}
+		})
+	}
+}
+
+func Test_newCollectionError(t *testing.T) {
+	records := []map[string]any{{"Name": "a"}}
+	body, _ := json.Marshal(sObjectCollection{Records: records})
+
+	plain := errors.New("500 internal server error")
+	if got := newCollectionError(plain, body, records, 0, jsonCodec{}); got != plain {
+		t.Errorf("newCollectionError() = %v, want errors that aren't salesforce errors returned as is", got)
+	}
+
+	fieldsErr := errors.New(`[{"message":"bad value","errorCode":"INVALID_TYPE","fields":["Name"]}]`)
+	want := &CollectionError{
+		Errors:      []SalesforceErrorMessage{{Message: "bad value", ErrorCode: "INVALID_TYPE", Fields: []string{"Name"}}},
+		Fields:      []string{"Name"},
+		RecordIndex: -1,
+	}
+	got := newCollectionError(fieldsErr, body, records, 0, jsonCodec{})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newCollectionError() = %#v, want %#v", got, want)
+	}
+	if got.Error() != "INVALID_TYPE: bad value (fields Name)" {
+		t.Errorf("Error() = %s", got.Error())
+	}
+
+	// a position past the records doesn't point to one
+	parserErr := errors.New(`[{"message":"unexpected end at [line:1, column:500]","errorCode":"JSON_PARSER_ERROR"}]`)
+	var collectionErr *CollectionError
+	if !errors.As(newCollectionError(parserErr, body, records, 0, jsonCodec{}), &collectionErr) || collectionErr.RecordIndex != -1 {
+		t.Errorf("newCollectionError() = %v, want no record index", collectionErr)
+	}
+}
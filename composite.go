@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"math"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type compositeRequest struct {
@@ -20,10 +23,12 @@ type compositeSubRequest struct {
 	Method      string            `json:"method"`
 	Url         string            `json:"url"`
 	ReferenceId string            `json:"referenceId"`
+	HttpHeaders map[string]string `json:"httpHeaders,omitempty"`
 }
 
 type compositeRequestResult struct {
 	CompositeResponse []compositeSubRequestResult `json:"compositeResponse"`
+	metadata          ResponseMetadata
 }
 
 type compositeSubRequestResult struct {
@@ -33,25 +38,274 @@ type compositeSubRequestResult struct {
 	ReferenceId    string             `json:"referenceId"`
 }
 
-func doCompositeRequest(auth *authentication, compReq compositeRequest) (SalesforceResults, error) {
-	body, jsonErr := json.Marshal(compReq)
+type CompositeBuilder struct {
+	allOrNone   bool
+	subRequests []compositeBuilderSubRequest
+	err         error
+}
+
+type compositeBuilderRequest struct {
+	AllOrNone        bool                         `json:"allOrNone"`
+	CompositeRequest []compositeBuilderSubRequest `json:"compositeRequest"`
+}
+
+type compositeBuilderSubRequest struct {
+	Body        json.RawMessage   `json:"body,omitempty"`
+	Method      string            `json:"method"`
+	Url         string            `json:"url"`
+	ReferenceId string            `json:"referenceId"`
+	HttpHeaders map[string]string `json:"httpHeaders,omitempty"`
+}
+
+type CompositeResults struct {
+	Results          []CompositeResult `json:"compositeResponse"`
+	HasErrors        bool              `json:"-"`
+	ResponseMetadata `json:"-"`
+}
+
+type CompositeResult struct {
+	Body           json.RawMessage   `json:"body"`
+	HttpHeaders    map[string]string `json:"httpHeaders"`
+	HttpStatusCode int               `json:"httpStatusCode"`
+	ReferenceId    string            `json:"referenceId"`
+}
+
+// matches the reference id in expressions such as @{refAccount.id} or @{refQuery.records[0].Id}
+var compositeReferencePattern = regexp.MustCompile(`@\{([^.\[}]+)`)
+
+// maps a retried subrequest back to its original subrequest and record positions
+type lockRetryPosition struct {
+	subRequest int
+	records    []int
+}
+
+func doCompositeRequest(auth *authentication, compReq compositeRequest, options dmlOptions) (SalesforceResults, error) {
+	for i := range compReq.CompositeRequest {
+		compReq.CompositeRequest[i].HttpHeaders = options.headers
+	}
+	compositeResults, err := sendCompositeRequest(auth, compReq, options)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+
+	for attempt := 0; attempt < options.lockRetries; attempt++ {
+		retryReq, positions, retryErr := createLockRetryRequest(compReq, compositeResults)
+		if retryErr != nil {
+			return SalesforceResults{}, retryErr
+		}
+		if len(retryReq.CompositeRequest) == 0 {
+			break
+		}
+		retry := TelemetryRetry{Operation: "POST composite", Reason: retryReasonLockedRecords, Attempt: attempt + 1}
+		for _, position := range positions {
+			retry.Records += len(position.records)
+		}
+		recordRetryTelemetry(auth, retry)
+		time.Sleep(options.lockRetryDelay)
+
+		retryResults, err := sendCompositeRequest(auth, retryReq, options)
+		if err != nil {
+			return SalesforceResults{}, err
+		}
+		mergeLockRetryResults(compositeResults, retryResults, positions)
+		compositeResults.metadata = retryResults.metadata
+	}
+
+	setCompositeResultIndexes(compReq, compositeResults)
+	results := flattenCompositeResults(compositeResults)
+	results.ResponseMetadata = compositeResults.metadata
+	results.SubRequests = newSubRequestResults(compReq, compositeResults)
+	return results, nil
+}
+
+// describes each subrequest's response along with the range of input records it covered
+func newSubRequestResults(compReq compositeRequest, compositeResults compositeRequestResult) []SubRequestResult {
+	var subRequests []SubRequestResult
+	offset := 0
+	for i, subReq := range compReq.CompositeRequest {
+		size := subRequestSize(subReq)
+		if i < len(compositeResults.CompositeResponse) {
+			subResult := compositeResults.CompositeResponse[i]
+			subRequest := SubRequestResult{
+				ReferenceId:    subResult.ReferenceId,
+				HttpStatusCode: subResult.HttpStatusCode,
+				HttpHeaders:    subResult.HttpHeaders,
+				Index:          offset,
+				Size:           size,
+				HasErrors:      subResult.HttpStatusCode >= http.StatusBadRequest,
+			}
+			for _, result := range subResult.Body {
+				subRequest.HasErrors = subRequest.HasErrors || !result.Success
+			}
+			subRequests = append(subRequests, subRequest)
+		}
+		offset += size
+	}
+	return subRequests
+}
+
+func setCompositeResultIndexes(compReq compositeRequest, compositeResults compositeRequestResult) {
+	offset := 0
+	for i, subReq := range compReq.CompositeRequest {
+		if i >= len(compositeResults.CompositeResponse) {
+			break
+		}
+		setResultIndexes(compositeResults.CompositeResponse[i].Body, offset)
+		offset += subRequestSize(subReq)
+	}
+}
+
+func sendCompositeRequest(auth *authentication, compReq compositeRequest, options dmlOptions) (compositeRequestResult, error) {
+	body, jsonErr := auth.config.codec().Marshal(compReq)
 	if jsonErr != nil {
-		return SalesforceResults{}, jsonErr
+		return compositeRequestResult{}, jsonErr
 	}
 	resp, httpErr := doRequest(auth, requestPayload{
 		method:  http.MethodPost,
 		uri:     "/composite",
 		content: jsonType,
 		body:    string(body),
+		timeout: options.timeout,
+		ctx:     options.ctx,
 	})
 	if httpErr != nil {
-		return SalesforceResults{}, httpErr
+		return compositeRequestResult{}, httpErr
 	}
-	results, salesforceErrors := processCompositeResponse(*resp, compReq.AllOrNone)
-	if salesforceErrors != nil {
-		return SalesforceResults{}, salesforceErrors
+	compositeResults, err := parseCompositeResponse(auth.config.codec(), *resp)
+	if err != nil {
+		return compositeRequestResult{}, err
+	}
+	orderCompositeResults(compReq, compositeResults)
+
+	// each record result reports the status of its subrequest, along with the composite response's headers
+	compositeResults.metadata = newResponseMetadata(resp)
+	for _, subResult := range compositeResults.CompositeResponse {
+		metadata := compositeResults.metadata
+		metadata.StatusCode = subResult.HttpStatusCode
+		setResponseMetadata(subResult.Body, metadata)
+	}
+	return compositeResults, nil
+}
+
+// results are matched to their subrequests by position, so put the responses in the order of the
+// subrequests; they are left as they are if the reference ids don't match up one to one
+func orderCompositeResults(compReq compositeRequest, compositeResults compositeRequestResult) {
+	if len(compReq.CompositeRequest) != len(compositeResults.CompositeResponse) {
+		return
+	}
+	byReference := make(map[string]compositeSubRequestResult, len(compositeResults.CompositeResponse))
+	for _, subResult := range compositeResults.CompositeResponse {
+		byReference[subResult.ReferenceId] = subResult
+	}
+	ordered := make([]compositeSubRequestResult, 0, len(compReq.CompositeRequest))
+	for _, subReq := range compReq.CompositeRequest {
+		subResult, ok := byReference[subReq.ReferenceId]
+		if !ok {
+			return
+		}
+		delete(byReference, subReq.ReferenceId)
+		ordered = append(ordered, subResult)
+	}
+	copy(compositeResults.CompositeResponse, ordered)
+}
+
+func isLockError(result SalesforceResult) bool {
+	for _, err := range result.Errors {
+		if err.StatusCode == unableToLockRowError || err.ErrorCode == unableToLockRowError {
+			return true
+		}
+	}
+	return false
+}
+
+func subsetSubRequest(subReq compositeSubRequest, positions []int) (compositeSubRequest, error) {
+	if subReq.Method != http.MethodDelete {
+		records := make([]map[string]any, 0, len(positions))
+		for _, position := range positions {
+			records = append(records, subReq.Body.Records[position])
+		}
+		subReq.Body.Records = records
+		return subReq, nil
+	}
+
+	// delete subrequests carry their record ids in the url rather than the body
+	subReqUrl, err := url.Parse(subReq.Url)
+	if err != nil {
+		return compositeSubRequest{}, err
+	}
+	query := subReqUrl.Query()
+	ids := strings.Split(query.Get("ids"), ",")
+	retryIds := make([]string, 0, len(positions))
+	for _, position := range positions {
+		retryIds = append(retryIds, ids[position])
+	}
+	subReq.Url = subReqUrl.Path + "?ids=" + strings.Join(retryIds, ",") + "&allOrNone=" + query.Get("allOrNone")
+	return subReq, nil
+}
+
+func subRequestSize(subReq compositeSubRequest) int {
+	if subReq.Method != http.MethodDelete {
+		return len(subReq.Body.Records)
+	}
+	subReqUrl, err := url.Parse(subReq.Url)
+	if err != nil {
+		return 0
+	}
+	return len(strings.Split(subReqUrl.Query().Get("ids"), ","))
+}
+
+func createLockRetryRequest(compReq compositeRequest, compositeResults compositeRequestResult) (compositeRequest, []lockRetryPosition, error) {
+	retryReq := compositeRequest{AllOrNone: compReq.AllOrNone}
+	var positions []lockRetryPosition
+
+	locked := false
+	for _, subResult := range compositeResults.CompositeResponse {
+		for _, result := range subResult.Body {
+			locked = locked || isLockError(result)
+		}
+	}
+	if !locked {
+		return retryReq, positions, nil
+	}
+
+	for i, subReq := range compReq.CompositeRequest {
+		if i >= len(compositeResults.CompositeResponse) {
+			break
+		}
+		size := subRequestSize(subReq)
+		body := compositeResults.CompositeResponse[i].Body
+		var retryPositions []int
+		for j := 0; j < size && j < len(body); j++ {
+			// a lock failure rolls back every record when the request is atomic
+			if !body[j].Success && (compReq.AllOrNone || subReq.Body.AllOrNone || isLockError(body[j])) {
+				retryPositions = append(retryPositions, j)
+			}
+		}
+		if len(retryPositions) == 0 {
+			continue
+		}
+		retrySubReq, err := subsetSubRequest(subReq, retryPositions)
+		if err != nil {
+			return compositeRequest{}, nil, err
+		}
+		retryReq.CompositeRequest = append(retryReq.CompositeRequest, retrySubReq)
+		positions = append(positions, lockRetryPosition{subRequest: i, records: retryPositions})
+	}
+	return retryReq, positions, nil
+}
+
+func mergeLockRetryResults(compositeResults compositeRequestResult, retryResults compositeRequestResult, positions []lockRetryPosition) {
+	for k, subResult := range retryResults.CompositeResponse {
+		if k >= len(positions) {
+			break
+		}
+		original := compositeResults.CompositeResponse[positions[k].subRequest]
+		for m, result := range subResult.Body {
+			if m < len(positions[k].records) {
+				original.Body[positions[k].records[m]] = result
+			}
+		}
 	}
-	return results, nil
 }
 
 func validateNumberOfSubrequests(dataSize int, batchSize int) error {
@@ -102,19 +356,17 @@ func createCompositeRequestForCollection(method string, url string, allOrNone bo
 	}, nil
 }
 
-func processCompositeResponse(resp http.Response, allOrNone bool) (SalesforceResults, error) {
+func parseCompositeResponse(codec Codec, resp http.Response) (compositeRequestResult, error) {
 	compositeResults := compositeRequestResult{}
-	results := SalesforceResults{}
-
-	responseData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return SalesforceResults{}, err
-	}
-	jsonError := json.Unmarshal(responseData, &compositeResults)
-	if jsonError != nil {
-		return SalesforceResults{}, jsonError
+	if err := decodeBody(codec, resp.Body, &compositeResults); err != nil {
+		return compositeRequestResult{}, err
 	}
 
+	return compositeResults, nil
+}
+
+func flattenCompositeResults(compositeResults compositeRequestResult) SalesforceResults {
+	results := SalesforceResults{}
 	for _, subResult := range compositeResults.CompositeResponse {
 		for _, result := range subResult.Body {
 			if !result.Success {
@@ -123,15 +375,18 @@ func processCompositeResponse(resp http.Response, allOrNone bool) (SalesforceRes
 		}
 		results.Results = append(results.Results, subResult.Body...)
 	}
-
-	return results, nil
+	return results
 }
 
-func doInsertComposite(auth *authentication, sObjectName string, records any, allOrNone bool, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doInsertComposite(auth *authentication, sObjectName string, records any, allOrNone bool, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	allOrNone = allOrNone || options.allOrNone
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return SalesforceResults{}, err
+	}
 
 	for i := range recordMap {
 		delete(recordMap[i], "Id")
@@ -143,7 +398,7 @@ func doInsertComposite(auth *authentication, sObjectName string, records any, al
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(auth, compReq)
+	results, compositeReqErr := doCompositeRequests(auth, compReq, options)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
@@ -151,13 +406,18 @@ func doInsertComposite(auth *authentication, sObjectName string, records any, al
 	return results, nil
 }
 
-func doUpdateComposite(auth *authentication, sObjectName string, records any, allOrNone bool, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doUpdateComposite(auth *authentication, sObjectName string, records any, allOrNone bool, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	allOrNone = allOrNone || options.allOrNone
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return SalesforceResults{}, err
+	}
 
 	for i := range recordMap {
+		setFieldsToNull(recordMap[i], options.fieldsToNull)
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 		recordId, ok := recordMap[i]["Id"].(string)
 		if !ok || recordId == "" {
@@ -170,7 +430,7 @@ func doUpdateComposite(auth *authentication, sObjectName string, records any, al
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(auth, compReq)
+	results, compositeReqErr := doCompositeRequests(auth, compReq, options)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
@@ -178,13 +438,18 @@ func doUpdateComposite(auth *authentication, sObjectName string, records any, al
 	return results, nil
 }
 
-func doUpsertComposite(auth *authentication, sObjectName string, fieldName string, records any, allOrNone bool, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doUpsertComposite(auth *authentication, sObjectName string, fieldName string, records any, allOrNone bool, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	allOrNone = allOrNone || options.allOrNone
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return SalesforceResults{}, err
+	}
 
 	for i := range recordMap {
+		setFieldsToNull(recordMap[i], options.fieldsToNull)
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 		externalIdValue, ok := recordMap[i][fieldName].(string)
 		if !ok || externalIdValue == "" {
@@ -197,7 +462,7 @@ func doUpsertComposite(auth *authentication, sObjectName string, fieldName strin
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(auth, compReq)
+	results, compositeReqErr := doCompositeRequests(auth, compReq, options)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
@@ -205,8 +470,9 @@ func doUpsertComposite(auth *authentication, sObjectName string, fieldName strin
 	return results, nil
 }
 
-func doDeleteComposite(auth *authentication, sObjectName string, records any, allOrNone bool, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doDeleteComposite(auth *authentication, sObjectName string, records any, allOrNone bool, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	allOrNone = allOrNone || options.allOrNone
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -250,10 +516,130 @@ func doDeleteComposite(auth *authentication, sObjectName string, records any, al
 		AllOrNone:        allOrNone,
 		CompositeRequest: subReqs,
 	}
-	results, compositeReqErr := doCompositeRequest(auth, compReq)
+	results, compositeReqErr := doCompositeRequests(auth, compReq, options)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
 
+	if options.ignoreDeleted {
+		return ignoreDeletedResults(results), nil
+	}
+
+	return results, nil
+}
+
+func NewCompositeBuilder(allOrNone bool) *CompositeBuilder {
+	return &CompositeBuilder{allOrNone: allOrNone}
+}
+
+// adds a subrequest; path is relative to /services/data/apiVersion unless it starts with /services/
+func (builder *CompositeBuilder) Add(method string, path string, body any, referenceId string) *CompositeBuilder {
+	if builder.err != nil {
+		return builder
+	}
+	builder.err = builder.add(method, path, body, referenceId)
+	return builder
+}
+
+func (builder *CompositeBuilder) add(method string, path string, body any, referenceId string) error {
+	if len(builder.subRequests) >= 25 {
+		return errors.New("composite requests are limited to 25 subrequests")
+	}
+	if method == "" || path == "" {
+		return errors.New("subrequest method and path are required")
+	}
+	if referenceId == "" {
+		return errors.New("subrequest reference id is required")
+	}
+	if builder.hasReference(referenceId) {
+		return errors.New("duplicate subrequest reference id: " + referenceId)
+	}
+
+	subReq := compositeBuilderSubRequest{
+		Method:      method,
+		Url:         path,
+		ReferenceId: referenceId,
+	}
+	if !strings.HasPrefix(path, "/services/") {
+		subReq.Url = "/services/data/" + apiVersion + path
+	}
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		subReq.Body = jsonBody
+	}
+
+	// references can only point to subrequests that run earlier
+	for _, text := range []string{subReq.Url, string(subReq.Body)} {
+		for _, match := range compositeReferencePattern.FindAllStringSubmatch(text, -1) {
+			if !builder.hasReference(match[1]) {
+				return errors.New("subrequest " + referenceId + " references unknown or later subrequest: " + match[1])
+			}
+		}
+	}
+
+	builder.subRequests = append(builder.subRequests, subReq)
+	return nil
+}
+
+func (builder *CompositeBuilder) hasReference(referenceId string) bool {
+	for _, subReq := range builder.subRequests {
+		if subReq.ReferenceId == referenceId {
+			return true
+		}
+	}
+	return false
+}
+
+func (result CompositeResult) Decode(v any) error {
+	if len(result.Body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(result.Body, v)
+}
+
+func doExecuteComposite(auth *authentication, builder *CompositeBuilder, options dmlOptions) (CompositeResults, error) {
+	if builder == nil || len(builder.subRequests) == 0 {
+		return CompositeResults{}, errors.New("composite request has no subrequests")
+	}
+	if builder.err != nil {
+		return CompositeResults{}, builder.err
+	}
+	for i := range builder.subRequests {
+		builder.subRequests[i].HttpHeaders = options.headers
+	}
+
+	body, jsonErr := auth.config.codec().Marshal(compositeBuilderRequest{
+		AllOrNone:        builder.allOrNone || options.allOrNone,
+		CompositeRequest: builder.subRequests,
+	})
+	if jsonErr != nil {
+		return CompositeResults{}, jsonErr
+	}
+	resp, httpErr := doRequest(auth, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/composite",
+		content: jsonType,
+		body:    string(body),
+		timeout: options.timeout,
+		ctx:     options.ctx,
+	})
+	if httpErr != nil {
+		return CompositeResults{}, httpErr
+	}
+	defer resp.Body.Close()
+
+	results := CompositeResults{}
+	if err := decodeBody(auth.config.codec(), resp.Body, &results); err != nil {
+		return CompositeResults{}, err
+	}
+	for _, result := range results.Results {
+		if result.HttpStatusCode >= http.StatusBadRequest {
+			results.HasErrors = true
+		}
+	}
+	results.ResponseMetadata = newResponseMetadata(resp)
 	return results, nil
 }
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
 )
 
@@ -153,7 +155,7 @@ func Test_createCompositeRequestForCollection(t *testing.T) {
 	}
 }
 
-func Test_processCompositeResponse(t *testing.T) {
+func Test_parseCompositeResponse(t *testing.T) {
 	message := []SalesforceErrorMessage{{
 		Message:    "example error",
 		StatusCode: "500",
@@ -208,8 +210,7 @@ func Test_processCompositeResponse(t *testing.T) {
 	}
 
 	type args struct {
-		resp      http.Response
-		allOrNone bool
+		resp http.Response
 	}
 	tests := []struct {
 		name    string
@@ -242,12 +243,16 @@ func Test_processCompositeResponse(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processCompositeResponse(tt.args.resp, tt.args.allOrNone)
+			codec := countingCodec{marshals: &atomic.Int64{}, unmarshals: &atomic.Int64{}}
+			compositeResults, err := parseCompositeResponse(codec, tt.args.resp)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("processCompositeResponse() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("parseCompositeResponse() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("processCompositeResponse() = %v, want %v", got, tt.want)
+			if codec.unmarshals.Load() != 1 {
+				t.Errorf("parseCompositeResponse() decoded with the codec %d times, want 1", codec.unmarshals.Load())
+			}
+			if got := flattenCompositeResults(compositeResults); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flattenCompositeResults() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -270,13 +275,25 @@ func Test_doCompositeRequest(t *testing.T) {
 				Message:    "error",
 				StatusCode: "500",
 			}},
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusBadRequest},
 		}},
 		HasSalesforceErrors: true,
+		SubRequests: []SubRequestResult{{
+			ReferenceId:    "sobject",
+			HttpStatusCode: http.StatusBadRequest,
+			HttpHeaders:    map[string]string{},
+			Size:           1,
+			HasErrors:      true,
+		}},
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	compReqResultFail := compositeRequestResult{
 		CompositeResponse: []compositeSubRequestResult{{
-			Body:           sfResultsFail.Results,
+			Body: []SalesforceResult{{
+				Success: false,
+				Errors:  sfResultsFail.Results[0].Errors,
+			}},
 			HttpHeaders:    map[string]string{},
 			HttpStatusCode: http.StatusBadRequest,
 			ReferenceId:    "sobject",
@@ -322,8 +339,15 @@ func Test_doCompositeRequest(t *testing.T) {
 				compReq: compReq,
 			},
 			want: SalesforceResults{
-				Results:             []SalesforceResult{{Success: true}},
+				Results:             []SalesforceResult{{Success: true, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK}}},
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests: []SubRequestResult{{
+					ReferenceId:    "sobject",
+					HttpStatusCode: http.StatusOK,
+					HttpHeaders:    map[string]string{},
+					Size:           1,
+				}},
 			},
 			wantErr: false,
 		},
@@ -348,7 +372,7 @@ func Test_doCompositeRequest(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doCompositeRequest(tt.args.auth, tt.args.compReq)
+			got, err := doCompositeRequest(tt.args.auth, tt.args.compReq, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doCompositeRequest() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -409,6 +433,32 @@ func Test_doInsertComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "successful_insert_composite_maps",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+				records: []map[string]any{
+					{
+						"Name": "test account 1",
+					},
+					{
+						"Name": "test account 2",
+					},
+				},
+				batchSize: 200,
+				allOrNone: true,
+			},
+			want: SalesforceResults{
+				Results:             compResult.CompositeResponse[0].Body,
+				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -427,7 +477,7 @@ func Test_doInsertComposite(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doInsertComposite(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.allOrNone, tt.args.batchSize)
+			got, err := doInsertComposite(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.allOrNone, tt.args.batchSize, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doInsertComposite() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -438,6 +488,37 @@ func Test_doInsertComposite(t *testing.T) {
 	}
 }
 
+func Test_doInsertComposite_assignmentRule(t *testing.T) {
+	var got compositeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err.Error())
+		}
+		body, _ := json.Marshal(compositeRequestResult{})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	records := []map[string]any{{"Subject": "a"}, {"Subject": "b"}}
+	if _, err := doInsertComposite(&sfAuth, "Case", records, false, 1, newDMLOptions(WithAutoAssign(true))); err != nil {
+		t.Fatalf("doInsertComposite() error = %v", err)
+	}
+	if len(got.CompositeRequest) != 2 {
+		t.Fatalf("doInsertComposite() sent %d subrequests, want 2", len(got.CompositeRequest))
+	}
+	for _, subRequest := range got.CompositeRequest {
+		if subRequest.HttpHeaders["Sforce-Auto-Assign"] != "TRUE" {
+			t.Errorf("doInsertComposite() subrequest headers = %v, want Sforce-Auto-Assign: TRUE", subRequest.HttpHeaders)
+		}
+	}
+}
+
 func Test_doUpdateComposite(t *testing.T) {
 	type account struct {
 		Id   string
@@ -491,6 +572,8 @@ func Test_doUpdateComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -525,7 +608,7 @@ func Test_doUpdateComposite(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doUpdateComposite(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.allOrNone, tt.args.batchSize)
+			got, err := doUpdateComposite(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.allOrNone, tt.args.batchSize, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doUpdateComposite() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -591,6 +674,8 @@ func Test_doUpsertComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -627,7 +712,7 @@ func Test_doUpsertComposite(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doUpsertComposite(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.records, tt.args.allOrNone, tt.args.batchSize)
+			got, err := doUpsertComposite(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.records, tt.args.allOrNone, tt.args.batchSize, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doUpsertComposite() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -688,6 +773,8 @@ func Test_doDeleteComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -710,6 +797,8 @@ func Test_doDeleteComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 1}},
 			},
 			wantErr: false,
 		},
@@ -740,7 +829,7 @@ func Test_doDeleteComposite(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doDeleteComposite(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.allOrNone, tt.args.batchSize)
+			got, err := doDeleteComposite(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.allOrNone, tt.args.batchSize, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doDeleteComposite() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -750,3 +839,482 @@ func Test_doDeleteComposite(t *testing.T) {
 		})
 	}
 }
+
+func Test_doCompositeRequest_lockRetry(t *testing.T) {
+	lockError := SalesforceResult{
+		Success: false,
+		Errors: []SalesforceErrorMessage{{
+			Message:    "unable to obtain exclusive access to this record",
+			StatusCode: unableToLockRowError,
+		}},
+	}
+	responses := []compositeRequestResult{
+		{CompositeResponse: []compositeSubRequestResult{{
+			Body:           []SalesforceResult{{Id: "1", Success: true}, lockError},
+			HttpStatusCode: http.StatusOK,
+			ReferenceId:    "refObj0",
+		}}},
+		{CompositeResponse: []compositeSubRequestResult{{
+			Body:           []SalesforceResult{{Id: "2", Success: true}},
+			HttpStatusCode: http.StatusOK,
+			ReferenceId:    "refObj0",
+		}}},
+	}
+	var requests []compositeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var compReq compositeRequest
+		if err := json.NewDecoder(r.Body).Decode(&compReq); err != nil {
+			t.Fatal(err.Error())
+		}
+		requests = append(requests, compReq)
+		body, _ := json.Marshal(responses[len(requests)-1])
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	compReq := compositeRequest{
+		CompositeRequest: []compositeSubRequest{{
+			Body: sObjectCollection{
+				Records: []map[string]any{{"Name": "a"}, {"Name": "b"}},
+			},
+			Method:      http.MethodPost,
+			Url:         "endpoint/",
+			ReferenceId: "refObj0",
+		}},
+	}
+
+	got, err := doCompositeRequest(&sfAuth, compReq, dmlOptions{lockRetries: 2})
+	if err != nil {
+		t.Fatalf("doCompositeRequest() error = %v", err)
+	}
+	// the retried record reports the metadata of the retry response
+	okMetadata := ResponseMetadata{StatusCode: http.StatusOK}
+	want := SalesforceResults{
+		Results: []SalesforceResult{
+			{Id: "1", Success: true, ResponseMetadata: okMetadata},
+			{Id: "2", Success: true, Index: 1, ResponseMetadata: okMetadata},
+		},
+		SubRequests:      []SubRequestResult{{ReferenceId: "refObj0", HttpStatusCode: http.StatusOK, Size: 2}},
+		ResponseMetadata: okMetadata,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doCompositeRequest() = %v, want %v", got, want)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("doCompositeRequest() sent %d requests, want 2", len(requests))
+	}
+	wantRecords := []map[string]any{{"Name": "b"}}
+	if !reflect.DeepEqual(requests[1].CompositeRequest[0].Body.Records, wantRecords) {
+		t.Errorf("doCompositeRequest() retried %v, want %v", requests[1].CompositeRequest[0].Body.Records, wantRecords)
+	}
+}
+
+func Test_subsetSubRequest(t *testing.T) {
+	type args struct {
+		subReq    compositeSubRequest
+		positions []int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    compositeSubRequest
+		wantErr bool
+	}{
+		{
+			name: "collection",
+			args: args{
+				subReq: compositeSubRequest{
+					Body:   sObjectCollection{Records: []map[string]any{{"Id": "1"}, {"Id": "2"}, {"Id": "3"}}},
+					Method: http.MethodPatch,
+				},
+				positions: []int{0, 2},
+			},
+			want: compositeSubRequest{
+				Body:   sObjectCollection{Records: []map[string]any{{"Id": "1"}, {"Id": "3"}}},
+				Method: http.MethodPatch,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete",
+			args: args{
+				subReq: compositeSubRequest{
+					Method: http.MethodDelete,
+					Url:    "/services/data/" + apiVersion + "/composite/sobjects/?ids=1,2,3&allOrNone=false",
+				},
+				positions: []int{1},
+			},
+			want: compositeSubRequest{
+				Method: http.MethodDelete,
+				Url:    "/services/data/" + apiVersion + "/composite/sobjects/?ids=2&allOrNone=false",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := subsetSubRequest(tt.args.subReq, tt.args.positions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("subsetSubRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subsetSubRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setCompositeResultIndexes(t *testing.T) {
+	compReq := compositeRequest{
+		CompositeRequest: []compositeSubRequest{
+			{
+				Body:   sObjectCollection{Records: []map[string]any{{"Name": "a"}, {"Name": "b"}}},
+				Method: http.MethodPost,
+			},
+			{
+				Method: http.MethodDelete,
+				Url:    "/services/data/v62.0/composite/sobjects/?ids=003A,003B,003C&allOrNone=false",
+			},
+			{
+				Body:   sObjectCollection{Records: []map[string]any{{"Name": "c"}}},
+				Method: http.MethodPost,
+			},
+		},
+	}
+	compositeResults := compositeRequestResult{
+		CompositeResponse: []compositeSubRequestResult{
+			{Body: []SalesforceResult{{Id: "1"}, {Id: "2"}}},
+			{Body: []SalesforceResult{{Id: "003A"}, {Id: "003B"}, {Id: "003C"}}},
+			{Body: []SalesforceResult{{Id: "3"}}},
+		},
+	}
+
+	setCompositeResultIndexes(compReq, compositeResults)
+	var got []int
+	for _, result := range flattenCompositeResults(compositeResults).Results {
+		got = append(got, result.Index)
+	}
+	want := []int{0, 1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("setCompositeResultIndexes() = %v, want %v", got, want)
+	}
+}
+
+func Test_orderCompositeResults(t *testing.T) {
+	compReq := compositeRequest{
+		CompositeRequest: []compositeSubRequest{
+			{ReferenceId: "refObj0"},
+			{ReferenceId: "refObj1"},
+			{ReferenceId: "refObj2"},
+		},
+	}
+	tests := []struct {
+		name      string
+		responses []string
+		want      []string
+	}{
+		{
+			name:      "out_of_order",
+			responses: []string{"refObj2", "refObj0", "refObj1"},
+			want:      []string{"refObj0", "refObj1", "refObj2"},
+		},
+		{
+			name:      "in_order",
+			responses: []string{"refObj0", "refObj1", "refObj2"},
+			want:      []string{"refObj0", "refObj1", "refObj2"},
+		},
+		{
+			name:      "unknown_reference",
+			responses: []string{"refObj2", "other", "refObj1"},
+			want:      []string{"refObj2", "other", "refObj1"},
+		},
+		{
+			name:      "missing_response",
+			responses: []string{"refObj1", "refObj0"},
+			want:      []string{"refObj1", "refObj0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compositeResults := compositeRequestResult{}
+			for _, referenceId := range tt.responses {
+				compositeResults.CompositeResponse = append(compositeResults.CompositeResponse, compositeSubRequestResult{ReferenceId: referenceId})
+			}
+			orderCompositeResults(compReq, compositeResults)
+			var got []string
+			for _, subResult := range compositeResults.CompositeResponse {
+				got = append(got, subResult.ReferenceId)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderCompositeResults() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doInsertComposite_outOfOrderResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		compReq := compositeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&compReq); err != nil {
+			panic(err.Error())
+		}
+		compResult := compositeRequestResult{}
+		for i := len(compReq.CompositeRequest) - 1; i >= 0; i-- {
+			subReq := compReq.CompositeRequest[i]
+			subResult := compositeSubRequestResult{ReferenceId: subReq.ReferenceId, HttpStatusCode: http.StatusOK}
+			for _, record := range subReq.Body.Records {
+				name := record["Name"].(string)
+				subResult.Body = append(subResult.Body, SalesforceResult{Id: "001" + name, Success: name != "b"})
+			}
+			compResult.CompositeResponse = append(compResult.CompositeResponse, subResult)
+		}
+		if err := json.NewEncoder(w).Encode(compResult); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	records := []map[string]any{{"Name": "a"}, {"Name": "b"}, {"Name": "c"}}
+	results, err := doInsertComposite(&sfAuth, "Account", records, false, 1, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doInsertComposite() error = %v", err)
+	}
+	var ids []string
+	for i, result := range results.Results {
+		if result.Index != i {
+			t.Errorf("doInsertComposite() result %d has index %d", i, result.Index)
+		}
+		ids = append(ids, result.Id)
+	}
+	if want := []string{"001a", "001b", "001c"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("doInsertComposite() ids = %v, want %v", ids, want)
+	}
+	failed := results.Failed()
+	if len(failed) != 1 || failed[0].Index != 1 {
+		t.Errorf("Failed() = %v, want the result of record 1", failed)
+	}
+}
+
+func Test_newSubRequestResults(t *testing.T) {
+	compReq := compositeRequest{
+		CompositeRequest: []compositeSubRequest{
+			{
+				Body:        sObjectCollection{Records: []map[string]any{{"Name": "a"}, {"Name": "b"}}},
+				Method:      http.MethodPost,
+				ReferenceId: "refObj0",
+			},
+			{
+				Method:      http.MethodDelete,
+				Url:         "/services/data/v62.0/composite/sobjects/?ids=003A,003B,003C&allOrNone=false",
+				ReferenceId: "refObj1",
+			},
+			{
+				Body:        sObjectCollection{Records: []map[string]any{{"Name": "c"}}},
+				Method:      http.MethodPost,
+				ReferenceId: "refObj2",
+			},
+		},
+	}
+	compositeResults := compositeRequestResult{
+		CompositeResponse: []compositeSubRequestResult{
+			{Body: []SalesforceResult{{Id: "1", Success: true}, {Id: "2", Success: true}}, HttpStatusCode: http.StatusOK, ReferenceId: "refObj0"},
+			{Body: []SalesforceResult{{Id: "003A", Success: true}, {Id: "003B"}, {Id: "003C", Success: true}}, HttpStatusCode: http.StatusOK, ReferenceId: "refObj1"},
+			{Body: []SalesforceResult{{}}, HttpStatusCode: http.StatusBadRequest, ReferenceId: "refObj2"},
+		},
+	}
+
+	got := newSubRequestResults(compReq, compositeResults)
+	want := []SubRequestResult{
+		{ReferenceId: "refObj0", HttpStatusCode: http.StatusOK, Index: 0, Size: 2},
+		{ReferenceId: "refObj1", HttpStatusCode: http.StatusOK, Index: 2, Size: 3, HasErrors: true},
+		{ReferenceId: "refObj2", HttpStatusCode: http.StatusBadRequest, Index: 5, Size: 1, HasErrors: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newSubRequestResults() = %v, want %v", got, want)
+	}
+}
+
+func TestCompositeBuilder_Add(t *testing.T) {
+	type subRequest struct {
+		method      string
+		path        string
+		body        any
+		referenceId string
+	}
+	tests := []struct {
+		name        string
+		subRequests []subRequest
+		wantUrls    []string
+		wantErr     bool
+	}{
+		{
+			name: "reference_chaining",
+			subRequests: []subRequest{
+				{http.MethodPost, "/sobjects/Account", map[string]any{"Name": "Stark Industries"}, "refAccount"},
+				{http.MethodPost, "/sobjects/Contact", map[string]any{"LastName": "Stark", "AccountId": "@{refAccount.id}"}, "refContact"},
+				{http.MethodGet, "/services/data/" + apiVersion + "/sobjects/Contact/@{refContact.id}", nil, "refGet"},
+			},
+			wantUrls: []string{
+				"/services/data/" + apiVersion + "/sobjects/Account",
+				"/services/data/" + apiVersion + "/sobjects/Contact",
+				"/services/data/" + apiVersion + "/sobjects/Contact/@{refContact.id}",
+			},
+			wantErr: false,
+		},
+		{
+			name: "query_reference",
+			subRequests: []subRequest{
+				{http.MethodGet, "/query/?q=SELECT+Id+FROM+Account+LIMIT+1", nil, "refQuery"},
+				{http.MethodDelete, "/sobjects/Account/@{refQuery.records[0].Id}", nil, "refDelete"},
+			},
+			wantUrls: []string{
+				"/services/data/" + apiVersion + "/query/?q=SELECT+Id+FROM+Account+LIMIT+1",
+				"/services/data/" + apiVersion + "/sobjects/Account/@{refQuery.records[0].Id}",
+			},
+			wantErr: false,
+		},
+		{
+			name: "forward_reference",
+			subRequests: []subRequest{
+				{http.MethodPost, "/sobjects/Contact", map[string]any{"AccountId": "@{refAccount.id}"}, "refContact"},
+				{http.MethodPost, "/sobjects/Account", map[string]any{"Name": "Stark Industries"}, "refAccount"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate_reference_id",
+			subRequests: []subRequest{
+				{http.MethodPost, "/sobjects/Account", map[string]any{"Name": "Stark Industries"}, "refAccount"},
+				{http.MethodPost, "/sobjects/Account", map[string]any{"Name": "Wayne Enterprises"}, "refAccount"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing_reference_id",
+			subRequests: []subRequest{
+				{http.MethodPost, "/sobjects/Account", map[string]any{"Name": "Stark Industries"}, ""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unmarshalable_body",
+			subRequests: []subRequest{
+				{http.MethodPost, "/sobjects/Account", map[string]any{"Name": make(chan int)}, "refAccount"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewCompositeBuilder(true)
+			for _, subReq := range tt.subRequests {
+				builder.Add(subReq.method, subReq.path, subReq.body, subReq.referenceId)
+			}
+			if (builder.err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", builder.err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var urls []string
+			for _, subReq := range builder.subRequests {
+				urls = append(urls, subReq.Url)
+			}
+			if !reflect.DeepEqual(urls, tt.wantUrls) {
+				t.Errorf("Add() urls = %v, want %v", urls, tt.wantUrls)
+			}
+		})
+	}
+
+	builder := NewCompositeBuilder(false)
+	for i := 0; i < 26; i++ {
+		builder.Add(http.MethodGet, "/limits", nil, fmt.Sprintf("ref%d", i))
+	}
+	if builder.err == nil || len(builder.subRequests) != 25 {
+		t.Errorf("Add() expected error after 25 subrequests, got %d subrequests", len(builder.subRequests))
+	}
+}
+
+func Test_doExecuteComposite(t *testing.T) {
+	var gotRequest compositeBuilderRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := map[string]any{
+			"compositeResponse": []map[string]any{
+				{
+					"body":           map[string]any{"id": "001000000000001", "success": true, "errors": []any{}},
+					"httpHeaders":    map[string]string{"Location": "/services/data/" + apiVersion + "/sobjects/Account/001000000000001"},
+					"httpStatusCode": http.StatusCreated,
+					"referenceId":    "refAccount",
+				},
+				{
+					"body":           []map[string]any{{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing: [LastName]"}},
+					"httpHeaders":    map[string]string{},
+					"httpStatusCode": http.StatusBadRequest,
+					"referenceId":    "refContact",
+				},
+			},
+		}
+		body, _ := json.Marshal(resp)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	builder := NewCompositeBuilder(false).
+		Add(http.MethodPost, "/sobjects/Account", map[string]any{"Name": "Stark Industries"}, "refAccount").
+		Add(http.MethodPost, "/sobjects/Contact", map[string]any{"AccountId": "@{refAccount.id}"}, "refContact")
+
+	results, err := doExecuteComposite(&sfAuth, builder, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doExecuteComposite() error = %v", err)
+	}
+	if len(gotRequest.CompositeRequest) != 2 || string(gotRequest.CompositeRequest[1].Body) != `{"AccountId":"@{refAccount.id}"}` {
+		t.Errorf("doExecuteComposite() sent %v", gotRequest)
+	}
+	if !results.HasErrors || len(results.Results) != 2 {
+		t.Fatalf("doExecuteComposite() = %v", results)
+	}
+	created := SalesforceResult{}
+	if err := results.Results[0].Decode(&created); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if created.Id != "001000000000001" || !created.Success {
+		t.Errorf("Decode() = %v", created)
+	}
+	if results.Results[1].ReferenceId != "refContact" || results.Results[1].HttpStatusCode != http.StatusBadRequest {
+		t.Errorf("doExecuteComposite() second result = %v", results.Results[1])
+	}
+
+	if _, err := doExecuteComposite(&badSfAuth, builder, dmlOptions{}); err == nil {
+		t.Errorf("doExecuteComposite() expected error for bad request")
+	}
+	if _, err := doExecuteComposite(&sfAuth, NewCompositeBuilder(false), dmlOptions{}); err == nil {
+		t.Errorf("doExecuteComposite() expected error for empty builder")
+	}
+	invalid := NewCompositeBuilder(false).
+		Add(http.MethodGet, "/limits", nil, "refLimits").
+		Add(http.MethodGet, "/limits", nil, "refLimits")
+	if _, err := doExecuteComposite(&sfAuth, invalid, dmlOptions{}); err == nil {
+		t.Errorf("doExecuteComposite() expected error for invalid builder")
+	}
+}
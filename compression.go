@@ -0,0 +1,79 @@
+package salesforce
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const gzipEncoding = "gzip"
+
+// gzip compresses request bodies, including bulk csv uploads, and asks for gzip compressed responses,
+// including bulk query results
+func WithCompression() Option {
+	return func(config *configuration) error {
+		config.compression = true
+		return nil
+	}
+}
+
+func (config *configuration) compressed() bool {
+	return config != nil && config.compression
+}
+
+func gzipBody(body string) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	if _, err := io.Copy(gzipWriter, strings.NewReader(body)); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// the writer is stopped with an error once the request body is closed, such as when the request fails
+func streamBody(write func(w io.Writer) error, compress bool) io.ReadCloser {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		if !compress {
+			pipeWriter.CloseWithError(write(pipeWriter))
+			return
+		}
+		gzipWriter := gzip.NewWriter(pipeWriter)
+		err := write(gzipWriter)
+		pipeWriter.CloseWithError(errors.Join(err, gzipWriter.Close()))
+	}()
+	return pipeReader
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (reader gzipReadCloser) Close() error {
+	return errors.Join(reader.Reader.Close(), reader.body.Close())
+}
+
+// accept-encoding is set explicitly, so the transport leaves decompression to us
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), gzipEncoding) {
+		return nil
+	}
+	gzipReader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	resp.Body = gzipReadCloser{Reader: gzipReader, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
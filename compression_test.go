@@ -0,0 +1,132 @@
+package salesforce
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newCompressionTestServer(t *testing.T, uploads map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != gzipEncoding {
+			t.Errorf("%s %s Accept-Encoding = %q, want gzip", r.Method, r.URL.Path, r.Header.Get("Accept-Encoding"))
+		}
+		if r.ContentLength != 0 {
+			if r.Header.Get("Content-Encoding") != gzipEncoding {
+				t.Errorf("%s %s Content-Encoding = %q, want gzip", r.Method, r.URL.Path, r.Header.Get("Content-Encoding"))
+			}
+			gzipReader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Fatalf("request body is not gzip compressed: %v", err)
+			}
+			body, _ := io.ReadAll(gzipReader)
+			uploads[r.Method+" "+r.URL.Path] = string(body)
+		}
+
+		var resp string
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/results"):
+			w.Header().Set("Sforce-NumberOfRecords", "2")
+			w.Header().Set("Sforce-Locator", "null")
+			resp = "\"Id\",\"Name\"\n\"001000000000001\",\"Acme\"\n\"001000000000002\",\"Globex\"\n"
+		case strings.HasSuffix(r.URL.Path, "/batches"):
+			w.WriteHeader(http.StatusCreated)
+			return
+		default:
+			resp = `{"id": "7500000000001", "state": "UploadComplete"}`
+		}
+		w.Header().Set("Content-Encoding", gzipEncoding)
+		gzipWriter := gzip.NewWriter(w)
+		if _, err := gzipWriter.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+		if err := gzipWriter.Close(); err != nil {
+			panic(err.Error())
+		}
+	}))
+}
+
+func Test_uploadJobData_compression(t *testing.T) {
+	uploads := map[string]string{}
+	server := newCompressionTestServer(t, uploads)
+	defer server.Close()
+	config, _ := newConfiguration(WithCompression())
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", config: config}
+
+	data := "Name\nAcme\nGlobex\n"
+	if err := uploadJobData(&sfAuth, data, bulkJob{Id: "7500000000001"}); err != nil {
+		t.Fatalf("uploadJobData() error = %v", err)
+	}
+	if got := uploads["PUT /services/data/"+apiVersion+"/jobs/ingest/7500000000001/batches"]; got != data {
+		t.Errorf("uploadJobData() uploaded %q, want %q", got, data)
+	}
+}
+
+func Test_getQueryJobResults_compression(t *testing.T) {
+	server := newCompressionTestServer(t, map[string]string{})
+	defer server.Close()
+	config, _ := newConfiguration(WithCompression())
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", config: config}
+
+	got, err := getQueryJobResults(&sfAuth, "7500000000001", "", BulkQueryOptions{})
+	if err != nil {
+		t.Fatalf("getQueryJobResults() error = %v", err)
+	}
+	want := bulkJobQueryResults{
+		NumberOfRecords: 2,
+		Data:            [][]string{{"Id", "Name"}, {"001000000000001", "Acme"}, {"001000000000002", "Globex"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getQueryJobResults() = %v, want %v", got, want)
+	}
+}
+
+func Test_doRequest_withoutCompression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "" {
+			t.Errorf("Content-Encoding = %q, want none", r.Header.Get("Content-Encoding"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	resp, err := doRequest(&sfAuth, requestPayload{method: http.MethodPost, uri: "/echo", content: jsonType, body: `{"Name": "Acme"}`})
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"Name": "Acme"}` {
+		t.Errorf("doRequest() sent %q", body)
+	}
+}
+
+func Test_decompressResponse(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": {gzipEncoding}},
+		Body:   io.NopCloser(strings.NewReader("not gzip")),
+	}
+	if err := decompressResponse(resp); err == nil {
+		t.Errorf("decompressResponse() expected error for invalid gzip body")
+	}
+
+	resp = &http.Response{
+		Header: http.Header{},
+		Body:   io.NopCloser(strings.NewReader("plain")),
+	}
+	if err := decompressResponse(resp); err != nil {
+		t.Fatalf("decompressResponse() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "plain" {
+		t.Errorf("decompressResponse() changed an uncompressed body to %q", body)
+	}
+}
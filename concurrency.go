@@ -0,0 +1,189 @@
+package salesforce
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// guards a client's session tokens, which may be refreshed while batches are submitted concurrently
+type sessionLock struct {
+	sync.RWMutex
+	// held for the whole refresh, so workers that see the same expired session refresh it once
+	refresh sync.Mutex
+}
+
+// authentications built without a lock, such as in tests, share this one
+var defaultSessionLock sessionLock
+
+func (auth *authentication) sessionLock() *sessionLock {
+	if auth.lock == nil {
+		return &defaultSessionLock
+	}
+	return auth.lock
+}
+
+// token providers can change the instance url along with the token, so both are read together
+func (auth *authentication) session() (instanceUrl string, accessToken string) {
+	lock := auth.sessionLock()
+	lock.RLock()
+	defer lock.RUnlock()
+	return auth.InstanceUrl, auth.AccessToken
+}
+
+// cached responses depend on the org and on what the user can see, so cache entries are scoped by the
+// instance url and the user's identity url, or a hash of the access token when the identity isn't known
+func (auth *authentication) cacheScope() string {
+	lock := auth.sessionLock()
+	lock.RLock()
+	defer lock.RUnlock()
+	user := auth.Id
+	if user == "" {
+		sum := sha256.Sum256([]byte(auth.AccessToken))
+		user = hex.EncodeToString(sum[:])
+	}
+	return auth.InstanceUrl + " " + user
+}
+
+func WithConcurrency(n int) Option {
+	return func(config *configuration) error {
+		if n < 1 {
+			return errors.New("concurrency must be at least 1")
+		}
+		config.concurrency = n
+		return nil
+	}
+}
+
+func (config *configuration) workers() int {
+	if config == nil || config.concurrency < 1 {
+		return 1
+	}
+	return config.concurrency
+}
+
+// calls fn for every index in 0..n-1 using up to the given number of workers
+// no new calls are started after one fails, and a single worker runs the calls in order
+func runConcurrently(n int, workers int, fn func(i int) error) error {
+	errs, _ := runEach(n, workers, true, fn)
+	return errors.Join(errs...)
+}
+
+// returns the error of each call and whether it was started at all
+func runEach(n int, workers int, stopOnError bool, fn func(i int) error) ([]error, []bool) {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, n)
+	started := make([]bool, n)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		if stopOnError && failed.Load() {
+			break
+		}
+		started[i] = true
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(i); err != nil {
+				errs[i] = err
+				failed.Store(true)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs, started
+}
+
+// a range of input records, starting at Index
+type RecordRange struct {
+	Index int
+	Size  int
+}
+
+type BatchError struct {
+	Batch   int // 0-based batch number
+	Records RecordRange
+	Err     error
+}
+
+func (e BatchError) Error() string {
+	return "batch " + strconv.Itoa(e.Batch) + " (records " + strconv.Itoa(e.Records.Index) + "-" +
+		strconv.Itoa(e.Records.Index+e.Records.Size-1) + "): " + e.Err.Error()
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+// returned by batched collection operations when batches fail
+// NotAttempted lists the records that were never sent, which is always empty with WithContinueOnError
+type MultiError struct {
+	Errors       []BatchError
+	NotAttempted []RecordRange
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, 0, len(e.Errors)+1)
+	for _, batchErr := range e.Errors {
+		messages = append(messages, batchErr.Error())
+	}
+	if len(e.NotAttempted) > 0 {
+		notAttempted := 0
+		for _, records := range e.NotAttempted {
+			notAttempted += records.Size
+		}
+		messages = append(messages, strconv.Itoa(notAttempted)+" records were not attempted")
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, batchErr := range e.Errors {
+		errs[i] = batchErr.Err
+	}
+	return errs
+}
+
+func batchSizes[T any](batches [][]T) []int {
+	sizes := make([]int, len(batches))
+	for i, batch := range batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+// sends every batch of records, stopping after the first failure unless continueOnError is set
+func runBatches(batchSizes []int, workers int, continueOnError bool, fn func(i int) error) error {
+	errs, started := runEach(len(batchSizes), workers, !continueOnError, fn)
+	multiErr := &MultiError{}
+	index := 0
+	for i, size := range batchSizes {
+		records := RecordRange{Index: index, Size: size}
+		index += size
+		switch {
+		case errs[i] != nil:
+			multiErr.Errors = append(multiErr.Errors, BatchError{Batch: i, Records: records, Err: errs[i]})
+		case !started[i]:
+			// adjacent batches that were never started are reported as one range
+			if last := len(multiErr.NotAttempted) - 1; last >= 0 && multiErr.NotAttempted[last].Index+multiErr.NotAttempted[last].Size == records.Index {
+				multiErr.NotAttempted[last].Size += size
+			} else {
+				multiErr.NotAttempted = append(multiErr.NotAttempted, records)
+			}
+		}
+	}
+	if len(multiErr.Errors) == 0 {
+		return nil
+	}
+	return multiErr
+}
@@ -0,0 +1,304 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithConcurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "set_concurrency",
+			n:       4,
+			want:    4,
+			wantErr: false,
+		},
+		{
+			name:    "zero",
+			n:       0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newConfiguration(WithConcurrency(tt.n))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithConcurrency() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && config.workers() != tt.want {
+				t.Errorf("WithConcurrency() = %v, want %v", config.workers(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_runConcurrently(t *testing.T) {
+	t.Run("all_succeed", func(t *testing.T) {
+		results := make([]int, 10)
+		err := runConcurrently(len(results), 3, func(i int) error {
+			results[i] = i * i
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("runConcurrently() error = %v", err)
+		}
+		want := []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}
+		if !reflect.DeepEqual(results, want) {
+			t.Errorf("runConcurrently() = %v, want %v", results, want)
+		}
+	})
+	t.Run("stops_after_failure", func(t *testing.T) {
+		var calls atomic.Int64
+		err := runConcurrently(10, 1, func(i int) error {
+			calls.Add(1)
+			if i == 2 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+		if err == nil {
+			t.Fatal("runConcurrently() expected an error")
+		}
+		if calls.Load() != 3 {
+			t.Errorf("runConcurrently() made %d calls, want 3", calls.Load())
+		}
+	})
+}
+
+func Test_doBatchedRequestsForCollection_concurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := sObjectCollection{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		results := []SalesforceResult{}
+		for _, record := range payload.Records {
+			results = append(results, SalesforceResult{Id: record["Name"].(string), Errors: []SalesforceErrorMessage{}, Success: true})
+		}
+		body, _ := json.Marshal(results)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	config, _ := newConfiguration(WithConcurrency(4))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstoken",
+		config:      config,
+	}
+
+	records := []map[string]any{}
+	want := []SalesforceResult{}
+	for i := 0; i < 25; i++ {
+		records = append(records, map[string]any{"Name": strconv.Itoa(i)})
+		want = append(want, SalesforceResult{Id: strconv.Itoa(i), Errors: []SalesforceErrorMessage{}, Success: true, Index: i, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK}})
+	}
+
+	got, err := doBatchedRequestsForCollection(&sfAuth, "Account", http.MethodPost, "/composite/sobjects/", 2, records, ThrottleProfile{}, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doBatchedRequestsForCollection() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Results, want) {
+		t.Errorf("doBatchedRequestsForCollection() = %v, want %v", got.Results, want)
+	}
+}
+
+func Test_runBatches(t *testing.T) {
+	failure := errors.New("failed")
+	failSecond := func(i int) error {
+		if i == 1 {
+			return failure
+		}
+		return nil
+	}
+	tests := []struct {
+		name            string
+		continueOnError bool
+		fn              func(i int) error
+		want            *MultiError
+	}{
+		{
+			name: "all_succeed",
+			fn:   func(i int) error { return nil },
+			want: nil,
+		},
+		{
+			name: "stops_after_failure",
+			fn:   failSecond,
+			want: &MultiError{
+				Errors:       []BatchError{{Batch: 1, Records: RecordRange{Index: 2, Size: 2}, Err: failure}},
+				NotAttempted: []RecordRange{{Index: 4, Size: 3}},
+			},
+		},
+		{
+			name:            "continue_on_error",
+			continueOnError: true,
+			fn: func(i int) error {
+				if i == 1 || i == 3 {
+					return failure
+				}
+				return nil
+			},
+			want: &MultiError{
+				Errors: []BatchError{
+					{Batch: 1, Records: RecordRange{Index: 2, Size: 2}, Err: failure},
+					{Batch: 3, Records: RecordRange{Index: 6, Size: 1}, Err: failure},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runBatches([]int{2, 2, 2, 1}, 1, tt.continueOnError, tt.fn)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("runBatches() error = %v", err)
+				}
+				return
+			}
+			var multiErr *MultiError
+			if !errors.As(err, &multiErr) || !errors.Is(err, failure) {
+				t.Fatalf("runBatches() error = %v, want MultiError", err)
+			}
+			if !reflect.DeepEqual(multiErr, tt.want) {
+				t.Errorf("runBatches() = %v, want %v", multiErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiError_Error(t *testing.T) {
+	err := &MultiError{
+		Errors:       []BatchError{{Batch: 1, Records: RecordRange{Index: 200, Size: 200}, Err: errors.New("timeout")}},
+		NotAttempted: []RecordRange{{Index: 400, Size: 200}, {Index: 800, Size: 50}},
+	}
+	want := "batch 1 (records 200-399): timeout\n250 records were not attempted"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func Test_doBatchedRequestsForCollection_continueOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := sObjectCollection{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Records[0]["Name"] == "2" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		results := []SalesforceResult{}
+		for _, record := range payload.Records {
+			results = append(results, SalesforceResult{Id: record["Name"].(string), Success: true})
+		}
+		body, _ := json.Marshal(results)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstoken",
+	}
+
+	records := []map[string]any{}
+	for i := 0; i < 6; i++ {
+		records = append(records, map[string]any{"Name": strconv.Itoa(i)})
+	}
+
+	got, err := doBatchedRequestsForCollection(&sfAuth, "Account", http.MethodPost, "/composite/sobjects/", 2, records, ThrottleProfile{}, newDMLOptions(WithContinueOnError()))
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || len(multiErr.Errors) != 1 || multiErr.Errors[0].Records != (RecordRange{Index: 2, Size: 2}) || len(multiErr.NotAttempted) != 0 {
+		t.Fatalf("doBatchedRequestsForCollection() error = %v", err)
+	}
+	gotIndexes := []int{}
+	for _, result := range got.Results {
+		gotIndexes = append(gotIndexes, result.Index)
+	}
+	if want := []int{0, 1, 4, 5}; !reflect.DeepEqual(gotIndexes, want) {
+		t.Errorf("doBatchedRequestsForCollection() indexes = %v, want %v", gotIndexes, want)
+	}
+
+	_, err = doBatchedRequestsForCollection(&sfAuth, "Account", http.MethodPost, "/composite/sobjects/", 2, records, ThrottleProfile{}, dmlOptions{})
+	if !errors.As(err, &multiErr) || !reflect.DeepEqual(multiErr.NotAttempted, []RecordRange{{Index: 4, Size: 2}}) {
+		t.Errorf("doBatchedRequestsForCollection() error = %v, want the last batch not attempted", err)
+	}
+}
+
+func Test_refreshExpiredSession_once(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			if _, err := w.Write([]byte(`[{"message": "Session expired or invalid", "errorCode": "INVALID_SESSION_ID"}]`)); err != nil {
+				panic(err.Error())
+			}
+			return
+		}
+		if _, err := w.Write([]byte(`{}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	var refreshes atomic.Int32
+	provider := func(ctx context.Context) (string, string, error) {
+		refreshes.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return "fresh", "", nil
+	}
+	config, err := newConfiguration(WithTokenProvider(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &authentication{InstanceUrl: server.URL, AccessToken: "expired", config: config, lock: &sessionLock{}}
+
+	err = runConcurrently(8, 8, func(i int) error {
+		resp, err := doRequest(auth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	if got := refreshes.Load(); got != 1 {
+		t.Errorf("session refreshed %d times, want 1", got)
+	}
+}
+
+func Test_sessionLock_perClient(t *testing.T) {
+	refreshing := &authentication{AccessToken: "1234", lock: &sessionLock{}}
+	other := &authentication{AccessToken: "5678", lock: &sessionLock{}}
+	refreshing.lock.Lock()
+	defer refreshing.lock.Unlock()
+
+	done := make(chan string)
+	go func() {
+		_, token := other.session()
+		done <- token
+	}()
+	select {
+	case token := <-done:
+		if token != "5678" {
+			t.Errorf("session() token = %v, want 5678", token)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("session() blocked on another client's lock")
+	}
+}
@@ -0,0 +1,105 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// the format salesforce uses for datetimes in api responses, which it also accepts in requests
+	datetimeFormat = "2006-01-02T15:04:05.000-0700"
+	timeFormat     = "15:04:05.000Z"
+)
+
+// a date without a time, such as a Birthdate or CloseDate field, formatted as 2024-01-31
+type Date time.Time
+
+// a date and time, always sent in utc, formatted as 2024-01-31T14:30:00.000+0000
+type Datetime time.Time
+
+// a time of day without a date, formatted as 14:30:00.000Z
+type Time time.Time
+
+func (d Date) String() string {
+	return time.Time(d).Format(soqlDateFormat)
+}
+
+func (d Datetime) String() string {
+	return time.Time(d).UTC().Format(datetimeFormat)
+}
+
+func (t Time) String() string {
+	return time.Time(t).UTC().Format(timeFormat)
+}
+
+// zero values are left out of DML records before they're encoded, so null is only written when a value is marshaled on its own
+func marshalSalesforceTime(value time.Time, formatted string) ([]byte, error) {
+	if value.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(formatted)
+}
+
+func unmarshalSalesforceTime(data []byte) (time.Time, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return time.Time{}, nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return time.Time{}, err
+	}
+	return parseSalesforceTime(value)
+}
+
+func parseSalesforceTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range salesforceTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse %s as a date, datetime, or time", value)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return marshalSalesforceTime(time.Time(d), d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalSalesforceTime(data)
+	if err != nil {
+		return err
+	}
+	*d = Date(parsed)
+	return nil
+}
+
+func (d Datetime) MarshalJSON() ([]byte, error) {
+	return marshalSalesforceTime(time.Time(d), d.String())
+}
+
+func (d *Datetime) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalSalesforceTime(data)
+	if err != nil {
+		return err
+	}
+	*d = Datetime(parsed)
+	return nil
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	return marshalSalesforceTime(time.Time(t), t.String())
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalSalesforceTime(data)
+	if err != nil {
+		return err
+	}
+	*t = Time(parsed)
+	return nil
+}
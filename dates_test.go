@@ -0,0 +1,159 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDates_MarshalJSON(t *testing.T) {
+	eastern := time.FixedZone("EST", -5*60*60)
+	value := time.Date(2024, 1, 2, 10, 4, 5, 0, eastern)
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "date", value: Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), want: `"2024-01-02"`},
+		{name: "datetime_in_utc", value: Datetime(value), want: `"2024-01-02T15:04:05.000+0000"`},
+		{name: "time_in_utc", value: Time(value), want: `"15:04:05.000Z"`},
+		{name: "zero_date", value: Date{}, want: `null`},
+		{name: "zero_datetime", value: Datetime{}, want: `null`},
+		{name: "zero_time", value: Time{}, want: `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDates_UnmarshalJSON(t *testing.T) {
+	type opportunity struct {
+		CloseDate      Date
+		LastActivity   Datetime
+		PreferredTime  Time
+		NextFollowUp   *Datetime
+		EmptyCloseDate Date
+	}
+	body := `{
+		"CloseDate": "2024-01-02",
+		"LastActivity": "2024-01-02T15:04:05.000+0000",
+		"PreferredTime": "15:04:05.000Z",
+		"NextFollowUp": null,
+		"EmptyCloseDate": ""
+	}`
+	got := opportunity{}
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := opportunity{
+		CloseDate:     Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+		LastActivity:  Datetime(time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("", 0))),
+		PreferredTime: Time(time.Date(0, 1, 1, 15, 4, 5, 0, time.UTC)),
+	}
+	if !time.Time(got.CloseDate).Equal(time.Time(want.CloseDate)) ||
+		!time.Time(got.LastActivity).Equal(time.Time(want.LastActivity)) ||
+		!time.Time(got.PreferredTime).Equal(time.Time(want.PreferredTime)) ||
+		got.NextFollowUp != nil ||
+		!time.Time(got.EmptyCloseDate).IsZero() {
+		t.Errorf("json.Unmarshal() = %+v, want %+v", got, want)
+	}
+
+	var invalid Date
+	if err := json.Unmarshal([]byte(`"yesterday"`), &invalid); err == nil {
+		t.Errorf("json.Unmarshal() expected error for invalid date")
+	}
+	if err := json.Unmarshal([]byte(`20240102`), &invalid); err == nil {
+		t.Errorf("json.Unmarshal() expected error for non string date")
+	}
+}
+
+func TestDates_formatting(t *testing.T) {
+	value := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    any
+		wantSoql string
+		wantCSV  string
+	}{
+		{name: "date", value: Date(value), wantSoql: "2024-01-02", wantCSV: "2024-01-02"},
+		{name: "datetime", value: Datetime(value), wantSoql: "2024-01-02T15:04:05Z", wantCSV: "2024-01-02T15:04:05.000Z"},
+		{name: "time", value: Time(value), wantSoql: "15:04:05.000Z", wantCSV: "15:04:05.000Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSoql, err := formatSoqlValue(tt.value)
+			if err != nil {
+				t.Fatalf("formatSoqlValue() error = %v", err)
+			}
+			if gotSoql != tt.wantSoql {
+				t.Errorf("formatSoqlValue() = %v, want %v", gotSoql, tt.wantSoql)
+			}
+			if gotCSV := formatCSVValue(tt.value, BulkJobOptions{}); gotCSV != tt.wantCSV {
+				t.Errorf("formatCSVValue() = %v, want %v", gotCSV, tt.wantCSV)
+			}
+		})
+	}
+
+	for _, zero := range []any{Date{}, Datetime{}, Time{}} {
+		if got := formatCSVValue(zero, BulkJobOptions{ExplicitNulls: true}); got != bulkNullValue {
+			t.Errorf("formatCSVValue(%T{}) = %v, want %v", zero, got, bulkNullValue)
+		}
+	}
+}
+
+func TestDates_decodeBulkRecords(t *testing.T) {
+	type opportunity struct {
+		CloseDate    Date
+		LastActivity Datetime
+		Reminder     Time
+	}
+	records := []map[string]any{{
+		"CloseDate":    "2024-01-02",
+		"LastActivity": "2024-01-02T15:04:05.000+0000",
+		"Reminder":     "",
+	}}
+	got := []opportunity{}
+	if err := decodeBulkRecords(records, &got); err != nil {
+		t.Fatalf("decodeBulkRecords() error = %v", err)
+	}
+	want := []opportunity{{
+		CloseDate:    Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+		LastActivity: Datetime(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)),
+	}}
+	if len(got) != 1 ||
+		!time.Time(got[0].CloseDate).Equal(time.Time(want[0].CloseDate)) ||
+		!time.Time(got[0].LastActivity).Equal(time.Time(want[0].LastActivity)) ||
+		!time.Time(got[0].Reminder).IsZero() {
+		t.Errorf("decodeBulkRecords() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDates_convertToSliceOfMaps(t *testing.T) {
+	type task struct {
+		ActivityDate Date
+		Reminder     Datetime `sf:"ReminderDateTime"`
+	}
+	value := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	got, err := convertToSliceOfMaps([]task{{ActivityDate: Date(value), Reminder: Datetime(value)}}, false)
+	if err != nil {
+		t.Fatalf("convertToSliceOfMaps() error = %v", err)
+	}
+	want := []map[string]any{{"ActivityDate": Date(value), "ReminderDateTime": Datetime(value)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertToSliceOfMaps() = %v, want %v", got, want)
+	}
+	body, _ := json.Marshal(got)
+	if string(body) != `[{"ActivityDate":"2024-01-02","ReminderDateTime":"2024-01-02T15:04:05.000+0000"}]` {
+		t.Errorf("json.Marshal() = %s", body)
+	}
+}
@@ -0,0 +1,152 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type SObjectDescribe struct {
+	Name       string          `json:"name"`
+	Label      string          `json:"label"`
+	KeyPrefix  string          `json:"keyPrefix"`
+	Custom     bool            `json:"custom"`
+	Createable bool            `json:"createable"`
+	Updateable bool            `json:"updateable"`
+	Deletable  bool            `json:"deletable"`
+	Queryable  bool            `json:"queryable"`
+	Fields     []DescribeField `json:"fields"`
+}
+
+type DescribeField struct {
+	Name             string   `json:"name"`
+	Label            string   `json:"label"`
+	Type             string   `json:"type"`
+	Length           int      `json:"length"`
+	Custom           bool     `json:"custom"`
+	Createable       bool     `json:"createable"`
+	Updateable       bool     `json:"updateable"`
+	Nillable         bool     `json:"nillable"`
+	ExternalId       bool     `json:"externalId"`
+	Unique           bool     `json:"unique"`
+	RelationshipName string   `json:"relationshipName"`
+	ReferenceTo      []string `json:"referenceTo"`
+}
+
+// returned when strict field validation finds fields that don't exist on the target object
+var ErrInvalidFields = errors.New("fields do not exist")
+
+// wraps ErrInvalidFields with the object and the fields that don't exist on it
+type InvalidFieldsError struct {
+	SObjectName string
+	Fields      []string
+}
+
+func (e *InvalidFieldsError) Error() string {
+	return ErrInvalidFields.Error() + " on " + e.SObjectName + ": " + strings.Join(e.Fields, ", ")
+}
+
+func (e *InvalidFieldsError) Unwrap() error {
+	return ErrInvalidFields
+}
+
+// rejects DML payloads containing fields that don't exist on the target object before they are sent
+func WithStrictFieldValidation() Option {
+	return func(config *configuration) error {
+		config.strictFieldValidation = true
+		if config.metadataCache == nil {
+			config.metadataCache = NewMemoryMetadataCache()
+		}
+		return nil
+	}
+}
+
+func (config *configuration) strictFields() bool {
+	return config != nil && config.strictFieldValidation
+}
+
+func doDescribeSObject(auth *authentication, sObjectName string) (SObjectDescribe, error) {
+	if sObjectName == "" {
+		return SObjectDescribe{}, errors.New("sObject name is required")
+	}
+	key := newMetadataCacheKey(auth, sObjectName, "describe")
+	body, cached := auth.config.cachedMetadata(key)
+	if !cached {
+		resp, err := doRequest(auth, requestPayload{
+			method:  http.MethodGet,
+			uri:     "/sobjects/" + url.PathEscape(sObjectName) + "/describe",
+			content: jsonType,
+		})
+		if err != nil {
+			return SObjectDescribe{}, err
+		}
+		defer resp.Body.Close()
+
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return SObjectDescribe{}, err
+		}
+	}
+	describe := SObjectDescribe{}
+	if err := json.Unmarshal(body, &describe); err != nil {
+		return SObjectDescribe{}, err
+	}
+	if !cached {
+		auth.config.cacheMetadata(key, body)
+	}
+	return describe, nil
+}
+
+// only checks fields when strict field validation is enabled
+func validateRecordFields(auth *authentication, sObjectName string, records []map[string]any) error {
+	if !auth.config.strictFields() || len(records) == 0 {
+		return nil
+	}
+	describe, err := doDescribeSObject(auth, sObjectName)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]bool{}
+	relationships := map[string]bool{}
+	for _, field := range describe.Fields {
+		fields[strings.ToLower(field.Name)] = true
+		if field.RelationshipName != "" {
+			relationships[strings.ToLower(field.RelationshipName)] = true
+		}
+	}
+
+	invalid := map[string]bool{}
+	for _, record := range records {
+		for key, value := range record {
+			if key == "attributes" {
+				continue
+			}
+			// relationships are referenced by their external ids, either nested or as a path like Account.External_Id__c
+			name, _, isPath := strings.Cut(key, relationshipSeparator)
+			if _, isMap := value.(map[string]any); isPath || isMap {
+				if !relationships[strings.ToLower(name)] {
+					invalid[key] = true
+				}
+				continue
+			}
+			if !fields[strings.ToLower(key)] {
+				invalid[key] = true
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	invalidFields := make([]string, 0, len(invalid))
+	for field := range invalid {
+		invalidFields = append(invalidFields, field)
+	}
+	sort.Strings(invalidFields)
+	return &InvalidFieldsError{SObjectName: sObjectName, Fields: invalidFields}
+}
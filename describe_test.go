@@ -0,0 +1,171 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var accountDescribe = SObjectDescribe{
+	Name:       "Account",
+	Label:      "Account",
+	KeyPrefix:  "001",
+	Createable: true,
+	Updateable: true,
+	Fields: []DescribeField{
+		{Name: "Id", Type: "id"},
+		{Name: "Name", Type: "string", Createable: true, Updateable: true},
+		{Name: "External_Id__c", Type: "string", Custom: true, ExternalId: true},
+		{Name: "ParentId", Type: "reference", RelationshipName: "Parent", ReferenceTo: []string{"Account"}},
+	},
+}
+
+func setupDescribeTestServer(t *testing.T, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/sobjects/Account/describe" {
+			// dml requests that pass validation
+			body, _ := json.Marshal([]SalesforceResult{{Id: "001000000000001", Success: true}})
+			if _, err := w.Write(body); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		*requests++
+		body, _ := json.Marshal(accountDescribe)
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+}
+
+func Test_doDescribeSObject(t *testing.T) {
+	requests := 0
+	server := setupDescribeTestServer(t, &requests)
+	defer server.Close()
+
+	config, err := newConfiguration(WithStrictFieldValidation())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := doDescribeSObject(&sfAuth, "Account")
+		if err != nil {
+			t.Fatalf("doDescribeSObject() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, accountDescribe) {
+			t.Errorf("doDescribeSObject() = %v, want %v", got, accountDescribe)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("doDescribeSObject() sent %d requests, want 1", requests)
+	}
+
+	if _, err := doDescribeSObject(&sfAuth, ""); err == nil {
+		t.Errorf("doDescribeSObject() expected error for missing sObject name")
+	}
+}
+
+func Test_validateRecordFields(t *testing.T) {
+	requests := 0
+	server := setupDescribeTestServer(t, &requests)
+	defer server.Close()
+
+	config, err := newConfiguration(WithStrictFieldValidation())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	strictAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+	lenientAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		records []map[string]any
+		want    []string
+	}{
+		{
+			name:    "strict_mode_disabled",
+			auth:    &lenientAuth,
+			records: []map[string]any{{"Nmae": "typo"}},
+		},
+		{
+			name: "valid_fields",
+			auth: &strictAuth,
+			records: []map[string]any{
+				{"name": "case insensitive", "External_Id__c": "1", "attributes": map[string]string{"type": "Account"}},
+				{"Parent": map[string]any{"External_Id__c": "2"}, "Parent.External_Id__c": "2"},
+			},
+		},
+		{
+			name: "invalid_fields",
+			auth: &strictAuth,
+			records: []map[string]any{
+				{"Name": "Stark Industries", "Nmae": "typo"},
+				{"Owner": map[string]any{"Name": "Tony"}, "Missing__c": 1, "Nmae": "typo"},
+			},
+			want: []string{"Missing__c", "Nmae", "Owner"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRecordFields(tt.auth, "Account", tt.records)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("validateRecordFields() error = %v", err)
+				}
+				return
+			}
+			var invalidFields *InvalidFieldsError
+			if !errors.As(err, &invalidFields) || !errors.Is(err, ErrInvalidFields) {
+				t.Fatalf("validateRecordFields() error = %v, want InvalidFieldsError", err)
+			}
+			if invalidFields.SObjectName != "Account" || !reflect.DeepEqual(invalidFields.Fields, tt.want) {
+				t.Errorf("validateRecordFields() = %v, want %v", invalidFields.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doInsertCollection_strictFieldValidation(t *testing.T) {
+	requests := 0
+	server := setupDescribeTestServer(t, &requests)
+	defer server.Close()
+
+	config, err := newConfiguration(WithStrictFieldValidation())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+
+	_, err = doInsertCollection(&sfAuth, "Account", []map[string]any{{"Name": "ok"}, {"Nmae": "typo"}}, 200, dmlOptions{})
+	if !errors.Is(err, ErrInvalidFields) {
+		t.Errorf("doInsertCollection() error = %v, want %v", err, ErrInvalidFields)
+	}
+	results, err := doInsertCollection(&sfAuth, "Account", []map[string]any{{"Name": "ok"}}, 200, dmlOptions{})
+	if err != nil || len(results.Results) != 1 {
+		t.Errorf("doInsertCollection() = %v, %v", results, err)
+	}
+	if requests != 1 {
+		t.Errorf("doInsertCollection() sent %d describe requests, want 1", requests)
+	}
+}
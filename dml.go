@@ -1,11 +1,11 @@
 package salesforce
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"reflect"
+	"strconv"
 
 	"github.com/go-viper/mapstructure/v2"
 )
@@ -15,110 +15,190 @@ type sObjectCollection struct {
 	Records   []map[string]any `json:"records"`
 }
 
-func convertToMap(obj any) (map[string]any, error) {
+func convertToMap(obj any, nullNilPointers bool) (map[string]any, error) {
+	if record, ok := obj.(map[string]any); ok {
+		return copyRecord(record), nil
+	}
 	var recordMap map[string]any
-	if _, ok := obj.(map[string]any); ok {
-		recordMap = obj.(map[string]any)
-	} else {
-		err := mapstructure.Decode(obj, &recordMap)
-		if err != nil {
-			return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
-		}
+	err := mapstructure.Decode(obj, &recordMap)
+	if err != nil {
+		return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
 	}
+	prepareRecord(reflect.ValueOf(obj), recordMap, nullNilPointers)
 	return recordMap, nil
 }
 
-func convertToSliceOfMaps(obj any) ([]map[string]any, error) {
+func convertToSliceOfMaps(obj any, nullNilPointers bool) ([]map[string]any, error) {
+	if records, ok := obj.([]map[string]any); ok {
+		recordMap := make([]map[string]any, len(records))
+		for i, record := range records {
+			recordMap[i] = copyRecord(record)
+		}
+		return recordMap, nil
+	}
 	var recordMap []map[string]any
-	if _, ok := obj.(map[string]any); ok {
-		recordMap = obj.([]map[string]any)
-	} else {
-		err := mapstructure.Decode(obj, &recordMap)
-		if err != nil {
-			return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
+	err := mapstructure.Decode(obj, &recordMap)
+	if err != nil {
+		return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
+	}
+	if v := reflect.ValueOf(obj); v.Kind() == reflect.Slice {
+		for i := range recordMap {
+			prepareRecord(v.Index(i), recordMap[i], nullNilPointers)
 		}
 	}
 	return recordMap, nil
 }
 
-func processSalesforceResponse(resp http.Response) ([]SalesforceResult, error) {
+// records are modified before they're sent, such as setting attributes and removing ids,
+// so maps passed in by the caller are copied to leave them unchanged
+func copyRecord(record map[string]any) map[string]any {
+	if record == nil {
+		return map[string]any{}
+	}
+	copied := make(map[string]any, len(record))
+	for key, value := range record {
+		copied[key] = value
+	}
+	return copied
+}
+
+// salesforce clears a field when its value is sent as null
+func setFieldsToNull(recordMap map[string]any, fields []string) {
+	for _, field := range fields {
+		recordMap[field] = nil
+	}
+}
+
+// records the position of each result's input record, offset by the records in earlier batches
+func setResultIndexes(results []SalesforceResult, offset int) []SalesforceResult {
+	for i := range results {
+		results[i].Index = offset + i
+	}
+	return results
+}
+
+// the results of records that failed, in input order; Index is the position of each record in the input
+func (results SalesforceResults) Failed() []SalesforceResult {
+	var failed []SalesforceResult
+	for _, result := range results.Results {
+		if !result.Success {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+func processSalesforceResponse(codec Codec, resp http.Response) ([]SalesforceResult, error) {
 	results := []SalesforceResult{}
-	responseData, err := io.ReadAll(resp.Body)
-	if err != nil {
+	if err := decodeBody(codec, resp.Body, &results); err != nil {
 		return nil, err
 	}
-	jsonError := json.Unmarshal(responseData, &results)
-	if jsonError != nil {
-		return nil, jsonError
-	}
 
 	return results, nil
 }
 
-func doBatchedRequestsForCollection(auth *authentication, method string, url string, batchSize int, recordMap []map[string]any) (SalesforceResults, error) {
-	var results = []SalesforceResult{}
+func doBatchedRequestsForCollection(auth *authentication, sObjectName string, method string, url string, batchSize int, recordMap []map[string]any, profile ThrottleProfile, options dmlOptions) (SalesforceResults, error) {
+	batchSize = profile.batchSize(batchSize)
 
-	for len(recordMap) > 0 {
-		var batch, remaining []map[string]any
-		if len(recordMap) > batchSize {
-			batch, remaining = recordMap[:batchSize], recordMap[batchSize:]
-		} else {
-			batch = recordMap
-		}
-		recordMap = remaining
+	overhead, err := jsonSize(sObjectCollection{AllOrNone: options.allOrNone, Records: []map[string]any{}})
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	batches, err := splitRecords(recordMap, batchSize, options.maxPayloadSize, overhead)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	offsets := batchOffsets(batches)
 
+	workers := auth.config.workers()
+	if profile.InterBatchDelay > 0 {
+		workers = 1
+	}
+	batchResults := make([][]SalesforceResult, len(batches))
+	batchMetadata := make([]ResponseMetadata, len(batches))
+	err = runBatches(batchSizes(batches), workers, options.continueOnError, func(i int) error {
+		profile.wait(i)
 		payload := sObjectCollection{
-			AllOrNone: false,
-			Records:   batch,
+			AllOrNone: options.allOrNone,
+			Records:   batches[i],
 		}
 
-		body, err := json.Marshal(payload)
+		body, err := auth.config.codec().Marshal(payload)
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return err
 		}
 
 		resp, err := doRequest(auth, requestPayload{
-			method:  method,
-			uri:     url,
-			content: jsonType,
-			body:    string(body),
+			method:      method,
+			uri:         url,
+			content:     jsonType,
+			body:        string(body),
+			headers:     options.headers,
+			timeout:     options.timeout,
+			ctx:         options.ctx,
+			sObjectName: sObjectName,
+			batch:       i + 1,
 		})
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return newCollectionError(err, body, batches[i], offsets[i], auth.config.codec())
 		}
-		currentResults, err := processSalesforceResponse(*resp)
+		currentResults, err := processSalesforceResponse(auth.config.codec(), *resp)
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return err
 		}
 
-		results = append(results, currentResults...)
+		batchMetadata[i] = newResponseMetadata(resp)
+		setResponseMetadata(currentResults, batchMetadata[i])
+		batchResults[i] = setResultIndexes(currentResults, offsets[i])
+		return nil
+	})
+
+	results := mergeBatchResults(batchResults, batchMetadata)
+	if err != nil {
+		return results, err
 	}
 
-	for _, result := range results {
+	for _, result := range results.Results {
 		if !result.Success {
-			return SalesforceResults{Results: results, HasSalesforceErrors: true}, nil
+			results.HasSalesforceErrors = true
+			return results, nil
 		}
 	}
 
-	return SalesforceResults{Results: results}, nil
+	return results, nil
 }
 
-func decodeResponseBody(response *http.Response) (value SalesforceResult, err error) {
+// concatenates batch results in order, taking the collection's metadata from the last batch that got a response
+func mergeBatchResults(batchResults [][]SalesforceResult, batchMetadata []ResponseMetadata) SalesforceResults {
+	results := SalesforceResults{Results: []SalesforceResult{}}
+	for i, currentResults := range batchResults {
+		results.Results = append(results.Results, currentResults...)
+		if batchMetadata[i].StatusCode != 0 {
+			results.ResponseMetadata = batchMetadata[i]
+		}
+	}
+	return results
+}
+
+func decodeResponseBody(codec Codec, response *http.Response) (value SalesforceResult, err error) {
 	defer response.Body.Close()
-	decoder := json.NewDecoder(response.Body)
-	err = decoder.Decode(&value)
+	err = decodeBody(codec, response.Body, &value)
+	value.ResponseMetadata = newResponseMetadata(response)
 	return value, err
 }
 
-func doInsertOne(auth *authentication, sObjectName string, record any) (SalesforceResult, error) {
-	recordMap, err := convertToMap(record)
+func doInsertOne(auth *authentication, sObjectName string, record any, options dmlOptions) (SalesforceResult, error) {
+	recordMap, err := convertToMap(record, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, []map[string]any{recordMap}); err != nil {
+		return SalesforceResult{}, err
+	}
 	recordMap["attributes"] = map[string]string{"type": sObjectName}
 	delete(recordMap, "Id")
 
-	body, err := json.Marshal(recordMap)
+	body, err := auth.config.codec().Marshal(recordMap)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
@@ -128,12 +208,15 @@ func doInsertOne(auth *authentication, sObjectName string, record any) (Salesfor
 		uri:     "/sobjects/" + sObjectName,
 		content: jsonType,
 		body:    string(body),
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
 	})
 	if err != nil {
 		return SalesforceResult{}, err
 	}
 
-	data, err := decodeResponseBody(resp)
+	data, err := decodeResponseBody(auth.config.codec(), resp)
 	if err != nil {
 		fmt.Println("Error decoding: ", err)
 		return SalesforceResult{}, err
@@ -142,11 +225,15 @@ func doInsertOne(auth *authentication, sObjectName string, record any) (Salesfor
 	return data, nil
 }
 
-func doUpdateOne(auth *authentication, sObjectName string, record any) error {
-	recordMap, err := convertToMap(record)
+func doUpdateOne(auth *authentication, sObjectName string, record any, options dmlOptions) error {
+	recordMap, err := convertToMap(record, options.nullNilPointers)
 	if err != nil {
 		return err
 	}
+	if err := validateRecordFields(auth, sObjectName, []map[string]any{recordMap}); err != nil {
+		return err
+	}
+	setFieldsToNull(recordMap, options.fieldsToNull)
 
 	recordId, ok := recordMap["Id"].(string)
 	if !ok || recordId == "" {
@@ -156,7 +243,7 @@ func doUpdateOne(auth *authentication, sObjectName string, record any) error {
 	recordMap["attributes"] = map[string]string{"type": sObjectName}
 	delete(recordMap, "Id")
 
-	body, err := json.Marshal(recordMap)
+	body, err := auth.config.codec().Marshal(recordMap)
 	if err != nil {
 		return err
 	}
@@ -166,6 +253,9 @@ func doUpdateOne(auth *authentication, sObjectName string, record any) error {
 		uri:     "/sobjects/" + sObjectName + "/" + recordId,
 		content: jsonType,
 		body:    string(body),
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
 	})
 	if err != nil {
 		return err
@@ -174,11 +264,15 @@ func doUpdateOne(auth *authentication, sObjectName string, record any) error {
 	return nil
 }
 
-func doUpsertOne(auth *authentication, sObjectName string, fieldName string, record any) (SalesforceResult, error) {
-	recordMap, err := convertToMap(record)
+func doUpsertOne(auth *authentication, sObjectName string, fieldName string, record any, options dmlOptions) (SalesforceResult, error) {
+	recordMap, err := convertToMap(record, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, []map[string]any{recordMap}); err != nil {
+		return SalesforceResult{}, err
+	}
+	setFieldsToNull(recordMap, options.fieldsToNull)
 
 	externalIdValue, ok := recordMap[fieldName].(string)
 	if !ok || externalIdValue == "" {
@@ -189,7 +283,7 @@ func doUpsertOne(auth *authentication, sObjectName string, fieldName string, rec
 	delete(recordMap, "Id")
 	delete(recordMap, fieldName)
 
-	body, err := json.Marshal(recordMap)
+	body, err := auth.config.codec().Marshal(recordMap)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
@@ -199,12 +293,18 @@ func doUpsertOne(auth *authentication, sObjectName string, fieldName string, rec
 		uri:     "/sobjects/" + sObjectName + "/" + fieldName + "/" + externalIdValue,
 		content: jsonType,
 		body:    string(body),
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
 	})
 	if err != nil {
 		return SalesforceResult{}, err
 	}
+	if err := checkMultipleMatches(resp); err != nil {
+		return SalesforceResult{}, err
+	}
 
-	data, err := decodeResponseBody(resp)
+	data, err := decodeResponseBody(auth.config.codec(), resp)
 	if err != nil {
 		fmt.Println("Error decoding: ", err)
 		return SalesforceResult{}, err
@@ -213,8 +313,8 @@ func doUpsertOne(auth *authentication, sObjectName string, fieldName string, rec
 	return data, nil
 }
 
-func doDeleteOne(auth *authentication, sObjectName string, record any) error {
-	recordMap, err := convertToMap(record)
+func doDeleteOne(auth *authentication, sObjectName string, record any, options dmlOptions) error {
+	recordMap, err := convertToMap(record, options.nullNilPointers)
 	if err != nil {
 		return err
 	}
@@ -228,6 +328,9 @@ func doDeleteOne(auth *authentication, sObjectName string, record any) error {
 		method:  http.MethodDelete,
 		uri:     "/sobjects/" + sObjectName + "/" + recordId,
 		content: jsonType,
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
 	})
 	if err != nil {
 		return err
@@ -236,25 +339,32 @@ func doDeleteOne(auth *authentication, sObjectName string, record any) error {
 	return nil
 }
 
-func doInsertCollection(auth *authentication, sObjectName string, records any, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doInsertCollection(auth *authentication, sObjectName string, records any, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return SalesforceResults{}, err
+	}
 	for i := range recordMap {
 		delete(recordMap[i], "Id")
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 	}
 
-	return doBatchedRequestsForCollection(auth, http.MethodPost, "/composite/sobjects/", batchSize, recordMap)
+	return doBatchedRequestsForCollection(auth, sObjectName, http.MethodPost, "/composite/sobjects/", batchSize, recordMap, auth.config.throttleProfile(sObjectName), options)
 }
 
-func doUpdateCollection(auth *authentication, sObjectName string, records any, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doUpdateCollection(auth *authentication, sObjectName string, records any, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return SalesforceResults{}, err
+	}
 	for i := range recordMap {
+		setFieldsToNull(recordMap[i], options.fieldsToNull)
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 		recordId, ok := recordMap[i]["Id"].(string)
 		if !ok || recordId == "" {
@@ -262,15 +372,19 @@ func doUpdateCollection(auth *authentication, sObjectName string, records any, b
 		}
 	}
 
-	return doBatchedRequestsForCollection(auth, http.MethodPatch, "/composite/sobjects/", batchSize, recordMap)
+	return doBatchedRequestsForCollection(auth, sObjectName, http.MethodPatch, "/composite/sobjects/", batchSize, recordMap, auth.config.throttleProfile(sObjectName), options)
 }
 
-func doUpsertCollection(auth *authentication, sObjectName string, fieldName string, records any, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doUpsertCollection(auth *authentication, sObjectName string, fieldName string, records any, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+		return SalesforceResults{}, err
+	}
 	for i := range recordMap {
+		setFieldsToNull(recordMap[i], options.fieldsToNull)
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 		externalIdValue, ok := recordMap[i][fieldName].(string)
 		if !ok || externalIdValue == "" {
@@ -279,18 +393,22 @@ func doUpsertCollection(auth *authentication, sObjectName string, fieldName stri
 	}
 
 	uri := "/composite/sobjects/" + sObjectName + "/" + fieldName
-	return doBatchedRequestsForCollection(auth, http.MethodPatch, uri, batchSize, recordMap)
+	return doBatchedRequestsForCollection(auth, sObjectName, http.MethodPatch, uri, batchSize, recordMap, auth.config.throttleProfile(sObjectName), options)
 
 }
 
-func doDeleteCollection(auth *authentication, sObjectName string, records any, batchSize int) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+func doDeleteCollection(auth *authentication, sObjectName string, records any, batchSize int, options dmlOptions) (SalesforceResults, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
 
+	profile := auth.config.throttleProfile(sObjectName)
+	batchSize = profile.batchSize(batchSize)
+
 	// we want to verify that ids are present before we start deleting
 	batchedIds := []string{}
+	sizes := []int{}
 	for len(recordMap) > 0 {
 		var batch, remaining []map[string]any
 		if len(recordMap) > batchSize {
@@ -314,32 +432,92 @@ func doDeleteCollection(auth *authentication, sObjectName string, records any, b
 			}
 		}
 		batchedIds = append(batchedIds, ids)
+		sizes = append(sizes, len(batch))
 	}
 
-	var results = []SalesforceResult{}
-
-	for i := range batchedIds {
+	workers := auth.config.workers()
+	if profile.InterBatchDelay > 0 {
+		workers = 1
+	}
+	batchResults := make([][]SalesforceResult, len(batchedIds))
+	batchMetadata := make([]ResponseMetadata, len(batchedIds))
+	err = runBatches(sizes, workers, options.continueOnError, func(i int) error {
+		profile.wait(i)
+		if len(options.snapshotFields) > 0 {
+			currentResults, metadata, err := deleteWithSnapshot(auth, sObjectName, batchedIds[i], options)
+			if err != nil {
+				return err
+			}
+			batchMetadata[i] = metadata
+			setResponseMetadata(currentResults, metadata)
+			batchResults[i] = setResultIndexes(currentResults, i*batchSize)
+			return nil
+		}
 		resp, err := doRequest(auth, requestPayload{
-			method:  http.MethodDelete,
-			uri:     "/composite/sobjects/?ids=" + batchedIds[i] + "&allOrNone=false",
-			content: jsonType,
+			method:      http.MethodDelete,
+			uri:         "/composite/sobjects/?ids=" + batchedIds[i] + "&allOrNone=" + strconv.FormatBool(options.allOrNone),
+			content:     jsonType,
+			headers:     options.headers,
+			timeout:     options.timeout,
+			ctx:         options.ctx,
+			sObjectName: sObjectName,
+			batch:       i + 1,
 		})
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return err
 		}
-		currentResults, err := processSalesforceResponse(*resp)
+		currentResults, err := processSalesforceResponse(auth.config.codec(), *resp)
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return err
 		}
 
-		results = append(results, currentResults...)
+		batchMetadata[i] = newResponseMetadata(resp)
+		setResponseMetadata(currentResults, batchMetadata[i])
+		batchResults[i] = setResultIndexes(currentResults, i*batchSize)
+		return nil
+	})
+
+	results := mergeBatchResults(batchResults, batchMetadata)
+	if err != nil {
+		return results, err
 	}
 
-	for _, result := range results {
+	if options.ignoreDeleted {
+		return ignoreDeletedResults(results), nil
+	}
+
+	for _, result := range results.Results {
 		if !result.Success {
-			return SalesforceResults{Results: results, HasSalesforceErrors: true}, nil
+			results.HasSalesforceErrors = true
+			return results, nil
 		}
 	}
 
-	return SalesforceResults{Results: results}, nil
+	return results, nil
+}
+
+func isEntityDeletedError(errs []SalesforceErrorMessage) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	for _, err := range errs {
+		if err.StatusCode != entityIsDeletedError && err.ErrorCode != entityIsDeletedError {
+			return false
+		}
+	}
+	return true
+}
+
+func ignoreDeletedResults(results SalesforceResults) SalesforceResults {
+	results.HasSalesforceErrors = false
+	for i := range results.Results {
+		if !results.Results[i].Success && isEntityDeletedError(results.Results[i].Errors) {
+			results.Results[i].Success = true
+			results.Results[i].Errors = nil
+		}
+		if !results.Results[i].Success {
+			results.HasSalesforceErrors = true
+		}
+	}
+	return results
 }
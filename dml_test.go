@@ -3,12 +3,16 @@ package salesforce
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_convertToMap(t *testing.T) {
@@ -46,7 +50,7 @@ func Test_convertToMap(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := convertToMap(tt.args.obj)
+			got, err := convertToMap(tt.args.obj, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("convertToMap() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -97,6 +101,28 @@ func Test_convertToSliceOfMaps(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "convert_slice_of_maps",
+			args: args{obj: []map[string]any{
+				{
+					"Id":   "1234",
+					"Name": "test account 1",
+				},
+				{
+					"Id": "5678",
+				},
+			}},
+			want: []map[string]any{
+				{
+					"Id":   "1234",
+					"Name": "test account 1",
+				},
+				{
+					"Id": "5678",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name:    "convert_fail",
 			args:    args{obj: 1},
@@ -106,7 +132,7 @@ func Test_convertToSliceOfMaps(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := convertToSliceOfMaps(tt.args.obj)
+			got, err := convertToSliceOfMaps(tt.args.obj, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("convertToSliceOfMaps() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -118,6 +144,31 @@ func Test_convertToSliceOfMaps(t *testing.T) {
 	}
 }
 
+func Test_convertToSliceOfMaps_copiesMaps(t *testing.T) {
+	records := []map[string]any{{"Id": "1234", "Name": "test account 1"}}
+	got, err := convertToSliceOfMaps(records, false)
+	if err != nil {
+		t.Fatalf("convertToSliceOfMaps() error = %v", err)
+	}
+	delete(got[0], "Id")
+	got[0]["attributes"] = map[string]string{"type": "Account"}
+
+	want := []map[string]any{{"Id": "1234", "Name": "test account 1"}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("convertToSliceOfMaps() modified input records = %v, want %v", records, want)
+	}
+
+	record := map[string]any{"Id": "1234"}
+	gotMap, err := convertToMap(record, false)
+	if err != nil {
+		t.Fatalf("convertToMap() error = %v", err)
+	}
+	delete(gotMap, "Id")
+	if _, ok := record["Id"]; !ok {
+		t.Errorf("convertToMap() modified input record = %v", record)
+	}
+}
+
 func Test_processSalesforceResponse(t *testing.T) {
 	message := []SalesforceErrorMessage{{
 		Message:    "example error",
@@ -169,7 +220,7 @@ func Test_processSalesforceResponse(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processSalesforceResponse(tt.args.resp)
+			got, err := processSalesforceResponse(jsonCodec{}, tt.args.resp)
 			if err != nil != tt.wantErr {
 				t.Errorf("processSalesforceResponse() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -187,13 +238,15 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 	badReqServer, badReqSfAuth := setupTestServer([]SalesforceResult{}, http.StatusBadRequest)
 	defer badReqServer.Close()
 
+	okMetadata := ResponseMetadata{StatusCode: http.StatusOK}
 	sfResultWithErr := []SalesforceResult{{
 		Id: "1234",
 		Errors: []SalesforceErrorMessage{{
 			Message:    "error",
 			StatusCode: "400",
 		}},
-		Success: false,
+		Success:          false,
+		ResponseMetadata: okMetadata,
 	}}
 
 	sfErrorServer, sfErrorSfAuth := setupTestServer(sfResultWithErr, http.StatusOK)
@@ -226,8 +279,9 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 				},
 			},
 			want: SalesforceResults{
-				Results:             []SalesforceResult{{Success: true}},
+				Results:             []SalesforceResult{{Success: true, ResponseMetadata: okMetadata}},
 				HasSalesforceErrors: false,
+				ResponseMetadata:    okMetadata,
 			},
 			wantErr: false,
 		},
@@ -248,8 +302,9 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 				},
 			},
 			want: SalesforceResults{
-				Results:             []SalesforceResult{{Success: true}, {Success: true}},
+				Results:             []SalesforceResult{{Success: true, ResponseMetadata: okMetadata}, {Success: true, Index: 1, ResponseMetadata: okMetadata}},
 				HasSalesforceErrors: false,
+				ResponseMetadata:    okMetadata,
 			},
 			wantErr: false,
 		},
@@ -285,13 +340,14 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 			want: SalesforceResults{
 				Results:             sfResultWithErr,
 				HasSalesforceErrors: true,
+				ResponseMetadata:    okMetadata,
 			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doBatchedRequestsForCollection(tt.args.auth, tt.args.method, tt.args.url, tt.args.batchSize, tt.args.recordMap)
+			got, err := doBatchedRequestsForCollection(tt.args.auth, "Account", tt.args.method, tt.args.url, tt.args.batchSize, tt.args.recordMap, ThrottleProfile{}, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doBatchedRequestsForCollection() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -308,9 +364,10 @@ func Test_doInsertOne(t *testing.T) {
 	}
 
 	successfulResult := SalesforceResult{
-		Id:      "1234",
-		Errors:  []SalesforceErrorMessage{},
-		Success: true,
+		Id:               "1234",
+		Errors:           []SalesforceErrorMessage{},
+		Success:          true,
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusCreated},
 	}
 
 	server, sfAuth := setupTestServer(successfulResult, http.StatusCreated)
@@ -367,7 +424,7 @@ func Test_doInsertOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doInsertOne(tt.args.auth, tt.args.sObjectName, tt.args.record)
+			got, err := doInsertOne(tt.args.auth, tt.args.sObjectName, tt.args.record, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doInsertOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -436,13 +493,186 @@ func Test_doUpdateOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := doUpdateOne(tt.args.auth, tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
+			if err := doUpdateOne(tt.args.auth, tt.args.sObjectName, tt.args.record, dmlOptions{}); (err != nil) != tt.wantErr {
 				t.Errorf("doUpdateOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func Test_doUpdateOne_fieldsToNull(t *testing.T) {
+	type account struct {
+		Id          string
+		Name        string
+		Description string
+	}
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err.Error())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	record := account{Id: "1234", Name: "test account"}
+	if err := doUpdateOne(&sfAuth, "Account", record, dmlOptions{fieldsToNull: []string{"Description"}}); err != nil {
+		t.Fatalf("doUpdateOne() error = %v", err)
+	}
+	if value, ok := got["Description"]; !ok || value != nil {
+		t.Errorf("doUpdateOne() Description = %v, want null", value)
+	}
+	if got["Name"] != "test account" {
+		t.Errorf("doUpdateOne() Name = %v, want %v", got["Name"], "test account")
+	}
+}
+
+func Test_doUpdateOne_conditional(t *testing.T) {
+	lastModified := time.Date(2024, 10, 1, 12, 30, 0, 0, time.FixedZone("EDT", -4*60*60))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Unmodified-Since") != "Tue, 01 Oct 2024 16:30:00 GMT" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/services/data/"+apiVersion+"/sobjects/Account/stale" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	options := newDMLOptions(WithIfUnmodifiedSince(lastModified))
+
+	if err := doUpdateOne(&sfAuth, "Account", map[string]any{"Id": "fresh", "Name": "test account"}, options); err != nil {
+		t.Errorf("doUpdateOne() error = %v", err)
+	}
+	if err := doUpdateOne(&sfAuth, "Account", map[string]any{"Id": "stale", "Name": "test account"}, options); !errors.Is(err, ErrStaleRecord) {
+		t.Errorf("doUpdateOne() error = %v, want %v", err, ErrStaleRecord)
+	}
+	if err := doDeleteOne(&sfAuth, "Account", map[string]any{"Id": "stale"}, options); !errors.Is(err, ErrStaleRecord) {
+		t.Errorf("doDeleteOne() error = %v, want %v", err, ErrStaleRecord)
+	}
+}
+
+func Test_doInsertCollection_options(t *testing.T) {
+	var header string
+	var got sObjectCollection
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Sforce-Auto-Assign")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err.Error())
+		}
+		body, _ := json.Marshal([]SalesforceResult{{Id: "1234", Success: true}})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	records := []map[string]any{{"Name": "test account"}}
+	options := newDMLOptions(WithAllOrNone(), WithAutoAssign(false))
+	if _, err := doInsertCollection(&sfAuth, "Account", records, 200, options); err != nil {
+		t.Fatalf("doInsertCollection() error = %v", err)
+	}
+	if header != "FALSE" {
+		t.Errorf("doInsertCollection() Sforce-Auto-Assign = %v, want FALSE", header)
+	}
+	if !got.AllOrNone {
+		t.Errorf("doInsertCollection() allOrNone = %v, want true", got.AllOrNone)
+	}
+}
+
+func Test_collections_allOrNone(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allOrNone := r.URL.Query().Get("allOrNone")
+		if r.Method != http.MethodDelete {
+			payload := sObjectCollection{}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatal(err.Error())
+			}
+			allOrNone = strconv.FormatBool(payload.AllOrNone)
+		}
+		requests = append(requests, r.Method+" "+allOrNone)
+		body, _ := json.Marshal([]SalesforceResult{{Id: "1234", Success: true}, {Id: "5678", Success: true}})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	records := []map[string]any{
+		{"Id": "1234", "ExternalId__c": "a"},
+		{"Id": "5678", "ExternalId__c": "b"},
+		{"Id": "9012", "ExternalId__c": "c"},
+	}
+	copyRecords := func() []map[string]any {
+		copied := []map[string]any{}
+		for _, record := range records {
+			copied = append(copied, copyRecord(record))
+		}
+		return copied
+	}
+	options := newDMLOptions(WithAllOrNone())
+	if _, err := doUpdateCollection(&sfAuth, "Account", copyRecords(), 2, options); err != nil {
+		t.Fatalf("doUpdateCollection() error = %v", err)
+	}
+	if _, err := doUpsertCollection(&sfAuth, "Account", "ExternalId__c", copyRecords(), 2, options); err != nil {
+		t.Fatalf("doUpsertCollection() error = %v", err)
+	}
+	if _, err := doDeleteCollection(&sfAuth, "Account", copyRecords(), 2, options); err != nil {
+		t.Fatalf("doDeleteCollection() error = %v", err)
+	}
+
+	// every batch is sent with allOrNone, each one rolling back on its own
+	want := []string{
+		"PATCH true", "PATCH true",
+		"PATCH true", "PATCH true",
+		"DELETE true", "DELETE true",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("collection requests = %v, want %v", requests, want)
+	}
+}
+
+func Test_doInsertOne_assignmentRule(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Sforce-Auto-Assign")
+		body, _ := json.Marshal(SalesforceResult{Id: "1234", Success: true})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	options := newDMLOptions(WithAssignmentRuleHeader("01Q000000000001"))
+	if _, err := doInsertOne(&sfAuth, "Lead", map[string]any{"LastName": "Parker"}, options); err != nil {
+		t.Fatalf("doInsertOne() error = %v", err)
+	}
+	if header != "01Q000000000001" {
+		t.Errorf("doInsertOne() Sforce-Auto-Assign = %v, want 01Q000000000001", header)
+	}
+}
+
 func Test_doUpsertOne(t *testing.T) {
 	type account struct {
 		ExternalId__c string
@@ -450,9 +680,10 @@ func Test_doUpsertOne(t *testing.T) {
 	}
 
 	successfulResult := SalesforceResult{
-		Id:      "1234",
-		Errors:  []SalesforceErrorMessage{},
-		Success: true,
+		Id:               "1234",
+		Errors:           []SalesforceErrorMessage{},
+		Success:          true,
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResult, http.StatusOK)
@@ -487,6 +718,20 @@ func Test_doUpsertOne(t *testing.T) {
 			want:    successfulResult,
 			wantErr: false,
 		},
+		{
+			name: "successful_upsert_tagged_external_id",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+				fieldName:   "External_Id__c",
+				record: taggedRecord{
+					ExternalId: "1234",
+					Name:       "test account",
+				},
+			},
+			want:    successfulResult,
+			wantErr: false,
+		},
 		{
 			name: "bad_request",
 			args: args{
@@ -515,7 +760,7 @@ func Test_doUpsertOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doUpsertOne(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.record)
+			got, err := doUpsertOne(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.record, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doUpsertOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -526,6 +771,22 @@ func Test_doUpsertOne(t *testing.T) {
 	}
 }
 
+func Test_doUpsertOne_multipleMatches(t *testing.T) {
+	urls := []string{"/services/data/" + apiVersion + "/sobjects/Account/001A", "/services/data/" + apiVersion + "/sobjects/Account/001B"}
+	server, sfAuth := setupTestServer(urls, http.StatusMultipleChoices)
+	defer server.Close()
+
+	record := map[string]any{"ExternalId__c": "1234", "Name": "test account"}
+	_, err := doUpsertOne(&sfAuth, "Account", "ExternalId__c", record, dmlOptions{})
+	if !errors.Is(err, ErrMultipleMatches) {
+		t.Fatalf("doUpsertOne() error = %v, want ErrMultipleMatches", err)
+	}
+	var matches *MultipleMatchesError
+	if !errors.As(err, &matches) || !reflect.DeepEqual(matches.RecordIds, []string{"001A", "001B"}) {
+		t.Errorf("doUpsertOne() error = %#v, want record ids 001A and 001B", err)
+	}
+}
+
 func Test_doDeleteOne(t *testing.T) {
 	type account struct {
 		Id string
@@ -581,7 +842,7 @@ func Test_doDeleteOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := doDeleteOne(tt.args.auth, tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
+			if err := doDeleteOne(tt.args.auth, tt.args.sObjectName, tt.args.record, dmlOptions{}); (err != nil) != tt.wantErr {
 				t.Errorf("doDeleteOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -635,6 +896,24 @@ func Test_doInsertCollection(t *testing.T) {
 			want:    successfulResults,
 			wantErr: false,
 		},
+		{
+			name: "successful_insert_collection_maps",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+				records: []map[string]any{
+					{
+						"Name": "test account 1",
+					},
+					{
+						"Name": "test account 2",
+					},
+				},
+				batchSize: 200,
+			},
+			want:    successfulResults,
+			wantErr: false,
+		},
 		{
 			name: "bad_data",
 			args: args{
@@ -649,7 +928,7 @@ func Test_doInsertCollection(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if _, err := doInsertCollection(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.batchSize); (err != nil) != tt.wantErr {
+			if _, err := doInsertCollection(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.batchSize, dmlOptions{}); (err != nil) != tt.wantErr {
 				t.Errorf("doInsertCollection() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -664,11 +943,13 @@ func Test_doUpdateCollection(t *testing.T) {
 
 	successfulResults := SalesforceResults{
 		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 		}},
 		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
@@ -706,6 +987,22 @@ func Test_doUpdateCollection(t *testing.T) {
 			want:    successfulResults,
 			wantErr: false,
 		},
+		{
+			name: "successful_update_collection_maps",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+				records: []map[string]any{
+					{
+						"Id":   "1234",
+						"Name": "test account 1",
+					},
+				},
+				batchSize: 200,
+			},
+			want:    successfulResults,
+			wantErr: false,
+		},
 		{
 			name: "bad_data",
 			args: args{
@@ -720,7 +1017,7 @@ func Test_doUpdateCollection(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doUpdateCollection(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.batchSize)
+			got, err := doUpdateCollection(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.batchSize, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doUpdateCollection() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -739,11 +1036,13 @@ func Test_doUpsertCollection(t *testing.T) {
 
 	successfulResults := SalesforceResults{
 		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 		}},
 		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
@@ -798,7 +1097,7 @@ func Test_doUpsertCollection(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doUpsertCollection(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.records, tt.args.batchSize)
+			got, err := doUpsertCollection(tt.args.auth, tt.args.sObjectName, tt.args.fieldName, tt.args.records, tt.args.batchSize, dmlOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doUpsertCollection() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -816,27 +1115,33 @@ func Test_doDeleteCollection(t *testing.T) {
 
 	successfulResults := SalesforceResults{
 		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 		}},
 		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	successfulResultsMultiBatch := SalesforceResults{
 		Results: []SalesforceResult{
 			{
-				Id:      "1234",
-				Errors:  []SalesforceErrorMessage{},
-				Success: true,
+				Id:               "1234",
+				Errors:           []SalesforceErrorMessage{},
+				Success:          true,
+				ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 			},
 			{
-				Id:      "1234",
-				Errors:  []SalesforceErrorMessage{},
-				Success: true,
+				Id:               "1234",
+				Errors:           []SalesforceErrorMessage{},
+				Success:          true,
+				ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
+				Index:            1,
 			},
 		},
 		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	failedResults := SalesforceResults{
@@ -847,9 +1152,11 @@ func Test_doDeleteCollection(t *testing.T) {
 				StatusCode: "500",
 				Fields:     []string{},
 			}},
-			Success: false,
+			Success:          false,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 		}},
 		HasSalesforceErrors: true,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
@@ -861,11 +1168,24 @@ func Test_doDeleteCollection(t *testing.T) {
 	sfErrorServer, sfErrorSfAuth := setupTestServer(failedResults.Results, http.StatusOK)
 	defer sfErrorServer.Close()
 
+	deletedResults := []SalesforceResult{{
+		Errors: []SalesforceErrorMessage{{
+			Message:    "entity is deleted",
+			StatusCode: entityIsDeletedError,
+			Fields:     []string{},
+		}},
+		Success:          false,
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
+	}}
+	deletedServer, deletedSfAuth := setupTestServer(deletedResults, http.StatusOK)
+	defer deletedServer.Close()
+
 	type args struct {
 		auth        *authentication
 		sObjectName string
 		records     any
 		batchSize   int
+		options     dmlOptions
 	}
 	tests := []struct {
 		name    string
@@ -950,10 +1270,30 @@ func Test_doDeleteCollection(t *testing.T) {
 			want:    failedResults,
 			wantErr: false,
 		},
+		{
+			name: "ignore_deleted_records",
+			args: args{
+				auth:        &deletedSfAuth,
+				sObjectName: "Account",
+				records: []account{
+					{
+						Id: "1234",
+					},
+				},
+				batchSize: 1,
+				options:   dmlOptions{ignoreDeleted: true},
+			},
+			want: SalesforceResults{
+				Results:             []SalesforceResult{{Success: true, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK}}},
+				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doDeleteCollection(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.batchSize)
+			got, err := doDeleteCollection(tt.args.auth, tt.args.sObjectName, tt.args.records, tt.args.batchSize, tt.args.options)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doDeleteCollection() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -963,3 +1303,55 @@ func Test_doDeleteCollection(t *testing.T) {
 		})
 	}
 }
+
+func Test_ignoreDeletedResults(t *testing.T) {
+	deletedError := SalesforceErrorMessage{Message: "entity is deleted", StatusCode: entityIsDeletedError}
+	otherError := SalesforceErrorMessage{Message: "insufficient access", StatusCode: "INSUFFICIENT_ACCESS_OR_READONLY"}
+	tests := []struct {
+		name    string
+		results SalesforceResults
+		want    SalesforceResults
+	}{
+		{
+			name: "only_deleted_errors",
+			results: SalesforceResults{
+				Results: []SalesforceResult{
+					{Id: "1", Success: true},
+					{Errors: []SalesforceErrorMessage{deletedError}, Success: false},
+				},
+				HasSalesforceErrors: true,
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{
+					{Id: "1", Success: true},
+					{Success: true},
+				},
+				HasSalesforceErrors: false,
+			},
+		},
+		{
+			name: "mixed_errors",
+			results: SalesforceResults{
+				Results: []SalesforceResult{
+					{Errors: []SalesforceErrorMessage{deletedError}, Success: false},
+					{Errors: []SalesforceErrorMessage{otherError}, Success: false},
+				},
+				HasSalesforceErrors: true,
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{
+					{Success: true},
+					{Errors: []SalesforceErrorMessage{otherError}, Success: false},
+				},
+				HasSalesforceErrors: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ignoreDeletedResults(tt.results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ignoreDeletedResults() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
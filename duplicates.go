@@ -0,0 +1,59 @@
+package salesforce
+
+import (
+	"strconv"
+	"strings"
+)
+
+type DuplicateRuleHeader struct {
+	AllowSave            bool
+	IncludeRecordDetails bool
+	RunAsCurrentUser     bool
+}
+
+type DuplicateResult struct {
+	AllowSave               bool                   `json:"allowSave"`
+	DuplicateRule           string                 `json:"duplicateRule"`
+	DuplicateRuleEntityType string                 `json:"duplicateRuleEntityType"`
+	ErrorMessage            string                 `json:"errorMessage"`
+	MatchResults            []DuplicateMatchResult `json:"matchResults"`
+}
+
+type DuplicateMatchResult struct {
+	EntityType   string                 `json:"entityType"`
+	MatchEngine  string                 `json:"matchEngine"`
+	Rule         string                 `json:"rule"`
+	Size         int                    `json:"size"`
+	Success      bool                   `json:"success"`
+	MatchRecords []DuplicateMatchRecord `json:"matchRecords"`
+}
+
+type DuplicateMatchRecord struct {
+	MatchConfidence float64        `json:"matchConfidence"`
+	Record          map[string]any `json:"record"`
+}
+
+func (header DuplicateRuleHeader) String() string {
+	return strings.Join([]string{
+		"allowSave=" + strconv.FormatBool(header.AllowSave),
+		"includeRecordDetails=" + strconv.FormatBool(header.IncludeRecordDetails),
+		"runAsCurrentUser=" + strconv.FormatBool(header.RunAsCurrentUser),
+	}, ", ")
+}
+
+func WithDuplicateRuleHeader(header DuplicateRuleHeader) DMLOption {
+	return func(options *dmlOptions) {
+		options.setHeader(duplicateRuleHeader, header.String())
+	}
+}
+
+// returns the duplicate rule details of every error caused by a duplicate rule
+func (result SalesforceResult) DuplicateResults() []DuplicateResult {
+	duplicates := []DuplicateResult{}
+	for _, err := range result.Errors {
+		if err.DuplicateResult != nil {
+			duplicates = append(duplicates, *err.DuplicateResult)
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,96 @@
+package salesforce
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDuplicateRuleHeader_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		header DuplicateRuleHeader
+		want   string
+	}{
+		{
+			name:   "defaults",
+			header: DuplicateRuleHeader{},
+			want:   "allowSave=false, includeRecordDetails=false, runAsCurrentUser=false",
+		},
+		{
+			name:   "all_set",
+			header: DuplicateRuleHeader{AllowSave: true, IncludeRecordDetails: true, RunAsCurrentUser: true},
+			want:   "allowSave=true, includeRecordDetails=true, runAsCurrentUser=true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.header.String(); got != tt.want {
+				t.Errorf("DuplicateRuleHeader.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforceResult_DuplicateResults(t *testing.T) {
+	body := `[{
+		"id": null,
+		"success": false,
+		"errors": [{
+			"statusCode": "DUPLICATES_DETECTED",
+			"message": "Use one of these records?",
+			"fields": [],
+			"duplicateResult": {
+				"allowSave": false,
+				"duplicateRule": "Standard_Rule_for_Contacts",
+				"duplicateRuleEntityType": "Contact",
+				"errorMessage": "Use one of these records?",
+				"matchResults": [{
+					"entityType": "Contact",
+					"matchEngine": "FuzzyMatchEngine",
+					"rule": "Standard_Contact_Match_Rule_v1_1",
+					"size": 1,
+					"success": true,
+					"matchRecords": [{
+						"matchConfidence": 100.0,
+						"record": {"Id": "003000000000001AAA"}
+					}]
+				}]
+			}
+		}, {
+			"statusCode": "REQUIRED_FIELD_MISSING",
+			"message": "Required fields are missing: [LastName]",
+			"fields": ["LastName"]
+		}]
+	}]`
+	results, err := processSalesforceResponse(jsonCodec{}, http.Response{Body: io.NopCloser(bytes.NewBufferString(body))})
+	if err != nil {
+		t.Fatalf("processSalesforceResponse() error = %v", err)
+	}
+
+	want := []DuplicateResult{{
+		AllowSave:               false,
+		DuplicateRule:           "Standard_Rule_for_Contacts",
+		DuplicateRuleEntityType: "Contact",
+		ErrorMessage:            "Use one of these records?",
+		MatchResults: []DuplicateMatchResult{{
+			EntityType:  "Contact",
+			MatchEngine: "FuzzyMatchEngine",
+			Rule:        "Standard_Contact_Match_Rule_v1_1",
+			Size:        1,
+			Success:     true,
+			MatchRecords: []DuplicateMatchRecord{{
+				MatchConfidence: 100,
+				Record:          map[string]any{"Id": "003000000000001AAA"},
+			}},
+		}},
+	}}
+	if got := results[0].DuplicateResults(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DuplicateResults() = %v, want %v", got, want)
+	}
+	if got := (SalesforceResult{Success: true}).DuplicateResults(); len(got) != 0 {
+		t.Errorf("DuplicateResults() = %v, want none", got)
+	}
+}
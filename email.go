@@ -0,0 +1,71 @@
+package salesforce
+
+import (
+	"errors"
+)
+
+type EmailMessage struct {
+	To              []string
+	Cc              []string
+	Subject         string
+	Body            string
+	TemplateId      string
+	RecipientId     string
+	RelatedRecordId string
+	SenderType      string
+	SenderAddress   string
+	LogEmail        bool
+}
+
+const (
+	EmailSenderCurrentUser         = "CurrentUser"
+	EmailSenderDefaultWorkflowUser = "DefaultWorkflowUser"
+	EmailSenderOrgWideAddress      = "OrgWideEmailAddress"
+	emailSimpleType                = "emailSimple"
+)
+
+type emailSimpleInput struct {
+	EmailAddressesArray []string `json:"emailAddressesArray,omitempty"`
+	EmailSubject        string   `json:"emailSubject,omitempty"`
+	EmailBody           string   `json:"emailBody,omitempty"`
+	EmailTemplateId     string   `json:"emailTemplateId,omitempty"`
+	RecipientId         string   `json:"recipientId,omitempty"`
+	RelatedRecordId     string   `json:"relatedRecordId,omitempty"`
+	SenderType          string   `json:"senderType,omitempty"`
+	SenderAddress       string   `json:"senderAddress,omitempty"`
+	LogEmailOnSend      bool     `json:"logEmailOnSend,omitempty"`
+}
+
+func validateEmailMessage(message EmailMessage) error {
+	if len(message.To) == 0 && len(message.Cc) == 0 && message.RecipientId == "" {
+		return errors.New("email requires at least one recipient")
+	}
+	if message.TemplateId == "" && (message.Subject == "" || message.Body == "") {
+		return errors.New("email requires a subject and body, or a template id")
+	}
+	if message.SenderType == EmailSenderOrgWideAddress && message.SenderAddress == "" {
+		return errors.New("sender address is required when sending from an org-wide email address")
+	}
+	return nil
+}
+
+// emailSimple has no separate cc list, so cc addresses are sent the email along with the to addresses
+func doSendEmail(auth *authentication, message EmailMessage) error {
+	if err := validateEmailMessage(message); err != nil {
+		return err
+	}
+	addresses := append(append([]string{}, message.To...), message.Cc...)
+	input := emailSimpleInput{
+		EmailAddressesArray: addresses,
+		EmailSubject:        message.Subject,
+		EmailBody:           message.Body,
+		EmailTemplateId:     message.TemplateId,
+		RecipientId:         message.RecipientId,
+		RelatedRecordId:     message.RelatedRecordId,
+		SenderType:          message.SenderType,
+		SenderAddress:       message.SenderAddress,
+		LogEmailOnSend:      message.LogEmail,
+	}
+	_, err := doInvokeStandardAction(auth, emailSimpleType, []any{input})
+	return err
+}
@@ -0,0 +1,111 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_validateEmailMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message EmailMessage
+		wantErr bool
+	}{
+		{
+			name:    "subject_and_body",
+			message: EmailMessage{To: []string{"tony@starkindustries.com"}, Subject: "Sync failed", Body: "3 records failed"},
+			wantErr: false,
+		},
+		{
+			name:    "template_with_recipient_id",
+			message: EmailMessage{RecipientId: "003000000000001", TemplateId: "00X000000000001"},
+			wantErr: false,
+		},
+		{
+			name:    "cc_only",
+			message: EmailMessage{Cc: []string{"pepper@starkindustries.com"}, Subject: "Sync failed", Body: "3 records failed"},
+			wantErr: false,
+		},
+		{
+			name:    "no_recipients",
+			message: EmailMessage{Subject: "Sync failed", Body: "3 records failed"},
+			wantErr: true,
+		},
+		{
+			name:    "no_body",
+			message: EmailMessage{To: []string{"tony@starkindustries.com"}, Subject: "Sync failed"},
+			wantErr: true,
+		},
+		{
+			name:    "org_wide_without_address",
+			message: EmailMessage{To: []string{"tony@starkindustries.com"}, Subject: "Sync failed", Body: "3 records failed", SenderType: EmailSenderOrgWideAddress},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateEmailMessage(tt.message); (err != nil) != tt.wantErr {
+				t.Errorf("validateEmailMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_doSendEmail(t *testing.T) {
+	var gotBody actionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/actions/standard/emailSimple" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotBody); err != nil {
+			panic(err.Error())
+		}
+		if _, err := w.Write([]byte(`[{"actionName": "emailSimple", "errors": null, "isSuccess": true, "outputValues": null}]`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	message := EmailMessage{
+		To:              []string{"tony@starkindustries.com"},
+		Cc:              []string{"pepper@starkindustries.com"},
+		Subject:         "Sync failed",
+		Body:            "3 records failed to sync",
+		RelatedRecordId: "001000000000001",
+		SenderType:      EmailSenderOrgWideAddress,
+		SenderAddress:   "alerts@starkindustries.com",
+		LogEmail:        true,
+	}
+	if err := doSendEmail(&sfAuth, message); err != nil {
+		t.Fatalf("doSendEmail() error = %v", err)
+	}
+	wantInput := map[string]any{
+		"emailAddressesArray": []any{"tony@starkindustries.com", "pepper@starkindustries.com"},
+		"emailSubject":        "Sync failed",
+		"emailBody":           "3 records failed to sync",
+		"relatedRecordId":     "001000000000001",
+		"senderType":          EmailSenderOrgWideAddress,
+		"senderAddress":       "alerts@starkindustries.com",
+		"logEmailOnSend":      true,
+	}
+	if len(gotBody.Inputs) != 1 || !reflect.DeepEqual(gotBody.Inputs[0], wantInput) {
+		t.Errorf("doSendEmail() inputs = %v, want %v", gotBody.Inputs, wantInput)
+	}
+	if len(message.To) != 1 {
+		t.Errorf("doSendEmail() modified message.To = %v", message.To)
+	}
+
+	if err := doSendEmail(&sfAuth, EmailMessage{Subject: "Sync failed"}); err == nil {
+		t.Errorf("doSendEmail() expected validation error")
+	}
+}
@@ -0,0 +1,241 @@
+package salesforce
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	ExportFormatCSV       = "CSV"
+	ExportFormatJSON      = "JSON"
+	ExportFormatJSONLines = "JSONL"
+)
+
+// selects, orders, and renames the columns of bulk query results before they are exported
+type columnSelector struct {
+	indexes []int
+	headers []string
+}
+
+func newColumnSelector(header []string, options BulkQueryOptions) (columnSelector, error) {
+	selector := columnSelector{}
+	if len(options.Columns) == 0 {
+		for i := range header {
+			selector.indexes = append(selector.indexes, i)
+		}
+	} else {
+		// soql field names are case insensitive, so columns are matched the same way
+		positions := make(map[string]int, len(header))
+		for i, name := range header {
+			positions[strings.ToLower(name)] = i
+		}
+		for _, column := range options.Columns {
+			i, ok := positions[strings.ToLower(column)]
+			if !ok {
+				return columnSelector{}, errors.New("column not found in query results: " + column)
+			}
+			selector.indexes = append(selector.indexes, i)
+		}
+	}
+
+	for _, i := range selector.indexes {
+		name := header[i]
+		if renamed, ok := options.ColumnHeaders[name]; ok {
+			name = renamed
+		}
+		selector.headers = append(selector.headers, name)
+	}
+	return selector, nil
+}
+
+func (selector columnSelector) row(record []string) []string {
+	row := make([]string, len(selector.indexes))
+	for i, index := range selector.indexes {
+		if index < len(record) {
+			row[i] = record[index]
+		}
+	}
+	return row
+}
+
+type exportWriter interface {
+	writeHeader(headers []string) error
+	writeRow(row []string) error
+	close() error
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvExportWriter) writeHeader(headers []string) error {
+	return w.writer.Write(headers)
+}
+
+func (w *csvExportWriter) writeRow(row []string) error {
+	return w.writer.Write(row)
+}
+
+func (w *csvExportWriter) close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// writes each record as an object keyed by column header, either as a single array or one object per line
+type jsonExportWriter struct {
+	writer  *bufio.Writer
+	headers []string
+	lines   bool
+	started bool
+}
+
+func (w *jsonExportWriter) writeHeader(headers []string) error {
+	w.headers = headers
+	return nil
+}
+
+func (w *jsonExportWriter) writeRow(row []string) error {
+	if !w.lines {
+		separator := ",\n"
+		if !w.started {
+			separator = "[\n"
+			w.started = true
+		}
+		if _, err := w.writer.WriteString(separator); err != nil {
+			return err
+		}
+	}
+	// fields are written by hand to keep them in column order
+	if err := w.writer.WriteByte('{'); err != nil {
+		return err
+	}
+	for i, header := range w.headers {
+		if i > 0 {
+			if err := w.writer.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		key, _ := json.Marshal(header)
+		value := []byte("null") // bulk results represent null values as empty strings
+		if row[i] != "" {
+			value, _ = json.Marshal(row[i])
+		}
+		if _, err := w.writer.Write(append(append(key, ':'), value...)); err != nil {
+			return err
+		}
+	}
+	if err := w.writer.WriteByte('}'); err != nil {
+		return err
+	}
+	if w.lines {
+		if err := w.writer.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *jsonExportWriter) close() error {
+	if !w.lines {
+		closing := "\n]\n"
+		if !w.started {
+			closing = "[]\n"
+		}
+		if _, err := w.writer.WriteString(closing); err != nil {
+			return err
+		}
+	}
+	return w.writer.Flush()
+}
+
+func newExportWriter(w io.Writer, options BulkQueryOptions) (exportWriter, error) {
+	switch options.Format {
+	case ExportFormatJSON:
+		return &jsonExportWriter{writer: bufio.NewWriter(w)}, nil
+	case ExportFormatJSONLines:
+		return &jsonExportWriter{writer: bufio.NewWriter(w), lines: true}, nil
+	}
+	delimiter, err := getColumnDelimiter(options.ColumnDelimiter)
+	if err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	writer.UseCRLF = options.LineEnding == LineEndingCRLF
+	return &csvExportWriter{writer: writer}, nil
+}
+
+// writes each page of query results to the file as it arrives, gzip compressing it when
+// the path ends in .gz or compression is enabled
+func exportQueryResults(auth *authentication, bulkJobId string, filePath string, options BulkQueryOptions) error {
+	file, err := appFs.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = file
+	var gzipWriter *gzip.Writer
+	if options.Compress || strings.HasSuffix(strings.ToLower(filePath), ".gz") {
+		gzipWriter = gzip.NewWriter(file)
+		w = gzipWriter
+	}
+	writer, err := newExportWriter(w, options)
+	if err == nil {
+		var selector *columnSelector
+		err = collectQueryResults(auth, bulkJobId, options, func(records [][]string) error {
+			if selector == nil {
+				if len(records) == 0 {
+					return nil
+				}
+				newSelector, selectorErr := newColumnSelector(records[0], options)
+				if selectorErr != nil {
+					return selectorErr
+				}
+				selector = &newSelector
+				if writeErr := writer.writeHeader(selector.headers); writeErr != nil {
+					return writeErr
+				}
+				records = records[1:]
+			}
+			for _, record := range records {
+				if writeErr := writer.writeRow(selector.row(record)); writeErr != nil {
+					return writeErr
+				}
+			}
+			return nil
+		})
+		err = errors.Join(err, writer.close())
+	}
+	if gzipWriter != nil {
+		err = errors.Join(err, gzipWriter.Close())
+	}
+	err = errors.Join(err, file.Close())
+	if err != nil {
+		// don't leave a partial export behind
+		_ = appFs.Remove(filePath)
+		return err
+	}
+	return nil
+}
+
+func validateExportOptions(options BulkQueryOptions) error {
+	if options.Format != "" && options.Format != ExportFormatCSV && options.Format != ExportFormatJSON && options.Format != ExportFormatJSONLines {
+		return errors.New("invalid export format: " + options.Format)
+	}
+	seen := map[string]bool{}
+	for _, column := range options.Columns {
+		if column == "" {
+			return errors.New("export columns must not be empty")
+		}
+		if seen[strings.ToLower(column)] {
+			return errors.New("duplicate export column: " + column)
+		}
+		seen[strings.ToLower(column)] = true
+	}
+	return nil
+}
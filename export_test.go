@@ -0,0 +1,318 @@
+package salesforce
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func Test_newColumnSelector(t *testing.T) {
+	records := [][]string{
+		{"Id", "FirstName", "LastName"},
+		{"003A", "Tony", "Stark"},
+		{"003B", "Bruce", "Banner"},
+	}
+	shape := func(options BulkQueryOptions) ([][]string, error) {
+		selector, err := newColumnSelector(records[0], options)
+		if err != nil {
+			return nil, err
+		}
+		shaped := [][]string{selector.headers}
+		for _, record := range records[1:] {
+			shaped = append(shaped, selector.row(record))
+		}
+		return shaped, nil
+	}
+
+	tests := []struct {
+		name    string
+		options BulkQueryOptions
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:    "no_options",
+			options: BulkQueryOptions{},
+			want:    records,
+			wantErr: false,
+		},
+		{
+			name:    "reorder_and_subset",
+			options: BulkQueryOptions{Columns: []string{"lastname", "Id"}},
+			want: [][]string{
+				{"LastName", "Id"},
+				{"Stark", "003A"},
+				{"Banner", "003B"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rename_headers",
+			options: BulkQueryOptions{
+				Columns:       []string{"Id", "LastName"},
+				ColumnHeaders: map[string]string{"Id": "contact_id", "LastName": "last_name"},
+			},
+			want: [][]string{
+				{"contact_id", "last_name"},
+				{"003A", "Stark"},
+				{"003B", "Banner"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing_column",
+			options: BulkQueryOptions{Columns: []string{"Email"}},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := shape(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newColumnSelector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newColumnSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateExportOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		format  string
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			columns: []string{"Id", "Name"},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate",
+			columns: []string{"Id", "id"},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			columns: []string{""},
+			wantErr: true,
+		},
+		{
+			name:    "json_format",
+			format:  ExportFormatJSONLines,
+			wantErr: false,
+		},
+		{
+			name:    "invalid_format",
+			format:  "PARQUET",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateExportOptions(BulkQueryOptions{Columns: tt.columns, Format: tt.format}); (err != nil) != tt.wantErr {
+				t.Errorf("validateExportOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_doQueryBulk_shaped(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+
+	jobCreationRespBody, _ := json.Marshal(bulkJob{Id: "1234", State: BulkJobStateJobComplete})
+	jobResultsRespBody, _ := json.Marshal(BulkJobResults{Id: "1234", State: BulkJobStateJobComplete})
+	// results use the delimiter and line ending the job was created with
+	csvData := "\"Id\"|\"Name\"|\"Industry\"\r\n\"001A\"|\"Stark Industries\"|\"Technology\"\r\n"
+	var jobRequest bulkQueryJobCreationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.RequestURI, "/query"):
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &jobRequest); err != nil {
+				t.Error(err.Error())
+			}
+			if _, err := w.Write(jobCreationRespBody); err != nil {
+				t.Error(err.Error())
+			}
+		case strings.HasSuffix(r.RequestURI, "/1234"):
+			if _, err := w.Write(jobResultsRespBody); err != nil {
+				t.Error(err.Error())
+			}
+		case strings.HasSuffix(r.RequestURI, "/results"):
+			w.Header().Add("Sforce-Locator", "null")
+			w.Header().Add("Sforce-Numberofrecords", "1")
+			if _, err := w.Write([]byte(csvData)); err != nil {
+				t.Error(err.Error())
+			}
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	options := BulkQueryOptions{
+		Columns:         []string{"Name", "Id"},
+		ColumnHeaders:   map[string]string{"Name": "account_name"},
+		ColumnDelimiter: ColumnDelimiterPipe,
+		LineEnding:      LineEndingCRLF,
+	}
+	if err := doQueryBulk(&sfAuth, "data/export.psv", "SELECT Id, Name, Industry FROM Account", options); err != nil {
+		t.Fatalf("doQueryBulk() error = %v", err)
+	}
+	got, err := afero.ReadFile(appFs, "data/export.psv")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := "account_name|Id\r\nStark Industries|001A\r\n"
+	if string(got) != want {
+		t.Errorf("doQueryBulk() wrote %q, want %q", got, want)
+	}
+	wantRequest := bulkQueryJobCreationRequest{
+		Operation:       queryJobType,
+		Query:           "SELECT Id, Name, Industry FROM Account",
+		ColumnDelimiter: ColumnDelimiterPipe,
+		LineEnding:      LineEndingCRLF,
+	}
+	if jobRequest != wantRequest {
+		t.Errorf("doQueryBulk() created job with %v, want %v", jobRequest, wantRequest)
+	}
+
+	options.Columns = []string{"Email"}
+	if err := doQueryBulk(&sfAuth, "data/missing.csv", "SELECT Id, Name, Industry FROM Account", options); err == nil {
+		t.Errorf("doQueryBulk() expected error for missing column")
+	}
+	if exists, _ := afero.Exists(appFs, "data/missing.csv"); exists {
+		t.Errorf("doQueryBulk() left a partial export behind")
+	}
+}
+
+func Test_exportQueryResults_gzip(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("locator") == "" {
+			w.Header().Add("Sforce-Locator", "abc")
+		} else {
+			w.Header().Add("Sforce-Locator", "null")
+		}
+		w.Header().Add("Sforce-Numberofrecords", "1")
+		if _, err := w.Write([]byte("Id\n001\n")); err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		options  BulkQueryOptions
+	}{
+		{
+			name:     "gz_extension",
+			filePath: "data/export.csv.gz",
+			options:  BulkQueryOptions{},
+		},
+		{
+			name:     "compress_option",
+			filePath: "data/export.csv",
+			options:  BulkQueryOptions{Compress: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := exportQueryResults(&sfAuth, "1234", tt.filePath, tt.options); err != nil {
+				t.Fatalf("exportQueryResults() error = %v", err)
+			}
+			file, err := appFs.Open(tt.filePath)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			defer file.Close()
+			reader, err := gzip.NewReader(file)
+			if err != nil {
+				t.Fatalf("exportQueryResults() did not write gzip data: %v", err)
+			}
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if string(got) != "Id\n001\n001\n" {
+				t.Errorf("exportQueryResults() wrote %q", got)
+			}
+		})
+	}
+}
+
+func Test_jsonExportWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		rows   [][]string
+		want   string
+	}{
+		{
+			name:   "json_array",
+			format: ExportFormatJSON,
+			rows:   [][]string{{"001A", "Stark \"Industries\""}, {"001B", ""}},
+			want:   "[\n{\"Name\":\"Stark \\\"Industries\\\"\",\"Id\":\"001A\"},\n{\"Name\":null,\"Id\":\"001B\"}\n]\n",
+		},
+		{
+			name:   "json_lines",
+			format: ExportFormatJSONLines,
+			rows:   [][]string{{"001A", "Stark"}, {"001B", "Wayne"}},
+			want:   "{\"Name\":\"Stark\",\"Id\":\"001A\"}\n{\"Name\":\"Wayne\",\"Id\":\"001B\"}\n",
+		},
+		{
+			name:   "empty_json_array",
+			format: ExportFormatJSON,
+			rows:   nil,
+			want:   "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := newExportWriter(&buf, BulkQueryOptions{Format: tt.format})
+			if err != nil {
+				t.Fatalf("newExportWriter() error = %v", err)
+			}
+			selector, _ := newColumnSelector([]string{"Id", "Name"}, BulkQueryOptions{Columns: []string{"Name", "Id"}})
+			if tt.rows != nil {
+				if err := writer.writeHeader(selector.headers); err != nil {
+					t.Fatal(err.Error())
+				}
+			}
+			for _, row := range tt.rows {
+				if err := writer.writeRow(selector.row(row)); err != nil {
+					t.Fatal(err.Error())
+				}
+			}
+			if err := writer.close(); err != nil {
+				t.Fatal(err.Error())
+			}
+			if buf.String() != tt.want {
+				t.Errorf("jsonExportWriter wrote %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// updates look up the record's Id before patching it, so each record takes two subrequests
+	externalIdUpdatesPerRequest = 12
+	externalIdDeletesPerRequest = 25
+)
+
+func externalIdValue(recordMap map[string]any, sObjectName string, fieldName string) (string, error) {
+	value, ok := recordMap[fieldName].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("salesforce externalId: %s not found in %s data. make sure to append custom fields with '__c'", fieldName, sObjectName)
+	}
+	return value, nil
+}
+
+func externalIdUri(sObjectName string, fieldName string, value string) string {
+	return "/sobjects/" + sObjectName + "/" + fieldName + "/" + url.PathEscape(value)
+}
+
+// a PATCH to the external id url would create the record when none match, so
+// the Id is looked up first and the update is chained to it with a reference
+func addExternalIdUpdate(builder *CompositeBuilder, sObjectName string, fieldName string, recordMap map[string]any, position int) error {
+	value, err := externalIdValue(recordMap, sObjectName, fieldName)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{}
+	for key, fieldValue := range recordMap {
+		if key != fieldName && key != "Id" {
+			body[key] = fieldValue
+		}
+	}
+	lookupRef := "lookup" + strconv.Itoa(position)
+	builder.Add(http.MethodGet, externalIdUri(sObjectName, fieldName, value)+"?fields=Id", nil, lookupRef)
+	builder.Add(http.MethodPatch, "/sobjects/"+sObjectName+"/@{"+lookupRef+".Id}", body, "update"+strconv.Itoa(position))
+	return builder.err
+}
+
+func addExternalIdDelete(builder *CompositeBuilder, sObjectName string, fieldName string, recordMap map[string]any, position int) error {
+	value, err := externalIdValue(recordMap, sObjectName, fieldName)
+	if err != nil {
+		return err
+	}
+	builder.Add(http.MethodDelete, externalIdUri(sObjectName, fieldName, value), nil, "delete"+strconv.Itoa(position))
+	return builder.err
+}
+
+func compositeResultErrors(result CompositeResult) []SalesforceErrorMessage {
+	var errs []SalesforceErrorMessage
+	if err := json.Unmarshal(result.Body, &errs); err != nil || len(errs) == 0 {
+		return []SalesforceErrorMessage{{
+			Message:    string(result.Body),
+			StatusCode: strconv.Itoa(result.HttpStatusCode),
+		}}
+	}
+	return errs
+}
+
+// converts the subrequest results of one record, where lookup is nil for deletes
+func externalIdResult(lookup *CompositeResult, change CompositeResult, metadata ResponseMetadata) SalesforceResult {
+	result := SalesforceResult{ResponseMetadata: metadata}
+	result.StatusCode = change.HttpStatusCode
+	if lookup != nil {
+		if lookup.HttpStatusCode >= http.StatusMultipleChoices {
+			result.StatusCode = lookup.HttpStatusCode
+			result.Errors = compositeResultErrors(*lookup)
+			return result
+		}
+		record := map[string]any{}
+		if err := lookup.Decode(&record); err == nil {
+			result.Id, _ = record["Id"].(string)
+		}
+	}
+	if change.HttpStatusCode >= http.StatusMultipleChoices {
+		result.Errors = compositeResultErrors(change)
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+func doExternalIdCollection(auth *authentication, sObjectName string, fieldName string, records any, update bool, options dmlOptions) (SalesforceResults, error) {
+	recordMap, err := convertToSliceOfMaps(records, options.nullNilPointers)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if update {
+		if err := validateRecordFields(auth, sObjectName, recordMap); err != nil {
+			return SalesforceResults{}, err
+		}
+	}
+	for i := range recordMap {
+		if update {
+			setFieldsToNull(recordMap[i], options.fieldsToNull)
+		}
+		if _, err := externalIdValue(recordMap[i], sObjectName, fieldName); err != nil {
+			return SalesforceResults{}, err
+		}
+	}
+
+	profile := auth.config.throttleProfile(sObjectName)
+	batchSize := profile.batchSize(externalIdDeletesPerRequest)
+	if update {
+		batchSize = profile.batchSize(externalIdUpdatesPerRequest)
+	}
+	var batches [][]map[string]any
+	for len(recordMap) > 0 {
+		size := min(len(recordMap), batchSize)
+		batches = append(batches, recordMap[:size])
+		recordMap = recordMap[size:]
+	}
+
+	workers := auth.config.workers()
+	if profile.InterBatchDelay > 0 {
+		workers = 1
+	}
+	batchResults := make([][]SalesforceResult, len(batches))
+	batchMetadata := make([]ResponseMetadata, len(batches))
+	err = runBatches(batchSizes(batches), workers, options.continueOnError, func(i int) error {
+		profile.wait(i)
+		builder := NewCompositeBuilder(false)
+		for position, record := range batches[i] {
+			var addErr error
+			if update {
+				addErr = addExternalIdUpdate(builder, sObjectName, fieldName, record, position)
+			} else {
+				addErr = addExternalIdDelete(builder, sObjectName, fieldName, record, position)
+			}
+			if addErr != nil {
+				return addErr
+			}
+		}
+		compositeResults, err := doExecuteComposite(auth, builder, options)
+		if err != nil {
+			return err
+		}
+
+		subRequestsPerRecord := 1
+		if update {
+			subRequestsPerRecord = 2
+		}
+		if len(compositeResults.Results) != len(batches[i])*subRequestsPerRecord {
+			return errors.New("unexpected number of composite results: " + strconv.Itoa(len(compositeResults.Results)))
+		}
+		results := make([]SalesforceResult, len(batches[i]))
+		for position := range batches[i] {
+			if update {
+				results[position] = externalIdResult(&compositeResults.Results[2*position], compositeResults.Results[2*position+1], compositeResults.ResponseMetadata)
+			} else {
+				results[position] = externalIdResult(nil, compositeResults.Results[position], compositeResults.ResponseMetadata)
+			}
+		}
+		batchMetadata[i] = compositeResults.ResponseMetadata
+		batchResults[i] = setResultIndexes(results, i*batchSize)
+		return nil
+	})
+
+	results := mergeBatchResults(batchResults, batchMetadata)
+	if err != nil {
+		return results, err
+	}
+
+	if !update && options.ignoreDeleted {
+		return ignoreDeletedResults(results), nil
+	}
+
+	for _, result := range results.Results {
+		if !result.Success {
+			results.HasSalesforceErrors = true
+		}
+	}
+	return results, nil
+}
+
+func doUpdateByExternalId(auth *authentication, sObjectName string, fieldName string, record any, options dmlOptions) error {
+	recordMap, err := convertToMap(record, options.nullNilPointers)
+	if err != nil {
+		return err
+	}
+	results, err := doExternalIdCollection(auth, sObjectName, fieldName, []map[string]any{recordMap}, true, options)
+	if err != nil {
+		return err
+	}
+	if results.HasSalesforceErrors {
+		// a lookup that matched several records keeps salesforce's list of record urls as its message
+		if results.Results[0].StatusCode == http.StatusMultipleChoices {
+			return newMultipleMatchesError([]byte(results.Results[0].Errors[0].Message))
+		}
+		errs, _ := json.Marshal(results.Results[0].Errors)
+		return errors.New(string(errs))
+	}
+	return nil
+}
+
+func doDeleteByExternalId(auth *authentication, sObjectName string, fieldName string, record any, options dmlOptions) error {
+	recordMap, err := convertToMap(record, options.nullNilPointers)
+	if err != nil {
+		return err
+	}
+	value, err := externalIdValue(recordMap, sObjectName, fieldName)
+	if err != nil {
+		return err
+	}
+
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodDelete,
+		uri:     externalIdUri(sObjectName, fieldName, value),
+		content: jsonType,
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
+	})
+	if err != nil {
+		return err
+	}
+	return checkMultipleMatches(resp)
+}
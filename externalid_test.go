@@ -0,0 +1,355 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// responds to each composite subrequest as salesforce would, treating external ids containing "duplicate", "missing", or "deleted" as failures
+func setupExternalIdTestServer(t *testing.T) (*httptest.Server, authentication, *[]compositeBuilderSubRequest) {
+	var gotRequests []compositeBuilderSubRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req compositeBuilderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotRequests = append(gotRequests, req.CompositeRequest...)
+		var results []map[string]any
+		for _, subRequest := range req.CompositeRequest {
+			result := map[string]any{"referenceId": subRequest.ReferenceId, "httpHeaders": map[string]string{}}
+			switch {
+			case strings.Contains(subRequest.Url, "duplicate"):
+				result["httpStatusCode"] = http.StatusMultipleChoices
+				result["body"] = []string{"/services/data/" + apiVersion + "/sobjects/Account/001A", "/services/data/" + apiVersion + "/sobjects/Account/001B"}
+			case strings.Contains(subRequest.Url, "deleted"):
+				result["httpStatusCode"] = http.StatusNotFound
+				result["body"] = []map[string]any{{"errorCode": "ENTITY_IS_DELETED", "message": "entity is deleted"}}
+			case strings.Contains(subRequest.Url, "missing"):
+				result["httpStatusCode"] = http.StatusNotFound
+				result["body"] = []map[string]any{{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}}
+			case subRequest.Method == http.MethodGet:
+				result["httpStatusCode"] = http.StatusOK
+				result["body"] = map[string]any{"Id": "001" + subRequest.ReferenceId}
+			default:
+				result["httpStatusCode"] = http.StatusNoContent
+			}
+			results = append(results, result)
+		}
+		body, _ := json.Marshal(map[string]any{"compositeResponse": results})
+		if _, err := w.Write(body); err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	return server, sfAuth, &gotRequests
+}
+
+func Test_externalIdUri(t *testing.T) {
+	got := externalIdUri("Account", "External_Id__c", "abc/123 4")
+	want := "/sobjects/Account/External_Id__c/abc%2F123%204"
+	if got != want {
+		t.Errorf("externalIdUri() = %v, want %v", got, want)
+	}
+}
+
+func Test_doUpdateByExternalId(t *testing.T) {
+	type account struct {
+		External_Id__c string `json:"External_Id__c,omitempty"`
+		Name           string
+	}
+
+	server, sfAuth, gotRequests := setupExternalIdTestServer(t)
+	defer server.Close()
+
+	badReqServer, badReqSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badReqServer.Close()
+
+	type args struct {
+		auth   *authentication
+		record any
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "successful_update",
+			args: args{
+				auth:   &sfAuth,
+				record: account{External_Id__c: "acc-1", Name: "test account"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "record_not_found",
+			args: args{
+				auth:   &sfAuth,
+				record: account{External_Id__c: "missing-1", Name: "test account"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing_external_id",
+			args: args{
+				auth:   &sfAuth,
+				record: account{Name: "test account"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:   &badReqSfAuth,
+				record: account{External_Id__c: "acc-1", Name: "test account"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad_data",
+			args: args{
+				auth:   &sfAuth,
+				record: "1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := doUpdateByExternalId(tt.args.auth, "Account", "External_Id__c", tt.args.record, dmlOptions{}); (err != nil) != tt.wantErr {
+				t.Errorf("doUpdateByExternalId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	err := doUpdateByExternalId(&sfAuth, "Account", "External_Id__c", account{External_Id__c: "duplicate-1"}, dmlOptions{})
+	var matches *MultipleMatchesError
+	if !errors.As(err, &matches) || !reflect.DeepEqual(matches.RecordIds, []string{"001A", "001B"}) {
+		t.Errorf("doUpdateByExternalId() error = %v, want multiple matches", err)
+	}
+
+	requests := *gotRequests
+	if len(requests) < 2 {
+		t.Fatalf("doUpdateByExternalId() sent %v", requests)
+	}
+	wantLookup := "/services/data/" + apiVersion + "/sobjects/Account/External_Id__c/acc-1?fields=Id"
+	if requests[0].Method != http.MethodGet || requests[0].Url != wantLookup {
+		t.Errorf("doUpdateByExternalId() lookup = %v %v", requests[0].Method, requests[0].Url)
+	}
+	wantUpdate := "/services/data/" + apiVersion + "/sobjects/Account/@{lookup0.Id}"
+	if requests[1].Method != http.MethodPatch || requests[1].Url != wantUpdate || string(requests[1].Body) != `{"Name":"test account"}` {
+		t.Errorf("doUpdateByExternalId() update = %v %v %s", requests[1].Method, requests[1].Url, requests[1].Body)
+	}
+}
+
+func Test_doDeleteByExternalId(t *testing.T) {
+	type account struct {
+		External_Id__c string
+	}
+
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badReqServer, badReqSfAuth := setupTestServer("", http.StatusNotFound)
+	defer badReqServer.Close()
+
+	type args struct {
+		auth   *authentication
+		record any
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantPath string
+		wantErr  bool
+	}{
+		{
+			name: "successful_delete",
+			args: args{
+				auth:   &sfAuth,
+				record: account{External_Id__c: "acc 1"},
+			},
+			wantPath: "/services/data/" + apiVersion + "/sobjects/Account/External_Id__c/acc%201",
+			wantErr:  false,
+		},
+		{
+			name: "not_found",
+			args: args{
+				auth:   &badReqSfAuth,
+				record: account{External_Id__c: "acc-1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing_external_id",
+			args: args{
+				auth:   &sfAuth,
+				record: account{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath = ""
+			if err := doDeleteByExternalId(tt.args.auth, "Account", "External_Id__c", tt.args.record, dmlOptions{}); (err != nil) != tt.wantErr {
+				t.Errorf("doDeleteByExternalId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantPath != "" && gotPath != tt.wantPath {
+				t.Errorf("doDeleteByExternalId() path = %v, want %v", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
+
+func Test_doExternalIdCollection(t *testing.T) {
+	type account struct {
+		External_Id__c string
+		Name           string
+	}
+	records := []account{
+		{External_Id__c: "acc-1", Name: "test account 1"},
+		{External_Id__c: "missing-2", Name: "test account 2"},
+	}
+	notFound := []SalesforceErrorMessage{{ErrorCode: "NOT_FOUND", Message: "The requested resource does not exist"}}
+
+	server, sfAuth, _ := setupExternalIdTestServer(t)
+	defer server.Close()
+
+	badReqServer, badReqSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badReqServer.Close()
+
+	type args struct {
+		auth    *authentication
+		records any
+		update  bool
+		options dmlOptions
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    SalesforceResults
+		wantErr bool
+	}{
+		{
+			name: "update_partial_success",
+			args: args{
+				auth:    &sfAuth,
+				records: records,
+				update:  true,
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{
+					{Id: "001lookup0", Success: true, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusNoContent}},
+					{Errors: notFound, Index: 1, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusNotFound}},
+				},
+				HasSalesforceErrors: true,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete_partial_success",
+			args: args{
+				auth:    &sfAuth,
+				records: records,
+				update:  false,
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{
+					{Success: true, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusNoContent}},
+					{Errors: notFound, Index: 1, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusNotFound}},
+				},
+				HasSalesforceErrors: true,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete_ignore_deleted",
+			args: args{
+				auth:    &sfAuth,
+				records: []account{{External_Id__c: "acc-1"}, {External_Id__c: "deleted-2"}},
+				update:  false,
+				options: dmlOptions{ignoreDeleted: true},
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{
+					{Success: true, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusNoContent}},
+					{Index: 1, Success: true, ResponseMetadata: ResponseMetadata{StatusCode: http.StatusNotFound}},
+				},
+				ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing_external_id",
+			args: args{
+				auth:    &sfAuth,
+				records: []account{{Name: "test account"}},
+				update:  true,
+			},
+			want:    SalesforceResults{},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:    &badReqSfAuth,
+				records: records,
+				update:  true,
+			},
+			want:    SalesforceResults{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doExternalIdCollection(tt.args.auth, "Account", "External_Id__c", tt.args.records, tt.args.update, tt.args.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doExternalIdCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doExternalIdCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doExternalIdCollection_batches(t *testing.T) {
+	server, sfAuth, gotRequests := setupExternalIdTestServer(t)
+	defer server.Close()
+
+	records := make([]map[string]any, 30)
+	for i := range records {
+		records[i] = map[string]any{"External_Id__c": "acc", "Name": "test account"}
+	}
+	results, err := doExternalIdCollection(&sfAuth, "Account", "External_Id__c", records, true, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doExternalIdCollection() error = %v", err)
+	}
+	if len(results.Results) != 30 || results.Results[29].Index != 29 || results.HasSalesforceErrors {
+		t.Errorf("doExternalIdCollection() = %v", results)
+	}
+	if len(*gotRequests) != 60 {
+		t.Errorf("doExternalIdCollection() sent %d subrequests, want 60", len(*gotRequests))
+	}
+}
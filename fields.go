@@ -0,0 +1,304 @@
+package salesforce
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+type fieldTagOptions struct {
+	name        string
+	multiSelect bool
+	omitEmpty   bool
+}
+
+const (
+	fieldTag              = "sf"
+	multiSelectOption     = "multiselect"
+	omitEmptyOption       = "omitempty"
+	multiSelectSeparator  = ";"
+	relationshipSeparator = "."
+)
+
+func parseFieldTag(field reflect.StructField) fieldTagOptions {
+	options := fieldTagOptions{}
+	tag, ok := field.Tag.Lookup(fieldTag)
+	if !ok {
+		return options
+	}
+	parts := strings.Split(tag, ",")
+	options.name = parts[0]
+	for _, part := range parts[1:] {
+		switch part {
+		case multiSelectOption:
+			options.multiSelect = true
+		case omitEmptyOption:
+			options.omitEmpty = true
+		}
+	}
+	return options
+}
+
+// mapstructure keys struct fields by their mapstructure tag when present, otherwise by the field name
+func mapstructureKey(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("mapstructure"); ok {
+		name := strings.Split(tag, ",")[0]
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+var timeTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}): true,
+	reflect.TypeOf(Date{}):      true,
+	reflect.TypeOf(Datetime{}):  true,
+	reflect.TypeOf(Time{}):      true,
+}
+
+// mapstructure decodes time fields into empty maps, leaves pointer fields as pointers, and doesn't apply sf tags to nested structs,
+// so time values are copied back, pointers are dereferenced, and nested records are prepared the same way as the record itself.
+// nil pointer fields are left out of the record unless nullNilPointers is set, in which case they clear the field.
+// zero Date, Datetime, and Time fields are always left out, so clearing them requires WithFieldsToNull
+func prepareRecord(value reflect.Value, record map[string]any, nullNilPointers bool) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := mapstructureKey(field)
+		current, ok := record[key]
+		if !ok || !field.IsExported() {
+			continue
+		}
+		fieldValue := value.Field(i)
+		isPointer := fieldValue.Kind() == reflect.Pointer
+		if isPointer {
+			if fieldValue.IsNil() {
+				if nullNilPointers {
+					record[key] = nil
+				} else {
+					delete(record, key)
+				}
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		switch {
+		case timeTypes[fieldValue.Type()]:
+			if fieldValue.IsZero() && fieldValue.Type() != reflect.TypeOf(time.Time{}) {
+				delete(record, key)
+				continue
+			}
+			record[key] = fieldValue.Interface()
+		case fieldValue.Kind() == reflect.Struct:
+			nested, ok := current.(map[string]any)
+			if !ok {
+				if err := mapstructure.Decode(fieldValue.Interface(), &nested); err != nil {
+					continue
+				}
+				record[key] = nested
+			}
+			prepareRecord(fieldValue, nested, nullNilPointers)
+		case isPointer:
+			record[key] = fieldValue.Interface()
+		}
+	}
+	applyFieldTags(t, record)
+}
+
+// dereferences pointers, returning nil for nil pointers
+func indirectValue(value any) any {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func structType(t reflect.Type) (reflect.Type, bool) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return t, true
+}
+
+func findKey(record map[string]any, key string) (string, bool) {
+	if _, ok := record[key]; ok {
+		return key, true
+	}
+	for k := range record {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// applies sf tags to a record decoded from a struct before it is sent to salesforce
+func applyFieldTags(t reflect.Type, record map[string]any) {
+	t, ok := structType(t)
+	if !ok {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		options := parseFieldTag(field)
+		key := mapstructureKey(field)
+		value, ok := record[key]
+		if !ok {
+			continue
+		}
+		if values, ok := value.([]string); ok && options.multiSelect {
+			value = strings.Join(values, multiSelectSeparator)
+			record[key] = value
+		}
+		if options.omitEmpty && isEmptyValue(value) {
+			delete(record, key)
+			continue
+		}
+		if options.name != "" && options.name != key {
+			delete(record, key)
+			record[options.name] = value
+		}
+	}
+}
+
+// applies sf tags to records returned by salesforce so they can be decoded into a struct
+func resolveFieldTags(t reflect.Type, records []map[string]any) {
+	t, ok := structType(t)
+	if !ok {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		options := parseFieldTag(field)
+		name := mapstructureKey(field)
+		if options.name != "" {
+			name = options.name
+		}
+		for _, record := range records {
+			var key string
+			var value any
+			if strings.Contains(name, relationshipSeparator) {
+				value, ok = lookupRelationshipField(record, strings.Split(name, relationshipSeparator))
+			} else {
+				key, ok = findKey(record, name)
+				value = record[key]
+			}
+			if !ok {
+				continue
+			}
+			if key != "" {
+				delete(record, key)
+			}
+			record[mapstructureKey(field)] = resolveFieldValue(field.Type, options, value)
+		}
+	}
+}
+
+func resolveFieldValue(t reflect.Type, options fieldTagOptions, value any) any {
+	switch v := value.(type) {
+	case string:
+		if !options.multiSelect {
+			return v
+		}
+		if v == "" {
+			return []string{}
+		}
+		return strings.Split(v, multiSelectSeparator)
+	case map[string]any:
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Slice {
+			resolveFieldTags(t, []map[string]any{v})
+			return v
+		}
+		// child relationship subqueries are returned as a nested query result
+		records, ok := v["records"].([]any)
+		if !ok {
+			return v
+		}
+		for _, record := range records {
+			if recordMap, ok := record.(map[string]any); ok {
+				resolveFieldTags(t.Elem(), []map[string]any{recordMap})
+			}
+		}
+		return records
+	}
+	return value
+}
+
+// follows a dotted relationship path such as Account.Owner.Name through nested records
+func lookupRelationshipField(record map[string]any, path []string) (any, bool) {
+	var value any = record
+	for _, name := range path {
+		current, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		key, ok := findKey(current, name)
+		if !ok {
+			return nil, false
+		}
+		value = current[key]
+	}
+	return value, true
+}
+
+// related records include their own attributes, which can't be decoded into the parent's struct fields
+func stripRelationshipAttributes(records []map[string]any) {
+	for _, record := range records {
+		for _, value := range record {
+			stripAttributes(value)
+		}
+	}
+}
+
+func stripAttributes(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		delete(v, "attributes")
+		for _, field := range v {
+			stripAttributes(field)
+		}
+	case []any:
+		for _, item := range v {
+			stripAttributes(item)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package salesforce
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type multiSelectRecord struct {
+	Id           string
+	Interests__c []string `sf:",multiselect"`
+	Tags         []string
+}
+
+type taggedRecord struct {
+	Id          string
+	Name        string   `sf:"Name__c"`
+	ExternalId  string   `sf:"External_Id__c,omitempty"`
+	Description string   `sf:",omitempty"`
+	Interests   []string `sf:"Interests__c,multiselect,omitempty"`
+}
+
+func Test_parseFieldTag(t *testing.T) {
+	type record struct {
+		None        string
+		MultiSelect []string `sf:",multiselect"`
+		Other       []string `sf:",other"`
+		Named       string   `sf:"Named__c,omitempty"`
+	}
+	recordType := reflect.TypeOf(record{})
+	tests := []struct {
+		name  string
+		field string
+		want  fieldTagOptions
+	}{
+		{
+			name:  "no_tag",
+			field: "None",
+			want:  fieldTagOptions{},
+		},
+		{
+			name:  "multiselect",
+			field: "MultiSelect",
+			want:  fieldTagOptions{multiSelect: true},
+		},
+		{
+			name:  "unknown_option",
+			field: "Other",
+			want:  fieldTagOptions{},
+		},
+		{
+			name:  "name_and_omitempty",
+			field: "Named",
+			want:  fieldTagOptions{name: "Named__c", omitEmpty: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, _ := recordType.FieldByName(tt.field)
+			if got := parseFieldTag(field); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFieldTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyFieldTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType reflect.Type
+		record     map[string]any
+		want       map[string]any
+	}{
+		{
+			name:       "multiselect",
+			recordType: reflect.TypeOf(&multiSelectRecord{}),
+			record: map[string]any{
+				"Id":           "001",
+				"Interests__c": []string{"Hiking", "Chess"},
+				"Tags":         []string{"a", "b"},
+			},
+			want: map[string]any{
+				"Id":           "001",
+				"Interests__c": "Hiking;Chess",
+				"Tags":         []string{"a", "b"},
+			},
+		},
+		{
+			name:       "rename_and_omit_empty",
+			recordType: reflect.TypeOf(taggedRecord{}),
+			record: map[string]any{
+				"Id":          "001",
+				"Name":        "test",
+				"ExternalId":  "",
+				"Description": "",
+				"Interests":   []string{},
+			},
+			want: map[string]any{
+				"Id":      "001",
+				"Name__c": "test",
+			},
+		},
+		{
+			name:       "rename_with_values",
+			recordType: reflect.TypeOf(taggedRecord{}),
+			record: map[string]any{
+				"Id":          "001",
+				"Name":        "",
+				"ExternalId":  "ext1",
+				"Description": "desc",
+				"Interests":   []string{"Hiking"},
+			},
+			want: map[string]any{
+				"Id":             "001",
+				"Name__c":        "",
+				"External_Id__c": "ext1",
+				"Description":    "desc",
+				"Interests__c":   "Hiking",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyFieldTags(tt.recordType, tt.record)
+			if !reflect.DeepEqual(tt.record, tt.want) {
+				t.Errorf("applyFieldTags() = %v, want %v", tt.record, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolveFieldTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType reflect.Type
+		records    []map[string]any
+		want       []map[string]any
+	}{
+		{
+			name:       "multiselect",
+			recordType: reflect.TypeOf(multiSelectRecord{}),
+			records: []map[string]any{
+				{"Id": "001", "interests__c": "Hiking;Chess"},
+				{"Id": "002", "Interests__c": ""},
+				{"Id": "003", "Interests__c": nil},
+			},
+			want: []map[string]any{
+				{"Id": "001", "Interests__c": []string{"Hiking", "Chess"}},
+				{"Id": "002", "Interests__c": []string{}},
+				{"Id": "003", "Interests__c": nil},
+			},
+		},
+		{
+			name:       "rename",
+			recordType: reflect.TypeOf(taggedRecord{}),
+			records: []map[string]any{
+				{"Id": "001", "Name__c": "test", "External_Id__c": "ext1", "Interests__c": "Hiking"},
+			},
+			want: []map[string]any{
+				{"Id": "001", "Name": "test", "ExternalId": "ext1", "Interests": []string{"Hiking"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolveFieldTags(tt.recordType, tt.records)
+			if !reflect.DeepEqual(tt.records, tt.want) {
+				t.Errorf("resolveFieldTags() = %v, want %v", tt.records, tt.want)
+			}
+		})
+	}
+}
+
+func Test_convertToMap_multiSelect(t *testing.T) {
+	got, err := convertToMap(multiSelectRecord{Id: "001", Interests__c: []string{"Hiking", "Chess"}}, false)
+	if err != nil {
+		t.Fatalf("convertToMap() error = %v", err)
+	}
+	if got["Interests__c"] != "Hiking;Chess" {
+		t.Errorf("convertToMap() Interests__c = %v, want %v", got["Interests__c"], "Hiking;Chess")
+	}
+
+	gotSlice, err := convertToSliceOfMaps([]multiSelectRecord{{Id: "001", Interests__c: []string{"Hiking"}}}, false)
+	if err != nil {
+		t.Fatalf("convertToSliceOfMaps() error = %v", err)
+	}
+	if gotSlice[0]["Interests__c"] != "Hiking" {
+		t.Errorf("convertToSliceOfMaps() Interests__c = %v, want %v", gotSlice[0]["Interests__c"], "Hiking")
+	}
+}
+
+func Test_convertToMap_fieldNames(t *testing.T) {
+	got, err := convertToMap(taggedRecord{Id: "001", Name: "test"}, false)
+	if err != nil {
+		t.Fatalf("convertToMap() error = %v", err)
+	}
+	want := map[string]any{"Id": "001", "Name__c": "test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertToMap() = %v, want %v", got, want)
+	}
+}
+
+func Test_convertToSliceOfMaps_timeFields(t *testing.T) {
+	type event struct {
+		Name      string
+		StartDate Date       `sf:"Start_Date__c"`
+		StartTime time.Time  `mapstructure:"Start_Time__c"`
+		EndTime   *time.Time `sf:",omitempty"`
+		Reminder  *time.Time
+	}
+	start := time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)
+	got, err := convertToSliceOfMaps([]event{{Name: "launch", StartDate: Date(start), StartTime: start, Reminder: &start}}, false)
+	if err != nil {
+		t.Fatalf("convertToSliceOfMaps() error = %v", err)
+	}
+	want := []map[string]any{{"Name": "launch", "Start_Date__c": Date(start), "Start_Time__c": start, "Reminder": start}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertToSliceOfMaps() = %v, want %v", got, want)
+	}
+}
+
+func Test_convertToMap_nestedStructs(t *testing.T) {
+	type parent struct {
+		ExternalId string    `sf:"External_Id__c"`
+		Founded    time.Time `sf:",omitempty"`
+	}
+	type account struct {
+		Name   *string
+		Phone  *string
+		Parent parent
+	}
+	name := "Wayne Enterprises"
+	got, err := convertToMap(account{Name: &name, Parent: parent{ExternalId: "acme-1"}}, false)
+	if err != nil {
+		t.Fatalf("convertToMap() error = %v", err)
+	}
+	want := map[string]any{"Name": "Wayne Enterprises", "Parent": map[string]any{"External_Id__c": "acme-1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertToMap() = %v, want %v", got, want)
+	}
+}
+
+func Test_convertToMap_nilPointers(t *testing.T) {
+	type parent struct {
+		ExternalId string `sf:"External_Id__c"`
+	}
+	type contact struct {
+		Id              string
+		Phone           *string
+		NumberOfPets__c *int
+		Account         *parent
+		Owner           *parent
+	}
+	pets := 2
+	record := contact{Id: "003", NumberOfPets__c: &pets, Account: &parent{ExternalId: "acme-1"}}
+
+	tests := []struct {
+		name            string
+		nullNilPointers bool
+		want            map[string]any
+	}{
+		{
+			name:            "omit_nil_pointers",
+			nullNilPointers: false,
+			want: map[string]any{
+				"Id":              "003",
+				"NumberOfPets__c": 2,
+				"Account":         map[string]any{"External_Id__c": "acme-1"},
+			},
+		},
+		{
+			name:            "null_nil_pointers",
+			nullNilPointers: true,
+			want: map[string]any{
+				"Id":              "003",
+				"Phone":           nil,
+				"NumberOfPets__c": 2,
+				"Account":         map[string]any{"External_Id__c": "acme-1"},
+				"Owner":           nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertToMap(record, tt.nullNilPointers)
+			if err != nil {
+				t.Fatalf("convertToMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertToMap() = %v, want %v", got, tt.want)
+			}
+			gotSlice, err := convertToSliceOfMaps([]contact{record}, tt.nullNilPointers)
+			if err != nil {
+				t.Fatalf("convertToSliceOfMaps() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotSlice, []map[string]any{tt.want}) {
+				t.Errorf("convertToSliceOfMaps() = %v, want %v", gotSlice, []map[string]any{tt.want})
+			}
+		})
+	}
+}
+
+func Test_convertToMap_zeroTimeFields(t *testing.T) {
+	type opportunity struct {
+		Name          string
+		CloseDate     Date
+		LastActivity  Datetime
+		PreferredTime *Time
+		Created       time.Time
+	}
+	for _, nullNilPointers := range []bool{false, true} {
+		got, err := convertToMap(opportunity{Name: "Renewal", PreferredTime: &Time{}}, nullNilPointers)
+		if err != nil {
+			t.Fatalf("convertToMap() error = %v", err)
+		}
+		want := map[string]any{"Name": "Renewal", "Created": time.Time{}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("convertToMap() = %v, want %v", got, want)
+		}
+		setFieldsToNull(got, []string{"CloseDate"})
+		if value, ok := got["CloseDate"]; !ok || value != nil {
+			t.Errorf("setFieldsToNull() CloseDate = %v, want nil", value)
+		}
+	}
+}
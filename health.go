@@ -0,0 +1,120 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type TokenInfo struct {
+	Active    bool
+	Scope     string
+	ClientId  string
+	Username  string
+	Subject   string
+	TokenType string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type introspectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope"`
+	ClientId  string `json:"client_id"`
+	Username  string `json:"username"`
+	Sub       string `json:"sub"`
+	TokenType string `json:"token_type"`
+	Iat       int64  `json:"iat"`
+	Exp       int64  `json:"exp"`
+}
+
+const (
+	pingTimeout        = 10 * time.Second
+	introspectEndpoint = "/services/oauth2/introspect"
+)
+
+// pings /limits directly, without refreshing an expired session, so that readiness probes fail
+// when salesforce is unreachable or the access token is no longer valid
+func doPing(ctx context.Context, auth *authentication) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	instanceUrl, accessToken := auth.session()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, instanceUrl+"/services/data/"+apiVersion+"/limits", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "go-salesforce")
+	req.Header.Set("Accept", jsonType)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := auth.config.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.New(resp.Status + ": " + string(body))
+	}
+	return nil
+}
+
+// introspection requires the consumer key of the connected app, and the consumer secret unless
+// the app is configured to not require one
+func doGetTokenInfo(auth *authentication) (TokenInfo, error) {
+	if auth.creds.ConsumerKey == "" {
+		return TokenInfo{}, errors.New("token introspection requires a consumer key")
+	}
+	instanceUrl, token := auth.session()
+	payload := url.Values{
+		"token":           {token},
+		"token_type_hint": {"access_token"},
+		"client_id":       {auth.creds.ConsumerKey},
+	}
+	if auth.creds.ConsumerSecret != "" {
+		payload.Set("client_secret", auth.creds.ConsumerSecret)
+	}
+	resp, err := auth.config.client().PostForm(instanceUrl+introspectEndpoint, payload)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		oauthErr := oauthError{}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return TokenInfo{}, errors.New(resp.Status + ": " + oauthErr.Error + ": " + oauthErr.ErrorDescription)
+		}
+		return TokenInfo{}, errors.New(resp.Status + ": token introspection failed")
+	}
+
+	introspection := introspectionResponse{}
+	if err := json.Unmarshal(body, &introspection); err != nil {
+		return TokenInfo{}, err
+	}
+	info := TokenInfo{
+		Active:    introspection.Active,
+		Scope:     introspection.Scope,
+		ClientId:  introspection.ClientId,
+		Username:  introspection.Username,
+		Subject:   introspection.Sub,
+		TokenType: introspection.TokenType,
+	}
+	if introspection.Iat != 0 {
+		info.IssuedAt = time.Unix(introspection.Iat, 0).UTC()
+	}
+	if introspection.Exp != 0 {
+		info.ExpiresAt = time.Unix(introspection.Exp, 0).UTC()
+	}
+	return info, nil
+}
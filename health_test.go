@@ -0,0 +1,144 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_doPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/limits" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer accesstokenvalue" {
+			w.WriteHeader(http.StatusUnauthorized)
+			if _, err := w.Write([]byte(`[{"message": "Session expired or invalid", "errorCode": "INVALID_SESSION_ID"}]`)); err != nil {
+				panic(err.Error())
+			}
+			return
+		}
+		if _, err := w.Write([]byte(`{}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		auth    *authentication
+		wantErr bool
+	}{
+		{
+			name: "healthy",
+			ctx:  context.Background(),
+			auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"},
+		},
+		{
+			name:    "invalid_session",
+			ctx:     context.Background(),
+			auth:    &authentication{InstanceUrl: server.URL, AccessToken: "expired", grantType: grantTypeClientCredentials},
+			wantErr: true,
+		},
+		{
+			name:    "unreachable",
+			ctx:     context.Background(),
+			auth:    &authentication{InstanceUrl: "http://127.0.0.1:0", AccessToken: "accesstokenvalue"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := doPing(tt.ctx, tt.auth); (err != nil) != tt.wantErr {
+				t.Errorf("doPing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := doPing(cancelled, &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}); err == nil {
+		t.Errorf("doPing() expected error for cancelled context")
+	}
+}
+
+func Test_doGetTokenInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != introspectEndpoint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			panic(err.Error())
+		}
+		if r.PostForm.Get("client_id") != "key" || r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			if _, err := w.Write([]byte(`{"error": "invalid_client", "error_description": "invalid client credentials"}`)); err != nil {
+				panic(err.Error())
+			}
+			return
+		}
+		resp := `{"active": false}`
+		if r.PostForm.Get("token") == "accesstokenvalue" {
+			resp = `{"active": true, "scope": "api refresh_token", "client_id": "key", "username": "user@example.com", "sub": "https://login.salesforce.com/id/00D000000000001/005000000000001", "token_type": "access_token", "iat": 1706711400, "exp": 1706718600}`
+		}
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	creds := Creds{ConsumerKey: "key", ConsumerSecret: "secret"}
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    TokenInfo
+		wantErr bool
+	}{
+		{
+			name: "active",
+			auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", creds: creds},
+			want: TokenInfo{
+				Active:    true,
+				Scope:     "api refresh_token",
+				ClientId:  "key",
+				Username:  "user@example.com",
+				Subject:   "https://login.salesforce.com/id/00D000000000001/005000000000001",
+				TokenType: "access_token",
+				IssuedAt:  time.Unix(1706711400, 0).UTC(),
+				ExpiresAt: time.Unix(1706718600, 0).UTC(),
+			},
+		},
+		{
+			name: "inactive",
+			auth: &authentication{InstanceUrl: server.URL, AccessToken: "expired", creds: creds},
+			want: TokenInfo{},
+		},
+		{
+			name:    "invalid_client",
+			auth:    &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", creds: Creds{ConsumerKey: "key"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing_consumer_key",
+			auth:    &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doGetTokenInfo(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doGetTokenInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetTokenInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,12 @@
 package salesforce
 
 import (
+	"bufio"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/jszwec/csvutil"
@@ -21,19 +22,28 @@ type bulkJobQueryIterator struct {
 	NumberOfRecords int    `json:"Sforce-Numberofrecords"`
 	Locator         string `json:"Sforce-Locator"`
 	auth            *authentication
-	uri             string
+	bulkJobId       string
+	maxRecords      int
+	delimiter       rune
 	err             error
 	reader          io.ReadCloser
+	headerWritten   bool
 }
 
-func newBulkJobQueryIterator(auth *authentication, bulkJobId string) (*bulkJobQueryIterator, error) {
-	pollErr := waitForJobResults(auth, bulkJobId, queryJobType, (time.Second / 2))
+func newBulkJobQueryIterator(auth *authentication, bulkJobId string, options BulkQueryOptions) (*bulkJobQueryIterator, error) {
+	delimiter, err := getColumnDelimiter(options.ColumnDelimiter)
+	if err != nil {
+		return nil, err
+	}
+	pollErr := waitForJobResults(auth, bulkJobId, queryJobType, (time.Second / 2), options.Progress)
 	if pollErr != nil {
 		return nil, pollErr
 	}
 	return &bulkJobQueryIterator{
-		auth: auth,
-		uri:  "/jobs/query/" + bulkJobId + "/results",
+		auth:       auth,
+		bulkJobId:  bulkJobId,
+		maxRecords: options.ChunkSize,
+		delimiter:  delimiter,
 	}, nil
 }
 
@@ -44,27 +54,32 @@ func (it *bulkJobQueryIterator) Next() bool {
 			return false
 		}
 	}
-	uri := it.uri
-	if it.Locator != "" {
-		uri += "/?locator=" + it.Locator
-	}
+	uri := queryJobResultsUri(it.bulkJobId, it.Locator, it.maxRecords)
 	resp, err := doRequest(it.auth, requestPayload{method: http.MethodGet, uri: uri, content: jsonType})
 	if err != nil {
 		it.err = err
 		return false
 	}
-	it.reader = resp.Body
-
-	it.NumberOfRecords, _ = strconv.Atoi(resp.Header["Sforce-Numberofrecords"][0])
-	if resp.Header["Sforce-Locator"][0] != "null" {
-		it.Locator = resp.Header["Sforce-Locator"][0]
+	numberOfRecords, locator, err := parseQueryResultsHeaders(it.bulkJobId, resp.Header)
+	if err != nil {
+		resp.Body.Close()
+		it.reader = nil
+		it.err = err
+		return false
 	}
+	it.reader = resp.Body
+	it.NumberOfRecords = numberOfRecords
+	it.Locator = locator
 
 	return true
 }
 
 func (it *bulkJobQueryIterator) Decode(val any) error {
-	dec, err := csvutil.NewDecoder(csv.NewReader(it.reader))
+	reader := csv.NewReader(it.reader)
+	if it.delimiter != 0 {
+		reader.Comma = it.delimiter
+	}
+	dec, err := csvutil.NewDecoder(reader)
 	if err != nil {
 		return fmt.Errorf("NewDecoder: %w", err)
 	}
@@ -75,6 +90,30 @@ func (it *bulkJobQueryIterator) Decode(val any) error {
 	return nil
 }
 
+// reached through io.WriterTo rather than IteratorJob; streams the raw csv of the current page to w, so pages can be written to files, compressors, or uploads without decoding
+// every page starts with a header row, which is only written for the first page
+func (it *bulkJobQueryIterator) WriteTo(w io.Writer) (int64, error) {
+	if it.reader == nil {
+		return 0, errors.New("no page to write, Next must be called first")
+	}
+	var reader io.Reader = it.reader
+	if it.headerWritten {
+		buffered := bufio.NewReader(it.reader)
+		if _, err := buffered.ReadBytes('\n'); err != nil {
+			if err == io.EOF {
+				return 0, nil
+			}
+			return 0, err
+		}
+		reader = buffered
+	}
+	n, err := io.Copy(w, reader)
+	if n > 0 {
+		it.headerWritten = true
+	}
+	return n, err
+}
+
 func (it *bulkJobQueryIterator) Error() error {
 	return it.err
 }
@@ -0,0 +1,132 @@
+package salesforce
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_bulkJobQueryIterator_WriteTo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimSuffix(r.URL.Path, "/") != "/services/data/"+apiVersion+"/jobs/query/1234/results" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := "Id,Name\n001,Stark Industries\n002,Wayne Enterprises\n"
+		w.Header().Set("Sforce-Locator", "page2")
+		switch r.URL.Query().Get("locator") {
+		case "page2":
+			body = "Id,Name\n003,Acme\n"
+			w.Header().Set("Sforce-Locator", "page3")
+		case "page3":
+			body = "Id,Name\n"
+			w.Header().Set("Sforce-Locator", "null")
+		}
+		w.Header().Set("Sforce-Numberofrecords", "1")
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	it := &bulkJobQueryIterator{
+		auth:      &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"},
+		bulkJobId: "1234",
+	}
+
+	if _, err := it.WriteTo(&bytes.Buffer{}); err == nil {
+		t.Errorf("WriteTo() expected error before Next")
+	}
+
+	var buf bytes.Buffer
+	var written int64
+	for it.Next() {
+		n, err := it.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("WriteTo() error = %v", err)
+		}
+		written += n
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("Error() = %v", err)
+	}
+	want := "Id,Name\n001,Stark Industries\n002,Wayne Enterprises\n003,Acme\n"
+	if buf.String() != want {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), want)
+	}
+	if written != int64(len("Id,Name\n001,Stark Industries\n002,Wayne Enterprises\n")+len("003,Acme\n")) {
+		t.Errorf("WriteTo() wrote %d bytes", written)
+	}
+}
+
+func Test_bulkJobQueryIterator_Decode_delimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Sforce-Locator", "null")
+		w.Header().Set("Sforce-Numberofrecords", "1")
+		if _, err := w.Write([]byte("Id|Name\r\n001|Stark Industries\r\n")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	it := &bulkJobQueryIterator{
+		auth:      &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"},
+		bulkJobId: "1234",
+		delimiter: '|',
+	}
+
+	type account struct {
+		Id   string
+		Name string
+	}
+	var got []account
+	if !it.Next() {
+		t.Fatalf("Next() error = %v", it.Error())
+	}
+	if err := it.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := []account{{Id: "001", Name: "Stark Industries"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, want %v", got, want)
+	}
+}
+
+func Test_bulkJobQueryIterator_Next_missingHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Sforce-Numberofrecords", "1")
+		if _, err := w.Write([]byte("Id\n001\n")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	it := &bulkJobQueryIterator{
+		auth:      &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"},
+		bulkJobId: "1234",
+	}
+
+	if it.Next() {
+		t.Fatalf("Next() = true, want false when the locator header is missing")
+	}
+	var headerErr *QueryResultsHeaderError
+	if !errors.As(it.Error(), &headerErr) || headerErr.Header != locatorHeader {
+		t.Errorf("Error() = %v, want missing %s header", it.Error(), locatorHeader)
+	}
+}
+
+func TestSalesforce_QueryBulkIterator_WriterTo(t *testing.T) {
+	server, sfAuth := setupBulkQueryTestServer(t, "Id\n001\n")
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	it, err := sf.QueryBulkIterator("SELECT Id FROM Account")
+	if err != nil {
+		t.Fatalf("QueryBulkIterator() error = %v", err)
+	}
+	if _, ok := it.(io.WriterTo); !ok {
+		t.Errorf("QueryBulkIterator() = %T, want an io.WriterTo", it)
+	}
+}
@@ -0,0 +1,104 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type BulkJobEventType string
+
+const (
+	BulkJobEventStateChanged     BulkJobEventType = "StateChanged"
+	BulkJobEventRecordsProcessed BulkJobEventType = "RecordsProcessedDelta"
+	BulkJobEventCompleted        BulkJobEventType = "Completed"
+	BulkJobEventFailed           BulkJobEventType = "Failed"
+)
+
+type BulkJobEvent struct {
+	Type          BulkJobEventType
+	JobId         string
+	Job           BulkJobResults
+	PreviousState BulkJobState
+	// records processed and failed since the previous event
+	RecordsProcessedDelta int
+	RecordsFailedDelta    int
+	Err                   error
+}
+
+var jobWatchInterval = time.Second / 2
+
+// compares two polls of the same job, returning the events for whatever changed in between
+func bulkJobEvents(bulkJobId string, previous BulkJobResults, current BulkJobResults) []BulkJobEvent {
+	var events []BulkJobEvent
+	newEvent := func(eventType BulkJobEventType) BulkJobEvent {
+		return BulkJobEvent{Type: eventType, JobId: bulkJobId, Job: current, PreviousState: previous.State}
+	}
+	if current.State != previous.State {
+		events = append(events, newEvent(BulkJobEventStateChanged))
+	}
+	processed := current.NumberRecordsProcessed - previous.NumberRecordsProcessed
+	failed := current.NumberRecordsFailed - previous.NumberRecordsFailed
+	if processed > 0 || failed > 0 {
+		event := newEvent(BulkJobEventRecordsProcessed)
+		event.RecordsProcessedDelta = processed
+		event.RecordsFailedDelta = failed
+		events = append(events, event)
+	}
+	if current.State.IsTerminal() {
+		if jobErr := jobLevelError(current); jobErr != nil {
+			event := newEvent(BulkJobEventFailed)
+			event.Err = jobErr
+			events = append(events, event)
+		} else {
+			events = append(events, newEvent(BulkJobEventCompleted))
+		}
+	}
+	return events
+}
+
+// polls an ingest job until it finishes, the poll fails, or ctx is cancelled, then closes the channel
+func doWatchJob(ctx context.Context, auth *authentication, bulkJobId string) (<-chan BulkJobEvent, error) {
+	if bulkJobId == "" {
+		return nil, errors.New("bulk job id is required")
+	}
+	events := make(chan BulkJobEvent)
+	send := func(event BulkJobEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(events)
+		var previous BulkJobResults
+		ticker := time.NewTicker(jobWatchInterval)
+		defer ticker.Stop()
+		for {
+			job, err := getJobResults(auth, ingestJobType, bulkJobId, true)
+			if err != nil {
+				send(BulkJobEvent{Type: BulkJobEventFailed, JobId: bulkJobId, Job: previous, PreviousState: previous.State, Err: err})
+				return
+			}
+			for _, event := range bulkJobEvents(bulkJobId, previous, job) {
+				if !send(event) {
+					return
+				}
+			}
+			if job.State.IsTerminal() {
+				return
+			}
+			previous = job
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,146 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_bulkJobEvents(t *testing.T) {
+	open := BulkJobResults{Id: "750", State: BulkJobStateOpen}
+	inProgress := BulkJobResults{Id: "750", State: BulkJobStateInProgress, NumberRecordsProcessed: 100, NumberRecordsFailed: 2}
+	moreProgress := BulkJobResults{Id: "750", State: BulkJobStateInProgress, NumberRecordsProcessed: 250, NumberRecordsFailed: 2}
+	complete := BulkJobResults{Id: "750", State: BulkJobStateJobComplete, NumberRecordsProcessed: 300, NumberRecordsFailed: 2}
+	failed := BulkJobResults{Id: "750", State: BulkJobStateFailed, ErrorMessage: "InvalidBatch"}
+
+	tests := []struct {
+		name     string
+		previous BulkJobResults
+		current  BulkJobResults
+		want     []BulkJobEventType
+	}{
+		{name: "first_poll", previous: BulkJobResults{}, current: open, want: []BulkJobEventType{BulkJobEventStateChanged}},
+		{name: "unchanged", previous: open, current: open, want: nil},
+		{name: "state_and_records", previous: open, current: inProgress, want: []BulkJobEventType{BulkJobEventStateChanged, BulkJobEventRecordsProcessed}},
+		{name: "records_only", previous: inProgress, current: moreProgress, want: []BulkJobEventType{BulkJobEventRecordsProcessed}},
+		{name: "completed", previous: moreProgress, current: complete, want: []BulkJobEventType{BulkJobEventStateChanged, BulkJobEventRecordsProcessed, BulkJobEventCompleted}},
+		{name: "failed", previous: open, current: failed, want: []BulkJobEventType{BulkJobEventStateChanged, BulkJobEventFailed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []BulkJobEventType
+			for _, event := range bulkJobEvents("750", tt.previous, tt.current) {
+				got = append(got, event.Type)
+				if event.JobId != "750" || event.PreviousState != tt.previous.State {
+					t.Errorf("bulkJobEvents() event = %+v", event)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bulkJobEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	events := bulkJobEvents("750", inProgress, moreProgress)
+	if events[0].RecordsProcessedDelta != 150 || events[0].RecordsFailedDelta != 0 {
+		t.Errorf("bulkJobEvents() deltas = %d, %d, want 150, 0", events[0].RecordsProcessedDelta, events[0].RecordsFailedDelta)
+	}
+	events = bulkJobEvents("750", open, failed)
+	var jobErr *BulkJobError
+	if !errors.As(events[1].Err, &jobErr) {
+		t.Errorf("bulkJobEvents() failed event error = %v, want *BulkJobError", events[1].Err)
+	}
+}
+
+func Test_doWatchJob(t *testing.T) {
+	defaultInterval := jobWatchInterval
+	jobWatchInterval = time.Millisecond
+	defer func() { jobWatchInterval = defaultInterval }()
+
+	polls := []BulkJobResults{
+		{Id: "750", State: BulkJobStateUploadComplete},
+		{Id: "750", State: BulkJobStateInProgress, NumberRecordsProcessed: 10},
+		{Id: "750", State: BulkJobStateJobComplete, NumberRecordsProcessed: 20},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/jobs/ingest/750" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(polls[min(requests, len(polls)-1)])
+		requests++
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	events, err := doWatchJob(context.Background(), &sfAuth, "750")
+	if err != nil {
+		t.Fatalf("doWatchJob() error = %v", err)
+	}
+	var got []BulkJobEventType
+	for event := range events {
+		got = append(got, event.Type)
+	}
+	want := []BulkJobEventType{
+		BulkJobEventStateChanged,
+		BulkJobEventStateChanged,
+		BulkJobEventRecordsProcessed,
+		BulkJobEventStateChanged,
+		BulkJobEventRecordsProcessed,
+		BulkJobEventCompleted,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doWatchJob() = %v, want %v", got, want)
+	}
+
+	events, err = doWatchJob(context.Background(), &sfAuth, "missing")
+	if err != nil {
+		t.Fatalf("doWatchJob() error = %v", err)
+	}
+	event := <-events
+	if event.Type != BulkJobEventFailed || event.Err == nil {
+		t.Errorf("doWatchJob() = %+v, want failed event with error", event)
+	}
+	if _, ok := <-events; ok {
+		t.Errorf("doWatchJob() channel should be closed after a failed poll")
+	}
+
+	if _, err := doWatchJob(context.Background(), &sfAuth, ""); err == nil {
+		t.Errorf("doWatchJob() expected error for missing job id")
+	}
+}
+
+func Test_doWatchJob_cancel(t *testing.T) {
+	server, sfAuth := setupTestServer(BulkJobResults{Id: "750", State: BulkJobStateInProgress}, http.StatusOK)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := doWatchJob(ctx, &sfAuth, "750")
+	if err != nil {
+		t.Fatalf("doWatchJob() error = %v", err)
+	}
+	if event := <-events; event.Type != BulkJobEventStateChanged {
+		t.Errorf("doWatchJob() = %v, want %v", event.Type, BulkJobEventStateChanged)
+	}
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Errorf("doWatchJob() sent an event after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("doWatchJob() channel not closed after cancellation")
+	}
+}
@@ -0,0 +1,117 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type ListView struct {
+	Id             string `json:"id"`
+	Label          string `json:"label"`
+	DeveloperName  string `json:"developerName"`
+	SoqlCompatible bool   `json:"soqlCompatible"`
+	Url            string `json:"url"`
+	ResultsUrl     string `json:"resultsUrl"`
+	DescribeUrl    string `json:"describeUrl"`
+}
+
+type listViewsResponse struct {
+	ListViews      []ListView `json:"listviews"`
+	Done           bool       `json:"done"`
+	NextRecordsUrl string     `json:"nextRecordsUrl"`
+}
+
+type listViewResultsResponse struct {
+	Done    bool `json:"done"`
+	Records []struct {
+		Columns []listViewColumn `json:"columns"`
+	} `json:"records"`
+}
+
+type listViewColumn struct {
+	FieldNameOrPath string `json:"fieldNameOrPath"`
+	Value           any    `json:"value"`
+}
+
+const listViewResultsLimit = 2000
+
+func doGetListViews(auth *authentication, sObjectName string) ([]ListView, error) {
+	if sObjectName == "" {
+		return nil, errors.New("sObject name is required")
+	}
+	listViews := []ListView{}
+	paginator, err := newPaginator(auth, "/sobjects/"+url.PathEscape(sObjectName)+"/listviews", dmlOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for paginator.Next() {
+		page := listViewsResponse{}
+		if err := paginator.Decode(&page); err != nil {
+			return nil, err
+		}
+		listViews = append(listViews, page.ListViews...)
+	}
+	if err := paginator.Error(); err != nil {
+		return nil, err
+	}
+	return listViews, nil
+}
+
+func doGetListViewRecords(auth *authentication, sObjectName string, listViewId string, result any) error {
+	if sObjectName == "" || listViewId == "" {
+		return errors.New("sObject name and list view id are required")
+	}
+	var records []map[string]any
+	for {
+		uri := "/sobjects/" + url.PathEscape(sObjectName) + "/listviews/" + url.PathEscape(listViewId) + "/results" +
+			"?limit=" + strconv.Itoa(listViewResultsLimit) + "&offset=" + strconv.Itoa(len(records))
+		resp, err := doRequest(auth, requestPayload{
+			method:  http.MethodGet,
+			uri:     uri,
+			content: jsonType,
+		})
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		page := listViewResultsResponse{}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, record := range page.Records {
+			records = append(records, listViewRecord(record.Columns))
+		}
+		if page.Done || len(page.Records) == 0 {
+			break
+		}
+	}
+	return decodeBulkRecords(records, result)
+}
+
+// columns of related records, such as Account.Name, are nested so they can be decoded like query results
+func listViewRecord(columns []listViewColumn) map[string]any {
+	record := map[string]any{}
+	for _, column := range columns {
+		path := strings.Split(column.FieldNameOrPath, relationshipSeparator)
+		current := record
+		for _, name := range path[:len(path)-1] {
+			next, ok := current[name].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				current[name] = next
+			}
+			current = next
+		}
+		current[path[len(path)-1]] = column.Value
+	}
+	return record
+}
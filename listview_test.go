@@ -0,0 +1,155 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_doGetListViews(t *testing.T) {
+	pages := map[string]listViewsResponse{
+		"": {
+			ListViews:      []ListView{{Id: "00B000000000001", Label: "All Accounts", DeveloperName: "AllAccounts", SoqlCompatible: true}},
+			NextRecordsUrl: "/services/data/" + apiVersion + "/sobjects/Account/listviews?page=2",
+		},
+		"2": {
+			ListViews: []ListView{{Id: "00B000000000002", Label: "My Accounts", DeveloperName: "MyAccounts"}},
+			Done:      true,
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/sobjects/Account/listviews" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(pages[r.URL.Query().Get("page")])
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	type args struct {
+		auth        *authentication
+		sObjectName string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []ListView
+		wantErr bool
+	}{
+		{
+			name: "every_page",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+			},
+			want:    append(pages[""].ListViews, pages["2"].ListViews...),
+			wantErr: false,
+		},
+		{
+			name: "missing_sobject_name",
+			args: args{
+				auth: &sfAuth,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:        &badAuth,
+				sObjectName: "Account",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doGetListViews(tt.args.auth, tt.args.sObjectName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doGetListViews() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetListViews() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doGetListViewRecords(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/sobjects/Contact/listviews/00B000000000001/results" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		body := `{"done": false, "records": [{"columns": [{"fieldNameOrPath": "Name", "value": "Peter Parker"}, {"fieldNameOrPath": "Account.Name", "value": "Daily Bugle"}, {"fieldNameOrPath": "Age__c", "value": "18"}]}]}`
+		if r.URL.Query().Get("offset") == "1" {
+			body = `{"done": true, "records": [{"columns": [{"fieldNameOrPath": "Name", "value": "Matt Murdock"}, {"fieldNameOrPath": "Account.Name", "value": null}, {"fieldNameOrPath": "Age__c", "value": "30"}]}]}`
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	type contact struct {
+		Name        string
+		AccountName string `sf:"Account.Name"`
+		Age         int    `sf:"Age__c"`
+	}
+	got := []contact{}
+	if err := doGetListViewRecords(&sfAuth, "Contact", "00B000000000001", &got); err != nil {
+		t.Fatalf("doGetListViewRecords() error = %v", err)
+	}
+	want := []contact{
+		{Name: "Peter Parker", AccountName: "Daily Bugle", Age: 18},
+		{Name: "Matt Murdock", Age: 30},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doGetListViewRecords() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(offsets, []string{"0", "1"}) {
+		t.Errorf("doGetListViewRecords() requested offsets %v", offsets)
+	}
+
+	if err := doGetListViewRecords(&sfAuth, "Contact", "", &got); err == nil {
+		t.Errorf("doGetListViewRecords() expected error for missing list view id")
+	}
+}
+
+func Test_listViewRecord(t *testing.T) {
+	got := listViewRecord([]listViewColumn{
+		{FieldNameOrPath: "Id", Value: "003000000000001"},
+		{FieldNameOrPath: "Account.Owner.Name", Value: "Tony Stark"},
+		{FieldNameOrPath: "Account.Name", Value: "Stark Industries"},
+	})
+	want := map[string]any{
+		"Id": "003000000000001",
+		"Account": map[string]any{
+			"Name":  "Stark Industries",
+			"Owner": map[string]any{"Name": "Tony Stark"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("listViewRecord() = %v, want %v", got, want)
+	}
+}
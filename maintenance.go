@@ -0,0 +1,100 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// returned when salesforce responds 503, such as while the org is in maintenance
+var ErrServiceUnavailable = errors.New("service unavailable")
+
+// wraps ErrServiceUnavailable with how long salesforce asked clients to wait before retrying
+type ServiceUnavailableError struct {
+	// zero when the response had no Retry-After header
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *ServiceUnavailableError) Error() string {
+	message := ErrServiceUnavailable.Error()
+	if e.RetryAfter > 0 {
+		message += " (retry after " + e.RetryAfter.String() + ")"
+	}
+	if e.Body != "" {
+		message += ": " + e.Body
+	}
+	return message
+}
+
+func (e *ServiceUnavailableError) Unwrap() error {
+	return ErrServiceUnavailable
+}
+
+const (
+	// used when a 503 response has no Retry-After header
+	defaultServiceUnavailableDelay = 30 * time.Second
+	// stops retrying responses that keep asking for no wait at all
+	maxServiceUnavailableAttempts = 10
+)
+
+// waits and retries requests that fail with 503, as long as the total wait for a request stays within budget
+func WithServiceUnavailableRetry(budget time.Duration) Option {
+	return func(config *configuration) error {
+		if budget <= 0 {
+			return errors.New("service unavailable retry budget must be positive")
+		}
+		config.unavailableBudget = budget
+		return nil
+	}
+}
+
+func (config *configuration) serviceUnavailableBudget() time.Duration {
+	if config == nil {
+		return 0
+	}
+	return config.unavailableBudget
+}
+
+// Retry-After is either a number of seconds or an http date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+func handleServiceUnavailable(resp http.Response, responseData []byte, auth *authentication, payload requestPayload) (*http.Response, error) {
+	unavailableErr := &ServiceUnavailableError{
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Body:       strings.TrimSpace(string(responseData)),
+	}
+	delay := unavailableErr.RetryAfter
+	if resp.Header.Get("Retry-After") == "" {
+		delay = defaultServiceUnavailableDelay
+	}
+	budget := auth.config.serviceUnavailableBudget()
+	if budget <= 0 || payload.unavailableWait+delay > budget || payload.unavailableAttempts >= maxServiceUnavailableAttempts {
+		return &resp, unavailableErr
+	}
+
+	retryPayload := payload
+	retryPayload.unavailableWait += delay
+	retryPayload.unavailableAttempts++
+	recordRetryTelemetry(auth, TelemetryRetry{
+		Operation: newTelemetryRequest(payload).Operation,
+		Reason:    retryReasonServiceUnavailable,
+		Attempt:   retryPayload.unavailableAttempts,
+	})
+	time.Sleep(delay)
+	return doRequest(auth, retryPayload)
+}
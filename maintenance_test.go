@@ -0,0 +1,135 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "http_date", value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		{name: "past_date", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "negative_seconds", value: "-5", want: 0},
+		{name: "empty", value: "", want: 0},
+		{name: "invalid", value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func setupMaintenanceServer(unavailable int, retryAfter string, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if *requests <= unavailable {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if _, err := w.Write([]byte("<html>down for maintenance</html>")); err != nil {
+				panic(err.Error())
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestWithServiceUnavailableRetry(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		unavailable    int
+		retryAfter     string
+		wantRequests   int
+		wantRetryAfter time.Duration
+		wantErr        bool
+	}{
+		{
+			name:         "retried_within_budget",
+			opts:         []Option{WithServiceUnavailableRetry(time.Minute)},
+			unavailable:  2,
+			retryAfter:   "0",
+			wantRequests: 3,
+		},
+		{
+			name:           "retry_after_exceeds_budget",
+			opts:           []Option{WithServiceUnavailableRetry(time.Minute)},
+			unavailable:    1,
+			retryAfter:     "300",
+			wantRequests:   1,
+			wantRetryAfter: 5 * time.Minute,
+			wantErr:        true,
+		},
+		{
+			name:         "default_delay_exceeds_budget",
+			opts:         []Option{WithServiceUnavailableRetry(time.Second)},
+			unavailable:  1,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "attempts_exhausted",
+			opts:         []Option{WithServiceUnavailableRetry(time.Minute)},
+			unavailable:  maxServiceUnavailableAttempts + 1,
+			retryAfter:   "0",
+			wantRequests: maxServiceUnavailableAttempts + 1,
+			wantErr:      true,
+		},
+		{
+			name:           "not_retried_without_option",
+			unavailable:    1,
+			retryAfter:     "0",
+			wantRequests:   1,
+			wantRetryAfter: 0,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := setupMaintenanceServer(tt.unavailable, tt.retryAfter, &requests)
+			defer server.Close()
+			config, err := newConfiguration(tt.opts...)
+			if err != nil {
+				t.Fatalf("newConfiguration() error = %v", err)
+			}
+			sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", config: config}
+
+			_, err = doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if !tt.wantErr {
+				return
+			}
+			var unavailableErr *ServiceUnavailableError
+			if !errors.As(err, &unavailableErr) || !errors.Is(err, ErrServiceUnavailable) {
+				t.Fatalf("doRequest() error = %v, want a ServiceUnavailableError", err)
+			}
+			if unavailableErr.RetryAfter != tt.wantRetryAfter || unavailableErr.Body != "<html>down for maintenance</html>" {
+				t.Errorf("ServiceUnavailableError = %+v", unavailableErr)
+			}
+		})
+	}
+
+	if _, err := newConfiguration(WithServiceUnavailableRetry(0)); err == nil {
+		t.Errorf("WithServiceUnavailableRetry() expected error for a budget of 0")
+	}
+}
@@ -0,0 +1,299 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type DeployOptions struct {
+	AllowMissingFiles bool     `json:"allowMissingFiles"`
+	CheckOnly         bool     `json:"checkOnly"`
+	IgnoreWarnings    bool     `json:"ignoreWarnings"`
+	PurgeOnDelete     bool     `json:"purgeOnDelete"`
+	RollbackOnError   bool     `json:"rollbackOnError"`
+	SinglePackage     bool     `json:"singlePackage"`
+	TestLevel         string   `json:"testLevel,omitempty"`
+	RunTests          []string `json:"runTests,omitempty"`
+}
+
+type DeployResult struct {
+	Id                       string        `json:"id"`
+	Status                   string        `json:"status"`
+	Done                     bool          `json:"done"`
+	Success                  bool          `json:"success"`
+	CheckOnly                bool          `json:"checkOnly"`
+	NumberComponentsDeployed int           `json:"numberComponentsDeployed"`
+	NumberComponentsTotal    int           `json:"numberComponentsTotal"`
+	NumberComponentErrors    int           `json:"numberComponentErrors"`
+	NumberTestsCompleted     int           `json:"numberTestsCompleted"`
+	NumberTestsTotal         int           `json:"numberTestsTotal"`
+	NumberTestErrors         int           `json:"numberTestErrors"`
+	ErrorMessage             string        `json:"errorMessage"`
+	ErrorStatusCode          string        `json:"errorStatusCode"`
+	Details                  DeployDetails `json:"details"`
+}
+
+type DeployDetails struct {
+	ComponentFailures  []DeployMessage `json:"componentFailures"`
+	ComponentSuccesses []DeployMessage `json:"componentSuccesses"`
+}
+
+type DeployMessage struct {
+	ComponentType string `json:"componentType"`
+	FullName      string `json:"fullName"`
+	FileName      string `json:"fileName"`
+	Problem       string `json:"problem"`
+	ProblemType   string `json:"problemType"`
+	LineNumber    int    `json:"lineNumber"`
+	ColumnNumber  int    `json:"columnNumber"`
+	Success       bool   `json:"success"`
+	Created       bool   `json:"created"`
+	Changed       bool   `json:"changed"`
+	Deleted       bool   `json:"deleted"`
+}
+
+type RetrieveRequest struct {
+	PackageNames  []string
+	Types         map[string][]string // metadata type names mapped to the members to retrieve, such as "CustomObject": {"Account"}
+	SinglePackage bool
+}
+
+type RetrieveResult struct {
+	Id              string            `xml:"id"`
+	Status          string            `xml:"status"`
+	Done            bool              `xml:"done"`
+	Success         bool              `xml:"success"`
+	ErrorMessage    string            `xml:"errorMessage"`
+	ErrorStatusCode string            `xml:"errorStatusCode"`
+	Messages        []RetrieveMessage `xml:"messages"`
+	ZipFile         []byte            `xml:"-"`
+}
+
+type RetrieveMessage struct {
+	FileName string `xml:"fileName"`
+	Problem  string `xml:"problem"`
+}
+
+type deployRequestResponse struct {
+	Id           string       `json:"id"`
+	DeployResult DeployResult `json:"deployResult"`
+}
+
+type soapRetrieve struct {
+	Namespace string           `xml:"xmlns,attr"`
+	Request   soapRetrieveBody `xml:"retrieveRequest"`
+}
+
+type soapRetrieveBody struct {
+	ApiVersion    string        `xml:"apiVersion"`
+	PackageNames  []string      `xml:"packageNames,omitempty"`
+	SinglePackage bool          `xml:"singlePackage"`
+	Unpackaged    *soapManifest `xml:"unpackaged,omitempty"`
+}
+
+type soapManifest struct {
+	Types   []soapManifestType `xml:"types"`
+	Version string             `xml:"version"`
+}
+
+type soapManifestType struct {
+	Members []string `xml:"members"`
+	Name    string   `xml:"name"`
+}
+
+type soapCheckRetrieveStatus struct {
+	Namespace      string `xml:"xmlns,attr"`
+	AsyncProcessId string `xml:"asyncProcessId"`
+	IncludeZip     bool   `xml:"includeZip"`
+}
+
+const (
+	metadataNamespace = "http://soap.sforce.com/2006/04/metadata"
+	metadataSoapRoute = "/services/Soap/m/"
+	zipType           = "application/zip"
+)
+
+func doDeployMetadata(auth *authentication, zipFile []byte, options DeployOptions) (DeployResult, error) {
+	if len(zipFile) == 0 {
+		return DeployResult{}, errors.New("zip file is required")
+	}
+	deployOptions, err := json.Marshal(map[string]DeployOptions{"deployOptions": options})
+	if err != nil {
+		return DeployResult{}, err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	jsonPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {`form-data; name="json"`},
+		"Content-Type":        {jsonType},
+	})
+	if err != nil {
+		return DeployResult{}, err
+	}
+	if _, err := jsonPart.Write(deployOptions); err != nil {
+		return DeployResult{}, err
+	}
+	filePart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {`form-data; name="file"; filename="deploy.zip"`},
+		"Content-Type":        {zipType},
+	})
+	if err != nil {
+		return DeployResult{}, err
+	}
+	if _, err := filePart.Write(zipFile); err != nil {
+		return DeployResult{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return DeployResult{}, err
+	}
+
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/metadata/deployRequest",
+		content: writer.FormDataContentType(),
+		body:    body.String(),
+		headers: map[string]string{"Accept": jsonType},
+	})
+	if err != nil {
+		return DeployResult{}, err
+	}
+	return decodeDeployResult(resp)
+}
+
+func doCheckDeployStatus(auth *authentication, deployId string, polling bool) (DeployResult, error) {
+	if deployId == "" {
+		return DeployResult{}, errors.New("deploy id is required")
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     "/metadata/deployRequest/" + url.PathEscape(deployId) + "?includeDetails=true",
+		content: jsonType,
+		polling: polling,
+	})
+	if err != nil {
+		return DeployResult{}, err
+	}
+	return decodeDeployResult(resp)
+}
+
+func decodeDeployResult(resp *http.Response) (DeployResult, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	deployResp := deployRequestResponse{}
+	if err := json.Unmarshal(body, &deployResp); err != nil {
+		return DeployResult{}, err
+	}
+	result := deployResp.DeployResult
+	if result.Id == "" {
+		result.Id = deployResp.Id
+	}
+	return result, nil
+}
+
+func doWaitForDeploy(auth *authentication, deployId string, interval time.Duration, timeout time.Duration) (DeployResult, error) {
+	var result DeployResult
+	err := wait.PollUntilContextTimeout(context.Background(), interval, timeout, true, func(context.Context) (bool, error) {
+		var reqErr error
+		result, reqErr = doCheckDeployStatus(auth, deployId, true)
+		if reqErr != nil {
+			return true, reqErr
+		}
+		return result.Done, nil
+	})
+	return result, err
+}
+
+func newSoapRetrieve(request RetrieveRequest) (*soapRetrieve, error) {
+	if len(request.PackageNames) == 0 && len(request.Types) == 0 {
+		return nil, errors.New("retrieve request requires package names or metadata types")
+	}
+	version := strings.TrimPrefix(apiVersion, "v")
+	retrieve := &soapRetrieve{
+		Namespace: metadataNamespace,
+		Request: soapRetrieveBody{
+			ApiVersion:    version,
+			PackageNames:  request.PackageNames,
+			SinglePackage: request.SinglePackage,
+		},
+	}
+	if len(request.Types) > 0 {
+		manifest := &soapManifest{Version: version}
+		names := make([]string, 0, len(request.Types))
+		for name := range request.Types {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			manifest.Types = append(manifest.Types, soapManifestType{Members: request.Types[name], Name: name})
+		}
+		retrieve.Request.Unpackaged = manifest
+	}
+	return retrieve, nil
+}
+
+func doRetrieveMetadata(auth *authentication, request RetrieveRequest) (RetrieveResult, error) {
+	retrieve, err := newSoapRetrieve(request)
+	if err != nil {
+		return RetrieveResult{}, err
+	}
+	resp, err := doSoapRequest(auth, metadataSoapRoute, metadataNamespace, "retrieve", soapAction{Retrieve: retrieve}, false)
+	if err != nil {
+		return RetrieveResult{}, err
+	}
+	return resp.RetrieveResult, nil
+}
+
+func doCheckRetrieveStatus(auth *authentication, retrieveId string, polling bool) (RetrieveResult, error) {
+	if retrieveId == "" {
+		return RetrieveResult{}, errors.New("retrieve id is required")
+	}
+	resp, err := doSoapRequest(auth, metadataSoapRoute, metadataNamespace, "checkRetrieveStatus", soapAction{
+		CheckRetrieveStatus: &soapCheckRetrieveStatus{
+			Namespace:      metadataNamespace,
+			AsyncProcessId: retrieveId,
+			IncludeZip:     true,
+		},
+	}, polling)
+	if err != nil {
+		return RetrieveResult{}, err
+	}
+
+	result := resp.CheckRetrieveStatusResult.RetrieveResult
+	if resp.CheckRetrieveStatusResult.ZipFile != "" {
+		result.ZipFile, err = base64.StdEncoding.DecodeString(resp.CheckRetrieveStatusResult.ZipFile)
+		if err != nil {
+			return RetrieveResult{}, err
+		}
+	}
+	return result, nil
+}
+
+func doWaitForRetrieve(auth *authentication, retrieveId string, interval time.Duration, timeout time.Duration) (RetrieveResult, error) {
+	var result RetrieveResult
+	err := wait.PollUntilContextTimeout(context.Background(), interval, timeout, true, func(context.Context) (bool, error) {
+		var reqErr error
+		result, reqErr = doCheckRetrieveStatus(auth, retrieveId, true)
+		if reqErr != nil {
+			return true, reqErr
+		}
+		return result.Done, nil
+	})
+	return result, err
+}
@@ -0,0 +1,274 @@
+package salesforce
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_doDeployMetadata(t *testing.T) {
+	deployResp := deployRequestResponse{
+		Id:           "0Af000000000001",
+		DeployResult: DeployResult{Status: "Pending"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/"+apiVersion+"/metadata/deployRequest" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var options map[string]DeployOptions
+		if err := json.Unmarshal([]byte(r.FormValue("json")), &options); err != nil || !options["deployOptions"].CheckOnly {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil || header.Header.Get("Content-Type") != zipType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if zipFile, _ := io.ReadAll(file); string(zipFile) != "zipdata" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		body, _ := json.Marshal(deployResp)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	type args struct {
+		auth    *authentication
+		zipFile []byte
+		options DeployOptions
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    DeployResult
+		wantErr bool
+	}{
+		{
+			name: "successful_deploy",
+			args: args{
+				auth:    &sfAuth,
+				zipFile: []byte("zipdata"),
+				options: DeployOptions{CheckOnly: true},
+			},
+			want:    DeployResult{Id: "0Af000000000001", Status: "Pending"},
+			wantErr: false,
+		},
+		{
+			name: "missing_zip_file",
+			args: args{
+				auth: &sfAuth,
+			},
+			want:    DeployResult{},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:    &badAuth,
+				zipFile: []byte("zipdata"),
+			},
+			want:    DeployResult{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doDeployMetadata(tt.args.auth, tt.args.zipFile, tt.args.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doDeployMetadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doDeployMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doWaitForDeploy(t *testing.T) {
+	statuses := []DeployResult{
+		{Id: "0Af000000000001", Status: "InProgress"},
+		{
+			Id:      "0Af000000000001",
+			Status:  "Failed",
+			Done:    true,
+			Details: DeployDetails{ComponentFailures: []DeployMessage{{ComponentType: "Flow", FullName: "My_Flow", Problem: "invalid"}}},
+		},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/metadata/deployRequest/0Af000000000001" || r.URL.Query().Get("includeDetails") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(deployRequestResponse{Id: "0Af000000000001", DeployResult: statuses[requests]})
+		requests++
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := doWaitForDeploy(&sfAuth, "0Af000000000001", time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("doWaitForDeploy() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, statuses[1]) {
+		t.Errorf("doWaitForDeploy() = %v, want %v", got, statuses[1])
+	}
+	if requests != 2 {
+		t.Errorf("doWaitForDeploy() sent %d requests, want 2", requests)
+	}
+
+	if _, err := doCheckDeployStatus(&sfAuth, "", false); err == nil {
+		t.Errorf("doCheckDeployStatus() expected error for missing id")
+	}
+}
+
+func Test_newSoapRetrieve(t *testing.T) {
+	retrieve, err := newSoapRetrieve(RetrieveRequest{
+		Types: map[string][]string{
+			"Flow":         {"My_Flow"},
+			"CustomObject": {"Account", "Invoice__c"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newSoapRetrieve() error = %v", err)
+	}
+	want := &soapManifest{
+		Types: []soapManifestType{
+			{Members: []string{"Account", "Invoice__c"}, Name: "CustomObject"},
+			{Members: []string{"My_Flow"}, Name: "Flow"},
+		},
+		Version: strings.TrimPrefix(apiVersion, "v"),
+	}
+	if !reflect.DeepEqual(retrieve.Request.Unpackaged, want) {
+		t.Errorf("newSoapRetrieve() = %v, want %v", retrieve.Request.Unpackaged, want)
+	}
+
+	if _, err := newSoapRetrieve(RetrieveRequest{}); err == nil {
+		t.Errorf("newSoapRetrieve() expected error for empty request")
+	}
+}
+
+func setupMetadataSoapServer(t *testing.T, zipFile []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != metadataSoapRoute+strings.TrimPrefix(apiVersion, "v") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "<sessionId>accesstokenvalue</sessionId>") {
+			w.WriteHeader(http.StatusInternalServerError)
+			if _, err := w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><soapenv:Fault><faultcode>sf:INVALID_SESSION_ID</faultcode><faultstring>INVALID_SESSION_ID: Invalid Session ID</faultstring></soapenv:Fault></soapenv:Body></soapenv:Envelope>`)); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		var response string
+		switch r.Header.Get("SOAPAction") {
+		case "retrieve":
+			if !strings.Contains(string(body), "<members>Account</members><name>CustomObject</name>") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			response = `<retrieveResponse><result><done>false</done><id>09S000000000001</id><state>Queued</state></result></retrieveResponse>`
+		case "checkRetrieveStatus":
+			if !strings.Contains(string(body), "<asyncProcessId>09S000000000001</asyncProcessId>") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			response = `<checkRetrieveStatusResponse><result><done>true</done><id>09S000000000001</id><status>Succeeded</status><success>true</success>` +
+				`<messages><fileName>unpackaged/package.xml</fileName><problem>warning</problem></messages>` +
+				`<zipFile>` + base64.StdEncoding.EncodeToString(zipFile) + `</zipFile></result></checkRetrieveStatusResponse>`
+		}
+		if _, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://soap.sforce.com/2006/04/metadata"><soapenv:Body>` + response + `</soapenv:Body></soapenv:Envelope>`)); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+}
+
+func Test_doRetrieveMetadata(t *testing.T) {
+	server := setupMetadataSoapServer(t, []byte("zipdata"))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	badAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "expired",
+	}
+
+	request := RetrieveRequest{Types: map[string][]string{"CustomObject": {"Account"}}}
+	got, err := doRetrieveMetadata(&sfAuth, request)
+	if err != nil {
+		t.Fatalf("doRetrieveMetadata() error = %v", err)
+	}
+	want := RetrieveResult{Id: "09S000000000001"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doRetrieveMetadata() = %v, want %v", got, want)
+	}
+
+	_, err = doRetrieveMetadata(&badAuth, request)
+	if err == nil || !strings.Contains(err.Error(), "INVALID_SESSION_ID") {
+		t.Errorf("doRetrieveMetadata() error = %v, want soap fault", err)
+	}
+}
+
+func Test_doWaitForRetrieve(t *testing.T) {
+	server := setupMetadataSoapServer(t, []byte("zipdata"))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := doWaitForRetrieve(&sfAuth, "09S000000000001", time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("doWaitForRetrieve() error = %v", err)
+	}
+	want := RetrieveResult{
+		Id:       "09S000000000001",
+		Status:   "Succeeded",
+		Done:     true,
+		Success:  true,
+		Messages: []RetrieveMessage{{FileName: "unpackaged/package.xml", Problem: "warning"}},
+		ZipFile:  []byte("zipdata"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doWaitForRetrieve() = %v, want %v", got, want)
+	}
+
+	if _, err := doCheckRetrieveStatus(&sfAuth, "", false); err == nil {
+		t.Errorf("doCheckRetrieveStatus() expected error for missing id")
+	}
+}
@@ -0,0 +1,141 @@
+package salesforce
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stores raw describe and object info responses, implementations must be safe for concurrent use
+type MetadataCache interface {
+	Get(key MetadataCacheKey) ([]byte, bool)
+	Set(key MetadataCacheKey, value []byte, ttl time.Duration)
+	// removes every entry for the object, across resources, api versions, and scopes
+	Delete(sObjectName string)
+	Clear()
+}
+
+type MetadataCacheKey struct {
+	// the instance url and user the response was described for, since describes depend on the org and the
+	// user's permissions
+	Scope       string
+	ApiVersion  string
+	SObjectName string
+	Resource    string
+}
+
+func newMetadataCacheKey(auth *authentication, sObjectName string, resource string) MetadataCacheKey {
+	return MetadataCacheKey{
+		Scope:       auth.cacheScope(),
+		ApiVersion:  apiVersion,
+		SObjectName: strings.ToLower(sObjectName),
+		Resource:    resource,
+	}
+}
+
+type memoryMetadataCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+type memoryMetadataCache struct {
+	mu      sync.RWMutex
+	now     func() time.Time
+	entries map[MetadataCacheKey]memoryMetadataCacheEntry
+}
+
+// the default MetadataCache, entries are kept in memory for the life of the client
+func NewMemoryMetadataCache() MetadataCache {
+	return &memoryMetadataCache{
+		now:     time.Now,
+		entries: map[MetadataCacheKey]memoryMetadataCacheEntry{},
+	}
+}
+
+func (cache *memoryMetadataCache) Get(key MetadataCacheKey) ([]byte, bool) {
+	cache.mu.RLock()
+	entry, ok := cache.entries[key]
+	cache.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if !entry.expiresAt.IsZero() && !cache.now().Before(entry.expiresAt) {
+		cache.mu.Lock()
+		if current, ok := cache.entries[key]; ok && current.expiresAt.Equal(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+		cache.mu.Unlock()
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// a ttl of 0 or less never expires
+func (cache *memoryMetadataCache) Set(key MetadataCacheKey, value []byte, ttl time.Duration) {
+	entry := memoryMetadataCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = cache.now().Add(ttl)
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[key] = entry
+}
+
+func (cache *memoryMetadataCache) Delete(sObjectName string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for key := range cache.entries {
+		if strings.EqualFold(key.SObjectName, sObjectName) {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+func (cache *memoryMetadataCache) Clear() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries = map[MetadataCacheKey]memoryMetadataCacheEntry{}
+}
+
+// caches describe and object info responses, such as picklist values, for ttl (0 never expires)
+func WithMetadataCache(cache MetadataCache, ttl time.Duration) Option {
+	return func(config *configuration) error {
+		if cache == nil {
+			return errors.New("metadata cache is nil")
+		}
+		if ttl < 0 {
+			return errors.New("metadata cache ttl must not be negative")
+		}
+		config.metadataCache = cache
+		config.metadataCacheTTL = ttl
+		return nil
+	}
+}
+
+func (config *configuration) cachedMetadata(key MetadataCacheKey) ([]byte, bool) {
+	if config == nil || config.metadataCache == nil {
+		return nil, false
+	}
+	return config.metadataCache.Get(key)
+}
+
+func (config *configuration) cacheMetadata(key MetadataCacheKey, value []byte) {
+	if config == nil || config.metadataCache == nil {
+		return
+	}
+	config.metadataCache.Set(key, value, config.metadataCacheTTL)
+}
+
+func invalidateMetadataCache(auth *authentication, sObjectNames []string) {
+	if auth == nil || auth.config == nil || auth.config.metadataCache == nil {
+		return
+	}
+	if len(sObjectNames) == 0 {
+		auth.config.metadataCache.Clear()
+		return
+	}
+	for _, sObjectName := range sObjectNames {
+		auth.config.metadataCache.Delete(sObjectName)
+	}
+}
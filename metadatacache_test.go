@@ -0,0 +1,164 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_memoryMetadataCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewMemoryMetadataCache().(*memoryMetadataCache)
+	cache.now = func() time.Time { return now }
+
+	describeKey := newMetadataCacheKey(&authentication{}, "Account", "describe")
+	picklistKey := newMetadataCacheKey(&authentication{}, "account", "picklist-values/"+MasterRecordTypeId+"/rating")
+	contactKey := newMetadataCacheKey(&authentication{}, "Contact", "describe")
+	cache.Set(describeKey, []byte("describe"), time.Minute)
+	cache.Set(picklistKey, []byte("picklist"), 0)
+	cache.Set(contactKey, []byte("contact"), time.Hour)
+
+	if got, ok := cache.Get(describeKey); !ok || string(got) != "describe" {
+		t.Errorf("Get() = %s, %v, want describe", got, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get(describeKey); ok {
+		t.Errorf("Get() returned an expired entry")
+	}
+	if got, ok := cache.Get(picklistKey); !ok || string(got) != "picklist" {
+		t.Errorf("Get() = %s, %v, want entry without ttl", got, ok)
+	}
+
+	cache.Delete("ACCOUNT")
+	if _, ok := cache.Get(picklistKey); ok {
+		t.Errorf("Delete() kept an entry for the object")
+	}
+	if _, ok := cache.Get(contactKey); !ok {
+		t.Errorf("Delete() removed an entry for another object")
+	}
+
+	cache.Clear()
+	if _, ok := cache.Get(contactKey); ok {
+		t.Errorf("Clear() kept an entry")
+	}
+}
+
+func TestWithMetadataCache(t *testing.T) {
+	cache := NewMemoryMetadataCache()
+	config, err := newConfiguration(WithMetadataCache(cache, time.Hour), WithStrictFieldValidation())
+	if err != nil {
+		t.Fatalf("WithMetadataCache() error = %v", err)
+	}
+	if config.metadataCache != cache || config.metadataCacheTTL != time.Hour {
+		t.Errorf("WithMetadataCache() = %v, %v", config.metadataCache, config.metadataCacheTTL)
+	}
+
+	if _, err := newConfiguration(WithMetadataCache(nil, time.Hour)); err == nil {
+		t.Errorf("WithMetadataCache() expected error for nil cache")
+	}
+	if _, err := newConfiguration(WithMetadataCache(cache, -time.Hour)); err == nil {
+		t.Errorf("WithMetadataCache() expected error for negative ttl")
+	}
+}
+
+func Test_invalidateMetadataCache(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if _, err := w.Write([]byte(`{"name": "Account", "values": []}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	config, err := newConfiguration(WithMetadataCache(NewMemoryMetadataCache(), 0))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+	describePath := "/services/data/" + apiVersion + "/sobjects/Account/describe"
+	picklistPath := "/services/data/" + apiVersion + "/ui-api/object-info/Account/picklist-values/" + MasterRecordTypeId + "/Rating"
+	fetch := func() {
+		if _, err := doDescribeSObject(&sfAuth, "Account"); err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := doGetPicklistValues(&sfAuth, "Account", "", "Rating"); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	fetch()
+	fetch()
+	want := map[string]int{describePath: 1, picklistPath: 1}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("cached requests = %v, want %v", requests, want)
+	}
+
+	invalidateMetadataCache(&sfAuth, []string{"Contact"})
+	fetch()
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests after invalidating another object = %v, want %v", requests, want)
+	}
+
+	invalidateMetadataCache(&sfAuth, []string{"account"})
+	fetch()
+	want = map[string]int{describePath: 2, picklistPath: 2}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests after invalidating the object = %v, want %v", requests, want)
+	}
+
+	invalidateMetadataCache(&sfAuth, nil)
+	fetch()
+	want = map[string]int{describePath: 3, picklistPath: 3}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests after clearing the cache = %v, want %v", requests, want)
+	}
+
+	// no cache configured
+	invalidateMetadataCache(&authentication{}, nil)
+	invalidateMetadataCache(nil, []string{"Account"})
+}
+
+func TestSalesforce_WithToken_metadataCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		updateable := r.Header.Get("Authorization") == "Bearer admin"
+		if err := json.NewEncoder(w).Encode(SObjectDescribe{Name: "Account", Updateable: updateable}); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	for _, cache := range []MetadataCache{NewMemoryMetadataCache(), wrappedMetadataCache{NewMemoryMetadataCache()}} {
+		config, err := newConfiguration(WithMetadataCache(cache, 0))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "admin", config: config}}
+		userSf := sf.WithToken("user")
+		_, builtIn := cache.(*memoryMetadataCache)
+		if shared := userSf.auth.config.metadataCache == cache; shared == builtIn {
+			t.Errorf("WithToken() shared metadata cache = %v for %T", shared, cache)
+		}
+		for _, client := range []*Salesforce{sf, userSf, sf} {
+			describe, err := doDescribeSObject(client.auth, "Account")
+			if err != nil {
+				t.Fatalf("doDescribeSObject() error = %v", err)
+			}
+			if want := client.GetAccessToken() == "admin"; describe.Updateable != want {
+				t.Errorf("doDescribeSObject() for %v updateable = %v, want %v", client.GetAccessToken(), describe.Updateable, want)
+			}
+		}
+	}
+}
+
+type wrappedMetadataCache struct {
+	MetadataCache
+}
@@ -0,0 +1,46 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+)
+
+// wraps the transport used for every request, such as for tracing, logging, or custom retries
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// adapts a function to an http.RoundTripper, for writing middleware inline
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// middleware is applied in order, so the first middleware sees each request first
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(config *configuration) error {
+		for _, m := range middleware {
+			if m == nil {
+				return errors.New("middleware is nil")
+			}
+		}
+		config.middleware = append(config.middleware, middleware...)
+		return nil
+	}
+}
+
+// wraps the configured client's transport once all options are applied, so WithHTTPClient and WithRecorder keep working in any order
+func (config *configuration) applyMiddleware() {
+	if len(config.middleware) == 0 {
+		return
+	}
+	client := *config.client()
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(config.middleware) - 1; i >= 0; i-- {
+		transport = config.middleware[i](transport)
+	}
+	client.Transport = transport
+	config.httpClient = &client
+}
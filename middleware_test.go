@@ -0,0 +1,76 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "outer,inner" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	calls := []string{}
+	tracer := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				trace := req.Header.Get("X-Trace")
+				if trace != "" {
+					trace += ","
+				}
+				req.Header.Set("X-Trace", trace+name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	base := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "base")
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := &http.Client{Transport: base}
+
+	config, err := newConfiguration(WithMiddleware(tracer("outer")), WithHTTPClient(client), WithMiddleware(tracer("inner")))
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	resp, err := config.client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Get() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if want := []string{"outer", "inner", "base"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("middleware calls = %v, want %v", calls, want)
+	}
+	if config.client() == client {
+		t.Errorf("WithMiddleware() modified the http client passed to WithHTTPClient")
+	}
+
+	if _, err := newConfiguration(WithMiddleware(nil)); err == nil {
+		t.Errorf("WithMiddleware() expected error for nil middleware")
+	}
+}
+
+func TestWithMiddleware_defaultTransport(t *testing.T) {
+	wrapped := false
+	config, err := newConfiguration(WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		wrapped = next == http.DefaultTransport
+		return next
+	}))
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	if !wrapped || config.client() == http.DefaultClient {
+		t.Errorf("WithMiddleware() did not wrap the default transport")
+	}
+}
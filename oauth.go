@@ -0,0 +1,254 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type DeviceAuthorization struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationUri string `json:"verification_uri"`
+	Interval        int    `json:"interval"`
+	domain          string
+	consumerKey     string
+	config          *configuration
+}
+
+type AuthorizationCodeFlow struct {
+	domain       string
+	consumerKey  string
+	redirectUri  string
+	codeVerifier string
+	state        string
+	config       *configuration
+}
+
+type oauthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+const (
+	grantTypeDevice            = "device"
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeRefreshToken      = "refresh_token"
+	authorizationPendingError  = "authorization_pending"
+	slowDownError              = "slow_down"
+	defaultDevicePollInterval  = 5 * time.Second
+)
+
+// posts to the token endpoint, returning the oauth error code when salesforce rejects the request
+func requestToken(domain string, payload url.Values, config *configuration) (*authentication, string, error) {
+	resp, err := config.client().PostForm(domain+"/services/oauth2/token", payload)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		oauthErr := oauthError{}
+		if json.Unmarshal(respBody, &oauthErr) == nil && oauthErr.Error != "" {
+			return nil, oauthErr.Error, errors.New(resp.Status + ": " + oauthErr.Error + ": " + oauthErr.ErrorDescription)
+		}
+		return nil, "", errors.New(resp.Status + ": failed authentication")
+	}
+
+	auth := &authentication{lock: &sessionLock{}}
+	if err := json.Unmarshal(respBody, auth); err != nil {
+		return nil, "", err
+	}
+	auth.config = config
+	return auth, "", nil
+}
+
+func refreshTokenFlow(domain string, consumerKey string, refreshToken string, config *configuration) (*authentication, error) {
+	if refreshToken == "" {
+		return nil, errors.New("invalid session, no refresh token was issued")
+	}
+	payload := url.Values{
+		"grant_type":    {grantTypeRefreshToken},
+		"client_id":     {consumerKey},
+		"refresh_token": {refreshToken},
+	}
+	auth, _, err := requestToken(domain, payload, config)
+	return auth, err
+}
+
+func NewDeviceAuthorization(domain string, consumerKey string, opts ...Option) (*DeviceAuthorization, error) {
+	config, err := newConfiguration(opts...)
+	if err != nil {
+		return nil, err
+	}
+	domain, err = normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	if domain == "" || consumerKey == "" {
+		return nil, errors.New("domain and consumer key are required")
+	}
+
+	payload := url.Values{
+		"response_type": {"device_code"},
+		"client_id":     {consumerKey},
+	}
+	resp, err := config.client().PostForm(domain+"/services/oauth2/token", payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status + ": failed to start device authorization")
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	device := &DeviceAuthorization{}
+	if err := json.Unmarshal(respBody, device); err != nil {
+		return nil, err
+	}
+	if device.DeviceCode == "" {
+		return nil, errors.New("device code not found in response")
+	}
+	device.domain = domain
+	device.consumerKey = consumerKey
+	device.config = config
+	return device, nil
+}
+
+// blocks until the user approves the device, the device code expires, or the context is done
+func (device *DeviceAuthorization) Wait(ctx context.Context) (*Salesforce, error) {
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+	return pollDeviceToken(ctx, device, interval, defaultDevicePollInterval)
+}
+
+// slowDown is added to the interval each time salesforce asks the client to poll less often
+func pollDeviceToken(ctx context.Context, device *DeviceAuthorization, interval time.Duration, slowDown time.Duration) (*Salesforce, error) {
+	payload := url.Values{
+		"grant_type": {grantTypeDevice},
+		"client_id":  {device.consumerKey},
+		"code":       {device.DeviceCode},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		auth, errorCode, err := requestToken(device.domain, payload, device.config)
+		switch errorCode {
+		case authorizationPendingError:
+			continue
+		case slowDownError:
+			interval = interval + slowDown
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		auth.grantType = grantTypeDevice
+		auth.creds = Creds{Domain: device.domain, ConsumerKey: device.consumerKey}
+		return &Salesforce{auth: auth}, nil
+	}
+}
+
+func randomUrlSafeString(length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func NewAuthorizationCodeFlow(domain string, consumerKey string, redirectUri string, opts ...Option) (*AuthorizationCodeFlow, error) {
+	config, err := newConfiguration(opts...)
+	if err != nil {
+		return nil, err
+	}
+	domain, err = normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	if domain == "" || consumerKey == "" || redirectUri == "" {
+		return nil, errors.New("domain, consumer key, and redirect uri are required")
+	}
+
+	// 32 random bytes encode to a 43 character verifier, the minimum length allowed by pkce
+	codeVerifier, err := randomUrlSafeString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomUrlSafeString(16)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizationCodeFlow{
+		domain:       domain,
+		consumerKey:  consumerKey,
+		redirectUri:  redirectUri,
+		codeVerifier: codeVerifier,
+		state:        state,
+		config:       config,
+	}, nil
+}
+
+func (flow *AuthorizationCodeFlow) AuthorizationUrl() string {
+	challenge := sha256.Sum256([]byte(flow.codeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {flow.consumerKey},
+		"redirect_uri":          {flow.redirectUri},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+		"state":                 {flow.state},
+	}
+	return flow.domain + "/services/oauth2/authorize?" + query.Encode()
+}
+
+// the state sent with the authorization url, which the redirect should echo back unchanged
+func (flow *AuthorizationCodeFlow) State() string {
+	return flow.state
+}
+
+func (flow *AuthorizationCodeFlow) Exchange(code string) (*Salesforce, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, errors.New("authorization code is required")
+	}
+	payload := url.Values{
+		"grant_type":    {grantTypeAuthorizationCode},
+		"client_id":     {flow.consumerKey},
+		"redirect_uri":  {flow.redirectUri},
+		"code":          {code},
+		"code_verifier": {flow.codeVerifier},
+	}
+	auth, _, err := requestToken(flow.domain, payload, flow.config)
+	if err != nil {
+		return nil, err
+	}
+	if auth.AccessToken == "" {
+		return nil, errors.New("unknown authentication error")
+	}
+	auth.grantType = grantTypeAuthorizationCode
+	auth.creds = Creds{Domain: flow.domain, ConsumerKey: flow.consumerKey}
+	return &Salesforce{auth: auth}, nil
+}
@@ -0,0 +1,186 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewDeviceAuthorization(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err.Error())
+		}
+		form = r.PostForm
+		body, _ := json.Marshal(map[string]any{
+			"device_code":      "devicecode",
+			"user_code":        "ABCD1234",
+			"verification_uri": "https://example.my.salesforce.com/setup/connect",
+			"interval":         5,
+		})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	device, err := NewDeviceAuthorization(server.URL+"/", "key")
+	if err != nil {
+		t.Fatalf("NewDeviceAuthorization() error = %v", err)
+	}
+	if form.Get("response_type") != "device_code" || form.Get("client_id") != "key" {
+		t.Errorf("NewDeviceAuthorization() sent %v", form)
+	}
+	if device.UserCode != "ABCD1234" || device.DeviceCode != "devicecode" || device.Interval != 5 {
+		t.Errorf("NewDeviceAuthorization() = %+v", device)
+	}
+	if device.domain != server.URL {
+		t.Errorf("NewDeviceAuthorization() domain = %v, want %v", device.domain, server.URL)
+	}
+
+	if _, err := NewDeviceAuthorization(server.URL, ""); err == nil {
+		t.Error("NewDeviceAuthorization() expected an error without a consumer key")
+	}
+}
+
+func Test_pollDeviceToken(t *testing.T) {
+	newServer := func(responses []string) (*httptest.Server, *int) {
+		requests := 0
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response := responses[min(requests, len(responses)-1)]
+			requests++
+			if response == "" {
+				body, _ := json.Marshal(authentication{AccessToken: "1234", InstanceUrl: "https://example.my.salesforce.com", RefreshToken: "refresh"})
+				_, _ = w.Write(body)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			body, _ := json.Marshal(oauthError{Error: response, ErrorDescription: "description"})
+			_, _ = w.Write(body)
+		})), &requests
+	}
+
+	tests := []struct {
+		name         string
+		responses    []string
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "approved_after_pending",
+			responses:    []string{authorizationPendingError, slowDownError, ""},
+			wantRequests: 3,
+			wantErr:      false,
+		},
+		{
+			name:         "access_denied",
+			responses:    []string{authorizationPendingError, "access_denied"},
+			wantRequests: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newServer(tt.responses)
+			defer server.Close()
+			device := &DeviceAuthorization{DeviceCode: "devicecode", domain: server.URL, consumerKey: "key"}
+
+			sf, err := pollDeviceToken(context.Background(), device, time.Millisecond, time.Millisecond)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pollDeviceToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("pollDeviceToken() made %d requests, want %d", *requests, tt.wantRequests)
+			}
+			if !tt.wantErr && (sf.auth.grantType != grantTypeDevice || sf.auth.RefreshToken != "refresh") {
+				t.Errorf("pollDeviceToken() = %+v", sf.auth)
+			}
+		})
+	}
+
+	t.Run("context_cancelled", func(t *testing.T) {
+		server, _ := newServer([]string{authorizationPendingError})
+		defer server.Close()
+		device := &DeviceAuthorization{DeviceCode: "devicecode", domain: server.URL, consumerKey: "key"}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := pollDeviceToken(ctx, device, time.Millisecond, time.Millisecond); err != context.DeadlineExceeded {
+			t.Errorf("pollDeviceToken() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err.Error())
+		}
+		form = r.PostForm
+		body, _ := json.Marshal(authentication{AccessToken: "1234", InstanceUrl: "https://example.my.salesforce.com"})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	flow, err := NewAuthorizationCodeFlow(server.URL, "key", "http://localhost:8080/callback")
+	if err != nil {
+		t.Fatalf("NewAuthorizationCodeFlow() error = %v", err)
+	}
+
+	authorizationUrl, err := url.Parse(flow.AuthorizationUrl())
+	if err != nil {
+		t.Fatalf("AuthorizationUrl() error = %v", err)
+	}
+	query := authorizationUrl.Query()
+	challenge := sha256.Sum256([]byte(flow.codeVerifier))
+	if authorizationUrl.Path != "/services/oauth2/authorize" ||
+		query.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) ||
+		query.Get("code_challenge_method") != "S256" ||
+		query.Get("state") != flow.State() ||
+		query.Get("redirect_uri") != "http://localhost:8080/callback" {
+		t.Errorf("AuthorizationUrl() = %v", authorizationUrl)
+	}
+	if len(flow.codeVerifier) < 43 {
+		t.Errorf("code verifier length = %d, want at least 43", len(flow.codeVerifier))
+	}
+
+	sf, err := flow.Exchange("authcode")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if form.Get("grant_type") != grantTypeAuthorizationCode || form.Get("code") != "authcode" || form.Get("code_verifier") != flow.codeVerifier {
+		t.Errorf("Exchange() sent %v", form)
+	}
+	if sf.auth.grantType != grantTypeAuthorizationCode || sf.auth.AccessToken != "1234" {
+		t.Errorf("Exchange() = %+v", sf.auth)
+	}
+
+	if _, err := flow.Exchange(""); err == nil {
+		t.Error("Exchange() expected an error without a code")
+	}
+}
+
+func Test_refreshTokenFlow(t *testing.T) {
+	server, _ := setupTestServer(authentication{AccessToken: "5678"}, http.StatusOK)
+	defer server.Close()
+
+	auth, err := refreshTokenFlow(server.URL, "key", "refresh", nil)
+	if err != nil {
+		t.Fatalf("refreshTokenFlow() error = %v", err)
+	}
+	if auth.AccessToken != "5678" {
+		t.Errorf("refreshTokenFlow() = %v, want %v", auth.AccessToken, "5678")
+	}
+	if _, err := refreshTokenFlow(server.URL, "key", "", nil); err == nil {
+		t.Error("refreshTokenFlow() expected an error without a refresh token")
+	}
+}
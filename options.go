@@ -0,0 +1,213 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Option func(*configuration) error
+
+type configuration struct {
+	httpClient            *http.Client
+	customHTTPClient      bool
+	skipSessionValidation bool
+	throttleProfiles      map[string]ThrottleProfile
+	concurrency           int
+	rateLimiter           *rateLimiter
+	pollRateLimiter       *rateLimiter
+	strictFieldValidation bool
+	metadataCache         MetadataCache
+	metadataCacheTTL      time.Duration
+	middleware            []Middleware
+	telemetry             Telemetry
+	tokenProvider         TokenProvider
+	compression           bool
+	highThroughput        bool
+	connectionStats       func(ConnectionEvent)
+	jsonCodec             Codec
+	clientCertificates    []tls.Certificate
+	proxy                 func(*http.Request) (*url.URL, error)
+	proxyHeaders          http.Header
+	queryCache            QueryCache
+	queryCacheTTL         time.Duration
+	unavailableBudget     time.Duration
+	openBulkJobs          chan struct{}
+	recorder              *Recorder
+}
+
+func newConfiguration(opts ...Option) (*configuration, error) {
+	config := &configuration{}
+	for _, opt := range opts {
+		if err := opt(config); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.applyTransport(); err != nil {
+		return nil, err
+	}
+	config.applyMiddleware()
+	return config, nil
+}
+
+func (config *configuration) client() *http.Client {
+	if config == nil || config.httpClient == nil {
+		return http.DefaultClient
+	}
+	return config.httpClient
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(config *configuration) error {
+		if client == nil {
+			return errors.New("http client is nil")
+		}
+		config.httpClient = client
+		config.customHTTPClient = true
+		return nil
+	}
+}
+
+func WithoutSessionValidation() Option {
+	return func(config *configuration) error {
+		config.skipSessionValidation = true
+		return nil
+	}
+}
+
+type DMLOption func(*dmlOptions)
+
+// options that apply to any request, such as headers, can be passed to DoRequest, queries, and DML operations
+type RequestOption = DMLOption
+
+type dmlOptions struct {
+	ignoreDeleted   bool
+	lockRetries     int
+	lockRetryDelay  time.Duration
+	fieldsToNull    []string
+	allOrNone       bool
+	headers         map[string]string
+	continueOnError bool
+	nullNilPointers bool
+	maxPayloadSize  int
+	timeout         time.Duration
+	snapshotFields  []string
+	ctx             context.Context
+}
+
+func newDMLOptions(opts ...DMLOption) dmlOptions {
+	options := dmlOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func WithIgnoreDeleted() DMLOption {
+	return func(options *dmlOptions) {
+		options.ignoreDeleted = true
+	}
+}
+
+func WithLockRetry(maxRetries int, delay time.Duration) DMLOption {
+	return func(options *dmlOptions) {
+		options.lockRetries = maxRetries
+		options.lockRetryDelay = delay
+	}
+}
+
+func WithFieldsToNull(fields ...string) DMLOption {
+	return func(options *dmlOptions) {
+		options.fieldsToNull = append(options.fieldsToNull, fields...)
+	}
+}
+
+func (options *dmlOptions) setHeader(key string, value string) {
+	if options.headers == nil {
+		options.headers = map[string]string{}
+	}
+	options.headers[key] = value
+}
+
+func WithAllOrNone() DMLOption {
+	return func(options *dmlOptions) {
+		options.allOrNone = true
+	}
+}
+
+// sends every batch of a collection operation even after one fails, see MultiError
+func WithContinueOnError() DMLOption {
+	return func(options *dmlOptions) {
+		options.continueOnError = true
+	}
+}
+
+// nil pointer fields are left out of the request by default, this sends them as null to clear the field
+func WithNilPointersAsNull() DMLOption {
+	return func(options *dmlOptions) {
+		options.nullNilPointers = true
+	}
+}
+
+func WithAssignmentRuleHeader(assignmentRuleId string) DMLOption {
+	return func(options *dmlOptions) {
+		options.setHeader(autoAssignHeader, assignmentRuleId)
+	}
+}
+
+func WithAutoAssign(autoAssign bool) DMLOption {
+	return func(options *dmlOptions) {
+		options.setHeader(autoAssignHeader, strings.ToUpper(strconv.FormatBool(autoAssign)))
+	}
+}
+
+func WithDuplicateRuleBypass() DMLOption {
+	return func(options *dmlOptions) {
+		options.setHeader(duplicateRuleHeader, DuplicateRuleHeader{AllowSave: true}.String())
+	}
+}
+
+func WithIfUnmodifiedSince(lastModified time.Time) DMLOption {
+	return func(options *dmlOptions) {
+		options.setHeader(ifUnmodifiedSinceHeader, lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+func WithIfMatch(eTag string) DMLOption {
+	return func(options *dmlOptions) {
+		options.setHeader(ifMatchHeader, eTag)
+	}
+}
+
+func WithHeader(key string, value string) RequestOption {
+	return func(options *dmlOptions) {
+		options.setHeader(key, value)
+	}
+}
+
+// overrides the http client's timeout for each request made by the call, including the time spent reading the response
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(options *dmlOptions) {
+		options.timeout = timeout
+	}
+}
+
+// cancels the call's requests when ctx is done, including requests queued by WithRateLimit
+func WithContext(ctx context.Context) RequestOption {
+	return func(options *dmlOptions) {
+		options.ctx = ctx
+	}
+}
+
+func WithHeaders(headers map[string]string) RequestOption {
+	return func(options *dmlOptions) {
+		for key, value := range headers {
+			options.setHeader(key, value)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_newConfiguration(t *testing.T) {
+	client := &http.Client{}
+	type args struct {
+		opts []Option
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *configuration
+		wantErr bool
+	}{
+		{
+			name:    "default_configuration",
+			args:    args{opts: nil},
+			want:    &configuration{},
+			wantErr: false,
+		},
+		{
+			name: "with_options",
+			args: args{opts: []Option{
+				WithHTTPClient(client),
+				WithoutSessionValidation(),
+			}},
+			want: &configuration{
+				httpClient:            client,
+				customHTTPClient:      true,
+				skipSessionValidation: true,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "nil_http_client",
+			args:    args{opts: []Option{WithHTTPClient(nil)}},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newConfiguration(tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newConfiguration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newConfiguration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_configuration_client(t *testing.T) {
+	client := &http.Client{}
+	tests := []struct {
+		name   string
+		config *configuration
+		want   *http.Client
+	}{
+		{
+			name:   "nil_configuration",
+			config: nil,
+			want:   http.DefaultClient,
+		},
+		{
+			name:   "default_client",
+			config: &configuration{},
+			want:   http.DefaultClient,
+		},
+		{
+			name:   "custom_client",
+			config: &configuration{httpClient: client},
+			want:   client,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.client(); got != tt.want {
+				t.Errorf("configuration.client() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newDMLOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []DMLOption
+		want dmlOptions
+	}{
+		{
+			name: "default_options",
+			opts: nil,
+			want: dmlOptions{},
+		},
+		{
+			name: "ignore_deleted",
+			opts: []DMLOption{WithIgnoreDeleted()},
+			want: dmlOptions{ignoreDeleted: true},
+		},
+		{
+			name: "lock_retry",
+			opts: []DMLOption{WithLockRetry(3, time.Second)},
+			want: dmlOptions{lockRetries: 3, lockRetryDelay: time.Second},
+		},
+		{
+			name: "fields_to_null",
+			opts: []DMLOption{WithFieldsToNull("Description"), WithFieldsToNull("Phone", "Fax")},
+			want: dmlOptions{fieldsToNull: []string{"Description", "Phone", "Fax"}},
+		},
+		{
+			name: "all_or_none",
+			opts: []DMLOption{WithAllOrNone()},
+			want: dmlOptions{allOrNone: true},
+		},
+		{
+			name: "headers",
+			opts: []DMLOption{WithAutoAssign(false), WithDuplicateRuleBypass()},
+			want: dmlOptions{headers: map[string]string{
+				"Sforce-Auto-Assign":           "FALSE",
+				"Sforce-Duplicate-Rule-Header": "allowSave=true, includeRecordDetails=false, runAsCurrentUser=false",
+			}},
+		},
+		{
+			name: "assignment_rule",
+			opts: []DMLOption{WithAutoAssign(false), WithAssignmentRuleHeader("01Q000000000001")},
+			want: dmlOptions{headers: map[string]string{"Sforce-Auto-Assign": "01Q000000000001"}},
+		},
+		{
+			name: "conditional",
+			opts: []DMLOption{
+				WithIfUnmodifiedSince(time.Date(2024, 10, 1, 16, 30, 0, 0, time.UTC)),
+				WithIfMatch("\"abc123\""),
+			},
+			want: dmlOptions{headers: map[string]string{
+				"If-Unmodified-Since": "Tue, 01 Oct 2024 16:30:00 GMT",
+				"If-Match":            "\"abc123\"",
+			}},
+		},
+		{
+			name: "custom_headers",
+			opts: []DMLOption{
+				WithHeader("Sforce-Call-Options", "client=sync"),
+				WithHeaders(map[string]string{"If-Modified-Since": "Tue, 01 Oct 2024 00:00:00 GMT", "Sforce-Call-Options": "client=etl"}),
+			},
+			want: dmlOptions{headers: map[string]string{
+				"Sforce-Call-Options": "client=etl",
+				"If-Modified-Since":   "Tue, 01 Oct 2024 00:00:00 GMT",
+			}},
+		},
+		{
+			name: "timeout",
+			opts: []DMLOption{WithTimeout(5 * time.Second)},
+			want: dmlOptions{timeout: 5 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newDMLOptions(tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newDMLOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
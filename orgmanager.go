@@ -0,0 +1,154 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrOrgNotFound = errors.New("org not found")
+
+// holds a named client per org, for applications that work with many orgs from one process
+type OrgManager struct {
+	mu    sync.Mutex
+	orgs  map[string]*managedOrg
+	names []string
+	next  int
+}
+
+// clients are created on first use, a failed Init is not cached and is retried on the next lookup
+type managedOrg struct {
+	mu     sync.Mutex
+	creds  Creds
+	config *configuration
+	client *Salesforce
+}
+
+func NewOrgManager() *OrgManager {
+	return &OrgManager{orgs: map[string]*managedOrg{}}
+}
+
+// checked up front so that bad creds fail at registration rather than on first use; the configuration is
+// kept for Init, since options such as rate limits and caches must only be applied once
+func newManagedOrg(creds Creds, opts []Option) (*managedOrg, error) {
+	config, err := newConfiguration(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if creds == (Creds{}) && !config.hasTokenProvider() {
+		return nil, errors.New("creds is empty")
+	}
+	return &managedOrg{creds: creds, config: config}, nil
+}
+
+func (manager *OrgManager) register(name string, org *managedOrg) error {
+	if name == "" {
+		return errors.New("org name is required")
+	}
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if _, ok := manager.orgs[name]; ok {
+		return fmt.Errorf("org %s is already registered", name)
+	}
+	manager.orgs[name] = org
+	manager.names = append(manager.names, name)
+	return nil
+}
+
+// registers the org's creds without authenticating, Init is called the first time the org is used
+func (manager *OrgManager) Register(name string, creds Creds, opts ...Option) error {
+	org, err := newManagedOrg(creds, opts)
+	if err != nil {
+		return err
+	}
+	return manager.register(name, org)
+}
+
+// registers a client that has already been initialized
+func (manager *OrgManager) Add(name string, sf *Salesforce) error {
+	if sf == nil {
+		return errors.New("client is nil")
+	}
+	if err := validateAuth(*sf); err != nil {
+		return err
+	}
+	return manager.register(name, &managedOrg{client: sf})
+}
+
+// replaces the org's creds, the client is re-created with them the next time the org is used
+func (manager *OrgManager) Reload(name string, creds Creds, opts ...Option) error {
+	org, err := newManagedOrg(creds, opts)
+	if err != nil {
+		return err
+	}
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if _, ok := manager.orgs[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrOrgNotFound, name)
+	}
+	manager.orgs[name] = org
+	return nil
+}
+
+func (manager *OrgManager) Remove(name string) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if _, ok := manager.orgs[name]; !ok {
+		return
+	}
+	delete(manager.orgs, name)
+	for i, registered := range manager.names {
+		if registered == name {
+			manager.names = append(manager.names[:i], manager.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// names of the registered orgs, in the order they were registered
+func (manager *OrgManager) Names() []string {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	return append([]string{}, manager.names...)
+}
+
+func (manager *OrgManager) Get(name string) (*Salesforce, error) {
+	manager.mu.Lock()
+	org, ok := manager.orgs[name]
+	manager.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrOrgNotFound, name)
+	}
+	return org.get(name)
+}
+
+// returns the orgs in turn, in the order they were registered
+func (manager *OrgManager) Next() (string, *Salesforce, error) {
+	manager.mu.Lock()
+	if len(manager.names) == 0 {
+		manager.mu.Unlock()
+		return "", nil, errors.New("no orgs are registered")
+	}
+	name := manager.names[manager.next%len(manager.names)]
+	manager.next = (manager.next + 1) % len(manager.names)
+	org := manager.orgs[name]
+	manager.mu.Unlock()
+
+	sf, err := org.get(name)
+	return name, sf, err
+}
+
+// the org's own lock keeps slow logins from blocking lookups of other orgs
+func (org *managedOrg) get(name string) (*Salesforce, error) {
+	org.mu.Lock()
+	defer org.mu.Unlock()
+	if org.client != nil {
+		return org.client, nil
+	}
+	sf, err := initWithConfiguration(org.creds, org.config)
+	if err != nil {
+		return nil, fmt.Errorf("org %s: %w", name, err)
+	}
+	org.client = sf
+	return sf, nil
+}
@@ -0,0 +1,177 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_OrgManager_Get(t *testing.T) {
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logins.Add(1)
+		if _, err := w.Write([]byte(`{"access_token": "token", "instance_url": "` + "http://" + r.Host + `"}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	manager := NewOrgManager()
+	creds := Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"}
+	var applied atomic.Int32
+	countOptions := func(config *configuration) error {
+		applied.Add(1)
+		return nil
+	}
+	if err := manager.Register("acme", creds, WithoutSessionValidation(), countOptions); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if logins.Load() != 0 {
+		t.Errorf("Register() logged in before the org was used")
+	}
+
+	first, err := manager.Get("acme")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	second, err := manager.Get("acme")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if first != second || logins.Load() != 1 {
+		t.Errorf("Get() logged in %d times, want 1", logins.Load())
+	}
+	if applied.Load() != 1 {
+		t.Errorf("options applied %d times, want 1", applied.Load())
+	}
+
+	if err := manager.Reload("acme", creds, WithoutSessionValidation()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	reloaded, err := manager.Get("acme")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if reloaded == first || logins.Load() != 2 {
+		t.Errorf("Get() did not re-create the client after Reload")
+	}
+
+	if _, err := manager.Get("globex"); !errors.Is(err, ErrOrgNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, ErrOrgNotFound)
+	}
+	if err := manager.Reload("globex", creds); !errors.Is(err, ErrOrgNotFound) {
+		t.Errorf("Reload() error = %v, want %v", err, ErrOrgNotFound)
+	}
+}
+
+func Test_OrgManager_Get_initError(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, err := w.Write([]byte(`{"access_token": "token", "instance_url": "` + "http://" + r.Host + `"}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	manager := NewOrgManager()
+	creds := Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"}
+	if err := manager.Register("acme", creds, WithoutSessionValidation()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if _, err := manager.Get("acme"); err == nil {
+		t.Fatalf("Get() expected error for failed login")
+	}
+	fail.Store(false)
+	if _, err := manager.Get("acme"); err != nil {
+		t.Errorf("Get() error = %v, want failed login to be retried", err)
+	}
+}
+
+func Test_OrgManager_Next(t *testing.T) {
+	manager := NewOrgManager()
+	if _, _, err := manager.Next(); err == nil {
+		t.Errorf("Next() expected error with no orgs")
+	}
+
+	clients := map[string]*Salesforce{}
+	for _, name := range []string{"acme", "globex", "initech"} {
+		clients[name] = &Salesforce{auth: &authentication{InstanceUrl: "https://" + name + ".my.salesforce.com", AccessToken: "token"}}
+		if err := manager.Add(name, clients[name]); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	got := []string{}
+	for i := 0; i < 4; i++ {
+		name, sf, err := manager.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if sf != clients[name] {
+			t.Errorf("Next() returned the wrong client for %s", name)
+		}
+		got = append(got, name)
+	}
+	want := []string{"acme", "globex", "initech", "acme"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+
+	manager.Remove("globex")
+	if names := manager.Names(); !reflect.DeepEqual(names, []string{"acme", "initech"}) {
+		t.Errorf("Names() = %v, want %v", names, []string{"acme", "initech"})
+	}
+}
+
+func Test_OrgManager_register(t *testing.T) {
+	manager := NewOrgManager()
+	sf := &Salesforce{auth: &authentication{InstanceUrl: "https://acme.my.salesforce.com", AccessToken: "token"}}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr bool
+	}{
+		{
+			name: "add",
+			run:  func() error { return manager.Add("acme", sf) },
+		},
+		{
+			name: "duplicate",
+			run: func() error {
+				return manager.Register("acme", Creds{Domain: "acme.my.salesforce.com", AccessToken: "token"})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty_name",
+			run:     func() error { return manager.Add("", sf) },
+			wantErr: true,
+		},
+		{
+			name:    "empty_creds",
+			run:     func() error { return manager.Register("globex", Creds{}) },
+			wantErr: true,
+		},
+		{
+			name:    "uninitialized_client",
+			run:     func() error { return manager.Add("initech", &Salesforce{}) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
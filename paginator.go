@@ -0,0 +1,101 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// follows the nextRecordsUrl or nextPageUrl of each page of a REST list endpoint, such as
+// query, list view, chatter, and tooling endpoints
+type Paginator struct {
+	auth    *authentication
+	uri     string
+	options dmlOptions
+	page    []byte
+	err     error
+}
+
+type pageLinks struct {
+	Done           *bool  `json:"done"`
+	NextRecordsUrl string `json:"nextRecordsUrl"`
+	NextPageUrl    string `json:"nextPageUrl"`
+}
+
+func newPaginator(auth *authentication, uri string, options dmlOptions) (*Paginator, error) {
+	if uri == "" {
+		return nil, errors.New("uri is required")
+	}
+	return &Paginator{auth: auth, uri: nextPageUri(uri), options: options}, nil
+}
+
+// next page urls are returned with the /services/data/<version> prefix that doRequest adds,
+// and sometimes with the instance url
+func nextPageUri(next string) string {
+	if parsedUrl, err := url.Parse(next); err == nil && parsedUrl.IsAbs() {
+		next = parsedUrl.RequestURI()
+	}
+	if rest, ok := strings.CutPrefix(next, "/services/data/"); ok {
+		if i := strings.Index(rest, "/"); i >= 0 {
+			return rest[i:]
+		}
+		return ""
+	}
+	return next
+}
+
+// fetches the next page, returning false once every page has been fetched or a request fails
+func (paginator *Paginator) Next() bool {
+	if paginator.err != nil || paginator.uri == "" {
+		return false
+	}
+	resp, err := doRequest(paginator.auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     paginator.uri,
+		content: jsonType,
+		headers: paginator.options.headers,
+		timeout: paginator.options.timeout,
+		ctx:     paginator.options.ctx,
+	})
+	if err != nil {
+		paginator.err = err
+		return false
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		paginator.err = err
+		return false
+	}
+
+	links := pageLinks{}
+	if err := json.Unmarshal(body, &links); err != nil {
+		paginator.err = err
+		return false
+	}
+	paginator.page = body
+	paginator.uri = ""
+	if links.Done == nil || !*links.Done {
+		next := links.NextRecordsUrl
+		if next == "" {
+			next = links.NextPageUrl
+		}
+		paginator.uri = nextPageUri(next)
+	}
+	return true
+}
+
+// decodes the current page's json
+func (paginator *Paginator) Decode(val any) error {
+	if paginator.page == nil {
+		return errors.New("no page has been fetched, call Next first")
+	}
+	return json.Unmarshal(paginator.page, val)
+}
+
+func (paginator *Paginator) Error() error {
+	return paginator.err
+}
@@ -0,0 +1,155 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_nextPageUri(t *testing.T) {
+	tests := []struct {
+		name string
+		next string
+		want string
+	}{
+		{
+			name: "relative",
+			next: "/query/01g000000000001-2000",
+			want: "/query/01g000000000001-2000",
+		},
+		{
+			name: "versioned",
+			next: "/services/data/" + apiVersion + "/query/01g000000000001-2000",
+			want: "/query/01g000000000001-2000",
+		},
+		{
+			name: "other_version",
+			next: "/services/data/v58.0/chatter/feeds/news/me/feed-elements?page=2",
+			want: "/chatter/feeds/news/me/feed-elements?page=2",
+		},
+		{
+			name: "absolute",
+			next: "https://example.my.salesforce.com/services/data/" + apiVersion + "/tooling/query/01g000000000001-2000",
+			want: "/tooling/query/01g000000000001-2000",
+		},
+		{
+			name: "empty",
+			next: "",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageUri(tt.next); got != tt.want {
+				t.Errorf("nextPageUri() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp string
+		switch r.URL.RequestURI() {
+		case "/services/data/" + apiVersion + "/tooling/query/?q=SELECT+Id+FROM+ApexClass":
+			resp = `{"done": false, "nextRecordsUrl": "/services/data/` + apiVersion + `/tooling/query/01g000000000001-1", "records": [{"Id": "01p000000000001"}]}`
+		case "/services/data/" + apiVersion + "/tooling/query/01g000000000001-1":
+			resp = `{"done": false, "nextRecordsUrl": "/services/data/` + apiVersion + `/tooling/query/01g000000000001-2", "records": [{"Id": "01p000000000002"}]}`
+		case "/services/data/" + apiVersion + "/tooling/query/01g000000000001-2":
+			resp = `{"done": true, "nextRecordsUrl": "/services/data/` + apiVersion + `/tooling/query/01g000000000001-3", "records": [{"Id": "01p000000000003"}]}`
+		case "/services/data/" + apiVersion + "/chatter/users/me/groups":
+			resp = `{"groups": [{"id": "0F9000000000001"}], "nextPageUrl": "/services/data/` + apiVersion + `/chatter/users/me/groups?page=1"}`
+		case "/services/data/" + apiVersion + "/chatter/users/me/groups?page=1":
+			resp = `{"groups": [{"id": "0F9000000000002"}], "nextPageUrl": null}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			resp = `[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`
+		}
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+
+	type record struct {
+		Id string
+	}
+	tests := []struct {
+		name    string
+		uri     string
+		decode  func(paginator *Paginator) ([]string, error)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "next_records_url",
+			uri:  "/tooling/query/?q=SELECT+Id+FROM+ApexClass",
+			decode: func(paginator *Paginator) ([]string, error) {
+				page := struct{ Records []record }{}
+				err := paginator.Decode(&page)
+				ids := []string{}
+				for _, r := range page.Records {
+					ids = append(ids, r.Id)
+				}
+				return ids, err
+			},
+			want: []string{"01p000000000001", "01p000000000002", "01p000000000003"},
+		},
+		{
+			name: "next_page_url",
+			uri:  "/chatter/users/me/groups",
+			decode: func(paginator *Paginator) ([]string, error) {
+				page := struct{ Groups []record }{}
+				err := paginator.Decode(&page)
+				ids := []string{}
+				for _, r := range page.Groups {
+					ids = append(ids, r.Id)
+				}
+				return ids, err
+			},
+			want: []string{"0F9000000000001", "0F9000000000002"},
+		},
+		{
+			name:    "not_found",
+			uri:     "/chatter/users/me/missing",
+			want:    []string{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator, err := sf.Paginate(tt.uri)
+			if err != nil {
+				t.Fatalf("Paginate() error = %v", err)
+			}
+			got := []string{}
+			for paginator.Next() {
+				ids, err := tt.decode(paginator)
+				if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				got = append(got, ids...)
+			}
+			if (paginator.Error() != nil) != tt.wantErr {
+				t.Errorf("Error() = %v, wantErr %v", paginator.Error(), tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	paginator, _ := sf.Paginate("/chatter/users/me/groups")
+	if err := paginator.Decode(&struct{}{}); err == nil {
+		t.Errorf("Decode() expected error before Next")
+	}
+	if _, err := sf.Paginate(""); err == nil {
+		t.Errorf("Paginate() expected error for empty uri")
+	}
+	sf = &Salesforce{}
+	if _, err := sf.Paginate("/chatter/users/me/groups"); err == nil {
+		t.Errorf("Paginate() expected validation error")
+	}
+}
@@ -0,0 +1,177 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+const compositeSubRequestLimit = 25
+
+// splits collection and composite batches so that no request body is larger than maxBytes,
+// on top of the batch size; single record operations are not affected
+func WithMaxPayloadSize(maxBytes int) DMLOption {
+	return func(options *dmlOptions) {
+		options.maxPayloadSize = maxBytes
+	}
+}
+
+func jsonSize(value any) (int, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	return len(body), nil
+}
+
+// batches hold at most batchSize records and, when maxBytes is set, no more than maxBytes of json
+// along with the overhead of the request around the records
+func splitRecords(records []map[string]any, batchSize int, maxBytes int, overhead int) ([][]map[string]any, error) {
+	var batches [][]map[string]any
+	if maxBytes <= 0 {
+		for len(records) > 0 {
+			size := min(batchSize, len(records))
+			batches = append(batches, records[:size])
+			records = records[size:]
+		}
+		return batches, nil
+	}
+
+	start := 0
+	size := overhead
+	for i, record := range records {
+		// records are separated by commas
+		recordSize, err := jsonSize(record)
+		if err != nil {
+			return nil, err
+		}
+		recordSize++
+		if overhead+recordSize > maxBytes {
+			return nil, fmt.Errorf("%w: record %d is %d bytes, over the maximum of %d bytes", ErrPayloadTooLarge, i, recordSize, maxBytes)
+		}
+		if i > start && (i-start == batchSize || size+recordSize > maxBytes) {
+			batches = append(batches, records[start:i])
+			start = i
+			size = overhead
+		}
+		size += recordSize
+	}
+	if start < len(records) {
+		batches = append(batches, records[start:])
+	}
+	return batches, nil
+}
+
+func batchOffsets[T any](batches [][]T) []int {
+	offsets := make([]int, len(batches))
+	offset := 0
+	for i, batch := range batches {
+		offsets[i] = offset
+		offset += len(batch)
+	}
+	return offsets
+}
+
+// a composite request is sent as one request, so when it is too large its records are spread over
+// several composite requests, which isn't possible when the whole request must succeed or fail together
+func splitCompositeRequest(compReq compositeRequest, options dmlOptions) ([]compositeRequest, error) {
+	if options.maxPayloadSize <= 0 {
+		return []compositeRequest{compReq}, nil
+	}
+	maxBytes := options.maxPayloadSize
+	requestOverhead, err := jsonSize(compositeRequest{AllOrNone: compReq.AllOrNone, CompositeRequest: []compositeSubRequest{}})
+	if err != nil {
+		return nil, err
+	}
+
+	var subReqs []compositeSubRequest
+	for _, subReq := range compReq.CompositeRequest {
+		subReq.HttpHeaders = options.headers
+		if subReq.Method == http.MethodDelete {
+			subReqs = append(subReqs, subReq)
+			continue
+		}
+		empty := subReq
+		empty.Body.Records = []map[string]any{}
+		subReqOverhead, err := jsonSize(empty)
+		if err != nil {
+			return nil, err
+		}
+		batches, err := splitRecords(subReq.Body.Records, len(subReq.Body.Records), maxBytes, requestOverhead+subReqOverhead+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range batches {
+			split := subReq
+			split.Body.Records = batch
+			subReqs = append(subReqs, split)
+		}
+	}
+
+	var compReqs []compositeRequest
+	current := compositeRequest{AllOrNone: compReq.AllOrNone}
+	size := requestOverhead
+	for i, subReq := range subReqs {
+		subReq.ReferenceId = "refObj" + strconv.Itoa(i)
+		subReqSize, err := jsonSize(subReq)
+		if err != nil {
+			return nil, err
+		}
+		subReqSize++
+		if requestOverhead+subReqSize > maxBytes {
+			return nil, fmt.Errorf("%w: subrequest %s is %d bytes, over the maximum of %d bytes", ErrPayloadTooLarge, subReq.ReferenceId, subReqSize, maxBytes)
+		}
+		if len(current.CompositeRequest) > 0 && (len(current.CompositeRequest) == compositeSubRequestLimit || size+subReqSize > maxBytes) {
+			compReqs = append(compReqs, current)
+			current = compositeRequest{AllOrNone: compReq.AllOrNone}
+			size = requestOverhead
+		}
+		current.CompositeRequest = append(current.CompositeRequest, subReq)
+		size += subReqSize
+	}
+	compReqs = append(compReqs, current)
+
+	if len(compReqs) > 1 && compReq.AllOrNone {
+		return nil, fmt.Errorf("%w: an all or none composite request can't be split into %d requests", ErrPayloadTooLarge, len(compReqs))
+	}
+	return compReqs, nil
+}
+
+// sends the composite request, split when it is over the maximum payload size, offsetting the results of
+// each request by the records sent before it
+func doCompositeRequests(auth *authentication, compReq compositeRequest, options dmlOptions) (SalesforceResults, error) {
+	compReqs, err := splitCompositeRequest(compReq, options)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if len(compReqs) == 1 {
+		return doCompositeRequest(auth, compReqs[0], options)
+	}
+
+	merged := SalesforceResults{}
+	offset := 0
+	for _, req := range compReqs {
+		results, err := doCompositeRequest(auth, req, options)
+		if err != nil {
+			return merged, err
+		}
+		for i := range results.Results {
+			results.Results[i].Index += offset
+		}
+		for i := range results.SubRequests {
+			results.SubRequests[i].Index += offset
+		}
+		merged.Results = append(merged.Results, results.Results...)
+		merged.SubRequests = append(merged.SubRequests, results.SubRequests...)
+		merged.HasSalesforceErrors = merged.HasSalesforceErrors || results.HasSalesforceErrors
+		merged.ResponseMetadata = results.ResponseMetadata
+		for _, subReq := range req.CompositeRequest {
+			offset += subRequestSize(subReq)
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,191 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_splitRecords(t *testing.T) {
+	// each record is 17 bytes of json, plus a comma
+	records := []map[string]any{
+		{"Name": "aaaaaa"},
+		{"Name": "bbbbbb"},
+		{"Name": "cccccc"},
+		{"Name": "dddddd"},
+		{"Name": "eeeeee"},
+	}
+	tests := []struct {
+		name      string
+		batchSize int
+		maxBytes  int
+		overhead  int
+		want      []int
+		wantErr   bool
+	}{
+		{
+			name:      "count_only",
+			batchSize: 2,
+			want:      []int{2, 2, 1},
+		},
+		{
+			name:      "size_limited",
+			batchSize: 200,
+			maxBytes:  64,
+			overhead:  10,
+			want:      []int{3, 2},
+		},
+		{
+			name:      "count_before_size",
+			batchSize: 2,
+			maxBytes:  1000,
+			overhead:  10,
+			want:      []int{2, 2, 1},
+		},
+		{
+			name:      "record_too_large",
+			batchSize: 200,
+			maxBytes:  20,
+			overhead:  10,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches, err := splitRecords(records, tt.batchSize, tt.maxBytes, tt.overhead)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitRecords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrPayloadTooLarge) {
+					t.Errorf("splitRecords() error = %v, want %v", err, ErrPayloadTooLarge)
+				}
+				return
+			}
+			if got := batchSizes(batches); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitRecords() batch sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doInsertCollection_maxPayloadSize(t *testing.T) {
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload := sObjectCollection{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			panic(err.Error())
+		}
+		if len(body) > 180 {
+			t.Errorf("request body is %d bytes, over the maximum of 180", len(body))
+		}
+		names := []string{}
+		results := []SalesforceResult{}
+		for _, record := range payload.Records {
+			names = append(names, record["Name"].(string))
+			results = append(results, SalesforceResult{Id: "001" + record["Name"].(string), Success: true})
+		}
+		batches = append(batches, names)
+		resp, _ := json.Marshal(results)
+		if _, err := w.Write(resp); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	records := []map[string]any{
+		{"Name": "a", "Description": strings.Repeat("x", 40)},
+		{"Name": "b", "Description": strings.Repeat("x", 40)},
+		{"Name": "c", "Description": strings.Repeat("x", 40)},
+		{"Name": "d"},
+	}
+	results, err := doInsertCollection(&sfAuth, "Account", records, 200, newDMLOptions(WithMaxPayloadSize(180)))
+	if err != nil {
+		t.Fatalf("doInsertCollection() error = %v", err)
+	}
+	wantBatches := [][]string{{"a"}, {"b"}, {"c", "d"}}
+	if !reflect.DeepEqual(batches, wantBatches) {
+		t.Errorf("doInsertCollection() batches = %v, want %v", batches, wantBatches)
+	}
+	for i, result := range results.Results {
+		if result.Index != i {
+			t.Errorf("doInsertCollection() result %d has index %d", i, result.Index)
+		}
+	}
+
+	_, err = doInsertCollection(&sfAuth, "Account", records, 200, newDMLOptions(WithMaxPayloadSize(50)))
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("doInsertCollection() error = %v, want %v", err, ErrPayloadTooLarge)
+	}
+}
+
+func Test_doInsertComposite_maxPayloadSize(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 400 {
+			t.Errorf("request body is %d bytes, over the maximum of 400", len(body))
+		}
+		compReq := compositeRequest{}
+		if err := json.Unmarshal(body, &compReq); err != nil {
+			panic(err.Error())
+		}
+		compResult := compositeRequestResult{}
+		for _, subReq := range compReq.CompositeRequest {
+			subResult := compositeSubRequestResult{ReferenceId: subReq.ReferenceId, HttpStatusCode: http.StatusOK}
+			for _, record := range subReq.Body.Records {
+				subResult.Body = append(subResult.Body, SalesforceResult{Id: "001" + record["Name"].(string), Success: true})
+			}
+			compResult.CompositeResponse = append(compResult.CompositeResponse, subResult)
+		}
+		resp, _ := json.Marshal(compResult)
+		if _, err := w.Write(resp); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	records := []map[string]any{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		records = append(records, map[string]any{"Name": name, "Description": strings.Repeat("x", 60)})
+	}
+	results, err := doInsertComposite(&sfAuth, "Account", records, false, 200, newDMLOptions(WithMaxPayloadSize(400)))
+	if err != nil {
+		t.Fatalf("doInsertComposite() error = %v", err)
+	}
+	if requests < 2 {
+		t.Errorf("doInsertComposite() sent %d requests, want the records split over several", requests)
+	}
+	ids := []string{}
+	for i, result := range results.Results {
+		if result.Index != i {
+			t.Errorf("doInsertComposite() result %d has index %d", i, result.Index)
+		}
+		ids = append(ids, result.Id)
+	}
+	if want := []string{"001a", "001b", "001c", "001d", "001e"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("doInsertComposite() ids = %v, want %v", ids, want)
+	}
+	index := 0
+	for _, subRequest := range results.SubRequests {
+		if subRequest.Index != index {
+			t.Errorf("doInsertComposite() subrequest %s has index %d, want %d", subRequest.ReferenceId, subRequest.Index, index)
+		}
+		index += subRequest.Size
+	}
+
+	requests = 0
+	_, err = doInsertComposite(&sfAuth, "Account", records, true, 200, newDMLOptions(WithMaxPayloadSize(400)))
+	if !errors.Is(err, ErrPayloadTooLarge) || requests != 0 {
+		t.Errorf("doInsertComposite() error = %v after %d requests, want %v before sending", err, requests, ErrPayloadTooLarge)
+	}
+}
@@ -0,0 +1,48 @@
+package salesforce
+
+import (
+	"errors"
+	"strings"
+)
+
+// describe results reflect the current user's object permissions and field level security
+func doCanCreateObject(auth *authentication, sObjectName string) (bool, error) {
+	describe, err := doDescribeSObject(auth, sObjectName)
+	if err != nil {
+		return false, err
+	}
+	return describe.Createable, nil
+}
+
+func doCanUpdateObject(auth *authentication, sObjectName string) (bool, error) {
+	describe, err := doDescribeSObject(auth, sObjectName)
+	if err != nil {
+		return false, err
+	}
+	return describe.Updateable, nil
+}
+
+func doCanDeleteObject(auth *authentication, sObjectName string) (bool, error) {
+	describe, err := doDescribeSObject(auth, sObjectName)
+	if err != nil {
+		return false, err
+	}
+	return describe.Deletable, nil
+}
+
+// fields the current user can't read are left out of the describe
+func doFieldAccessible(auth *authentication, sObjectName string, fieldName string) (bool, error) {
+	if fieldName == "" {
+		return false, errors.New("field name is required")
+	}
+	describe, err := doDescribeSObject(auth, sObjectName)
+	if err != nil {
+		return false, err
+	}
+	for _, field := range describe.Fields {
+		if strings.EqualFold(field.Name, fieldName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
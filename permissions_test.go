@@ -0,0 +1,66 @@
+package salesforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_doCanObject(t *testing.T) {
+	server, sfAuth := setupTestServer(accountDescribe, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		check func(*authentication, string) (bool, error)
+		want  bool
+	}{
+		{name: "create", check: doCanCreateObject, want: true},
+		{name: "update", check: doCanUpdateObject, want: true},
+		{name: "delete", check: doCanDeleteObject, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.check(&sfAuth, "Account")
+			if err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusNotFound)
+	defer badServer.Close()
+	if _, err := doCanCreateObject(&badAuth, "Account"); err == nil {
+		t.Errorf("doCanCreateObject() expected error")
+	}
+}
+
+func Test_doFieldAccessible(t *testing.T) {
+	server, sfAuth := setupTestServer(accountDescribe, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		fieldName string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "accessible", fieldName: "Name", want: true},
+		{name: "case_insensitive", fieldName: "external_id__c", want: true},
+		{name: "not_accessible", fieldName: "AnnualRevenue", want: false},
+		{name: "missing_field_name", fieldName: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doFieldAccessible(&sfAuth, "Account", tt.fieldName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doFieldAccessible() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("doFieldAccessible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// record type id used for objects without record types, or for the master record type
+const MasterRecordTypeId = "012000000000000AAA"
+
+type PicklistValues struct {
+	DefaultValue     *PicklistValue  `json:"defaultValue"`
+	Values           []PicklistValue `json:"values"`
+	ControllerValues map[string]int  `json:"controllerValues"`
+}
+
+type PicklistValue struct {
+	Label    string `json:"label"`
+	Value    string `json:"value"`
+	ValidFor []int  `json:"validFor"`
+}
+
+func doGetPicklistValues(auth *authentication, sObjectName string, recordTypeId string, fieldName string) (PicklistValues, error) {
+	if sObjectName == "" || fieldName == "" {
+		return PicklistValues{}, errors.New("sObject name and field name are required")
+	}
+	if recordTypeId == "" {
+		recordTypeId = MasterRecordTypeId
+	}
+	key := newMetadataCacheKey(auth, sObjectName, "picklist-values/"+recordTypeId+"/"+strings.ToLower(fieldName))
+	body, cached := auth.config.cachedMetadata(key)
+	if !cached {
+		uri := "/ui-api/object-info/" + url.PathEscape(sObjectName) + "/picklist-values/" + url.PathEscape(recordTypeId) + "/" + url.PathEscape(fieldName)
+		resp, err := doRequest(auth, requestPayload{
+			method:  http.MethodGet,
+			uri:     uri,
+			content: jsonType,
+		})
+		if err != nil {
+			return PicklistValues{}, err
+		}
+		defer resp.Body.Close()
+
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return PicklistValues{}, err
+		}
+	}
+	values := PicklistValues{}
+	if err := json.Unmarshal(body, &values); err != nil {
+		return PicklistValues{}, err
+	}
+	if !cached {
+		auth.config.cacheMetadata(key, body)
+	}
+	return values, nil
+}
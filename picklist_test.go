@@ -0,0 +1,111 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_doGetPicklistValues(t *testing.T) {
+	resp := `{
+		"controllerValues": {"Prospecting": 0},
+		"defaultValue": {"attributes": null, "label": "Hot", "validFor": [], "value": "Hot"},
+		"eTag": "abc",
+		"url": "/services/data/v62.0/ui-api/object-info/Account/picklist-values/012000000000000AAA/Rating",
+		"values": [
+			{"attributes": null, "label": "Hot", "validFor": [0], "value": "Hot"},
+			{"attributes": null, "label": "Warm", "validFor": [], "value": "Warm"}
+		]
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/services/data/"+apiVersion+"/ui-api/object-info/Account/picklist-values/"+MasterRecordTypeId+"/Rating" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	want := PicklistValues{
+		DefaultValue:     &PicklistValue{Label: "Hot", Value: "Hot", ValidFor: []int{}},
+		ControllerValues: map[string]int{"Prospecting": 0},
+		Values: []PicklistValue{
+			{Label: "Hot", Value: "Hot", ValidFor: []int{0}},
+			{Label: "Warm", Value: "Warm", ValidFor: []int{}},
+		},
+	}
+
+	type args struct {
+		auth         *authentication
+		sObjectName  string
+		recordTypeId string
+		fieldName    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    PicklistValues
+		wantErr bool
+	}{
+		{
+			name: "master_record_type",
+			args: args{
+				auth:         &sfAuth,
+				sObjectName:  "Account",
+				recordTypeId: MasterRecordTypeId,
+				fieldName:    "Rating",
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "default_record_type",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+				fieldName:   "Rating",
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "missing_field_name",
+			args: args{
+				auth:        &sfAuth,
+				sObjectName: "Account",
+			},
+			want:    PicklistValues{},
+			wantErr: true,
+		},
+		{
+			name: "not_found",
+			args: args{
+				auth:         &sfAuth,
+				sObjectName:  "Account",
+				recordTypeId: "0125e000000AAAAAAA",
+				fieldName:    "Rating",
+			},
+			want:    PicklistValues{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doGetPicklistValues(tt.args.auth, tt.args.sObjectName, tt.args.recordTypeId, tt.args.fieldName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doGetPicklistValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetPicklistValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
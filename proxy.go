@@ -0,0 +1,75 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// sends every request made by this client, including authentication, through the proxy at proxyURL,
+// instead of the process-wide proxy from the environment; credentials in the url are sent to the proxy
+// as basic auth
+func WithProxy(proxyURL string) Option {
+	return func(config *configuration) error {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %w", err)
+		}
+		switch parsed.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return errors.New("proxy url must use http, https, or socks5: " + proxyURL)
+		}
+		if parsed.Host == "" {
+			return errors.New("proxy url is missing a host: " + proxyURL)
+		}
+		config.proxy = http.ProxyURL(parsed)
+		return nil
+	}
+}
+
+// uses the proxy from the HTTPS_PROXY, HTTP_PROXY, and NO_PROXY environment variables, even when the
+// transport of the client passed to WithHTTPClient doesn't
+func WithProxyFromEnvironment() Option {
+	return func(config *configuration) error {
+		config.proxy = http.ProxyFromEnvironment
+		return nil
+	}
+}
+
+// headers sent to the proxy when opening a tunnel to salesforce, such as a Proxy-Authorization token
+func WithProxyHeaders(headers map[string]string) Option {
+	return func(config *configuration) error {
+		if config.proxyHeaders == nil {
+			config.proxyHeaders = http.Header{}
+		}
+		for key, value := range headers {
+			config.proxyHeaders.Set(key, value)
+		}
+		return nil
+	}
+}
+
+func (config *configuration) hasProxy() bool {
+	return config.proxy != nil || len(config.proxyHeaders) > 0
+}
+
+// the given transport is cloned, so it isn't modified
+func withProxy(base *http.Transport, proxy func(*http.Request) (*url.URL, error), headers http.Header) *http.Transport {
+	transport := base.Clone()
+	if proxy != nil {
+		transport.Proxy = proxy
+	}
+	if len(headers) > 0 {
+		connectHeaders := transport.ProxyConnectHeader.Clone()
+		if connectHeaders == nil {
+			connectHeaders = http.Header{}
+		}
+		for key, values := range headers {
+			connectHeaders[key] = values
+		}
+		transport.ProxyConnectHeader = connectHeaders
+	}
+	return transport
+}
@@ -0,0 +1,154 @@
+package salesforce
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// tunnels CONNECT requests to their target, recording the headers sent to the proxy
+func setupProxyServer(connects *[]http.Header) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		mu.Lock()
+		*connects = append(*connects, r.Header.Clone())
+		mu.Unlock()
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+		go func() {
+			defer target.Close()
+			defer conn.Close()
+			_, _ = io.Copy(target, conn)
+		}()
+		go func() {
+			_, _ = io.Copy(conn, target)
+		}()
+	}))
+}
+
+func TestWithProxy(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	connects := []http.Header{}
+	proxy := setupProxyServer(&connects)
+	defer proxy.Close()
+
+	config, err := newConfiguration(
+		WithHTTPClient(target.Client()),
+		WithProxy("http://user:pass@"+proxy.Listener.Addr().String()),
+		WithProxyHeaders(map[string]string{"X-Proxy-Token": "tokenvalue"}),
+	)
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	sfAuth := authentication{InstanceUrl: target.URL, AccessToken: "accesstokenvalue", config: config}
+	resp, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(connects) != 1 {
+		t.Fatalf("proxy received %d tunnels, want 1", len(connects))
+	}
+	if got := connects[0].Get("Proxy-Authorization"); got != "Basic dXNlcjpwYXNz" {
+		t.Errorf("Proxy-Authorization = %s, want basic auth from the proxy url", got)
+	}
+	if got := connects[0].Get("X-Proxy-Token"); got != "tokenvalue" {
+		t.Errorf("X-Proxy-Token = %s, want tokenvalue", got)
+	}
+	if target.Client().Transport.(*http.Transport).Proxy != nil {
+		t.Errorf("WithProxy() modified the http client passed to WithHTTPClient")
+	}
+}
+
+func TestWithProxy_afterRecorder(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	connects := []http.Header{}
+	proxy := setupProxyServer(&connects)
+	defer proxy.Close()
+	recorder, err := NewRecorder(filepath.Join(t.TempDir(), "fixture.json"), RecordMode)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	config, err := newConfiguration(
+		WithRecorder(recorder),
+		WithHTTPClient(target.Client()),
+		WithProxy("http://"+proxy.Listener.Addr().String()),
+		WithHighThroughputTransport(),
+	)
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	sfAuth := authentication{InstanceUrl: target.URL, AccessToken: "accesstokenvalue", config: config}
+	resp, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(connects) != 1 {
+		t.Errorf("proxy received %d tunnels, want 1", len(connects))
+	}
+	if len(recorder.interactions) != 1 {
+		t.Errorf("recorder has %d interactions, want 1", len(recorder.interactions))
+	}
+}
+
+func TestWithProxy_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "unparseable_url", opts: []Option{WithProxy("http://proxy:port")}},
+		{name: "unsupported_scheme", opts: []Option{WithProxy("ftp://proxy:8080")}},
+		{name: "missing_host", opts: []Option{WithProxy("http:///")}},
+		{
+			name: "custom_transport",
+			opts: []Option{
+				WithHTTPClient(&http.Client{Transport: RoundTripperFunc(http.DefaultTransport.RoundTrip)}),
+				WithProxy("http://proxy:8080"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newConfiguration(tt.opts...); err == nil {
+				t.Errorf("newConfiguration() expected error")
+			}
+		})
+	}
+}
+
+func TestWithProxyFromEnvironment(t *testing.T) {
+	config, err := newConfiguration(WithHTTPClient(&http.Client{Transport: &http.Transport{}}), WithProxyFromEnvironment())
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	if transport, ok := config.client().Transport.(*http.Transport); !ok || transport.Proxy == nil {
+		t.Errorf("WithProxyFromEnvironment() did not set the transport's proxy")
+	}
+}
@@ -1,10 +1,9 @@
 package salesforce
 
 import (
-	"encoding/json"
-	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/go-viper/mapstructure/v2"
@@ -17,11 +16,87 @@ type queryResponse struct {
 	Records        []map[string]any `json:"records"`
 }
 
-func performQuery(auth *authentication, query string, sObject any) error {
-	query = url.QueryEscape(query)
+// each page is decoded into the destination slice as it arrives, so only one page of maps is held at a time
+func performQuery(auth *authentication, query string, sObject any, options dmlOptions) error {
+	if auth.config.hasQueryCache() {
+		records, err := fetchCachedQueryRecords(auth, query, options)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		return decodeQueryRecords(records, sObject)
+	}
+
+	t := reflect.TypeOf(sObject)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Slice {
+		records, err := fetchQueryRecords(auth, query, options)
+		if err != nil {
+			return err
+		}
+		return decodeQueryRecords(records, sObject)
+	}
+
+	sliceType := t.Elem()
+	decoded := reflect.Zero(sliceType)
+	err := forEachQueryPage(auth, "/query/?q="+url.QueryEscape(query), options, func(records []map[string]any) error {
+		if err := fetchChildRecords(auth, records, options); err != nil {
+			return err
+		}
+		page := reflect.New(sliceType)
+		if err := decodeQueryRecords(records, page.Interface()); err != nil {
+			return err
+		}
+		decoded = reflect.AppendSlice(decoded, page.Elem())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// a query without records leaves the destination as it was
+	if decoded.Len() > 0 {
+		reflect.ValueOf(sObject).Elem().Set(decoded)
+	}
+	return nil
+}
+
+func decodeQueryRecords(records []map[string]any, sObject any) error {
+	stripRelationshipAttributes(records)
+	if t := reflect.TypeOf(sObject); t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice {
+		resolveFieldTags(t.Elem().Elem(), records)
+	}
+	return mapstructure.Decode(records, sObject)
+}
+
+func fetchQueryRecords(auth *authentication, query string, options dmlOptions) ([]map[string]any, error) {
+	records, err := fetchQueryPages(auth, "/query/?q="+url.QueryEscape(query), options)
+	if err != nil {
+		return nil, err
+	}
+	if err := fetchChildRecords(auth, records, options); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// follows nextRecordsUrl until every record of the query has been retrieved
+func fetchQueryPages(auth *authentication, uri string, options dmlOptions) ([]map[string]any, error) {
+	var records []map[string]any
+	err := forEachQueryPage(auth, uri, options, func(page []map[string]any) error {
+		records = append(records, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func forEachQueryPage(auth *authentication, uri string, options dmlOptions, handlePage func(records []map[string]any) error) error {
 	queryResp := &queryResponse{
 		Done:           false,
-		NextRecordsUrl: "/query/?q=" + query,
+		NextRecordsUrl: uri,
 	}
 
 	for !queryResp.Done {
@@ -29,34 +104,73 @@ func performQuery(auth *authentication, query string, sObject any) error {
 			method:  http.MethodGet,
 			uri:     queryResp.NextRecordsUrl,
 			content: jsonType,
+			headers: options.headers,
+			timeout: options.timeout,
+			ctx:     options.ctx,
 		})
 		if err != nil {
 			return err
 		}
 
-		respBody, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return readErr
-		}
-
 		tempQueryResp := &queryResponse{}
-		queryResponseError := json.Unmarshal(respBody, &tempQueryResp)
+		queryResponseError := decodeBody(auth.config.codec(), resp.Body, &tempQueryResp)
+		resp.Body.Close()
 		if queryResponseError != nil {
 			return queryResponseError
 		}
+		if len(tempQueryResp.Records) > 0 {
+			if err := handlePage(tempQueryResp.Records); err != nil {
+				return err
+			}
+		}
 
-		queryResp.TotalSize = queryResp.TotalSize + tempQueryResp.TotalSize
-		queryResp.Records = append(queryResp.Records, tempQueryResp.Records...)
 		queryResp.Done = tempQueryResp.Done
 		if !tempQueryResp.Done && tempQueryResp.NextRecordsUrl != "" {
 			queryResp.NextRecordsUrl = strings.TrimPrefix(tempQueryResp.NextRecordsUrl, "/services/data/"+apiVersion)
 		}
 	}
 
-	sObjectError := mapstructure.Decode(queryResp.Records, sObject)
-	if sObjectError != nil {
-		return sObjectError
-	}
+	return nil
+}
+
+// child relationship subqueries are returned as nested query results, which are paged separately from their parent
+func fetchChildRecords(auth *authentication, records []map[string]any, options dmlOptions) error {
+	for _, record := range records {
+		for _, value := range record {
+			child, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			childRecords, ok := child["records"].([]any)
+			if !ok {
+				continue
+			}
+			if done, _ := child["done"].(bool); !done {
+				nextRecordsUrl, _ := child["nextRecordsUrl"].(string)
+				if nextRecordsUrl != "" {
+					more, err := fetchQueryPages(auth, strings.TrimPrefix(nextRecordsUrl, "/services/data/"+apiVersion), options)
+					if err != nil {
+						return err
+					}
+					for _, childRecord := range more {
+						childRecords = append(childRecords, childRecord)
+					}
+					child["records"] = childRecords
+					child["done"] = true
+					delete(child, "nextRecordsUrl")
+				}
+			}
 
+			var nested []map[string]any
+			for _, childRecord := range childRecords {
+				if childMap, ok := childRecord.(map[string]any); ok {
+					nested = append(nested, childMap)
+				}
+			}
+			if err := fetchChildRecords(auth, nested, options); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
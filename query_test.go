@@ -113,7 +113,7 @@ func Test_performQuery(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := performQuery(tt.args.auth, tt.args.query, &tt.args.sObject); (err != nil) != tt.wantErr {
+			if err := performQuery(tt.args.auth, tt.args.query, &tt.args.sObject, dmlOptions{}); (err != nil) != tt.wantErr {
 				t.Errorf("performQuery() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !reflect.DeepEqual(tt.args.sObject, tt.want) {
@@ -122,3 +122,243 @@ func Test_performQuery(t *testing.T) {
 		})
 	}
 }
+
+func Test_performQuery_pages(t *testing.T) {
+	type account struct {
+		Id        string
+		Employees int
+	}
+	pages := map[string]queryResponse{
+		"/query/": {Done: false, NextRecordsUrl: "/services/data/" + apiVersion + "/query/01g-2000", Records: []map[string]any{
+			{"Id": "001", "Employees": 1},
+			{"Id": "002", "Employees": 2},
+		}},
+		"/query/01g-2000": {Done: false, NextRecordsUrl: "/services/data/" + apiVersion + "/query/01g-4000", Records: []map[string]any{
+			{"Id": "003", "Employees": 3},
+		}},
+		"/query/01g-4000": {Done: true, Records: []map[string]any{
+			{"Id": "004", "Employees": "many"},
+		}},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := pages[strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion)]
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstoken"}
+
+	// the last page can't be decoded, which leaves the destination as it was
+	accounts := []account{}
+	if err := performQuery(&sfAuth, "SELECT Id, Employees FROM Account", &accounts, dmlOptions{}); err == nil {
+		t.Errorf("performQuery() expected error decoding the last page")
+	}
+	if len(accounts) != 0 || requests != 3 {
+		t.Errorf("performQuery() = %v after %d requests, want no records after 3 requests", accounts, requests)
+	}
+
+	pages["/query/01g-4000"].Records[0]["Employees"] = 4
+	if err := performQuery(&sfAuth, "SELECT Id, Employees FROM Account", &accounts, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	want := []account{{"001", 1}, {"002", 2}, {"003", 3}, {"004", 4}}
+	if !reflect.DeepEqual(accounts, want) {
+		t.Errorf("performQuery() = %v, want %v", accounts, want)
+	}
+}
+
+func Test_performQuery_multiSelect(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"Id":           "001",
+			"Interests__c": "Hiking;Chess",
+		}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	records := []multiSelectRecord{}
+	if err := performQuery(&sfAuth, "SELECT Id, Interests__c FROM Contact", &records, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	want := []multiSelectRecord{{Id: "001", Interests__c: []string{"Hiking", "Chess"}}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("performQuery() = %v, want %v", records, want)
+	}
+}
+
+func Test_performQuery_relationships(t *testing.T) {
+	type user struct {
+		Name string
+	}
+	type account struct {
+		Name  string
+		Owner *user
+	}
+	type contact struct {
+		Id        string
+		Account   account
+		OwnerName string `sf:"Account.Owner.Name"`
+	}
+	type accountWithContacts struct {
+		Id       string
+		Contacts []contact
+	}
+	attributes := map[string]any{"type": "Account", "url": "/services/data/v62.0/sobjects/Account/001"}
+
+	contactResp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"attributes": attributes,
+			"Id":         "003",
+			"Account": map[string]any{
+				"attributes": attributes,
+				"Name":       "Stark Industries",
+				"Owner": map[string]any{
+					"attributes": attributes,
+					"Name":       "Tony",
+				},
+			},
+		}},
+	}
+	server, sfAuth := setupTestServer(contactResp, http.StatusOK)
+	defer server.Close()
+
+	contacts := []contact{}
+	if err := performQuery(&sfAuth, "SELECT Id, Account.Name, Account.Owner.Name FROM Contact", &contacts, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	wantContacts := []contact{{
+		Id:        "003",
+		Account:   account{Name: "Stark Industries", Owner: &user{Name: "Tony"}},
+		OwnerName: "Tony",
+	}}
+	if !reflect.DeepEqual(contacts, wantContacts) {
+		t.Errorf("performQuery() = %v, want %v", contacts, wantContacts)
+	}
+
+	accountResp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"attributes": attributes,
+			"Id":         "001",
+			"Contacts": map[string]any{
+				"totalSize": 1,
+				"done":      true,
+				"records":   []map[string]any{{"attributes": attributes, "Id": "003"}},
+			},
+		}},
+	}
+	subqueryServer, subquerySfAuth := setupTestServer(accountResp, http.StatusOK)
+	defer subqueryServer.Close()
+
+	accounts := []accountWithContacts{}
+	if err := performQuery(&subquerySfAuth, "SELECT Id, (SELECT Id FROM Contacts) FROM Account", &accounts, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	wantAccounts := []accountWithContacts{{Id: "001", Contacts: []contact{{Id: "003"}}}}
+	if !reflect.DeepEqual(accounts, wantAccounts) {
+		t.Errorf("performQuery() = %v, want %v", accounts, wantAccounts)
+	}
+}
+
+func Test_performQuery_childPages(t *testing.T) {
+	type contact struct {
+		Id string
+	}
+	type accountWithContacts struct {
+		Id       string
+		Contacts []contact
+	}
+
+	var requestUris []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestUris = append(requestUris, r.URL.Path)
+		var resp queryResponse
+		if strings.HasSuffix(r.URL.Path, "/query/01g-2000") {
+			resp = queryResponse{
+				TotalSize: 3,
+				Done:      true,
+				Records:   []map[string]any{{"Id": "003C"}},
+			}
+		} else {
+			resp = queryResponse{
+				TotalSize: 1,
+				Done:      true,
+				Records: []map[string]any{{
+					"Id": "001",
+					"Contacts": map[string]any{
+						"totalSize":      3,
+						"done":           false,
+						"nextRecordsUrl": "/services/data/" + apiVersion + "/query/01g-2000",
+						"records":        []map[string]any{{"Id": "003A"}, {"Id": "003B"}},
+					},
+				}},
+			}
+		}
+		body, _ := json.Marshal(resp)
+		if _, err := w.Write(body); err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	accounts := []accountWithContacts{}
+	if err := performQuery(&sfAuth, "SELECT Id, (SELECT Id FROM Contacts) FROM Account", &accounts, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	want := []accountWithContacts{{Id: "001", Contacts: []contact{{Id: "003A"}, {Id: "003B"}, {Id: "003C"}}}}
+	if !reflect.DeepEqual(accounts, want) {
+		t.Errorf("performQuery() = %v, want %v", accounts, want)
+	}
+	if len(requestUris) != 2 || requestUris[1] != "/services/data/"+apiVersion+"/query/01g-2000" {
+		t.Errorf("performQuery() requested %v", requestUris)
+	}
+
+	records := []map[string]any{}
+	if err := performQuery(&sfAuth, "SELECT Id, (SELECT Id FROM Contacts) FROM Account", &records, dmlOptions{}); err != nil {
+		t.Fatalf("performQuery() error = %v", err)
+	}
+	contacts, _ := records[0]["Contacts"].(map[string]any)
+	if childRecords, _ := contacts["records"].([]any); len(childRecords) != 3 || contacts["done"] != true {
+		t.Errorf("performQuery() = %v, want every child record in a map result", records)
+	}
+}
+
+func Test_performQuery_headers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Sforce-Call-Options") != "client=sync" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := json.Marshal(queryResponse{TotalSize: 0, Done: true, Records: []map[string]any{}})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	records := []map[string]any{}
+	options := newDMLOptions(WithHeader("Sforce-Call-Options", "client=sync"))
+	if err := performQuery(&sfAuth, "SELECT Id FROM Contact", &records, options); err != nil {
+		t.Errorf("performQuery() error = %v", err)
+	}
+	if err := performQuery(&sfAuth, "SELECT Id FROM Contact", &records, dmlOptions{}); err == nil {
+		t.Errorf("performQuery() expected error without header")
+	}
+}
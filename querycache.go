@@ -0,0 +1,179 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stores the records of queries made with Query and QueryStruct, keyed by the instance url, the user, and the
+// soql text; implementations must be safe for concurrent use
+type QueryCache interface {
+	// expired entries are returned too, so ones with an etag can be revalidated
+	Get(query string) (QueryCacheEntry, bool)
+	Set(query string, entry QueryCacheEntry)
+	Delete(query string)
+	Clear()
+}
+
+type QueryCacheEntry struct {
+	// every record of the query, json encoded with the client's codec
+	Records []byte
+	// the etag of the query's first page, if salesforce sent one
+	ETag string
+	// zero never expires
+	ExpiresAt time.Time
+}
+
+type memoryQueryCache struct {
+	mu      sync.RWMutex
+	entries map[string]QueryCacheEntry
+}
+
+// the default QueryCache, entries are kept in memory for the life of the client
+func NewMemoryQueryCache() QueryCache {
+	return &memoryQueryCache{entries: map[string]QueryCacheEntry{}}
+}
+
+func (cache *memoryQueryCache) Get(query string) (QueryCacheEntry, bool) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	entry, ok := cache.entries[query]
+	return entry, ok
+}
+
+func (cache *memoryQueryCache) Set(query string, entry QueryCacheEntry) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[query] = entry
+}
+
+func (cache *memoryQueryCache) Delete(query string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	delete(cache.entries, query)
+}
+
+func (cache *memoryQueryCache) Clear() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries = map[string]QueryCacheEntry{}
+}
+
+// caches query results for ttl (0 never expires); once expired, results with an etag are revalidated with
+// If-None-Match and reused when salesforce responds 304 Not Modified
+func WithQueryCache(cache QueryCache, ttl time.Duration) Option {
+	return func(config *configuration) error {
+		if cache == nil {
+			return errors.New("query cache is nil")
+		}
+		if ttl < 0 {
+			return errors.New("query cache ttl must not be negative")
+		}
+		config.queryCache = cache
+		config.queryCacheTTL = ttl
+		return nil
+	}
+}
+
+func (config *configuration) hasQueryCache() bool {
+	return config != nil && config.queryCache != nil
+}
+
+func (config *configuration) queryCacheExpiry() time.Time {
+	if config.queryCacheTTL <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(config.queryCacheTTL)
+}
+
+// the same query returns different records for different users and orgs
+func queryCacheKey(auth *authentication, query string) string {
+	return auth.cacheScope() + "\n" + strings.TrimSpace(query)
+}
+
+func fetchCachedQueryRecords(auth *authentication, query string, options dmlOptions) ([]map[string]any, error) {
+	cache := auth.config.queryCache
+	codec := auth.config.codec()
+	key := queryCacheKey(auth, query)
+	entry, cached := cache.Get(key)
+	if cached && (entry.ExpiresAt.IsZero() || time.Now().Before(entry.ExpiresAt)) {
+		return decodeCachedRecords(codec, entry)
+	}
+
+	headers := map[string]string{}
+	for k, v := range options.headers {
+		headers[k] = v
+	}
+	if cached && entry.ETag != "" {
+		headers["If-None-Match"] = entry.ETag
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     "/query/?q=" + url.QueryEscape(query),
+		content: jsonType,
+		headers: headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if cached && resp.StatusCode == http.StatusNotModified {
+		entry.ExpiresAt = auth.config.queryCacheExpiry()
+		cache.Set(key, entry)
+		return decodeCachedRecords(codec, entry)
+	}
+
+	firstPage := queryResponse{}
+	if err := decodeBody(codec, resp.Body, &firstPage); err != nil {
+		return nil, err
+	}
+	records := firstPage.Records
+	if !firstPage.Done && firstPage.NextRecordsUrl != "" {
+		more, err := fetchQueryPages(auth, strings.TrimPrefix(firstPage.NextRecordsUrl, "/services/data/"+apiVersion), options)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, more...)
+	}
+	if err := fetchChildRecords(auth, records, options); err != nil {
+		return nil, err
+	}
+
+	encoded, err := codec.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	cache.Set(key, QueryCacheEntry{
+		Records:   encoded,
+		ETag:      resp.Header.Get("ETag"),
+		ExpiresAt: auth.config.queryCacheExpiry(),
+	})
+	return records, nil
+}
+
+func decodeCachedRecords(codec Codec, entry QueryCacheEntry) ([]map[string]any, error) {
+	var records []map[string]any
+	if err := codec.Unmarshal(entry.Records, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func invalidateQueryCache(auth *authentication, queries []string) {
+	if auth == nil || !auth.config.hasQueryCache() {
+		return
+	}
+	if len(queries) == 0 {
+		auth.config.queryCache.Clear()
+		return
+	}
+	for _, query := range queries {
+		auth.config.queryCache.Delete(queryCacheKey(auth, query))
+	}
+}
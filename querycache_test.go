@@ -0,0 +1,190 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_memoryQueryCache(t *testing.T) {
+	cache := NewMemoryQueryCache()
+	accounts := QueryCacheEntry{Records: []byte(`[{"Name":"test"}]`), ETag: `"1"`}
+	contacts := QueryCacheEntry{Records: []byte(`[]`)}
+	cache.Set("SELECT Name FROM Account", accounts)
+	cache.Set("SELECT Name FROM Contact", contacts)
+
+	if got, ok := cache.Get("SELECT Name FROM Account"); !ok || !reflect.DeepEqual(got, accounts) {
+		t.Errorf("Get() = %v, %v, want %v", got, ok, accounts)
+	}
+	cache.Delete("SELECT Name FROM Account")
+	if _, ok := cache.Get("SELECT Name FROM Account"); ok {
+		t.Errorf("Delete() kept the entry")
+	}
+	if _, ok := cache.Get("SELECT Name FROM Contact"); !ok {
+		t.Errorf("Delete() removed another query's entry")
+	}
+	cache.Clear()
+	if _, ok := cache.Get("SELECT Name FROM Contact"); ok {
+		t.Errorf("Clear() kept an entry")
+	}
+}
+
+func TestWithQueryCache(t *testing.T) {
+	cache := NewMemoryQueryCache()
+	config, err := newConfiguration(WithQueryCache(cache, time.Minute))
+	if err != nil {
+		t.Fatalf("WithQueryCache() error = %v", err)
+	}
+	if config.queryCache != cache || config.queryCacheTTL != time.Minute {
+		t.Errorf("WithQueryCache() = %v, %v", config.queryCache, config.queryCacheTTL)
+	}
+	if _, err := newConfiguration(WithQueryCache(nil, time.Minute)); err == nil {
+		t.Errorf("WithQueryCache() expected error for nil cache")
+	}
+	if _, err := newConfiguration(WithQueryCache(cache, -time.Minute)); err == nil {
+		t.Errorf("WithQueryCache() expected error for negative ttl")
+	}
+}
+
+func Test_performQuery_cached(t *testing.T) {
+	type account struct {
+		Name string
+	}
+	requests := []string{}
+	name := "first"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"`+name+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"`+name+`"`)
+		resp := queryResponse{Done: true, Records: []map[string]any{{"attributes": map[string]any{"type": "Account"}, "Name": name}}}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	cache := NewMemoryQueryCache()
+	config, err := newConfiguration(WithQueryCache(cache, time.Hour))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", config: config}
+	query := "SELECT Name FROM Account"
+	assertQuery := func(wantName string, wantRequests []string) {
+		t.Helper()
+		accounts := []account{}
+		if err := performQuery(&sfAuth, query, &accounts, dmlOptions{}); err != nil {
+			t.Fatalf("performQuery() error = %v", err)
+		}
+		if want := []account{{Name: wantName}}; !reflect.DeepEqual(accounts, want) {
+			t.Errorf("performQuery() = %v, want %v", accounts, want)
+		}
+		if !reflect.DeepEqual(requests, wantRequests) {
+			t.Errorf("requests = %q, want %q", requests, wantRequests)
+		}
+	}
+
+	assertQuery("first", []string{""})
+	assertQuery("first", []string{""})
+
+	// an expired entry is revalidated with its etag
+	expire := func() {
+		key := queryCacheKey(&sfAuth, query)
+		entry, _ := cache.Get(key)
+		entry.ExpiresAt = time.Now().Add(-time.Second)
+		cache.Set(key, entry)
+	}
+	expire()
+	assertQuery("first", []string{"", `"first"`})
+	assertQuery("first", []string{"", `"first"`})
+
+	name = "second"
+	expire()
+	assertQuery("second", []string{"", `"first"`, `"first"`})
+
+	invalidateQueryCache(&sfAuth, []string{query})
+	assertQuery("second", []string{"", `"first"`, `"first"`, ""})
+	invalidateQueryCache(&sfAuth, nil)
+	assertQuery("second", []string{"", `"first"`, `"first"`, "", ""})
+
+	// no cache configured
+	invalidateQueryCache(&authentication{}, nil)
+	invalidateQueryCache(nil, []string{query})
+}
+
+func Test_queryCacheKey(t *testing.T) {
+	query := "SELECT Name FROM Account"
+	auth := &authentication{InstanceUrl: "https://example.my.salesforce.com", AccessToken: "1234"}
+	tests := []struct {
+		name string
+		auth *authentication
+		same bool
+	}{
+		{name: "same_session", auth: &authentication{InstanceUrl: auth.InstanceUrl, AccessToken: "1234"}, same: true},
+		{name: "other_token", auth: &authentication{InstanceUrl: auth.InstanceUrl, AccessToken: "5678"}},
+		{name: "other_instance", auth: &authentication{InstanceUrl: "https://other.my.salesforce.com", AccessToken: "1234"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryCacheKey(tt.auth, " "+query+" ") == queryCacheKey(auth, query); got != tt.same {
+				t.Errorf("queryCacheKey() same = %v, want %v", got, tt.same)
+			}
+		})
+	}
+
+	user := &authentication{InstanceUrl: auth.InstanceUrl, AccessToken: "1234", Id: "https://login.salesforce.com/id/00D/005A"}
+	refreshed := &authentication{InstanceUrl: auth.InstanceUrl, AccessToken: "5678", Id: user.Id}
+	if queryCacheKey(user, query) != queryCacheKey(refreshed, query) {
+		t.Errorf("queryCacheKey() changed when the same user's token was refreshed")
+	}
+	if strings.Contains(queryCacheKey(auth, query), "1234") {
+		t.Errorf("queryCacheKey() includes the access token")
+	}
+}
+
+func TestSalesforce_WithToken_queryCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		resp := queryResponse{Done: true, Records: []map[string]any{{"Name": name}}}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	config, err := newConfiguration(WithQueryCache(NewMemoryQueryCache(), time.Hour))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "admin", config: config}}
+	userSf := sf.WithToken("user")
+	if userSf.auth.config.queryCache == config.queryCache {
+		t.Errorf("WithToken() shared the built in query cache")
+	}
+	for _, client := range []*Salesforce{sf, userSf, sf} {
+		records := []map[string]any{}
+		if err := performQuery(client.auth, "SELECT Name FROM Account", &records, dmlOptions{}); err != nil {
+			t.Fatalf("performQuery() error = %v", err)
+		}
+		if want := client.GetAccessToken(); len(records) != 1 || records[0]["Name"] != want {
+			t.Errorf("performQuery() = %v, want the records of %v", records, want)
+		}
+	}
+
+	shared := NewMemoryQueryCache()
+	custom := &configuration{queryCache: wrappedQueryCache{shared}}
+	if got := custom.forToken().queryCache; got != custom.queryCache {
+		t.Errorf("forToken() replaced a custom query cache")
+	}
+}
+
+type wrappedQueryCache struct {
+	QueryCache
+}
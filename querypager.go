@@ -0,0 +1,163 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// salesforce rejects OFFSET values over 2000
+	maxQueryOffset     = 2000
+	minQueryBatchSize  = 200
+	maxQueryPageSize   = 2000
+	queryOptionsHeader = "Sforce-Query-Options"
+)
+
+var limitOffsetPattern = regexp.MustCompile(`(?i)\b(LIMIT|OFFSET)\b`)
+
+// fetches one page of a query at a time with LIMIT and OFFSET, switching to the query locator once
+// the offset is past what salesforce allows
+type QueryPager struct {
+	auth     *authentication
+	query    string
+	pageSize int
+	options  dmlOptions
+	offset   int
+	page     int
+	locator  string
+	records  []map[string]any
+	done     bool
+	err      error
+}
+
+func newQueryPager(auth *authentication, query string, pageSize int, options dmlOptions) (*QueryPager, error) {
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+	if pageSize < 1 || pageSize > maxQueryPageSize {
+		return nil, errors.New("page size must be between 1 and " + strconv.Itoa(maxQueryPageSize))
+	}
+	if limitOffsetPattern.MatchString(query) {
+		return nil, errors.New("paged queries can't include LIMIT or OFFSET")
+	}
+	return &QueryPager{auth: auth, query: query, pageSize: pageSize, options: options}, nil
+}
+
+// fetches the next page, returning false once every record has been fetched or a request fails
+func (pager *QueryPager) Next() bool {
+	if pager.err != nil || pager.done {
+		return false
+	}
+	var records []map[string]any
+	var err error
+	if pager.offset <= maxQueryOffset {
+		records, err = pager.offsetPage()
+	} else {
+		records, err = pager.locatorPage()
+	}
+	if err == nil {
+		err = fetchChildRecords(pager.auth, records, pager.options)
+	}
+	if err != nil {
+		pager.err = err
+		pager.records = nil
+		return false
+	}
+	if len(records) < pager.pageSize {
+		pager.done = true
+	}
+	if len(records) == 0 {
+		pager.records = nil
+		return false
+	}
+	pager.records = records
+	pager.offset += len(records)
+	pager.page++
+	return true
+}
+
+func (pager *QueryPager) offsetPage() ([]map[string]any, error) {
+	query := pager.query + " LIMIT " + strconv.Itoa(pager.pageSize) + " OFFSET " + strconv.Itoa(pager.offset)
+	queryResp, err := pager.get("/query/?q="+url.QueryEscape(query), pager.options.headers)
+	if err != nil {
+		return nil, err
+	}
+	return queryResp.Records, nil
+}
+
+// the locator's next records url ends with the position of its first record, which can be set to any offset
+func (pager *QueryPager) locatorPage() ([]map[string]any, error) {
+	headers := map[string]string{}
+	for key, value := range pager.options.headers {
+		headers[key] = value
+	}
+	headers[queryOptionsHeader] = "batchSize=" + strconv.Itoa(max(pager.pageSize, minQueryBatchSize))
+
+	uri := "/query/?q=" + url.QueryEscape(pager.query)
+	if pager.locator != "" {
+		uri = "/query/" + pager.locator + "-" + strconv.Itoa(pager.offset)
+	}
+	queryResp, err := pager.get(uri, headers)
+	if err != nil {
+		return nil, err
+	}
+	records := queryResp.Records
+	if pager.locator == "" {
+		if !queryResp.Done && queryResp.NextRecordsUrl != "" {
+			next := path.Base(queryResp.NextRecordsUrl)
+			i := strings.LastIndex(next, "-")
+			if i < 1 {
+				return nil, errors.New("unexpected next records url: " + queryResp.NextRecordsUrl)
+			}
+			pager.locator = next[:i]
+			if pager.offset >= len(records) {
+				return pager.locatorPage()
+			}
+		}
+		// the first batch starts with the first record of the query
+		records = records[min(pager.offset, len(records)):]
+	}
+	return records[:min(pager.pageSize, len(records))], nil
+}
+
+func (pager *QueryPager) get(uri string, headers map[string]string) (queryResponse, error) {
+	resp, err := doRequest(pager.auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     uri,
+		content: jsonType,
+		headers: headers,
+		timeout: pager.options.timeout,
+		ctx:     pager.options.ctx,
+	})
+	if err != nil {
+		return queryResponse{}, err
+	}
+	defer resp.Body.Close()
+	queryResp := queryResponse{}
+	if err := decodeBody(pager.auth.config.codec(), resp.Body, &queryResp); err != nil {
+		return queryResponse{}, err
+	}
+	return queryResp, nil
+}
+
+// decodes the current page's records, like Query
+func (pager *QueryPager) Decode(sObject any) error {
+	if pager.records == nil {
+		return errors.New("no page has been fetched")
+	}
+	return decodeQueryRecords(pager.records, sObject)
+}
+
+// the number of the current page, starting at 1
+func (pager *QueryPager) Page() int {
+	return pager.page
+}
+
+func (pager *QueryPager) Error() error {
+	return pager.err
+}
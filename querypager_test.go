@@ -0,0 +1,212 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func setupQueryPagerServer(total int, requests *[]string) *httptest.Server {
+	limitOffset := regexp.MustCompile(`LIMIT (\d+) OFFSET (\d+)$`)
+	records := make([]map[string]any, total)
+	for i := range records {
+		records[i] = map[string]any{"attributes": map[string]any{"type": "Account"}, "Name": "Account " + strconv.Itoa(i)}
+	}
+	locatorPage := func(w http.ResponseWriter, start int, batchSize int) {
+		end := min(start+batchSize, total)
+		resp := queryResponse{Done: end == total, Records: records[min(start, total):end]}
+		if !resp.Done {
+			resp.NextRecordsUrl = "/services/data/" + apiVersion + "/query/01gLOCATOR-" + strconv.Itoa(end)
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			panic(err.Error())
+		}
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batchSize, _ := strconv.Atoi(strings.TrimPrefix(r.Header.Get(queryOptionsHeader), "batchSize="))
+		if locator, ok := strings.CutPrefix(r.URL.Path, "/services/data/"+apiVersion+"/query/01gLOCATOR-"); ok {
+			*requests = append(*requests, "locator "+locator)
+			start, _ := strconv.Atoi(locator)
+			locatorPage(w, start, batchSize)
+			return
+		}
+		query := r.URL.Query().Get("q")
+		match := limitOffset.FindStringSubmatch(query)
+		if match == nil {
+			*requests = append(*requests, "query")
+			locatorPage(w, 0, batchSize)
+			return
+		}
+		*requests = append(*requests, "offset "+match[2])
+		limit, _ := strconv.Atoi(match[1])
+		offset, _ := strconv.Atoi(match[2])
+		resp := queryResponse{Done: true, Records: records[min(offset, total):min(offset+limit, total)]}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			panic(err.Error())
+		}
+	}))
+}
+
+func TestQueryPager(t *testing.T) {
+	type account struct {
+		Name string
+	}
+	tests := []struct {
+		name         string
+		total        int
+		pageSize     int
+		wantPages    []int
+		wantRequests []string
+	}{
+		{
+			name:         "offset_and_locator",
+			total:        3500,
+			pageSize:     900,
+			wantPages:    []int{900, 900, 900, 800},
+			wantRequests: []string{"offset 0", "offset 900", "offset 1800", "query", "locator 2700"},
+		},
+		{
+			name:         "small_pages_after_offset_limit",
+			total:        2200,
+			pageSize:     100,
+			wantPages:    append(repeatInt(100, 21), 100),
+			wantRequests: append(offsetRequests(0, 2000, 100), "query", "locator 2100", "locator 2200"),
+		},
+		{
+			name:         "exact_page",
+			total:        50,
+			pageSize:     50,
+			wantPages:    []int{50},
+			wantRequests: []string{"offset 0", "offset 50"},
+		},
+		{
+			name:         "no_records",
+			pageSize:     50,
+			wantRequests: []string{"offset 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := []string{}
+			server := setupQueryPagerServer(tt.total, &requests)
+			defer server.Close()
+			sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+			pager, err := newQueryPager(&sfAuth, "SELECT Name FROM Account ORDER BY Name", tt.pageSize, dmlOptions{})
+			if err != nil {
+				t.Fatalf("newQueryPager() error = %v", err)
+			}
+			var pages []int
+			next := 0
+			for pager.Next() {
+				accounts := []account{}
+				if err := pager.Decode(&accounts); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				for _, acc := range accounts {
+					if acc.Name != "Account "+strconv.Itoa(next) {
+						t.Fatalf("page %d has %s, want Account %d", pager.Page(), acc.Name, next)
+					}
+					next++
+				}
+				pages = append(pages, len(accounts))
+				if pager.Page() != len(pages) {
+					t.Errorf("Page() = %d, want %d", pager.Page(), len(pages))
+				}
+			}
+			if err := pager.Error(); err != nil {
+				t.Fatalf("Error() = %v", err)
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("page sizes = %v, want %v", pages, tt.wantPages)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func repeatInt(value int, count int) []int {
+	values := make([]int, count)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
+
+func offsetRequests(start int, end int, step int) []string {
+	requests := []string{}
+	for offset := start; offset <= end; offset += step {
+		requests = append(requests, "offset "+strconv.Itoa(offset))
+	}
+	return requests
+}
+
+func Test_newQueryPager(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		pageSize int
+		wantErr  bool
+	}{
+		{name: "valid", query: "SELECT Id FROM Account", pageSize: 200},
+		{name: "empty_query", pageSize: 200, wantErr: true},
+		{name: "page_size_too_small", query: "SELECT Id FROM Account", wantErr: true},
+		{name: "page_size_too_large", query: "SELECT Id FROM Account", pageSize: 2001, wantErr: true},
+		{name: "limit", query: "SELECT Id FROM Account limit 10", pageSize: 200, wantErr: true},
+		{name: "offset", query: "SELECT Id FROM Account OFFSET 10", pageSize: 200, wantErr: true},
+		{name: "limit_in_field_name", query: "SELECT Credit_Limit__c FROM Account", pageSize: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newQueryPager(&authentication{}, tt.query, tt.pageSize, dmlOptions{}); (err != nil) != tt.wantErr {
+				t.Errorf("newQueryPager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryPager_error(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusBadRequest)
+	defer server.Close()
+
+	pager, err := newQueryPager(&sfAuth, "SELECT Id FROM Account", 200, dmlOptions{})
+	if err != nil {
+		t.Fatalf("newQueryPager() error = %v", err)
+	}
+	if pager.Next() || pager.Error() == nil {
+		t.Errorf("Next() expected to stop with an error")
+	}
+	if err := pager.Decode(&[]map[string]any{}); err == nil {
+		t.Errorf("Decode() expected error without a page")
+	}
+}
+
+func TestSalesforce_QueryPaged(t *testing.T) {
+	requests := []string{}
+	server := setupQueryPagerServer(3, &requests)
+	defer server.Close()
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+
+	pager, err := sf.QueryPaged("SELECT Name FROM Account", 2)
+	if err != nil {
+		t.Fatalf("QueryPaged() error = %v", err)
+	}
+	pages := 0
+	for pager.Next() {
+		pages++
+	}
+	if pages != 2 || pager.Error() != nil {
+		t.Errorf("QueryPaged() fetched %d pages with error %v, want 2 pages", pages, pager.Error())
+	}
+
+	if _, err := (&Salesforce{}).QueryPaged("SELECT Name FROM Account", 2); err == nil {
+		t.Errorf("QueryPaged() expected validation error")
+	}
+}
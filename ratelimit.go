@@ -0,0 +1,92 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// token bucket that queues callers until a request can be sent
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(config *configuration) error {
+		if requestsPerSecond <= 0 {
+			return errors.New("requests per second must be greater than 0")
+		}
+		if burst < 1 {
+			return errors.New("burst must be at least 1")
+		}
+		// bulk job polling gets its own bucket so waiting on jobs doesn't starve other requests
+		config.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+		config.pollRateLimiter = newRateLimiter(requestsPerSecond, burst)
+		return nil
+	}
+}
+
+func (config *configuration) limiter(polling bool) *rateLimiter {
+	if config == nil {
+		return nil
+	}
+	if polling {
+		return config.pollRateLimiter
+	}
+	return config.rateLimiter
+}
+
+// blocks until a token is available or the context is done
+func (limiter *rateLimiter) wait(ctx context.Context) error {
+	if limiter == nil {
+		return nil
+	}
+	delay := limiter.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		limiter.cancel()
+		return ctx.Err()
+	}
+}
+
+// takes a token, returning how long to wait before it can be used
+func (limiter *rateLimiter) reserve() time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	now := time.Now()
+	limiter.tokens = min(limiter.burst, limiter.tokens+now.Sub(limiter.last).Seconds()*limiter.rate)
+	limiter.last = now
+	limiter.tokens--
+	if limiter.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+}
+
+// returns a reserved token that was never used
+func (limiter *rateLimiter) cancel() {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.tokens = min(limiter.burst, limiter.tokens+1)
+}
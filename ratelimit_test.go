@@ -0,0 +1,139 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerSecond float64
+		burst             int
+		wantErr           bool
+	}{
+		{
+			name:              "valid_rate_limit",
+			requestsPerSecond: 10,
+			burst:             5,
+			wantErr:           false,
+		},
+		{
+			name:              "zero_rate",
+			requestsPerSecond: 0,
+			burst:             5,
+			wantErr:           true,
+		},
+		{
+			name:              "zero_burst",
+			requestsPerSecond: 10,
+			burst:             0,
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newConfiguration(WithRateLimit(tt.requestsPerSecond, tt.burst))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithRateLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if config.limiter(false) == nil || config.limiter(true) == nil || config.limiter(false) == config.limiter(true) {
+				t.Errorf("WithRateLimit() expected separate request and polling limiters")
+			}
+		})
+	}
+
+	var config *configuration
+	if config.limiter(false) != nil {
+		t.Errorf("limiter() expected nil for a nil configuration")
+	}
+}
+
+func Test_rateLimiter_wait(t *testing.T) {
+	limiter := newRateLimiter(20, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := limiter.wait(ctx); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Errorf("wait() burst took %v, want no delay", elapsed)
+	}
+
+	start = time.Now()
+	if err := limiter.wait(ctx); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("wait() after burst took %v, want about 50ms", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := limiter.wait(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() error = %v, want %v", err, context.Canceled)
+	}
+
+	var nilLimiter *rateLimiter
+	if err := nilLimiter.wait(ctx); err != nil {
+		t.Errorf("wait() error = %v for nil limiter", err)
+	}
+}
+
+func Test_doRequest_rateLimit(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+	config, err := newConfiguration(WithRateLimit(1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfAuth.config = config
+
+	if _, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType}); err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	// the request bucket is empty, but polling uses its own
+	start := time.Now()
+	if _, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/jobs/ingest/1234", content: jsonType, polling: true}); err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("doRequest() polling took %v, want no delay", elapsed)
+	}
+}
+
+func TestSalesforce_DoRequest_rateLimitCanceled(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+	config, err := newConfiguration(WithRateLimit(0.1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfAuth.config = config
+	sf := &Salesforce{auth: &sfAuth}
+
+	if _, err := sf.DoRequest(http.MethodGet, "/limits", nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	// the bucket is empty, so the next request blocks in wait until it's canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = sf.DoRequest(http.MethodGet, "/limits", nil, WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DoRequest() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DoRequest() returned after %v, want it to stop waiting once canceled", elapsed)
+	}
+}
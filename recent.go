@@ -0,0 +1,52 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type RecentlyViewedRecord struct {
+	Id   string
+	Name string
+	Type string
+}
+
+type recentlyViewedResponse struct {
+	Attributes struct {
+		Type string `json:"type"`
+	} `json:"attributes"`
+	Id   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+func doRecentlyViewed(auth *authentication, limit int) ([]RecentlyViewedRecord, error) {
+	uri := "/recent/"
+	if limit > 0 {
+		uri += "?limit=" + strconv.Itoa(limit)
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     uri,
+		content: jsonType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var recent []recentlyViewedResponse
+	if err := json.Unmarshal(body, &recent); err != nil {
+		return nil, err
+	}
+	records := make([]RecentlyViewedRecord, 0, len(recent))
+	for _, record := range recent {
+		records = append(records, RecentlyViewedRecord{Id: record.Id, Name: record.Name, Type: record.Attributes.Type})
+	}
+	return records, nil
+}
@@ -0,0 +1,91 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_doRecentlyViewed(t *testing.T) {
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/recent/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		limits = append(limits, r.URL.Query().Get("limit"))
+		body := `[{"attributes": {"type": "Account", "url": "/services/data/` + apiVersion + `/sobjects/Account/001000000000001"}, "Id": "001000000000001", "Name": "Stark Industries"}]`
+		if _, err := w.Write([]byte(body)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	type args struct {
+		auth  *authentication
+		limit int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []RecentlyViewedRecord
+		wantErr bool
+	}{
+		{
+			name: "with_limit",
+			args: args{
+				auth:  &sfAuth,
+				limit: 5,
+			},
+			want:    []RecentlyViewedRecord{{Id: "001000000000001", Name: "Stark Industries", Type: "Account"}},
+			wantErr: false,
+		},
+		{
+			name: "without_limit",
+			args: args{
+				auth: &sfAuth,
+			},
+			want:    []RecentlyViewedRecord{{Id: "001000000000001", Name: "Stark Industries", Type: "Account"}},
+			wantErr: false,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth: &badAuth,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doRecentlyViewed(tt.args.auth, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRecentlyViewed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doRecentlyViewed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !reflect.DeepEqual(limits, []string{"5", ""}) {
+		t.Errorf("doRecentlyViewed() sent limits %v", limits)
+	}
+
+	emptyServer, emptyAuth := setupTestServer(json.RawMessage(`[]`), http.StatusOK)
+	defer emptyServer.Close()
+	got, err := doRecentlyViewed(&emptyAuth, 0)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("doRecentlyViewed() = %v, %v, want empty slice", got, err)
+	}
+}
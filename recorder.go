@@ -0,0 +1,289 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+type RecorderMode int
+
+const (
+	RecordMode RecorderMode = iota
+	ReplayMode
+)
+
+const (
+	redactedValue       = "REDACTED"
+	recordedInstanceUrl = "https://recorded.my.salesforce.com"
+)
+
+// credentials and tokens are always scrubbed from recorded bodies
+var defaultRedactedFields = []string{
+	"access_token",
+	"refresh_token",
+	"client_id",
+	"client_secret",
+	"username",
+	"password",
+	"assertion",
+	"signature",
+	"device_code",
+	"code_verifier",
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	Url    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type recordedInteraction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type Recorder struct {
+	mode         RecorderMode
+	fixturePath  string
+	transport    http.RoundTripper
+	redactFields map[string]bool
+	interactions []recordedInteraction
+	replayed     []bool
+	mu           sync.Mutex
+}
+
+func NewRecorder(fixturePath string, mode RecorderMode, redactFields ...string) (*Recorder, error) {
+	if fixturePath == "" {
+		return nil, errors.New("fixture path is required")
+	}
+	if mode != RecordMode && mode != ReplayMode {
+		return nil, fmt.Errorf("invalid recorder mode: %d", mode)
+	}
+	recorder := &Recorder{
+		mode:         mode,
+		fixturePath:  fixturePath,
+		transport:    http.DefaultTransport,
+		redactFields: map[string]bool{},
+	}
+	for _, field := range defaultRedactedFields {
+		recorder.redactFields[field] = true
+	}
+	for _, field := range redactFields {
+		recorder.redactFields[strings.ToLower(field)] = true
+	}
+
+	if mode == ReplayMode {
+		data, err := os.ReadFile(fixturePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &recorder.interactions); err != nil {
+			return nil, err
+		}
+		recorder.replayed = make([]bool, len(recorder.interactions))
+	}
+
+	return recorder, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.roundTrip(req, r.transport)
+}
+
+// records through next, so clients can send recorded requests through their own transport
+func (r *Recorder) roundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := recordedRequest{
+		Method: req.Method,
+		Url:    req.URL.RequestURI(),
+		Body:   r.scrubBody(string(body), req.Header.Get("Content-Type")),
+	}
+
+	if r.mode == ReplayMode {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded, next)
+}
+
+func (r *Recorder) record(req *http.Request, recorded recordedRequest, next http.RoundTripper) (*http.Response, error) {
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, recordedInteraction{
+		Request: recorded,
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.scrubBody(string(body), resp.Header.Get("Content-Type")),
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded recordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || interaction.Request != recorded {
+			continue
+		}
+		r.replayed[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", recorded.Method, recorded.Url)
+}
+
+func (r *Recorder) Save() error {
+	if r.mode != RecordMode {
+		return errors.New("recorder is not in record mode")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.fixturePath, data, 0644)
+}
+
+// fails when the fixture has interactions that were never requested, so replayed tests notice calls that no longer happen
+func (r *Recorder) Verify() error {
+	if r.mode != ReplayMode {
+		return errors.New("recorder is not in replay mode")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unplayed := []string{}
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] {
+			unplayed = append(unplayed, interaction.Request.Method+" "+interaction.Request.Url)
+		}
+	}
+	if len(unplayed) > 0 {
+		return fmt.Errorf("recorded interactions were not replayed: %s", strings.Join(unplayed, ", "))
+	}
+	return nil
+}
+
+func (r *Recorder) scrubBody(body string, contentType string) string {
+	if body == "" {
+		return body
+	}
+	if strings.HasPrefix(contentType, formType) {
+		values, err := url.ParseQuery(body)
+		if err != nil {
+			return body
+		}
+		for key := range values {
+			if r.redactFields[strings.ToLower(key)] {
+				values.Set(key, redactedValue)
+			}
+		}
+		return values.Encode()
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return body // not json, such as bulk csv data
+	}
+	scrubbed, err := json.Marshal(r.scrubValue(value))
+	if err != nil {
+		return body
+	}
+	return string(scrubbed)
+}
+
+func (r *Recorder) scrubValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			switch {
+			case key == "instance_url":
+				// replayed sessions still need a well formed url to send requests to
+				v[key] = recordedInstanceUrl
+			case r.redactFields[strings.ToLower(key)]:
+				v[key] = redactedValue
+			default:
+				v[key] = r.scrubValue(field)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = r.scrubValue(v[i])
+		}
+	}
+	return value
+}
+
+func WithRecorder(recorder *Recorder) Option {
+	return func(config *configuration) error {
+		if recorder == nil {
+			return errors.New("recorder is nil")
+		}
+		config.recorder = recorder
+		return nil
+	}
+}
+
+// the recorder sends through the client's transport, after transport options are applied to it
+type recorderTransport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (transport recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return transport.recorder.roundTrip(req, transport.next)
+}
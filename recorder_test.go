@@ -0,0 +1,263 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewRecorder(t *testing.T) {
+	fixturePath := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(fixturePath, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	badFixturePath := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(badFixturePath, []byte(`{`), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	type args struct {
+		fixturePath string
+		mode        RecorderMode
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "record",
+			args:    args{fixturePath: filepath.Join(t.TempDir(), "new.json"), mode: RecordMode},
+			wantErr: false,
+		},
+		{
+			name:    "replay",
+			args:    args{fixturePath: fixturePath, mode: ReplayMode},
+			wantErr: false,
+		},
+		{
+			name:    "replay_missing_fixture",
+			args:    args{fixturePath: filepath.Join(t.TempDir(), "missing.json"), mode: ReplayMode},
+			wantErr: true,
+		},
+		{
+			name:    "replay_bad_fixture",
+			args:    args{fixturePath: badFixturePath, mode: ReplayMode},
+			wantErr: true,
+		},
+		{
+			name:    "no_fixture_path",
+			args:    args{fixturePath: "", mode: RecordMode},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_mode",
+			args:    args{fixturePath: fixturePath, mode: RecorderMode(5)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRecorder(tt.args.fixturePath, tt.args.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRecorder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecorder_scrubBody(t *testing.T) {
+	recorder, err := NewRecorder("fixture.json", RecordMode, "Email")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	type args struct {
+		body        string
+		contentType string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "token_response",
+			args: args{
+				body:        `{"access_token":"secret","instance_url":"https://example.my.salesforce.com","token_type":"Bearer"}`,
+				contentType: jsonType,
+			},
+			want: `{"access_token":"REDACTED","instance_url":"` + recordedInstanceUrl + `","token_type":"Bearer"}`,
+		},
+		{
+			name: "nested_pii",
+			args: args{
+				body:        `{"records":[{"Id":"003","email":"a@b.com","Amount":1.50}]}`,
+				contentType: jsonType,
+			},
+			want: `{"records":[{"Amount":1.50,"Id":"003","email":"REDACTED"}]}`,
+		},
+		{
+			name: "form",
+			args: args{
+				body:        "client_id=key&client_secret=secret&grant_type=client_credentials",
+				contentType: formType,
+			},
+			want: "client_id=REDACTED&client_secret=REDACTED&grant_type=client_credentials",
+		},
+		{
+			name: "csv",
+			args: args{
+				body:        "Id,Email\n003,a@b.com\n",
+				contentType: csvType,
+			},
+			want: "Id,Email\n003,a@b.com\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recorder.scrubBody(tt.args.body, tt.args.contentType); got != tt.want {
+				t.Errorf("Recorder.scrubBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecorder_recordAndReplay(t *testing.T) {
+	type contact struct {
+		Id    string
+		Email string
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		if r.URL.Path == "/services/oauth2/token" {
+			body = map[string]any{"access_token": "secrettoken", "instance_url": server.URL}
+		} else {
+			body = queryResponse{
+				TotalSize: 1,
+				Done:      true,
+				Records:   []map[string]any{{"Id": "003", "Email": "tony@stark.com"}},
+			}
+		}
+		respBody, _ := json.Marshal(body)
+		w.Header().Set("Content-Type", jsonType)
+		if _, err := w.Write(respBody); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	fixturePath := filepath.Join(t.TempDir(), "fixture.json")
+	recorder, err := NewRecorder(fixturePath, RecordMode, "Email")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	creds := Creds{
+		Domain:         server.URL,
+		ConsumerKey:    "key",
+		ConsumerSecret: "supersecret",
+	}
+	sf, err := Init(creds, WithRecorder(recorder))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	recordedContacts := []contact{}
+	if err := sf.Query("SELECT Id, Email FROM Contact", &recordedContacts); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	fixture, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, secret := range []string{"secrettoken", "supersecret", "tony@stark.com", server.URL} {
+		if strings.Contains(string(fixture), secret) {
+			t.Errorf("fixture contains %s", secret)
+		}
+	}
+
+	replayer, err := NewRecorder(fixturePath, ReplayMode, "Email")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	creds.Domain = "https://login.salesforce.com"
+	sf, err = Init(creds, WithRecorder(replayer))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	replayedContacts := []contact{}
+	if err := sf.Query("SELECT Id, Email FROM Contact", &replayedContacts); err != nil {
+		t.Fatal(err.Error())
+	}
+	want := []contact{{Id: "003", Email: redactedValue}}
+	if !reflect.DeepEqual(replayedContacts, want) {
+		t.Errorf("replayed query = %v, want %v", replayedContacts, want)
+	}
+
+	if err := sf.Query("SELECT Id FROM Account", &replayedContacts); err == nil {
+		t.Errorf("expected error for unrecorded request")
+	}
+	if err := replayer.Save(); err == nil {
+		t.Errorf("expected error saving in replay mode")
+	}
+	if err := replayer.Verify(); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := recorder.Verify(); err == nil {
+		t.Errorf("expected error verifying in record mode")
+	}
+}
+
+func TestRecorder_Verify(t *testing.T) {
+	fixturePath := filepath.Join(t.TempDir(), "fixture.json")
+	fixture := `[
+		{"request": {"method": "GET", "url": "/services/data/v62.0/limits"}, "response": {"statusCode": 200, "body": "{}"}},
+		{"request": {"method": "DELETE", "url": "/services/data/v62.0/sobjects/Account/001"}, "response": {"statusCode": 204}}
+	]`
+	if err := os.WriteFile(fixturePath, []byte(fixture), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	replayer, err := NewRecorder(fixturePath, ReplayMode)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := httptest.NewRequest(http.MethodGet, "https://recorded.my.salesforce.com/services/data/v62.0/limits", nil)
+	resp, err := replayer.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp.Body.Close()
+
+	err = replayer.Verify()
+	if err == nil || !strings.Contains(err.Error(), "DELETE /services/data/v62.0/sobjects/Account/001") {
+		t.Errorf("Verify() error = %v, want unreplayed DELETE", err)
+	}
+	if strings.Contains(err.Error(), "limits") {
+		t.Errorf("Verify() error = %v, should not include replayed request", err)
+	}
+}
+
+func TestWithRecorder(t *testing.T) {
+	recorder, err := NewRecorder("fixture.json", RecordMode)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	config, err := newConfiguration(WithRecorder(recorder))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if transport, ok := config.client().Transport.(recorderTransport); !ok || transport.recorder != recorder || transport.next != http.DefaultTransport {
+		t.Errorf("WithRecorder() transport = %v, want the recorder over the default transport", config.client().Transport)
+	}
+	if _, err := newConfiguration(WithRecorder(nil)); err == nil {
+		t.Errorf("WithRecorder() expected error for nil recorder")
+	}
+}
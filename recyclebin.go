@@ -0,0 +1,83 @@
+package salesforce
+
+import (
+	"errors"
+)
+
+type soapIds struct {
+	Namespace string   `xml:"xmlns,attr"`
+	Ids       []string `xml:"ids"`
+}
+
+type soapSaveResult struct {
+	Id      string `xml:"id"`
+	Success bool   `xml:"success"`
+	Errors  []struct {
+		Message    string   `xml:"message"`
+		StatusCode string   `xml:"statusCode"`
+		Fields     []string `xml:"fields"`
+	} `xml:"errors"`
+}
+
+const (
+	partnerNamespace    = "urn:partner.soap.sforce.com"
+	partnerSoapRoute    = "/services/Soap/u/"
+	recycleBinBatchSize = 200
+)
+
+// undelete and emptyRecycleBin accept up to 200 ids per call
+func doRecycleBinOperation(auth *authentication, action string, ids []string) (SalesforceResults, error) {
+	if len(ids) == 0 {
+		return SalesforceResults{}, errors.New("at least one record id is required")
+	}
+	results := SalesforceResults{}
+	for offset := 0; offset < len(ids); offset += recycleBinBatchSize {
+		batch := ids[offset:min(offset+recycleBinBatchSize, len(ids))]
+		body := soapAction{}
+		if action == "undelete" {
+			body.Undelete = &soapIds{Namespace: partnerNamespace, Ids: batch}
+		} else {
+			body.EmptyRecycleBin = &soapIds{Namespace: partnerNamespace, Ids: batch}
+		}
+		resp, err := doSoapRequest(auth, partnerSoapRoute, partnerNamespace, action, body, false)
+		if err != nil {
+			return results, err
+		}
+
+		saveResults := resp.UndeleteResults
+		if action != "undelete" {
+			saveResults = resp.EmptyRecycleBinResults
+		}
+		for i, saveResult := range saveResults {
+			result := SalesforceResult{
+				Id:      saveResult.Id,
+				Success: saveResult.Success,
+				Index:   offset + i,
+				Errors:  []SalesforceErrorMessage{},
+			}
+			for _, saveError := range saveResult.Errors {
+				result.Errors = append(result.Errors, SalesforceErrorMessage{
+					Message:    saveError.Message,
+					StatusCode: saveError.StatusCode,
+					ErrorCode:  saveError.StatusCode,
+					Fields:     saveError.Fields,
+				})
+			}
+			// failed results don't echo the id, so it is taken from the request
+			if result.Id == "" && i < len(batch) {
+				result.Id = batch[i]
+			}
+			results.HasSalesforceErrors = results.HasSalesforceErrors || !result.Success
+			results.Results = append(results.Results, result)
+		}
+	}
+	return results, nil
+}
+
+func doUndelete(auth *authentication, ids []string) (SalesforceResults, error) {
+	return doRecycleBinOperation(auth, "undelete", ids)
+}
+
+func doEmptyRecycleBin(auth *authentication, ids []string) (SalesforceResults, error) {
+	return doRecycleBinOperation(auth, "emptyRecycleBin", ids)
+}
@@ -0,0 +1,102 @@
+package salesforce
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func setupRecycleBinTestServer(t *testing.T, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != partnerSoapRoute+strings.TrimPrefix(apiVersion, "v") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		*requests = append(*requests, string(body))
+		action := r.Header.Get("SOAPAction")
+		var results strings.Builder
+		for _, id := range strings.Split(string(body), "<ids>")[1:] {
+			id = strings.Split(id, "</ids>")[0]
+			if id == "001000000000404" {
+				results.WriteString(`<result><errors><message>entity is not in the recycle bin</message><statusCode>UNDELETE_FAILED</statusCode></errors><id xsi:nil="true"/><success>false</success></result>`)
+				continue
+			}
+			results.WriteString(`<result><id>` + id + `</id><success>true</success></result>`)
+		}
+		response := `<?xml version="1.0" encoding="UTF-8"?><soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns="urn:partner.soap.sforce.com" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><soapenv:Body><` +
+			action + `Response>` + results.String() + `</` + action + `Response></soapenv:Body></soapenv:Envelope>`
+		if _, err := w.Write([]byte(response)); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+}
+
+func Test_doUndelete(t *testing.T) {
+	var requests []string
+	server := setupRecycleBinTestServer(t, &requests)
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := doUndelete(&sfAuth, []string{"001000000000001", "001000000000404"})
+	if err != nil {
+		t.Fatalf("doUndelete() error = %v", err)
+	}
+	want := SalesforceResults{
+		Results: []SalesforceResult{
+			{Id: "001000000000001", Success: true, Errors: []SalesforceErrorMessage{}},
+			{
+				Id:    "001000000000404",
+				Index: 1,
+				Errors: []SalesforceErrorMessage{{
+					Message:    "entity is not in the recycle bin",
+					StatusCode: "UNDELETE_FAILED",
+					ErrorCode:  "UNDELETE_FAILED",
+				}},
+			},
+		},
+		HasSalesforceErrors: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doUndelete() = %v, want %v", got, want)
+	}
+	if len(requests) != 1 || !strings.Contains(requests[0], `<undelete xmlns="urn:partner.soap.sforce.com">`) {
+		t.Errorf("doUndelete() sent %v", requests)
+	}
+
+	if _, err := doUndelete(&sfAuth, nil); err == nil {
+		t.Errorf("doUndelete() expected error for missing ids")
+	}
+}
+
+func Test_doEmptyRecycleBin(t *testing.T) {
+	var requests []string
+	server := setupRecycleBinTestServer(t, &requests)
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	ids := make([]string, 250)
+	for i := range ids {
+		ids[i] = "001" + strconv.Itoa(100000000000+i)
+	}
+	got, err := doEmptyRecycleBin(&sfAuth, ids)
+	if err != nil {
+		t.Fatalf("doEmptyRecycleBin() error = %v", err)
+	}
+	if len(requests) != 2 || !strings.Contains(requests[0], "<emptyRecycleBin") {
+		t.Fatalf("doEmptyRecycleBin() sent %d requests, want 2", len(requests))
+	}
+	if len(got.Results) != 250 || got.HasSalesforceErrors || got.Results[249].Id != ids[249] || got.Results[249].Index != 249 {
+		t.Errorf("doEmptyRecycleBin() = %v", got)
+	}
+}
@@ -0,0 +1,283 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+type ReportFilter struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+type ReportCell struct {
+	Label string `json:"label"`
+	Value any    `json:"value"`
+}
+
+type ReportResult struct {
+	ReportId   string
+	InstanceId string
+	Status     string
+	AllData    bool
+	Columns    []string
+	Rows       []map[string]ReportCell
+	Raw        json.RawMessage
+}
+
+type ReportInstance struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+	Url    string `json:"url"`
+}
+
+type reportResponse struct {
+	Attributes struct {
+		ReportId string `json:"reportId"`
+		Id       string `json:"id"`
+		Status   string `json:"status"`
+	} `json:"attributes"`
+	AllData         bool                  `json:"allData"`
+	FactMap         map[string]reportFact `json:"factMap"`
+	GroupingsDown   reportGroupings       `json:"groupingsDown"`
+	GroupingsAcross reportGroupings       `json:"groupingsAcross"`
+	ReportMetadata  reportMetadata        `json:"reportMetadata"`
+}
+
+type reportFact struct {
+	Rows []struct {
+		DataCells []ReportCell `json:"dataCells"`
+	} `json:"rows"`
+}
+
+type reportGroupings struct {
+	Groupings []reportGrouping `json:"groupings"`
+}
+
+type reportGrouping struct {
+	Key       string           `json:"key"`
+	Label     string           `json:"label"`
+	Value     any              `json:"value"`
+	Groupings []reportGrouping `json:"groupings"`
+}
+
+type reportMetadata struct {
+	DetailColumns   []string             `json:"detailColumns"`
+	GroupingsDown   []reportGroupingInfo `json:"groupingsDown"`
+	GroupingsAcross []reportGroupingInfo `json:"groupingsAcross"`
+}
+
+type reportGroupingInfo struct {
+	Name string `json:"name"`
+}
+
+// a leaf of the grouping tree, with the grouping cells that lead to it
+type reportGroupingLeaf struct {
+	key   string
+	cells map[string]ReportCell
+}
+
+const (
+	reportStatusError = "Error"
+	reportTotalKey    = "T"
+)
+
+func reportFiltersBody(filters []ReportFilter) (string, error) {
+	if len(filters) == 0 {
+		return "", nil
+	}
+	body, err := json.Marshal(map[string]any{
+		"reportMetadata": map[string]any{"reportFilters": filters},
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func doRunReport(auth *authentication, reportId string, filters []ReportFilter) (ReportResult, error) {
+	if reportId == "" {
+		return ReportResult{}, errors.New("report id is required")
+	}
+	body, err := reportFiltersBody(filters)
+	if err != nil {
+		return ReportResult{}, err
+	}
+	// saved filters are used unless the report metadata is posted with new ones
+	method := http.MethodGet
+	if body != "" {
+		method = http.MethodPost
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  method,
+		uri:     "/analytics/reports/" + url.PathEscape(reportId) + "?includeDetails=true",
+		content: jsonType,
+		body:    body,
+	})
+	if err != nil {
+		return ReportResult{}, err
+	}
+	return decodeReportResult(resp)
+}
+
+func doRunReportAsync(auth *authentication, reportId string, filters []ReportFilter) (ReportInstance, error) {
+	if reportId == "" {
+		return ReportInstance{}, errors.New("report id is required")
+	}
+	body, err := reportFiltersBody(filters)
+	if err != nil {
+		return ReportInstance{}, err
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/analytics/reports/" + url.PathEscape(reportId) + "/instances?includeDetails=true",
+		content: jsonType,
+		body:    body,
+	})
+	if err != nil {
+		return ReportInstance{}, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ReportInstance{}, err
+	}
+	instance := ReportInstance{}
+	if err := json.Unmarshal(respBody, &instance); err != nil {
+		return ReportInstance{}, err
+	}
+	return instance, nil
+}
+
+func doGetReportData(auth *authentication, reportId string, instanceId string) (ReportResult, error) {
+	if reportId == "" || instanceId == "" {
+		return ReportResult{}, errors.New("report id and instance id are required")
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     "/analytics/reports/" + url.PathEscape(reportId) + "/instances/" + url.PathEscape(instanceId),
+		content: jsonType,
+	})
+	if err != nil {
+		return ReportResult{}, err
+	}
+	return decodeReportResult(resp)
+}
+
+func decodeReportResult(resp *http.Response) (ReportResult, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ReportResult{}, err
+	}
+	report := reportResponse{}
+	if err := json.Unmarshal(body, &report); err != nil {
+		return ReportResult{}, err
+	}
+
+	result := ReportResult{
+		ReportId:   report.Attributes.ReportId,
+		InstanceId: report.Attributes.Id,
+		Status:     report.Attributes.Status,
+		AllData:    report.AllData,
+		Raw:        body,
+	}
+	if result.Status == reportStatusError {
+		return result, errors.New("report run failed: " + result.ReportId)
+	}
+	result.Columns, result.Rows = flattenReportFactMap(report)
+	return result, nil
+}
+
+// detail rows are keyed in the fact map by their down and across grouping keys, such as 0_1!T for a summary report
+func flattenReportFactMap(report reportResponse) ([]string, []map[string]ReportCell) {
+	var columns []string
+	for _, grouping := range report.ReportMetadata.GroupingsDown {
+		columns = append(columns, grouping.Name)
+	}
+	for _, grouping := range report.ReportMetadata.GroupingsAcross {
+		columns = append(columns, grouping.Name)
+	}
+	columns = append(columns, report.ReportMetadata.DetailColumns...)
+
+	downLeaves := reportGroupingLeaves(report.GroupingsDown.Groupings, report.ReportMetadata.GroupingsDown, 0, map[string]ReportCell{})
+	acrossLeaves := reportGroupingLeaves(report.GroupingsAcross.Groupings, report.ReportMetadata.GroupingsAcross, 0, map[string]ReportCell{})
+
+	var rows []map[string]ReportCell
+	for _, down := range downLeaves {
+		for _, across := range acrossLeaves {
+			fact, ok := report.FactMap[down.key+"!"+across.key]
+			if !ok {
+				continue
+			}
+			for _, factRow := range fact.Rows {
+				row := make(map[string]ReportCell, len(columns))
+				for name, cell := range down.cells {
+					row[name] = cell
+				}
+				for name, cell := range across.cells {
+					row[name] = cell
+				}
+				for i, cell := range factRow.DataCells {
+					if i < len(report.ReportMetadata.DetailColumns) {
+						row[report.ReportMetadata.DetailColumns[i]] = cell
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return columns, rows
+}
+
+func reportGroupingLeaves(groupings []reportGrouping, info []reportGroupingInfo, depth int, cells map[string]ReportCell) []reportGroupingLeaf {
+	if len(groupings) == 0 {
+		if depth == 0 {
+			return []reportGroupingLeaf{{key: reportTotalKey, cells: cells}}
+		}
+		return nil
+	}
+	var leaves []reportGroupingLeaf
+	for _, grouping := range groupings {
+		groupingCells := make(map[string]ReportCell, len(cells)+1)
+		for name, cell := range cells {
+			groupingCells[name] = cell
+		}
+		if depth < len(info) {
+			groupingCells[info[depth].Name] = ReportCell{Label: grouping.Label, Value: grouping.Value}
+		}
+		if len(grouping.Groupings) == 0 {
+			leaves = append(leaves, reportGroupingLeaf{key: grouping.Key, cells: groupingCells})
+			continue
+		}
+		leaves = append(leaves, reportGroupingLeaves(grouping.Groupings, info, depth+1, groupingCells)...)
+	}
+	return leaves
+}
+
+// decodes the value of each cell into a slice of structs or maps, keyed by column name
+func (result ReportResult) DecodeRows(target any) error {
+	rows := make([]map[string]any, len(result.Rows))
+	for i, row := range result.Rows {
+		rows[i] = make(map[string]any, len(row))
+		for name, cell := range row {
+			rows[i][name] = cell.Value
+		}
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       stringToSalesforceTimeHook,
+		WeaklyTypedInput: true,
+		Result:           target,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(rows)
+}
@@ -0,0 +1,240 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const tabularReportResponse = `{
+	"attributes": {"reportId": "00O000000000001", "id": "0LG000000000001", "status": "Success"},
+	"allData": true,
+	"factMap": {
+		"T!T": {
+			"aggregates": [{"label": "2", "value": 2}],
+			"rows": [
+				{"dataCells": [{"label": "Stark Industries", "value": "001000000000001"}, {"label": "$1,500.00", "value": 1500}]},
+				{"dataCells": [{"label": "Wayne Enterprises", "value": "001000000000002"}, {"label": "$200.00", "value": 200}]}
+			]
+		}
+	},
+	"groupingsDown": {"groupings": []},
+	"groupingsAcross": {"groupings": []},
+	"reportMetadata": {"detailColumns": ["ACCOUNT.NAME", "AMOUNT"], "groupingsDown": [], "groupingsAcross": []}
+}`
+
+const summaryReportResponse = `{
+	"attributes": {"reportId": "00O000000000002"},
+	"allData": true,
+	"factMap": {
+		"0!T": {"rows": []},
+		"0_0!T": {"rows": [{"dataCells": [{"label": "Big Deal", "value": "Big Deal"}]}]},
+		"1!T": {"rows": []},
+		"1_0!T": {"rows": [{"dataCells": [{"label": "Small Deal", "value": "Small Deal"}]}, {"dataCells": [{"label": "Tiny Deal", "value": "Tiny Deal"}]}]},
+		"T!T": {"rows": []}
+	},
+	"groupingsDown": {"groupings": [
+		{"key": "0", "label": "Web", "value": "Web", "groupings": [{"key": "0_0", "label": "Prospecting", "value": "Prospecting", "groupings": []}]},
+		{"key": "1", "label": "Phone", "value": "Phone", "groupings": [{"key": "1_0", "label": "Closed Won", "value": "Closed Won", "groupings": []}]}
+	]},
+	"groupingsAcross": {"groupings": []},
+	"reportMetadata": {
+		"detailColumns": ["OPPORTUNITY_NAME"],
+		"groupingsDown": [{"name": "LEAD_SOURCE"}, {"name": "STAGE_NAME"}],
+		"groupingsAcross": []
+	}
+}`
+
+func Test_doRunReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/analytics/reports/00O000000000001" || r.URL.Query().Get("includeDetails") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"reportMetadata":{"reportFilters":[{"column":"AMOUNT","operator":"greaterThan","value":"100"}]}}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		if _, err := w.Write([]byte(tabularReportResponse)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	want := ReportResult{
+		ReportId:   "00O000000000001",
+		InstanceId: "0LG000000000001",
+		Status:     "Success",
+		AllData:    true,
+		Columns:    []string{"ACCOUNT.NAME", "AMOUNT"},
+		Rows: []map[string]ReportCell{
+			{"ACCOUNT.NAME": {Label: "Stark Industries", Value: "001000000000001"}, "AMOUNT": {Label: "$1,500.00", Value: float64(1500)}},
+			{"ACCOUNT.NAME": {Label: "Wayne Enterprises", Value: "001000000000002"}, "AMOUNT": {Label: "$200.00", Value: float64(200)}},
+		},
+		Raw: json.RawMessage(tabularReportResponse),
+	}
+
+	type args struct {
+		auth     *authentication
+		reportId string
+		filters  []ReportFilter
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    ReportResult
+		wantErr bool
+	}{
+		{
+			name: "saved_filters",
+			args: args{
+				auth:     &sfAuth,
+				reportId: "00O000000000001",
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "custom_filters",
+			args: args{
+				auth:     &sfAuth,
+				reportId: "00O000000000001",
+				filters:  []ReportFilter{{Column: "AMOUNT", Operator: "greaterThan", Value: "100"}},
+			},
+			want:    want,
+			wantErr: false,
+		},
+		{
+			name: "missing_report_id",
+			args: args{
+				auth: &sfAuth,
+			},
+			want:    ReportResult{},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:     &badAuth,
+				reportId: "00O000000000001",
+			},
+			want:    ReportResult{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doRunReport(tt.args.auth, tt.args.reportId, tt.args.filters)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRunReport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doRunReport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_flattenReportFactMap(t *testing.T) {
+	report := reportResponse{}
+	if err := json.Unmarshal([]byte(summaryReportResponse), &report); err != nil {
+		t.Fatal(err.Error())
+	}
+	columns, rows := flattenReportFactMap(report)
+	wantColumns := []string{"LEAD_SOURCE", "STAGE_NAME", "OPPORTUNITY_NAME"}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("flattenReportFactMap() columns = %v, want %v", columns, wantColumns)
+	}
+	wantRows := []map[string]ReportCell{
+		{"LEAD_SOURCE": {Label: "Web", Value: "Web"}, "STAGE_NAME": {Label: "Prospecting", Value: "Prospecting"}, "OPPORTUNITY_NAME": {Label: "Big Deal", Value: "Big Deal"}},
+		{"LEAD_SOURCE": {Label: "Phone", Value: "Phone"}, "STAGE_NAME": {Label: "Closed Won", Value: "Closed Won"}, "OPPORTUNITY_NAME": {Label: "Small Deal", Value: "Small Deal"}},
+		{"LEAD_SOURCE": {Label: "Phone", Value: "Phone"}, "STAGE_NAME": {Label: "Closed Won", Value: "Closed Won"}, "OPPORTUNITY_NAME": {Label: "Tiny Deal", Value: "Tiny Deal"}},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("flattenReportFactMap() rows = %v, want %v", rows, wantRows)
+	}
+}
+
+func Test_doRunReportAsync(t *testing.T) {
+	instance := ReportInstance{Id: "0LG000000000001", Status: "New", Url: "/services/data/" + apiVersion + "/analytics/reports/00O000000000001/instances/0LG000000000001"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/services/data/"+apiVersion+"/analytics/reports/00O000000000001/instances":
+			body = instance
+		case r.Method == http.MethodGet && r.URL.Path == "/services/data/"+apiVersion+"/analytics/reports/00O000000000001/instances/0LG000000000001":
+			body = json.RawMessage(tabularReportResponse)
+		case r.Method == http.MethodGet && r.URL.Path == "/services/data/"+apiVersion+"/analytics/reports/00O000000000001/instances/0LG000000000002":
+			body = json.RawMessage(`{"attributes": {"reportId": "00O000000000001", "id": "0LG000000000002", "status": "Error"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		respBody, _ := json.Marshal(body)
+		if _, err := w.Write(respBody); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	got, err := doRunReportAsync(&sfAuth, "00O000000000001", nil)
+	if err != nil {
+		t.Fatalf("doRunReportAsync() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, instance) {
+		t.Errorf("doRunReportAsync() = %v, want %v", got, instance)
+	}
+
+	result, err := doGetReportData(&sfAuth, "00O000000000001", got.Id)
+	if err != nil {
+		t.Fatalf("doGetReportData() error = %v", err)
+	}
+	if result.Status != "Success" || len(result.Rows) != 2 {
+		t.Errorf("doGetReportData() = %v", result)
+	}
+
+	if _, err := doGetReportData(&sfAuth, "00O000000000001", "0LG000000000002"); err == nil {
+		t.Errorf("doGetReportData() expected error for failed report run")
+	}
+	if _, err := doGetReportData(&sfAuth, "00O000000000001", ""); err == nil {
+		t.Errorf("doGetReportData() expected error for missing instance id")
+	}
+}
+
+func TestReportResult_DecodeRows(t *testing.T) {
+	result := ReportResult{
+		Rows: []map[string]ReportCell{
+			{"ACCOUNT.NAME": {Label: "Stark Industries", Value: "001000000000001"}, "AMOUNT": {Label: "$1,500.00", Value: float64(1500)}},
+		},
+	}
+	type accountAmount struct {
+		AccountId string  `mapstructure:"ACCOUNT.NAME"`
+		Amount    float64 `mapstructure:"AMOUNT"`
+	}
+	got := []accountAmount{}
+	if err := result.DecodeRows(&got); err != nil {
+		t.Fatalf("DecodeRows() error = %v", err)
+	}
+	want := []accountAmount{{AccountId: "001000000000001", Amount: 1500}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeRows() = %v, want %v", got, want)
+	}
+}
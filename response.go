@@ -0,0 +1,61 @@
+package salesforce
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	limitInfoHeader = "Sforce-Limit-Info"
+	requestIdHeader = "X-Request-Id"
+)
+
+type ResponseMetadata struct {
+	StatusCode int
+	RequestId  string
+	ApiUsage   ApiUsage
+}
+
+type ApiUsage struct {
+	Used  int
+	Limit int
+}
+
+func newResponseMetadata(resp *http.Response) ResponseMetadata {
+	if resp == nil {
+		return ResponseMetadata{}
+	}
+	return ResponseMetadata{
+		StatusCode: resp.StatusCode,
+		RequestId:  resp.Header.Get(requestIdHeader),
+		ApiUsage:   parseApiUsage(resp.Header.Get(limitInfoHeader)),
+	}
+}
+
+// parses a Sforce-Limit-Info header such as "api-usage=25/15000"
+func parseApiUsage(limitInfo string) ApiUsage {
+	for _, limit := range strings.Split(limitInfo, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(limit), "=")
+		if !ok || name != "api-usage" {
+			continue
+		}
+		used, max, ok := strings.Cut(value, "/")
+		if !ok {
+			return ApiUsage{}
+		}
+		usedCount, usedErr := strconv.Atoi(used)
+		limitCount, limitErr := strconv.Atoi(max)
+		if usedErr != nil || limitErr != nil {
+			return ApiUsage{}
+		}
+		return ApiUsage{Used: usedCount, Limit: limitCount}
+	}
+	return ApiUsage{}
+}
+
+func setResponseMetadata(results []SalesforceResult, metadata ResponseMetadata) {
+	for i := range results {
+		results[i].ResponseMetadata = metadata
+	}
+}
@@ -0,0 +1,96 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_parseApiUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		limitInfo string
+		want      ApiUsage
+	}{
+		{
+			name:      "api_usage",
+			limitInfo: "api-usage=25/15000",
+			want:      ApiUsage{Used: 25, Limit: 15000},
+		},
+		{
+			name:      "multiple_limits",
+			limitInfo: "per-app-api-usage=17/250(appName=sample-connected-app), api-usage=18/15000",
+			want:      ApiUsage{Used: 18, Limit: 15000},
+		},
+		{
+			name:      "missing_header",
+			limitInfo: "",
+			want:      ApiUsage{},
+		},
+		{
+			name:      "malformed",
+			limitInfo: "api-usage=abc",
+			want:      ApiUsage{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseApiUsage(tt.limitInfo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseApiUsage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newResponseMetadata(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header: http.Header{
+			"Sforce-Limit-Info": []string{"api-usage=25/15000"},
+			"X-Request-Id":      []string{"abc123"},
+		},
+	}
+	want := ResponseMetadata{
+		StatusCode: http.StatusCreated,
+		RequestId:  "abc123",
+		ApiUsage:   ApiUsage{Used: 25, Limit: 15000},
+	}
+	if got := newResponseMetadata(resp); !reflect.DeepEqual(got, want) {
+		t.Errorf("newResponseMetadata() = %v, want %v", got, want)
+	}
+	if got := newResponseMetadata(nil); !reflect.DeepEqual(got, ResponseMetadata{}) {
+		t.Errorf("newResponseMetadata() = %v, want zero value", got)
+	}
+}
+
+func Test_doInsertCollection_responseMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Sforce-Limit-Info", "api-usage=42/15000")
+		w.Header().Set("X-Request-Id", "abc123")
+		if _, err := w.Write([]byte(`[{"id":"1234","success":true,"errors":[]}]`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	results, err := doInsertCollection(&sfAuth, "Account", []map[string]any{{"Name": "test account"}}, 200, dmlOptions{})
+	if err != nil {
+		t.Fatalf("doInsertCollection() error = %v", err)
+	}
+	want := ResponseMetadata{
+		StatusCode: http.StatusOK,
+		RequestId:  "abc123",
+		ApiUsage:   ApiUsage{Used: 42, Limit: 15000},
+	}
+	if !reflect.DeepEqual(results.ResponseMetadata, want) {
+		t.Errorf("doInsertCollection() metadata = %v, want %v", results.ResponseMetadata, want)
+	}
+	if !reflect.DeepEqual(results.Results[0].ResponseMetadata, want) {
+		t.Errorf("doInsertCollection() result metadata = %v, want %v", results.Results[0].ResponseMetadata, want)
+	}
+}
@@ -0,0 +1,183 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// maximum number of ids per sObject Collections retrieve request
+const retrieveBatchSizeMax = 2000
+
+type collectionRetrieveRequest struct {
+	Ids    []string `json:"ids"`
+	Fields []string `json:"fields"`
+}
+
+func doGetOne(auth *authentication, sObjectName string, recordId string, fields []string, result any) error {
+	if recordId == "" {
+		return errors.New("salesforce id is required")
+	}
+	uri := "/sobjects/" + sObjectName + "/" + url.PathEscape(recordId)
+	if len(fields) > 0 {
+		uri = uri + "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	}
+	return getRecord(auth, uri, result)
+}
+
+func doGetByExternalId(auth *authentication, sObjectName string, fieldName string, externalIdValue string, result any) error {
+	if fieldName == "" || externalIdValue == "" {
+		return errors.New("external id field name and value are required")
+	}
+	uri := "/sobjects/" + sObjectName + "/" + fieldName + "/" + url.PathEscape(externalIdValue)
+	return getRecord(auth, uri, result)
+}
+
+func doGetCollection(auth *authentication, sObjectName string, ids []string, fields []string, result any) error {
+	if len(ids) == 0 {
+		return errors.New("at least one salesforce id is required")
+	}
+	if len(fields) == 0 {
+		return errors.New("at least one field is required")
+	}
+
+	records, err := getCollectionRecords(auth, sObjectName, ids, fields)
+	if err != nil {
+		return err
+	}
+	stripRelationshipAttributes(records)
+	if t := reflect.TypeOf(result); t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice {
+		resolveFieldTags(t.Elem().Elem(), records)
+	}
+	return mapstructure.Decode(records, result)
+}
+
+// records that don't exist or can't be accessed are returned as null, keeping each record at the index of its id
+func getCollectionRecords(auth *authentication, sObjectName string, ids []string, fields []string) ([]map[string]any, error) {
+	var batches [][]string
+	for len(ids) > 0 {
+		batchSize := min(len(ids), retrieveBatchSizeMax)
+		batches = append(batches, ids[:batchSize])
+		ids = ids[batchSize:]
+	}
+
+	batchRecords := make([][]map[string]any, len(batches))
+	err := runConcurrently(len(batches), auth.config.workers(), func(i int) error {
+		records, err := getCollectionBatch(auth, sObjectName, batches[i], fields)
+		batchRecords[i] = records
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]any
+	for _, batch := range batchRecords {
+		records = append(records, batch...)
+	}
+	return records, nil
+}
+
+// decodes the current values of fields into each record of the slice, such as formula and auto-number fields
+// after an insert; records that don't exist or can't be accessed are left unchanged
+func doRefreshCollection(auth *authentication, sObjectName string, records any, fields []string) error {
+	v := reflect.ValueOf(records)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return errors.New("expected a slice or a pointer to a slice of records, got: " + v.Kind().String())
+	}
+	if len(fields) == 0 {
+		return errors.New("at least one field is required")
+	}
+	if v.Len() == 0 {
+		return nil
+	}
+	recordMap, err := convertToSliceOfMaps(v.Interface(), false)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(recordMap))
+	for i := range recordMap {
+		recordId, ok := recordMap[i]["Id"].(string)
+		if !ok || recordId == "" {
+			return errors.New("salesforce id not found in object data")
+		}
+		ids[i] = recordId
+	}
+	current, err := getCollectionRecords(auth, sObjectName, ids, fields)
+	if err != nil {
+		return err
+	}
+
+	stripRelationshipAttributes(current)
+	resolveFieldTags(v.Type().Elem(), current)
+	for i, record := range current {
+		if record == nil || i >= v.Len() {
+			continue
+		}
+		delete(record, "attributes")
+		if err := mapstructure.Decode(record, v.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getCollectionBatch(auth *authentication, sObjectName string, ids []string, fields []string) ([]map[string]any, error) {
+	body, err := auth.config.codec().Marshal(collectionRetrieveRequest{Ids: ids, Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/composite/sobjects/" + sObjectName,
+		content: jsonType,
+		body:    string(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var records []map[string]any
+	if err := decodeBody(auth.config.codec(), resp.Body, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func getRecord(auth *authentication, uri string, result any) error {
+	resp, err := doRequest(auth, requestPayload{
+		method:  http.MethodGet,
+		uri:     uri,
+		content: jsonType,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkMultipleMatches(resp); err != nil {
+		return err
+	}
+
+	record := map[string]any{}
+	if err := decodeBody(auth.config.codec(), resp.Body, &record); err != nil {
+		return err
+	}
+	return decodeRecord(record, result)
+}
+
+func decodeRecord(record map[string]any, result any) error {
+	records := []map[string]any{record}
+	stripRelationshipAttributes(records)
+	if t := reflect.TypeOf(result); t != nil && t.Kind() == reflect.Pointer {
+		resolveFieldTags(t.Elem(), records)
+	}
+	return mapstructure.Decode(record, result)
+}
@@ -0,0 +1,410 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type retrievedContact struct {
+	Id          string
+	LastName    string
+	AccountName string `sf:"Account.Name"`
+}
+
+func Test_doGetOne(t *testing.T) {
+	var requestURI string
+	record := map[string]any{
+		"attributes": map[string]any{"type": "Contact", "url": "/services/data/v62.0/sobjects/Contact/003"},
+		"Id":         "003",
+		"LastName":   "Stark",
+		"Account": map[string]any{
+			"attributes": map[string]any{"type": "Account"},
+			"Name":       "Stark Industries",
+		},
+	}
+	body, _ := json.Marshal(record)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badSfAuth := setupTestServer([]SalesforceErrorMessage{{ErrorCode: "NOT_FOUND"}}, http.StatusNotFound)
+	defer badServer.Close()
+
+	type args struct {
+		auth     *authentication
+		recordId string
+		fields   []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantUri string
+		want    retrievedContact
+		wantErr bool
+	}{
+		{
+			name: "get_with_fields",
+			args: args{
+				auth:     &sfAuth,
+				recordId: "003",
+				fields:   []string{"Id", "LastName", "Account.Name"},
+			},
+			wantUri: "/services/data/" + apiVersion + "/sobjects/Contact/003?fields=Id%2CLastName%2CAccount.Name",
+			want:    retrievedContact{Id: "003", LastName: "Stark", AccountName: "Stark Industries"},
+			wantErr: false,
+		},
+		{
+			name: "get_all_fields",
+			args: args{
+				auth:     &sfAuth,
+				recordId: "003",
+			},
+			wantUri: "/services/data/" + apiVersion + "/sobjects/Contact/003",
+			want:    retrievedContact{Id: "003", LastName: "Stark", AccountName: "Stark Industries"},
+			wantErr: false,
+		},
+		{
+			name: "no_id",
+			args: args{
+				auth: &sfAuth,
+			},
+			wantErr: true,
+		},
+		{
+			name: "not_found",
+			args: args{
+				auth:     &badSfAuth,
+				recordId: "003",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURI = ""
+			got := retrievedContact{}
+			err := doGetOne(tt.args.auth, "Contact", tt.args.recordId, tt.args.fields, &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doGetOne() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if requestURI != tt.wantUri {
+				t.Errorf("doGetOne() uri = %v, want %v", requestURI, tt.wantUri)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetOne() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doGetByExternalId(t *testing.T) {
+	server, sfAuth := setupTestServer(map[string]any{"Id": "003", "LastName": "Stark"}, http.StatusOK)
+	defer server.Close()
+
+	multipleServer, multipleSfAuth := setupTestServer([]string{"/services/data/v62.0/sobjects/Contact/003", "/services/data/v62.0/sobjects/Contact/004"}, http.StatusMultipleChoices)
+	defer multipleServer.Close()
+
+	type args struct {
+		auth            *authentication
+		fieldName       string
+		externalIdValue string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    retrievedContact
+		wantErr bool
+	}{
+		{
+			name: "get_by_external_id",
+			args: args{
+				auth:            &sfAuth,
+				fieldName:       "ExternalId__c",
+				externalIdValue: "abc",
+			},
+			want:    retrievedContact{Id: "003", LastName: "Stark"},
+			wantErr: false,
+		},
+		{
+			name: "multiple_matches",
+			args: args{
+				auth:            &multipleSfAuth,
+				fieldName:       "ExternalId__c",
+				externalIdValue: "abc",
+			},
+			want:    retrievedContact{},
+			wantErr: true,
+		},
+		{
+			name: "no_value",
+			args: args{
+				auth:      &sfAuth,
+				fieldName: "ExternalId__c",
+			},
+			want:    retrievedContact{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := retrievedContact{}
+			if err := doGetByExternalId(tt.args.auth, "Contact", tt.args.fieldName, tt.args.externalIdValue, &got); (err != nil) != tt.wantErr {
+				t.Errorf("doGetByExternalId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetByExternalId() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doGetCollection(t *testing.T) {
+	var requests []collectionRetrieveRequest
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/services/data/"+apiVersion+"/composite/sobjects/Contact" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		req := collectionRetrieveRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+
+		records := make([]map[string]any, len(req.Ids))
+		for i, id := range req.Ids {
+			if id == "missing" {
+				continue
+			}
+			records[i] = map[string]any{
+				"attributes": map[string]any{"type": "Contact"},
+				"Id":         id,
+				"LastName":   "Stark",
+				"Account": map[string]any{
+					"attributes": map[string]any{"type": "Account"},
+					"Name":       "Stark Industries",
+				},
+			}
+		}
+		body, _ := json.Marshal(records)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	badServer, badSfAuth := setupTestServer([]SalesforceErrorMessage{{ErrorCode: "INVALID_FIELD"}}, http.StatusBadRequest)
+	defer badServer.Close()
+
+	manyIds := make([]string, retrieveBatchSizeMax+1)
+	for i := range manyIds {
+		manyIds[i] = strconv.Itoa(i)
+	}
+
+	type args struct {
+		auth   *authentication
+		ids    []string
+		fields []string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantRequests int
+		wantLen      int
+		want         []retrievedContact
+		wantErr      bool
+	}{
+		{
+			name: "get_with_missing_record",
+			args: args{
+				auth:   &sfAuth,
+				ids:    []string{"003", "missing", "004"},
+				fields: []string{"Id", "LastName", "Account.Name"},
+			},
+			wantRequests: 1,
+			wantLen:      3,
+			want: []retrievedContact{
+				{Id: "003", LastName: "Stark", AccountName: "Stark Industries"},
+				{},
+				{Id: "004", LastName: "Stark", AccountName: "Stark Industries"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "multiple_batches",
+			args: args{
+				auth:   &sfAuth,
+				ids:    manyIds,
+				fields: []string{"Id"},
+			},
+			wantRequests: 2,
+			wantLen:      retrieveBatchSizeMax + 1,
+			wantErr:      false,
+		},
+		{
+			name: "no_ids",
+			args: args{
+				auth:   &sfAuth,
+				fields: []string{"Id"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no_fields",
+			args: args{
+				auth: &sfAuth,
+				ids:  []string{"003"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			args: args{
+				auth:   &badSfAuth,
+				ids:    []string{"003"},
+				fields: []string{"Id"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			got := []retrievedContact{}
+			err := doGetCollection(tt.args.auth, "Contact", tt.args.ids, tt.args.fields, &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doGetCollection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(requests) != tt.wantRequests {
+				t.Errorf("doGetCollection() sent %d requests, want %d", len(requests), tt.wantRequests)
+			}
+			if len(got) != tt.wantLen {
+				t.Fatalf("doGetCollection() returned %d records, want %d", len(got), tt.wantLen)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doGetCollection() = %v, want %v", got, tt.want)
+			}
+			if got[len(got)-1].Id != tt.args.ids[len(tt.args.ids)-1] {
+				t.Errorf("doGetCollection() last record = %v, want id %v", got[len(got)-1], tt.args.ids[len(tt.args.ids)-1])
+			}
+		})
+	}
+}
+
+func Test_doRefreshCollection(t *testing.T) {
+	var requests []collectionRetrieveRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := collectionRetrieveRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+		records := make([]map[string]any, len(req.Ids))
+		for i, id := range req.Ids {
+			if id == "missing" {
+				continue
+			}
+			records[i] = map[string]any{
+				"attributes":    map[string]any{"type": "Contact"},
+				"Id":            id,
+				"ContactNumber": "C-" + id,
+				"Account":       map[string]any{"attributes": map[string]any{"type": "Account"}, "Name": "Stark Industries"},
+			}
+		}
+		if err := json.NewEncoder(w).Encode(records); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	type contact struct {
+		Id            string
+		LastName      string
+		ContactNumber string
+		AccountName   string `sf:"Account.Name"`
+	}
+	contacts := []contact{
+		{Id: "003A", LastName: "Stark"},
+		{Id: "missing", LastName: "Banner", ContactNumber: "C-old"},
+		{Id: "003B", LastName: "Rogers"},
+	}
+	if err := doRefreshCollection(&sfAuth, "Contact", contacts, []string{"ContactNumber", "Account.Name"}); err != nil {
+		t.Fatalf("doRefreshCollection() error = %v", err)
+	}
+	want := []contact{
+		{Id: "003A", LastName: "Stark", ContactNumber: "C-003A", AccountName: "Stark Industries"},
+		{Id: "missing", LastName: "Banner", ContactNumber: "C-old"},
+		{Id: "003B", LastName: "Rogers", ContactNumber: "C-003B", AccountName: "Stark Industries"},
+	}
+	if !reflect.DeepEqual(contacts, want) {
+		t.Errorf("doRefreshCollection() = %v, want %v", contacts, want)
+	}
+	wantRequest := collectionRetrieveRequest{Ids: []string{"003A", "missing", "003B"}, Fields: []string{"ContactNumber", "Account.Name"}}
+	if len(requests) != 1 || !reflect.DeepEqual(requests[0], wantRequest) {
+		t.Errorf("doRefreshCollection() requests = %v, want %v", requests, wantRequest)
+	}
+
+	pointers := []*contact{{Id: "003A"}}
+	if err := doRefreshCollection(&sfAuth, "Contact", &pointers, []string{"ContactNumber"}); err != nil {
+		t.Fatalf("doRefreshCollection() error = %v", err)
+	}
+	if pointers[0].ContactNumber != "C-003A" {
+		t.Errorf("doRefreshCollection() = %v, want the pointed to record refreshed", *pointers[0])
+	}
+
+	maps := []map[string]any{{"Id": "003A", "LastName": "Stark"}}
+	if err := doRefreshCollection(&sfAuth, "Contact", maps, []string{"ContactNumber"}); err != nil {
+		t.Fatalf("doRefreshCollection() error = %v", err)
+	}
+	if maps[0]["ContactNumber"] != "C-003A" || maps[0]["LastName"] != "Stark" || maps[0]["attributes"] != nil {
+		t.Errorf("doRefreshCollection() = %v, want ContactNumber added to the map", maps[0])
+	}
+
+	errorTests := []struct {
+		name    string
+		records any
+		fields  []string
+	}{
+		{name: "not_a_slice", records: contact{Id: "003A"}, fields: []string{"LastName"}},
+		{name: "no_fields", records: contacts},
+		{name: "missing_id", records: []contact{{LastName: "Stark"}}, fields: []string{"LastName"}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := doRefreshCollection(&sfAuth, "Contact", tt.records, tt.fields); err == nil {
+				t.Errorf("doRefreshCollection() expected error")
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package salesforce
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/forcedotcom/go-soql"
 )
@@ -17,21 +22,37 @@ type Salesforce struct {
 }
 
 type SalesforceErrorMessage struct {
-	Message    string   `json:"message"`
-	StatusCode string   `json:"statusCode"`
-	Fields     []string `json:"fields"`
-	ErrorCode  string   `json:"errorCode"`
+	Message         string           `json:"message"`
+	StatusCode      string           `json:"statusCode"`
+	Fields          []string         `json:"fields"`
+	ErrorCode       string           `json:"errorCode"`
+	DuplicateResult *DuplicateResult `json:"duplicateResult,omitempty"`
 }
 
 type SalesforceResult struct {
-	Id      string                   `json:"id"`
-	Errors  []SalesforceErrorMessage `json:"errors"`
-	Success bool                     `json:"success"`
+	Id               string                   `json:"id"`
+	Errors           []SalesforceErrorMessage `json:"errors"`
+	Success          bool                     `json:"success"`
+	Index            int                      `json:"-"`
+	Snapshot         map[string]any           `json:"-"`
+	ResponseMetadata `json:"-"`
 }
 
 type SalesforceResults struct {
 	Results             []SalesforceResult
 	HasSalesforceErrors bool
+	SubRequests         []SubRequestResult
+	ResponseMetadata    `json:"-"`
+}
+
+// the outcome of one subrequest of a composite request, covering Size records starting at Index
+type SubRequestResult struct {
+	ReferenceId    string
+	HttpStatusCode int
+	HttpHeaders    map[string]string
+	Index          int
+	Size           int
+	HasErrors      bool
 }
 
 type requestPayload struct {
@@ -40,44 +61,150 @@ type requestPayload struct {
 	content string
 	body    string
 	retry   bool
+	headers map[string]string
+	polling bool
+	// writes the body through a pipe instead of body, so large uploads aren't held in memory; called again on retry
+	stream func(w io.Writer) error
+	// reported to telemetry when the sObject isn't part of the uri
+	sObjectName string
+	batch       int
+	timeout     time.Duration
+	ctx         context.Context
+	// time already spent waiting on 503 responses, counted against the retry budget
+	unavailableWait     time.Duration
+	unavailableAttempts int
 }
 
 const (
-	apiVersion            = "v62.0"
-	jsonType              = "application/json"
-	csvType               = "text/csv"
-	batchSizeMax          = 200
-	bulkBatchSizeMax      = 10000
-	invalidSessionIdError = "INVALID_SESSION_ID"
+	apiVersion              = "v62.0"
+	jsonType                = "application/json"
+	csvType                 = "text/csv"
+	formType                = "application/x-www-form-urlencoded"
+	batchSizeMax            = 200
+	bulkBatchSizeMax        = 10000
+	invalidSessionIdError   = "INVALID_SESSION_ID"
+	entityIsDeletedError    = "ENTITY_IS_DELETED"
+	unableToLockRowError    = "UNABLE_TO_LOCK_ROW"
+	autoAssignHeader        = "Sforce-Auto-Assign"
+	duplicateRuleHeader     = "Sforce-Duplicate-Rule-Header"
+	ifMatchHeader           = "If-Match"
+	ifUnmodifiedSinceHeader = "If-Unmodified-Since"
 )
 
+// returned when a conditional update or delete fails because the record changed since it was read
+var ErrStaleRecord = errors.New("stale record: precondition failed")
+
+// returned when an external id matches more than one record
+var ErrMultipleMatches = errors.New("external id matches multiple records")
+
+// wraps ErrMultipleMatches with the ids of every record that matched
+type MultipleMatchesError struct {
+	RecordIds []string
+}
+
+func (e *MultipleMatchesError) Error() string {
+	return ErrMultipleMatches.Error() + ": " + strings.Join(e.RecordIds, ", ")
+}
+
+func (e *MultipleMatchesError) Unwrap() error {
+	return ErrMultipleMatches
+}
+
+// salesforce responds to ambiguous external ids with 300 and a list of urls, one for each matching record
+func checkMultipleMatches(resp *http.Response) error {
+	if resp.StatusCode != http.StatusMultipleChoices {
+		return nil
+	}
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return newMultipleMatchesError(responseData)
+}
+
+func newMultipleMatchesError(responseData []byte) error {
+	var urls []string
+	if err := json.Unmarshal(responseData, &urls); err != nil {
+		return fmt.Errorf("%w: %s", ErrMultipleMatches, responseData)
+	}
+	matches := &MultipleMatchesError{}
+	for _, recordUrl := range urls {
+		matches.RecordIds = append(matches.RecordIds, path.Base(recordUrl))
+	}
+	return matches
+}
+
 func doRequest(auth *authentication, payload requestPayload) (*http.Response, error) {
-	var reader *strings.Reader
-	var req *http.Request
-	var err error
-	endpoint := auth.InstanceUrl + "/services/data/" + apiVersion + payload.uri
+	var body io.Reader
+	instanceUrl, accessToken := auth.session()
+	endpoint := instanceUrl + "/services/data/" + apiVersion + payload.uri
+	compress := auth.config.compressed()
 
 	if payload.body != "" {
-		reader = strings.NewReader(payload.body)
-		req, err = http.NewRequest(payload.method, endpoint, reader)
-	} else {
-		req, err = http.NewRequest(payload.method, endpoint, nil)
+		body = strings.NewReader(payload.body)
+		if compress {
+			compressedBody, err := gzipBody(payload.body)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(compressedBody)
+		}
+	}
+	if payload.stream != nil {
+		body = streamBody(payload.stream, compress)
+	}
+	ctx := payload.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	req, err := http.NewRequestWithContext(ctx, payload.method, endpoint, body)
 	if err != nil {
+		if closer, ok := body.(io.Closer); ok {
+			closer.Close()
+		}
 		return nil, err
 	}
 
 	req.Header.Set("User-Agent", "go-salesforce")
 	req.Header.Set("Content-Type", payload.content)
 	req.Header.Set("Accept", payload.content)
-	req.Header.Set("Authorization", "Bearer "+auth.AccessToken)
+	if compress {
+		req.Header.Set("Accept-Encoding", gzipEncoding)
+		if body != nil {
+			req.Header.Set("Content-Encoding", gzipEncoding)
+		}
+	}
+	for key, value := range payload.headers {
+		req.Header.Set(key, value)
+	}
+	if err := auth.config.limiter(payload.polling).wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	resp, err := http.DefaultClient.Do(req)
+	client := auth.config.client()
+	if payload.timeout > 0 {
+		timed := *client
+		timed.Timeout = payload.timeout
+		client = &timed
+	}
+	req, endTelemetry := startRequestTelemetry(auth, req, newTelemetryRequest(payload))
+	resp, err := client.Do(req)
+	endTelemetry(resp, err)
 	if err != nil {
 		return resp, err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode > 300 {
-		resp, err = processSalesforceError(*resp, auth, payload)
+	if compress {
+		if err := decompressResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+	// conditional requests respond with 304 when the resource hasn't changed
+	if (resp.StatusCode < 200 || resp.StatusCode > 300) && resp.StatusCode != http.StatusNotModified {
+		resp, err = processSalesforceError(*resp, auth, payload, accessToken)
 	}
 
 	return resp, err
@@ -126,6 +253,17 @@ func validateGoSoql(sf Salesforce, record any) error {
 	return nil
 }
 
+func validateRetrieve(sf Salesforce, result any) error {
+	authErr := validateAuth(sf)
+	if authErr != nil {
+		return authErr
+	}
+	if result == nil || reflect.TypeOf(result).Kind() != reflect.Pointer || reflect.ValueOf(result).IsNil() {
+		return errors.New("expected a non-nil pointer to decode the record into")
+	}
+	return nil
+}
+
 func validateSingles(sf Salesforce, record any) error {
 	authErr := validateAuth(sf)
 	if authErr != nil {
@@ -172,11 +310,21 @@ func validateBulk(sf Salesforce, records any, batchSize int, isFile bool) error
 	return nil
 }
 
-func processSalesforceError(resp http.Response, auth *authentication, payload requestPayload) (*http.Response, error) {
+// accessToken is the token the request was sent with, so an expired session is only refreshed once
+func processSalesforceError(resp http.Response, auth *authentication, payload requestPayload, accessToken string) (*http.Response, error) {
 	responseData, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return &resp, err
 	}
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		if len(responseData) == 0 {
+			return &resp, ErrStaleRecord
+		}
+		return &resp, fmt.Errorf("%w: %s", ErrStaleRecord, responseData)
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return handleServiceUnavailable(resp, responseData, auth, payload)
+	}
 	var sfErrors []SalesforceErrorMessage
 	err = json.Unmarshal(responseData, &sfErrors)
 	if err != nil {
@@ -184,11 +332,18 @@ func processSalesforceError(resp http.Response, auth *authentication, payload re
 	}
 	for _, sfError := range sfErrors {
 		if sfError.ErrorCode == invalidSessionIdError && !payload.retry { // only attempt to refresh the session once
-			err = refreshSession(auth)
+			err = refreshExpiredSession(auth, accessToken)
 			if err != nil {
 				return &resp, err
 			}
-			newResp, err := doRequest(auth, requestPayload{payload.method, payload.uri, payload.content, payload.body, true})
+			retryPayload := payload
+			retryPayload.retry = true
+			recordRetryTelemetry(auth, TelemetryRetry{
+				Operation: newTelemetryRequest(payload).Operation,
+				Reason:    retryReasonInvalidSession,
+				Attempt:   1,
+			})
+			newResp, err := doRequest(auth, retryPayload)
 			if err != nil {
 				return &resp, err
 			}
@@ -199,11 +354,31 @@ func processSalesforceError(resp http.Response, auth *authentication, payload re
 	return &resp, errors.New(string(responseData))
 }
 
-func Init(creds Creds) (*Salesforce, error) {
+func Init(creds Creds, opts ...Option) (*Salesforce, error) {
+	config, err := newConfiguration(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return initWithConfiguration(creds, config)
+}
+
+// options are applied once, so callers that already built the configuration, such as OrgManager, pass it here
+func initWithConfiguration(creds Creds, config *configuration) (*Salesforce, error) {
 	var auth *authentication
 	var err error
 	if creds == (Creds{}) {
-		return nil, errors.New("creds is empty")
+		if !config.hasTokenProvider() {
+			return nil, errors.New("creds is empty")
+		}
+		auth, err = tokenProviderFlow(context.Background(), config)
+		if err != nil {
+			return nil, err
+		}
+		return &Salesforce{auth: auth}, nil
+	}
+	creds.Domain, err = resolveDomain(creds)
+	if err != nil {
+		return nil, err
 	}
 	if creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" &&
 		creds.Username != "" && creds.Password != "" && creds.SecurityToken != "" {
@@ -214,17 +389,20 @@ func Init(creds Creds) (*Salesforce, error) {
 			creds.SecurityToken,
 			creds.ConsumerKey,
 			creds.ConsumerSecret,
+			config,
 		)
 	} else if creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" {
 		auth, err = clientCredentialsFlow(
 			creds.Domain,
 			creds.ConsumerKey,
 			creds.ConsumerSecret,
+			config,
 		)
 	} else if creds.AccessToken != "" {
 		auth, err = setAccessToken(
 			creds.Domain,
 			creds.AccessToken,
+			config,
 		)
 	} else if creds.Domain != "" && creds.Username != "" &&
 		creds.ConsumerKey != "" && creds.ConsumerRSAPem != "" {
@@ -234,6 +412,7 @@ func Init(creds Creds) (*Salesforce, error) {
 			creds.ConsumerKey,
 			creds.ConsumerRSAPem,
 			JwtExpirationTime,
+			config,
 		)
 	}
 
@@ -246,17 +425,71 @@ func Init(creds Creds) (*Salesforce, error) {
 	return &Salesforce{auth: auth}, nil
 }
 
-func (sf *Salesforce) DoRequest(method string, uri string, body []byte) (*http.Response, error) {
+func NewFromToken(instanceUrl string, accessToken string, opts ...Option) (*Salesforce, error) {
+	config, err := newConfiguration(opts...)
+	if err != nil {
+		return nil, err
+	}
+	instanceUrl, err = normalizeDomain(instanceUrl)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := setAccessToken(instanceUrl, accessToken, config)
+	if err != nil {
+		return nil, err
+	}
+	auth.creds = Creds{Domain: instanceUrl, AccessToken: accessToken}
+	return &Salesforce{auth: auth}, nil
+}
+
+func (sf *Salesforce) WithToken(accessToken string) *Salesforce {
+	if sf.auth == nil {
+		return &Salesforce{auth: &authentication{AccessToken: accessToken, grantType: grantTypeAccessToken, lock: &sessionLock{}}}
+	}
+	instanceUrl, _ := sf.auth.session()
+	return &Salesforce{auth: &authentication{
+		AccessToken: accessToken,
+		InstanceUrl: instanceUrl,
+		grantType:   grantTypeAccessToken,
+		creds:       Creds{Domain: instanceUrl, AccessToken: accessToken},
+		config:      sf.auth.config.forToken(),
+		lock:        &sessionLock{},
+	}}
+}
+
+// clients made with WithToken share the parent's http client and limits, but not its token provider,
+// which would swap the caller's token for the provider's on the first expired session.
+// the built in query and metadata caches are replaced with empty ones, custom caches are shared since entries are scoped by user
+func (config *configuration) forToken() *configuration {
+	if config == nil {
+		return nil
+	}
+	derived := *config
+	derived.tokenProvider = nil
+	if _, ok := config.queryCache.(*memoryQueryCache); ok {
+		derived.queryCache = NewMemoryQueryCache()
+	}
+	if _, ok := config.metadataCache.(*memoryMetadataCache); ok {
+		derived.metadataCache = NewMemoryMetadataCache()
+	}
+	return &derived
+}
+
+func (sf *Salesforce) DoRequest(method string, uri string, body []byte, opts ...RequestOption) (*http.Response, error) {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return nil, authErr
 	}
 
+	options := newDMLOptions(opts...)
 	resp, err := doRequest(sf.auth, requestPayload{
 		method:  method,
 		uri:     uri,
 		content: jsonType,
 		body:    string(body),
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
 	})
 	if err != nil {
 		return nil, err
@@ -265,13 +498,31 @@ func (sf *Salesforce) DoRequest(method string, uri string, body []byte) (*http.R
 	return resp, nil
 }
 
-func (sf *Salesforce) Query(query string, sObject any) error {
+func (sf *Salesforce) QueryPaged(query string, pageSize int, opts ...RequestOption) (*QueryPager, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return newQueryPager(sf.auth, query, pageSize, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) Paginate(uri string, opts ...RequestOption) (*Paginator, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return newPaginator(sf.auth, uri, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) Query(query string, sObject any, opts ...RequestOption) error {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return authErr
 	}
 
-	queryErr := performQuery(sf.auth, query, sObject)
+	queryErr := performQuery(sf.auth, query, sObject, newDMLOptions(opts...))
 	if queryErr != nil {
 		return queryErr
 	}
@@ -279,7 +530,29 @@ func (sf *Salesforce) Query(query string, sObject any) error {
 	return nil
 }
 
-func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
+func (sf *Salesforce) QueryAggregate(query string, result any, opts ...RequestOption) error {
+	validationErr := validateRetrieve(*sf, result)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return performAggregateQuery(sf.auth, query, result, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) QueryWith(query string, params map[string]any, sObject any, opts ...RequestOption) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	boundQuery, bindErr := bindQueryParams(query, params)
+	if bindErr != nil {
+		return bindErr
+	}
+
+	return performQuery(sf.auth, boundQuery, sObject, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any, opts ...RequestOption) error {
 	validationErr := validateGoSoql(*sf, soqlStruct)
 	if validationErr != nil {
 		return validationErr
@@ -289,7 +562,7 @@ func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
 	if err != nil {
 		return err
 	}
-	queryErr := performQuery(sf.auth, soqlQuery, sObject)
+	queryErr := performQuery(sf.auth, soqlQuery, sObject, newDMLOptions(opts...))
 	if queryErr != nil {
 		return queryErr
 	}
@@ -297,120 +570,253 @@ func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
 	return nil
 }
 
-func (sf *Salesforce) InsertOne(sObjectName string, record any) (SalesforceResult, error) {
+func (sf *Salesforce) GetOne(sObjectName string, recordId string, fields []string, result any) error {
+	validationErr := validateRetrieve(*sf, result)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return doGetOne(sf.auth, sObjectName, recordId, fields, result)
+}
+
+func (sf *Salesforce) GetByExternalId(sObjectName string, externalIdFieldName string, externalIdValue string, result any) error {
+	validationErr := validateRetrieve(*sf, result)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return doGetByExternalId(sf.auth, sObjectName, externalIdFieldName, externalIdValue, result)
+}
+
+func (sf *Salesforce) GetCollection(sObjectName string, ids []string, fields []string, result any) error {
+	validationErr := validateRetrieve(*sf, result)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return doGetCollection(sf.auth, sObjectName, ids, fields, result)
+}
+
+func (sf *Salesforce) RefreshCollection(sObjectName string, records any, fields []string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doRefreshCollection(sf.auth, sObjectName, records, fields)
+}
+
+func (sf *Salesforce) SObject(sObjectName string) *SObjectHandle {
+	handle := &SObjectHandle{sf: sf, name: sObjectName}
+	if sObjectName == "" {
+		handle.err = errors.New("sObject name is required")
+	} else {
+		handle.err = validateSoqlName(sObjectName)
+	}
+	return handle
+}
+
+func (sf *Salesforce) InsertOne(sObjectName string, record any, opts ...DMLOption) (SalesforceResult, error) {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return SalesforceResult{}, validationErr
 	}
 
-	return doInsertOne(sf.auth, sObjectName, record)
+	return doInsertOne(sf.auth, sObjectName, record, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) UpdateOne(sObjectName string, record any) error {
+func (sf *Salesforce) UpdateOne(sObjectName string, record any, opts ...DMLOption) error {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return validationErr
 	}
 
-	return doUpdateOne(sf.auth, sObjectName, record)
+	return doUpdateOne(sf.auth, sObjectName, record, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) UpsertOne(sObjectName string, externalIdFieldName string, record any) (SalesforceResult, error) {
+func (sf *Salesforce) UpsertOne(sObjectName string, externalIdFieldName string, record any, opts ...DMLOption) (SalesforceResult, error) {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return SalesforceResult{}, validationErr
 	}
 
-	return doUpsertOne(sf.auth, sObjectName, externalIdFieldName, record)
+	return doUpsertOne(sf.auth, sObjectName, externalIdFieldName, record, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) DeleteOne(sObjectName string, record any) error {
+func (sf *Salesforce) DeleteOne(sObjectName string, record any, opts ...DMLOption) error {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return validationErr
 	}
 
-	return doDeleteOne(sf.auth, sObjectName, record)
+	return doDeleteOne(sf.auth, sObjectName, record, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) DeleteOneWithSnapshot(sObjectName string, record any, fields []string, opts ...DMLOption) (map[string]any, error) {
+	validationErr := validateSingles(*sf, record)
+	if validationErr != nil {
+		return nil, validationErr
+	}
+
+	return doDeleteOneWithSnapshot(sf.auth, sObjectName, record, fields, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) PublishEvent(eventName string, payload any) (SalesforceResult, error) {
+	validationErr := validateSingles(*sf, payload)
+	if validationErr != nil {
+		return SalesforceResult{}, validationErr
+	}
+
+	return doInsertOne(sf.auth, eventName, payload, dmlOptions{})
+}
+
+func (sf *Salesforce) Subscribe(ctx context.Context, channel string, replayId int64, handler func(StreamingEvent) error) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if handler == nil {
+		return errors.New("event handler is nil")
+	}
+
+	return doSubscribe(ctx, sf.auth, channel, replayId, handler)
 }
 
-func (sf *Salesforce) InsertCollection(sObjectName string, records any, batchSize int) (SalesforceResults, error) {
+func (sf *Salesforce) InsertCollection(sObjectName string, records any, batchSize int, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doInsertCollection(sf.auth, sObjectName, records, batchSize)
+	return doInsertCollection(sf.auth, sObjectName, records, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) UpdateCollection(sObjectName string, records any, batchSize int) (SalesforceResults, error) {
+func (sf *Salesforce) UpdateCollection(sObjectName string, records any, batchSize int, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpdateCollection(sf.auth, sObjectName, records, batchSize)
+	return doUpdateCollection(sf.auth, sObjectName, records, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) UpsertCollection(sObjectName string, externalIdFieldName string, records any, batchSize int) (SalesforceResults, error) {
+func (sf *Salesforce) UpsertCollection(sObjectName string, externalIdFieldName string, records any, batchSize int, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpsertCollection(sf.auth, sObjectName, externalIdFieldName, records, batchSize)
+	return doUpsertCollection(sf.auth, sObjectName, externalIdFieldName, records, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) DeleteCollection(sObjectName string, records any, batchSize int) (SalesforceResults, error) {
+func (sf *Salesforce) DeleteCollection(sObjectName string, records any, batchSize int, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doDeleteCollection(sf.auth, sObjectName, records, batchSize)
+	return doDeleteCollection(sf.auth, sObjectName, records, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) InsertComposite(sObjectName string, records any, batchSize int, allOrNone bool) (SalesforceResults, error) {
+func (sf *Salesforce) InsertComposite(sObjectName string, records any, batchSize int, allOrNone bool, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doInsertComposite(sf.auth, sObjectName, records, allOrNone, batchSize)
+	return doInsertComposite(sf.auth, sObjectName, records, allOrNone, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) UpdateComposite(sObjectName string, records any, batchSize int, allOrNone bool) (SalesforceResults, error) {
+func (sf *Salesforce) UpdateComposite(sObjectName string, records any, batchSize int, allOrNone bool, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpdateComposite(sf.auth, sObjectName, records, allOrNone, batchSize)
+	return doUpdateComposite(sf.auth, sObjectName, records, allOrNone, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) UpsertComposite(sObjectName string, externalIdFieldName string, records any, batchSize int, allOrNone bool) (SalesforceResults, error) {
+func (sf *Salesforce) UpsertComposite(sObjectName string, externalIdFieldName string, records any, batchSize int, allOrNone bool, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpsertComposite(sf.auth, sObjectName, externalIdFieldName, records, allOrNone, batchSize)
+	return doUpsertComposite(sf.auth, sObjectName, externalIdFieldName, records, allOrNone, batchSize, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) DeleteComposite(sObjectName string, records any, batchSize int, allOrNone bool) (SalesforceResults, error) {
+func (sf *Salesforce) DeleteComposite(sObjectName string, records any, batchSize int, allOrNone bool, opts ...DMLOption) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doDeleteComposite(sf.auth, sObjectName, records, allOrNone, batchSize)
+	return doDeleteComposite(sf.auth, sObjectName, records, allOrNone, batchSize, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) ExecuteComposite(builder *CompositeBuilder, opts ...RequestOption) (CompositeResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return CompositeResults{}, authErr
+	}
+
+	return doExecuteComposite(sf.auth, builder, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) UpdateByExternalId(sObjectName string, externalIdFieldName string, record any, opts ...DMLOption) error {
+	validationErr := validateSingles(*sf, record)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return doUpdateByExternalId(sf.auth, sObjectName, externalIdFieldName, record, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) DeleteByExternalId(sObjectName string, externalIdFieldName string, record any, opts ...DMLOption) error {
+	validationErr := validateSingles(*sf, record)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return doDeleteByExternalId(sf.auth, sObjectName, externalIdFieldName, record, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) UpdateCollectionByExternalId(sObjectName string, externalIdFieldName string, records any, opts ...DMLOption) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	typErr := validateOfTypeSlice(records)
+	if typErr != nil {
+		return SalesforceResults{}, typErr
+	}
+
+	return doExternalIdCollection(sf.auth, sObjectName, externalIdFieldName, records, true, newDMLOptions(opts...))
+}
+
+func (sf *Salesforce) DeleteCollectionByExternalId(sObjectName string, externalIdFieldName string, records any, opts ...DMLOption) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	typErr := validateOfTypeSlice(records)
+	if typErr != nil {
+		return SalesforceResults{}, typErr
+	}
+
+	return doExternalIdCollection(sf.auth, sObjectName, externalIdFieldName, records, false, newDMLOptions(opts...))
 }
 
-func (sf *Salesforce) QueryBulkExport(query string, filePath string) error {
+func (sf *Salesforce) QueryBulkExport(query string, filePath string, opts ...BulkQueryOptions) error {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return authErr
 	}
-	queryErr := doQueryBulk(sf.auth, filePath, query)
+	options, optionsErr := newBulkQueryOptions(opts...)
+	if optionsErr != nil {
+		return optionsErr
+	}
+	queryErr := doQueryBulk(sf.auth, filePath, query, options)
 	if queryErr != nil {
 		return queryErr
 	}
@@ -418,17 +824,37 @@ func (sf *Salesforce) QueryBulkExport(query string, filePath string) error {
 	return nil
 }
 
-func (sf *Salesforce) QueryStructBulkExport(soqlStruct any, filePath string) error {
+func (sf *Salesforce) QueryBulkExportJSON(query string, filePath string, opts ...BulkQueryOptions) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	options, optionsErr := newBulkQueryOptions(opts...)
+	if optionsErr != nil {
+		return optionsErr
+	}
+	if options.Format != ExportFormatJSONLines {
+		options.Format = ExportFormatJSON
+	}
+
+	return doQueryBulk(sf.auth, filePath, query, options)
+}
+
+func (sf *Salesforce) QueryStructBulkExport(soqlStruct any, filePath string, opts ...BulkQueryOptions) error {
 	validationErr := validateGoSoql(*sf, soqlStruct)
 	if validationErr != nil {
 		return validationErr
 	}
+	options, optionsErr := newBulkQueryOptions(opts...)
+	if optionsErr != nil {
+		return optionsErr
+	}
 
 	soqlQuery, err := soql.Marshal(soqlStruct)
 	if err != nil {
 		return err
 	}
-	queryErr := doQueryBulk(sf.auth, filePath, soqlQuery)
+	queryErr := doQueryBulk(sf.auth, filePath, soqlQuery, options)
 	if queryErr != nil {
 		return queryErr
 	}
@@ -436,38 +862,33 @@ func (sf *Salesforce) QueryStructBulkExport(soqlStruct any, filePath string) err
 	return nil
 }
 
-func (sf *Salesforce) QueryBulkIterator(query string) (IteratorJob, error) {
+func (sf *Salesforce) QueryBulkIterator(query string, opts ...BulkQueryOptions) (IteratorJob, error) {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return nil, authErr
 	}
-	queryJobReq := bulkQueryJobCreationRequest{
-		Operation: queryJobType,
-		Query:     query,
-	}
-	body, jsonErr := json.Marshal(queryJobReq)
-	if jsonErr != nil {
-		return nil, jsonErr
+	options, optionsErr := newBulkQueryOptions(opts...)
+	if optionsErr != nil {
+		return nil, optionsErr
 	}
-
-	job, jobCreationErr := createBulkJob(sf.auth, queryJobType, body)
+	job, jobCreationErr := createBulkQueryJob(sf.auth, query, options)
 	if jobCreationErr != nil {
 		return nil, jobCreationErr
 	}
-	if job.Id == "" {
-		newErr := errors.New("error creating bulk query job")
-		return nil, newErr
-	}
-	return newBulkJobQueryIterator(sf.auth, job.Id)
+	return newBulkJobQueryIterator(sf.auth, job.Id, options)
 }
 
-func (sf *Salesforce) InsertBulk(sObjectName string, records any, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) InsertBulk(sObjectName string, records any, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, records, batchSize, false)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, "", insertOperation, records, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, "", insertOperation, records, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -475,13 +896,17 @@ func (sf *Salesforce) InsertBulk(sObjectName string, records any, batchSize int,
 	return jobIds, nil
 }
 
-func (sf *Salesforce) InsertBulkFile(sObjectName string, filePath string, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) InsertBulkFile(sObjectName string, filePath string, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, nil, batchSize, true)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, "", insertOperation, filePath, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, "", insertOperation, filePath, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -489,13 +914,17 @@ func (sf *Salesforce) InsertBulkFile(sObjectName string, filePath string, batchS
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpdateBulk(sObjectName string, records any, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) UpdateBulk(sObjectName string, records any, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, records, batchSize, false)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, "", updateOperation, records, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, "", updateOperation, records, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -503,13 +932,17 @@ func (sf *Salesforce) UpdateBulk(sObjectName string, records any, batchSize int,
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpdateBulkFile(sObjectName string, filePath string, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) UpdateBulkFile(sObjectName string, filePath string, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, nil, batchSize, true)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, "", updateOperation, filePath, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, "", updateOperation, filePath, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -517,13 +950,17 @@ func (sf *Salesforce) UpdateBulkFile(sObjectName string, filePath string, batchS
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpsertBulk(sObjectName string, externalIdFieldName string, records any, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) UpsertBulk(sObjectName string, externalIdFieldName string, records any, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, records, batchSize, false)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, externalIdFieldName, upsertOperation, records, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, externalIdFieldName, upsertOperation, records, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -531,13 +968,17 @@ func (sf *Salesforce) UpsertBulk(sObjectName string, externalIdFieldName string,
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpsertBulkFile(sObjectName string, externalIdFieldName string, filePath string, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) UpsertBulkFile(sObjectName string, externalIdFieldName string, filePath string, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, nil, batchSize, true)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, externalIdFieldName, upsertOperation, filePath, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, externalIdFieldName, upsertOperation, filePath, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -545,13 +986,17 @@ func (sf *Salesforce) UpsertBulkFile(sObjectName string, externalIdFieldName str
 	return jobIds, nil
 }
 
-func (sf *Salesforce) DeleteBulk(sObjectName string, records any, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) DeleteBulk(sObjectName string, records any, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, records, batchSize, false)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, "", deleteOperation, records, batchSize, waitForResults)
+	jobIds, bulkErr := doBulkJob(sf.auth, sObjectName, "", bulkDeleteOperation(options), records, batchSize, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -559,13 +1004,35 @@ func (sf *Salesforce) DeleteBulk(sObjectName string, records any, batchSize int,
 	return jobIds, nil
 }
 
-func (sf *Salesforce) DeleteBulkFile(sObjectName string, filePath string, batchSize int, waitForResults bool) ([]string, error) {
+func (sf *Salesforce) DeleteBulkFile(sObjectName string, filePath string, batchSize int, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
 	validationErr := validateBulk(*sf, nil, batchSize, true)
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
+
+	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, "", bulkDeleteOperation(options), filePath, batchSize, waitForResults, options)
+	if bulkErr != nil {
+		return []string{}, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) ResumeBulkLoad(plan *BulkLoadPlan, records any, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
+	validationErr := validateBulk(*sf, records, 1, false)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
 
-	jobIds, bulkErr := doBulkJobWithFile(sf.auth, sObjectName, "", deleteOperation, filePath, batchSize, waitForResults)
+	jobIds, bulkErr := doResumeBulkLoad(sf.auth, plan, records, waitForResults, options)
 	if bulkErr != nil {
 		return []string{}, bulkErr
 	}
@@ -573,30 +1040,421 @@ func (sf *Salesforce) DeleteBulkFile(sObjectName string, filePath string, batchS
 	return jobIds, nil
 }
 
-func (sf *Salesforce) GetJobResults(bulkJobId string) (BulkJobResults, error) {
+func (sf *Salesforce) ResumeBulkLoadFile(plan *BulkLoadPlan, filePath string, waitForResults bool, opts ...BulkJobOptions) ([]string, error) {
+	validationErr := validateBulk(*sf, nil, 1, true)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	options, optionsErr := newBulkIngestJobOptions(opts...)
+	if optionsErr != nil {
+		return []string{}, optionsErr
+	}
+
+	jobIds, bulkErr := doResumeBulkLoadFile(sf.auth, plan, filePath, waitForResults, options)
+	if bulkErr != nil {
+		return []string{}, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) GetJobResults(bulkJobId string, opts ...BulkJobOptions) (BulkJobResults, error) {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return BulkJobResults{}, authErr
 	}
+	options, optionsErr := newBulkJobOptions(opts...)
+	if optionsErr != nil {
+		return BulkJobResults{}, optionsErr
+	}
 
-	job, err := getJobResults(sf.auth, ingestJobType, bulkJobId)
-	if err != nil {
-		return BulkJobResults{}, err
+	return doGetJobResults(sf.auth, bulkJobId, options)
+}
+
+func (sf *Salesforce) GetJobResultsInto(bulkJobId string, successful any, failed any, opts ...BulkJobOptions) (BulkJobResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return BulkJobResults{}, authErr
+	}
+	options, optionsErr := newBulkJobOptions(opts...)
+	if optionsErr != nil {
+		return BulkJobResults{}, optionsErr
 	}
 
-	if job.State == jobStateJobComplete {
-		job, err = getJobRecordResults(sf.auth, job)
-		if err != nil {
-			return job, err
+	return doGetJobResultsInto(sf.auth, bulkJobId, successful, failed, options)
+}
+
+func (sf *Salesforce) WatchJob(ctx context.Context, bulkJobId string) (<-chan BulkJobEvent, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return doWatchJob(ctx, sf.auth, bulkJobId)
+}
+
+func (sf *Salesforce) GetJobBatches(bulkJobId string) ([]BulkJobBatch, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return doGetJobBatches(sf.auth, bulkJobId)
+}
+
+func (sf *Salesforce) SaveJobRecordResults(bulkJobId string, successPath string, failedPath string, unprocessedPath string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if successPath == "" && failedPath == "" && unprocessedPath == "" {
+		return errors.New("at least one file path is required")
+	}
+
+	return doSaveJobRecordResults(sf.auth, bulkJobId, successPath, failedPath, unprocessedPath)
+}
+
+func (sf *Salesforce) GetAllJobs(jobType string, opts ...BulkJobFilter) ([]BulkJobSummary, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if jobType != ingestJobType && jobType != queryJobType {
+		return nil, errors.New("job type must be one of: " + ingestJobType + ", " + queryJobType)
+	}
+
+	filter := BulkJobFilter{}
+	for _, opt := range opts {
+		if opt.JobType != "" {
+			filter.JobType = opt.JobType
 		}
+		if opt.ConcurrencyMode != "" {
+			filter.ConcurrencyMode = opt.ConcurrencyMode
+		}
+		filter.IsPkChunkingEnabled = filter.IsPkChunkingEnabled || opt.IsPkChunkingEnabled
+	}
+
+	return getAllJobs(sf.auth, jobType, filter)
+}
+
+func (sf *Salesforce) AbortJob(bulkJobId string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return updateJobState(bulkJob{Id: bulkJobId}, BulkJobStateAborted, sf.auth)
+}
+
+func (sf *Salesforce) DeleteJob(bulkJobId string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return deleteBulkJob(sf.auth, bulkJobId)
+}
+
+func (sf *Salesforce) UserInfo() (UserInfo, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return UserInfo{}, authErr
+	}
+
+	return doGetUserInfo(sf.auth)
+}
+
+func (sf *Salesforce) GetPicklistValues(sObjectName string, recordTypeId string, fieldName string) (PicklistValues, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return PicklistValues{}, authErr
+	}
+
+	return doGetPicklistValues(sf.auth, sObjectName, recordTypeId, fieldName)
+}
+
+func (sf *Salesforce) DescribeSObject(sObjectName string) (SObjectDescribe, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SObjectDescribe{}, authErr
+	}
+
+	return doDescribeSObject(sf.auth, sObjectName)
+}
+
+func (sf *Salesforce) CanCreateObject(sObjectName string) (bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return false, authErr
+	}
+
+	return doCanCreateObject(sf.auth, sObjectName)
+}
+
+func (sf *Salesforce) CanUpdateObject(sObjectName string) (bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return false, authErr
+	}
+
+	return doCanUpdateObject(sf.auth, sObjectName)
+}
+
+func (sf *Salesforce) CanDeleteObject(sObjectName string) (bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return false, authErr
+	}
+
+	return doCanDeleteObject(sf.auth, sObjectName)
+}
+
+func (sf *Salesforce) FieldAccessible(sObjectName string, fieldName string) (bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return false, authErr
+	}
+
+	return doFieldAccessible(sf.auth, sObjectName, fieldName)
+}
+
+func (sf *Salesforce) InvalidateMetadataCache(sObjectNames ...string) {
+	invalidateMetadataCache(sf.auth, sObjectNames)
+}
+
+func (sf *Salesforce) InvalidateQueryCache(queries ...string) {
+	invalidateQueryCache(sf.auth, queries)
+}
+
+func (sf *Salesforce) GetListViews(sObjectName string) ([]ListView, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return doGetListViews(sf.auth, sObjectName)
+}
+
+func (sf *Salesforce) GetListViewRecords(sObjectName string, listViewId string, result any) error {
+	validationErr := validateRetrieve(*sf, result)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return doGetListViewRecords(sf.auth, sObjectName, listViewId, result)
+}
+
+func (sf *Salesforce) RecentlyViewed(limit int) ([]RecentlyViewedRecord, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return doRecentlyViewed(sf.auth, limit)
+}
+
+func (sf *Salesforce) Undelete(ids []string) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+
+	return doUndelete(sf.auth, ids)
+}
+
+func (sf *Salesforce) EmptyRecycleBin(ids []string) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
 	}
 
-	return job, nil
+	return doEmptyRecycleBin(sf.auth, ids)
+}
+
+func (sf *Salesforce) ExecuteAnonymousApex(code string) (ExecuteAnonymousResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ExecuteAnonymousResult{}, authErr
+	}
+
+	return doExecuteAnonymousApex(sf.auth, code)
+}
+
+func (sf *Salesforce) PostFeedElement(subjectId string, text string, mentions []string) (FeedElement, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return FeedElement{}, authErr
+	}
+
+	return doPostFeedElement(sf.auth, subjectId, text, mentions)
+}
+
+func (sf *Salesforce) PostFeedComment(feedElementId string, text string, mentions []string) (FeedComment, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return FeedComment{}, authErr
+	}
+
+	return doPostFeedComment(sf.auth, feedElementId, text, mentions)
+}
+
+func (sf *Salesforce) GetFeedElements(subjectId string) ([]FeedElement, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return doGetFeedElements(sf.auth, subjectId)
+}
+
+func (sf *Salesforce) DeleteFeedElement(feedElementId string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doDeleteFeedElement(sf.auth, feedElementId)
+}
+
+func (sf *Salesforce) SendCustomNotification(notification CustomNotification) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doSendCustomNotification(sf.auth, notification)
+}
+
+func (sf *Salesforce) SendEmail(message EmailMessage) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doSendEmail(sf.auth, message)
+}
+
+func (sf *Salesforce) CanUpdate(recordIds []string) ([]string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return []string{}, authErr
+	}
+
+	inaccessibleIds, err := doCanUpdate(sf.auth, recordIds)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return inaccessibleIds, nil
+}
+
+func (sf *Salesforce) DeployMetadata(zipFile []byte, options DeployOptions) (DeployResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return DeployResult{}, authErr
+	}
+
+	return doDeployMetadata(sf.auth, zipFile, options)
+}
+
+func (sf *Salesforce) CheckDeployStatus(deployId string) (DeployResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return DeployResult{}, authErr
+	}
+
+	return doCheckDeployStatus(sf.auth, deployId, false)
+}
+
+func (sf *Salesforce) WaitForDeploy(deployId string, interval time.Duration, timeout time.Duration) (DeployResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return DeployResult{}, authErr
+	}
+
+	return doWaitForDeploy(sf.auth, deployId, interval, timeout)
+}
+
+func (sf *Salesforce) RetrieveMetadata(request RetrieveRequest) (RetrieveResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return RetrieveResult{}, authErr
+	}
+
+	return doRetrieveMetadata(sf.auth, request)
+}
+
+func (sf *Salesforce) CheckRetrieveStatus(retrieveId string) (RetrieveResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return RetrieveResult{}, authErr
+	}
+
+	return doCheckRetrieveStatus(sf.auth, retrieveId, false)
+}
+
+func (sf *Salesforce) WaitForRetrieve(retrieveId string, interval time.Duration, timeout time.Duration) (RetrieveResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return RetrieveResult{}, authErr
+	}
+
+	return doWaitForRetrieve(sf.auth, retrieveId, interval, timeout)
+}
+
+func (sf *Salesforce) RunReport(reportId string, filters ...ReportFilter) (ReportResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ReportResult{}, authErr
+	}
+
+	return doRunReport(sf.auth, reportId, filters)
+}
+
+func (sf *Salesforce) RunReportAsync(reportId string, filters ...ReportFilter) (ReportInstance, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ReportInstance{}, authErr
+	}
+
+	return doRunReportAsync(sf.auth, reportId, filters)
+}
+
+func (sf *Salesforce) GetReportData(reportId string, instanceId string) (ReportResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ReportResult{}, authErr
+	}
+
+	return doGetReportData(sf.auth, reportId, instanceId)
 }
 
 func (sf *Salesforce) GetAccessToken() string {
 	if sf.auth == nil {
 		return ""
 	}
-	return sf.auth.AccessToken
+	_, accessToken := sf.auth.session()
+	return accessToken
+}
+
+func (sf *Salesforce) Ping(ctx context.Context) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doPing(ctx, sf.auth)
+}
+
+func (sf *Salesforce) TokenInfo() (TokenInfo, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return TokenInfo{}, authErr
+	}
+
+	return doGetTokenInfo(sf.auth)
+}
+
+func (sf *Salesforce) Capabilities() Capabilities {
+	return getCapabilities(sf.auth)
 }
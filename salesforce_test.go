@@ -1,7 +1,9 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -9,7 +11,9 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -114,6 +118,40 @@ func Test_doRequest(t *testing.T) {
 	}
 }
 
+func Test_newMultipleMatchesError(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseData string
+		want         []string
+	}{
+		{
+			name:         "record_urls",
+			responseData: `["/services/data/v62.0/sobjects/Contact/003A","/services/data/v62.0/sobjects/Contact/003B"]`,
+			want:         []string{"003A", "003B"},
+		},
+		{
+			name:         "unexpected_body",
+			responseData: `{"message":"ambiguous"}`,
+			want:         nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newMultipleMatchesError([]byte(tt.responseData))
+			if !errors.Is(err, ErrMultipleMatches) {
+				t.Fatalf("newMultipleMatchesError() = %v, want ErrMultipleMatches", err)
+			}
+			var matches *MultipleMatchesError
+			if errors.As(err, &matches) != (tt.want != nil) {
+				t.Fatalf("newMultipleMatchesError() = %#v", err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(matches.RecordIds, tt.want) {
+				t.Errorf("newMultipleMatchesError() record ids = %v, want %v", matches.RecordIds, tt.want)
+			}
+		})
+	}
+}
+
 func Test_validateOfTypeSlice(t *testing.T) {
 	type args struct {
 		data any
@@ -399,7 +437,7 @@ func Test_processSalesforceError(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processSalesforceError(tt.args.resp, tt.args.auth, tt.args.payload)
+			got, err := processSalesforceError(tt.args.resp, tt.args.auth, tt.args.payload, tt.args.auth.AccessToken)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processSalesforceError() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -419,6 +457,7 @@ func TestInit(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeUsernamePassword,
+		lock:        &sessionLock{},
 	}
 	serverUsernamePassword, _ := setupTestServer(sfAuthUsernamePassword, http.StatusOK)
 	defer serverUsernamePassword.Close()
@@ -431,6 +470,7 @@ func TestInit(t *testing.T) {
 		ConsumerSecret: "secret",
 	}
 	sfAuthUsernamePassword.creds = credsUsernamePassword
+	sfAuthUsernamePassword.config = &configuration{}
 
 	sfAuthClientCredentials := authentication{
 		AccessToken: "1234",
@@ -439,6 +479,7 @@ func TestInit(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeClientCredentials,
+		lock:        &sessionLock{},
 	}
 	serverClientCredentials, _ := setupTestServer(sfAuthClientCredentials, http.StatusOK)
 	defer serverClientCredentials.Close()
@@ -448,6 +489,7 @@ func TestInit(t *testing.T) {
 		ConsumerSecret: "secret",
 	}
 	sfAuthClientCredentials.creds = credsClientCredentials
+	sfAuthClientCredentials.config = &configuration{}
 
 	sfAuthAccessToken := authentication{
 		AccessToken: "1234",
@@ -536,6 +578,110 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestNewFromToken(t *testing.T) {
+	server, _ := setupTestServer("", http.StatusOK)
+	defer server.Close()
+
+	badServer, _ := setupTestServer("", http.StatusUnauthorized)
+	defer badServer.Close()
+
+	type args struct {
+		instanceUrl string
+		accessToken string
+		opts        []Option
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "validated_token",
+			args: args{
+				instanceUrl: server.URL,
+				accessToken: "1234",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid_token",
+			args: args{
+				instanceUrl: badServer.URL,
+				accessToken: "1234",
+			},
+			wantErr: true,
+		},
+		{
+			name: "skip_validation",
+			args: args{
+				instanceUrl: badServer.URL,
+				accessToken: "1234",
+				opts:        []Option{WithoutSessionValidation()},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty_token",
+			args: args{
+				instanceUrl: server.URL,
+				accessToken: "",
+				opts:        []Option{WithoutSessionValidation()},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad_option",
+			args: args{
+				instanceUrl: server.URL,
+				accessToken: "1234",
+				opts:        []Option{WithHTTPClient(nil)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFromToken(tt.args.instanceUrl, tt.args.accessToken, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.GetAccessToken() != tt.args.accessToken {
+				t.Errorf("NewFromToken() token = %v, want %v", got.GetAccessToken(), tt.args.accessToken)
+			}
+		})
+	}
+}
+
+func TestSalesforce_WithToken(t *testing.T) {
+	client := &http.Client{}
+	sf := &Salesforce{auth: &authentication{
+		AccessToken: "1234",
+		InstanceUrl: "example.com",
+		grantType:   grantTypeClientCredentials,
+		config:      &configuration{httpClient: client},
+	}}
+
+	got := sf.WithToken("5678")
+	if got.GetAccessToken() != "5678" {
+		t.Errorf("Salesforce.WithToken() token = %v, want %v", got.GetAccessToken(), "5678")
+	}
+	if got.auth.InstanceUrl != sf.auth.InstanceUrl {
+		t.Errorf("Salesforce.WithToken() instance url = %v, want %v", got.auth.InstanceUrl, sf.auth.InstanceUrl)
+	}
+	if got.auth.config.httpClient != client {
+		t.Errorf("Salesforce.WithToken() did not share the http client")
+	}
+	if sf.GetAccessToken() != "1234" {
+		t.Errorf("Salesforce.WithToken() modified original token")
+	}
+
+	empty := (&Salesforce{}).WithToken("5678")
+	if empty.GetAccessToken() != "5678" {
+		t.Errorf("Salesforce.WithToken() token = %v, want %v", empty.GetAccessToken(), "5678")
+	}
+}
+
 func Test_validateSingles(t *testing.T) {
 	type account struct{}
 
@@ -804,6 +950,139 @@ func TestSalesforce_DoRequest(t *testing.T) {
 	}
 }
 
+func TestSalesforce_DoRequest_headers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") != "Tue, 01 Oct 2024 00:00:00 GMT" || r.Header.Get("Sforce-Call-Options") != "client=sync" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	sf := &Salesforce{auth: &sfAuth}
+	resp, err := sf.DoRequest(http.MethodGet, "/sobjects/Account/describe", nil,
+		WithHeader("If-Modified-Since", "Tue, 01 Oct 2024 00:00:00 GMT"),
+		WithHeaders(map[string]string{"Sforce-Call-Options": "client=sync"}),
+	)
+	if err != nil {
+		t.Fatalf("Salesforce.DoRequest() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("Salesforce.DoRequest() status = %d, want %d", resp.StatusCode, http.StatusNotModified)
+	}
+}
+
+func TestSalesforce_DoRequest_timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	client := &http.Client{Timeout: time.Minute}
+	sf := &Salesforce{auth: &authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      &configuration{httpClient: client},
+	}}
+
+	start := time.Now()
+	if _, err := sf.DoRequest(http.MethodGet, "/limits", nil, WithTimeout(50*time.Millisecond)); err == nil {
+		t.Errorf("Salesforce.DoRequest() expected timeout error")
+	}
+	records := []map[string]any{}
+	if err := sf.Query("SELECT Id FROM Account", &records, WithTimeout(50*time.Millisecond)); err == nil {
+		t.Errorf("Salesforce.Query() expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("requests took %v, want them to stop after the request timeout", elapsed)
+	}
+	if client.Timeout != time.Minute {
+		t.Errorf("client timeout = %v, want it left at %v", client.Timeout, time.Minute)
+	}
+}
+
+func TestSalesforce_QueryAggregate(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"attributes": map[string]any{"type": "AggregateResult"}, "expr0": 5}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	results := []AggregateResult{}
+	if err := sf.QueryAggregate("SELECT COUNT(Id) FROM Account", &results); err != nil {
+		t.Fatalf("Salesforce.QueryAggregate() error = %v", err)
+	}
+	if !reflect.DeepEqual(results, []AggregateResult{{"expr0": float64(5)}}) {
+		t.Errorf("Salesforce.QueryAggregate() = %v", results)
+	}
+
+	if err := sf.QueryAggregate("SELECT COUNT(Id) FROM Account", results); err == nil {
+		t.Errorf("Salesforce.QueryAggregate() expected error for non-pointer result")
+	}
+	sf = &Salesforce{}
+	if err := sf.QueryAggregate("SELECT COUNT(Id) FROM Account", &results); err == nil {
+		t.Errorf("Salesforce.QueryAggregate() expected validation error")
+	}
+}
+
+func TestSalesforce_QueryWith(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string
+	}
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("q")
+		body, _ := json.Marshal(queryResponse{
+			TotalSize: 1,
+			Done:      true,
+			Records:   []map[string]any{{"Id": "123abc", "Name": "O'Brien"}},
+		})
+		if _, err := w.Write(body); err != nil {
+			t.Error(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	sf := &Salesforce{auth: &sfAuth}
+	accounts := []account{}
+	params := map[string]any{"name": "O'Brien", "ids": []string{"123abc"}}
+	if err := sf.QueryWith("SELECT Id, Name FROM Account WHERE Name = :name AND Id IN :ids", params, &accounts); err != nil {
+		t.Fatalf("Salesforce.QueryWith() error = %v", err)
+	}
+	wantQuery := `SELECT Id, Name FROM Account WHERE Name = 'O\'Brien' AND Id IN ('123abc')`
+	if gotQuery != wantQuery {
+		t.Errorf("Salesforce.QueryWith() sent %v, want %v", gotQuery, wantQuery)
+	}
+	if !reflect.DeepEqual(accounts, []account{{Id: "123abc", Name: "O'Brien"}}) {
+		t.Errorf("Salesforce.QueryWith() = %v", accounts)
+	}
+
+	if err := sf.QueryWith("SELECT Id FROM Account WHERE Name = :missing", nil, &accounts); err == nil {
+		t.Errorf("Salesforce.QueryWith() expected error for missing bind variable")
+	}
+	sf = &Salesforce{}
+	if err := sf.QueryWith("SELECT Id FROM Account", nil, &accounts); err == nil {
+		t.Errorf("Salesforce.QueryWith() expected validation error")
+	}
+}
+
 func TestSalesforce_Query(t *testing.T) {
 	type account struct {
 		Id   string
@@ -956,9 +1235,10 @@ func TestSalesforce_InsertOne(t *testing.T) {
 	}
 
 	successfulResult := SalesforceResult{
-		Id:      "1234",
-		Errors:  []SalesforceErrorMessage{},
-		Success: true,
+		Id:               "1234",
+		Errors:           []SalesforceErrorMessage{},
+		Success:          true,
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusCreated},
 	}
 
 	server, sfAuth := setupTestServer(successfulResult, http.StatusCreated)
@@ -1100,9 +1380,10 @@ func TestSalesforce_UpsertOne(t *testing.T) {
 	}
 
 	successfulResult := SalesforceResult{
-		Id:      "1234",
-		Errors:  []SalesforceErrorMessage{},
-		Success: true,
+		Id:               "1234",
+		Errors:           []SalesforceErrorMessage{},
+		Success:          true,
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResult, http.StatusOK)
@@ -1184,19 +1465,20 @@ func TestSalesforce_UpsertOne(t *testing.T) {
 	}
 }
 
-func TestSalesforce_DeleteOne(t *testing.T) {
-	type account struct {
-		Id string
+func TestSalesforce_GetOne(t *testing.T) {
+	type contact struct {
+		Id       string
+		LastName string
 	}
-	server, sfAuth := setupTestServer("", http.StatusNoContent)
+	server, sfAuth := setupTestServer(map[string]any{"Id": "003", "LastName": "Stark"}, http.StatusOK)
 	defer server.Close()
 
 	type fields struct {
 		auth *authentication
 	}
 	type args struct {
-		sObjectName string
-		record      any
+		recordId string
+		result   any
 	}
 	tests := []struct {
 		name    string
@@ -1205,15 +1487,13 @@ func TestSalesforce_DeleteOne(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "successful_delete",
+			name: "successful_get",
 			fields: fields{
 				auth: &sfAuth,
 			},
 			args: args{
-				sObjectName: "Account",
-				record: account{
-					Id: "1234",
-				},
+				recordId: "003",
+				result:   &contact{},
 			},
 			wantErr: false,
 		},
@@ -1223,19 +1503,19 @@ func TestSalesforce_DeleteOne(t *testing.T) {
 				auth: &sfAuth,
 			},
 			args: args{
-				sObjectName: "Account",
-				record:      0,
+				recordId: "003",
+				result:   contact{},
 			},
 			wantErr: true,
 		},
 		{
-			name: "fail_no_id",
+			name: "not_authenticated",
 			fields: fields{
-				auth: &sfAuth,
+				auth: nil,
 			},
 			args: args{
-				sObjectName: "Account",
-				record:      account{},
+				recordId: "003",
+				result:   &contact{},
 			},
 			wantErr: true,
 		},
@@ -1245,66 +1525,110 @@ func TestSalesforce_DeleteOne(t *testing.T) {
 			sf := &Salesforce{
 				auth: tt.fields.auth,
 			}
-			if err := sf.DeleteOne(tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
-				t.Errorf("Salesforce.DeleteOne() error = %v, wantErr %v", err, tt.wantErr)
+			if err := sf.GetOne("Contact", tt.args.recordId, []string{"Id", "LastName"}, tt.args.result); (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.GetOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestSalesforce_InsertCollection(t *testing.T) {
-	type account struct {
-		Name string
+func TestSalesforce_GetByExternalId(t *testing.T) {
+	type contact struct {
+		Id       string
+		LastName string
 	}
+	server, sfAuth := setupTestServer(map[string]any{"Id": "003", "LastName": "Stark"}, http.StatusOK)
+	defer server.Close()
 
-	successfulResults := SalesforceResults{
-		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
-		}},
-		HasSalesforceErrors: false,
+	sf := &Salesforce{auth: &sfAuth}
+	got := contact{}
+	if err := sf.GetByExternalId("Contact", "ExternalId__c", "abc", &got); err != nil {
+		t.Fatalf("Salesforce.GetByExternalId() error = %v", err)
+	}
+	want := contact{Id: "003", LastName: "Stark"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Salesforce.GetByExternalId() = %v, want %v", got, want)
+	}
+	if err := sf.GetByExternalId("Contact", "ExternalId__c", "abc", nil); err == nil {
+		t.Error("Salesforce.GetByExternalId() expected an error for a nil result")
 	}
+}
 
-	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
+func TestSalesforce_GetCollection(t *testing.T) {
+	type contact struct {
+		Id       string
+		LastName string
+	}
+	server, sfAuth := setupTestServer([]map[string]any{{"Id": "003", "LastName": "Stark"}}, http.StatusOK)
 	defer server.Close()
 
-	badReqServer, badReqSfAuth := setupTestServer("", http.StatusBadRequest)
-	defer badReqServer.Close()
+	sf := &Salesforce{auth: &sfAuth}
+	got := []contact{}
+	if err := sf.GetCollection("Contact", []string{"003"}, []string{"Id", "LastName"}, &got); err != nil {
+		t.Fatalf("Salesforce.GetCollection() error = %v", err)
+	}
+	want := []contact{{Id: "003", LastName: "Stark"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Salesforce.GetCollection() = %v, want %v", got, want)
+	}
+	if err := sf.GetCollection("Contact", []string{"003"}, []string{"Id"}, nil); err == nil {
+		t.Error("Salesforce.GetCollection() expected an error for a nil result")
+	}
+}
+
+func TestSalesforce_RefreshCollection(t *testing.T) {
+	type contact struct {
+		Id       string
+		LastName string
+	}
+	server, sfAuth := setupTestServer([]map[string]any{{"Id": "003", "LastName": "Stark"}}, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	contacts := []contact{{Id: "003"}}
+	if err := sf.RefreshCollection("Contact", contacts, []string{"LastName"}); err != nil {
+		t.Fatalf("Salesforce.RefreshCollection() error = %v", err)
+	}
+	want := []contact{{Id: "003", LastName: "Stark"}}
+	if !reflect.DeepEqual(contacts, want) {
+		t.Errorf("Salesforce.RefreshCollection() = %v, want %v", contacts, want)
+	}
+	if err := (&Salesforce{}).RefreshCollection("Contact", contacts, []string{"LastName"}); err == nil {
+		t.Error("Salesforce.RefreshCollection() expected validation error")
+	}
+}
+
+func TestSalesforce_DeleteOne(t *testing.T) {
+	type account struct {
+		Id string
+	}
+	server, sfAuth := setupTestServer("", http.StatusNoContent)
+	defer server.Close()
 
 	type fields struct {
 		auth *authentication
 	}
 	type args struct {
 		sObjectName string
-		records     any
-		batchSize   int
+		record      any
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		want    SalesforceResults
 		wantErr bool
 	}{
 		{
-			name: "successful_insert",
+			name: "successful_delete",
 			fields: fields{
 				auth: &sfAuth,
 			},
 			args: args{
 				sObjectName: "Account",
-				records: []account{
-					{
-						Name: "test account 1",
-					},
-					{
-						Name: "test account 2",
-					},
+				record: account{
+					Id: "1234",
 				},
-				batchSize: 200,
 			},
-			want:    successfulResults,
 			wantErr: false,
 		},
 		{
@@ -1314,29 +1638,19 @@ func TestSalesforce_InsertCollection(t *testing.T) {
 			},
 			args: args{
 				sObjectName: "Account",
-				records: account{
-					Name: "test account 1",
-				},
-				batchSize: 200,
+				record:      0,
 			},
-			want:    SalesforceResults{},
 			wantErr: true,
 		},
 		{
-			name: "bad_request",
+			name: "fail_no_id",
 			fields: fields{
-				auth: &badReqSfAuth,
+				auth: &sfAuth,
 			},
 			args: args{
 				sObjectName: "Account",
-				records: []account{
-					{
-						Name: "test account 1",
-					},
-				},
-				batchSize: 200,
+				record:      account{},
 			},
-			want:    SalesforceResults{Results: []SalesforceResult{}},
 			wantErr: true,
 		},
 	}
@@ -1345,48 +1659,247 @@ func TestSalesforce_InsertCollection(t *testing.T) {
 			sf := &Salesforce{
 				auth: tt.fields.auth,
 			}
-			got, err := sf.InsertCollection(tt.args.sObjectName, tt.args.records, tt.args.batchSize)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Salesforce.InsertCollection() error = %v, wantErr %v", err, tt.wantErr)
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Salesforce.InsertCollection() = %v, want %v", got, tt.want)
+			if err := sf.DeleteOne(tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.DeleteOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestSalesforce_UpdateCollection(t *testing.T) {
-	type account struct {
-		Id   string
-		Name string
+func TestSalesforce_PublishEvent(t *testing.T) {
+	type event struct {
+		Message__c string
 	}
-
-	successfulResults := SalesforceResults{
-		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
-		}},
-		HasSalesforceErrors: false,
+	resp := SalesforceResult{
+		Id:               "e00xx0000000001AAA",
+		Success:          true,
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusCreated},
 	}
-
-	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
+	server, sfAuth := setupTestServer(resp, http.StatusCreated)
 	defer server.Close()
 
 	type fields struct {
 		auth *authentication
 	}
 	type args struct {
-		sObjectName string
-		records     any
-		batchSize   int
+		eventName string
+		payload   any
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		want    SalesforceResults
+		want    SalesforceResult
+		wantErr bool
+	}{
+		{
+			name: "publish_event",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				eventName: "Test__e",
+				payload:   event{Message__c: "hello"},
+			},
+			want:    resp,
+			wantErr: false,
+		},
+		{
+			name: "validation_fail",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				eventName: "Test__e",
+				payload:   []event{{Message__c: "hello"}},
+			},
+			want:    SalesforceResult{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{
+				auth: tt.fields.auth,
+			}
+			got, err := sf.PublishEvent(tt.args.eventName, tt.args.payload)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.PublishEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.PublishEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_Subscribe(t *testing.T) {
+	handler := func(StreamingEvent) error { return nil }
+	tests := []struct {
+		name    string
+		sf      *Salesforce
+		handler func(StreamingEvent) error
+	}{
+		{
+			name:    "validation_fail",
+			sf:      &Salesforce{},
+			handler: handler,
+		},
+		{
+			name:    "nil_handler",
+			sf:      &Salesforce{auth: &authentication{AccessToken: "1234"}},
+			handler: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sf.Subscribe(context.Background(), "/event/Test__e", ReplayNewEvents, tt.handler); err == nil {
+				t.Errorf("Salesforce.Subscribe() expected error")
+			}
+		})
+	}
+}
+
+func TestSalesforce_InsertCollection(t *testing.T) {
+	type account struct {
+		Name string
+	}
+
+	successfulResults := SalesforceResults{
+		Results: []SalesforceResult{{
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
+		}},
+		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+	}
+
+	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
+	defer server.Close()
+
+	badReqServer, badReqSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badReqServer.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	type args struct {
+		sObjectName string
+		records     any
+		batchSize   int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    SalesforceResults
+		wantErr bool
+	}{
+		{
+			name: "successful_insert",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				sObjectName: "Account",
+				records: []account{
+					{
+						Name: "test account 1",
+					},
+					{
+						Name: "test account 2",
+					},
+				},
+				batchSize: 200,
+			},
+			want:    successfulResults,
+			wantErr: false,
+		},
+		{
+			name: "validation_fail",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				sObjectName: "Account",
+				records: account{
+					Name: "test account 1",
+				},
+				batchSize: 200,
+			},
+			want:    SalesforceResults{},
+			wantErr: true,
+		},
+		{
+			name: "bad_request",
+			fields: fields{
+				auth: &badReqSfAuth,
+			},
+			args: args{
+				sObjectName: "Account",
+				records: []account{
+					{
+						Name: "test account 1",
+					},
+				},
+				batchSize: 200,
+			},
+			want:    SalesforceResults{Results: []SalesforceResult{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{
+				auth: tt.fields.auth,
+			}
+			got, err := sf.InsertCollection(tt.args.sObjectName, tt.args.records, tt.args.batchSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.InsertCollection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.InsertCollection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_UpdateCollection(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string
+	}
+
+	successfulResults := SalesforceResults{
+		Results: []SalesforceResult{{
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
+		}},
+		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+	}
+
+	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
+	defer server.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	type args struct {
+		sObjectName string
+		records     any
+		batchSize   int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    SalesforceResults
 		wantErr bool
 	}{
 		{
@@ -1469,11 +1982,13 @@ func TestSalesforce_UpsertCollection(t *testing.T) {
 
 	successfulResults := SalesforceResults{
 		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 		}},
 		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
@@ -1577,11 +2092,13 @@ func TestSalesforce_DeleteCollection(t *testing.T) {
 
 	successfulResults := SalesforceResults{
 		Results: []SalesforceResult{{
-			Id:      "1234",
-			Errors:  []SalesforceErrorMessage{},
-			Success: true,
+			Id:               "1234",
+			Errors:           []SalesforceErrorMessage{},
+			Success:          true,
+			ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
 		}},
 		HasSalesforceErrors: false,
+		ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
 	}
 
 	server, sfAuth := setupTestServer(successfulResults.Results, http.StatusOK)
@@ -1723,6 +2240,8 @@ func TestSalesforce_InsertComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -1838,6 +2357,8 @@ func TestSalesforce_UpdateComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -1954,6 +2475,8 @@ func TestSalesforce_UpsertComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -2066,6 +2589,8 @@ func TestSalesforce_DeleteComposite(t *testing.T) {
 			want: SalesforceResults{
 				Results:             compResult.CompositeResponse[0].Body,
 				HasSalesforceErrors: false,
+				ResponseMetadata:    ResponseMetadata{StatusCode: http.StatusOK},
+				SubRequests:         []SubRequestResult{{Size: 2}},
 			},
 			wantErr: false,
 		},
@@ -2120,7 +2645,7 @@ func TestSalesforce_InsertBulk(t *testing.T) {
 	}
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2133,6 +2658,7 @@ func TestSalesforce_InsertBulk(t *testing.T) {
 		records        any
 		batchSize      int
 		waitForResults bool
+		opts           []BulkJobOptions
 	}
 	tests := []struct {
 		name    string
@@ -2176,13 +2702,52 @@ func TestSalesforce_InsertBulk(t *testing.T) {
 			want:    []string{},
 			wantErr: true,
 		},
+		{
+			name: "successful_insert_with_options",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				sObjectName: "Account",
+				records: []account{
+					{
+						Name: "test account 1",
+					},
+				},
+				batchSize: 2000,
+				opts: []BulkJobOptions{{
+					ColumnDelimiter: ColumnDelimiterPipe,
+					LineEnding:      LineEndingCRLF,
+				}},
+			},
+			want:    []string{"1234"},
+			wantErr: false,
+		},
+		{
+			name: "invalid_options",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				sObjectName: "Account",
+				records: []account{
+					{
+						Name: "test account 1",
+					},
+				},
+				batchSize: 2000,
+				opts:      []BulkJobOptions{{ColumnDelimiter: "COLON"}},
+			},
+			want:    []string{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sf := &Salesforce{
 				auth: tt.fields.auth,
 			}
-			got, err := sf.InsertBulk(tt.args.sObjectName, tt.args.records, tt.args.batchSize, tt.args.waitForResults)
+			got, err := sf.InsertBulk(tt.args.sObjectName, tt.args.records, tt.args.batchSize, tt.args.waitForResults, tt.args.opts...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Salesforce.InsertBulk() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -2201,7 +2766,7 @@ func TestSalesforce_UpdateBulk(t *testing.T) {
 	}
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2282,7 +2847,7 @@ func TestSalesforce_UpsertBulk(t *testing.T) {
 	}
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2365,7 +2930,7 @@ func TestSalesforce_DeleteBulk(t *testing.T) {
 	}
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2437,38 +3002,164 @@ func TestSalesforce_DeleteBulk(t *testing.T) {
 	}
 }
 
-func TestSalesforce_GetJobResults(t *testing.T) {
+func TestSalesforce_DeleteBulk_hardDelete(t *testing.T) {
+	var mu sync.Mutex
+	operations := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/data/"+apiVersion+"/jobs/ingest" {
+			body := bulkJobCreationRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				panic(err.Error())
+			}
+			mu.Lock()
+			operations = append(operations, body.Operation)
+			mu.Unlock()
+		}
+		if err := json.NewEncoder(w).Encode(bulkJob{Id: "1234", State: BulkJobStateOpen}); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	appFs = afero.NewMemMapFs()
+	if err := afero.WriteFile(appFs, "delete.csv", []byte("Id\n1234\n"), 0644); err != nil {
+		t.Fatalf("error creating file in virtual file system")
+	}
+
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+	records := []map[string]any{{"Id": "1234"}}
+	if _, err := sf.DeleteBulk("Account", records, 2000, false, BulkJobOptions{HardDelete: true}); err != nil {
+		t.Fatalf("Salesforce.DeleteBulk() error = %v", err)
+	}
+	if _, err := sf.DeleteBulkFile("Account", "delete.csv", 2000, false, BulkJobOptions{HardDelete: true}); err != nil {
+		t.Fatalf("Salesforce.DeleteBulkFile() error = %v", err)
+	}
+	if _, err := sf.DeleteBulk("Account", records, 2000, false); err != nil {
+		t.Fatalf("Salesforce.DeleteBulk() error = %v", err)
+	}
+	want := []string{hardDeleteOperation, hardDeleteOperation, deleteOperation}
+	if !reflect.DeepEqual(operations, want) {
+		t.Errorf("bulk delete operations = %v, want %v", operations, want)
+	}
+}
+
+func TestSalesforce_GetJobResultsInto(t *testing.T) {
 	jobResults := BulkJobResults{
-		Id:                  "1234",
-		State:               jobStateOpen,
-		NumberRecordsFailed: 0,
-		ErrorMessage:        "",
+		Id:    "1234",
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(jobResults, http.StatusOK)
 	defer server.Close()
 
+	type record struct {
+		Id string `mapstructure:"sf__Id"`
+	}
+	sf := &Salesforce{auth: &sfAuth}
+	successful := []record{}
+	got, err := sf.GetJobResultsInto("1234", &successful, nil)
+	if err != nil {
+		t.Fatalf("Salesforce.GetJobResultsInto() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, jobResults) {
+		t.Errorf("Salesforce.GetJobResultsInto() = %v, want %v", got, jobResults)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.GetJobResultsInto("1234", &successful, nil); err == nil {
+		t.Errorf("Salesforce.GetJobResultsInto() expected validation error")
+	}
+}
+
+func TestSalesforce_SaveJobRecordResults(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+
 	type fields struct {
 		auth *authentication
 	}
 	type args struct {
-		bulkJobId string
+		successPath string
+		failedPath  string
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		want    BulkJobResults
 		wantErr bool
 	}{
 		{
-			name: "get_job_results",
+			name: "save_failed_records",
 			fields: fields{
 				auth: &sfAuth,
 			},
 			args: args{
-				bulkJobId: "1234",
+				failedPath: "failed.csv",
 			},
-			want:    jobResults,
+			wantErr: false,
+		},
+		{
+			name: "no_paths",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args:    args{},
+			wantErr: true,
+		},
+		{
+			name: "validation_fail",
+			fields: fields{
+				auth: nil,
+			},
+			args: args{
+				successPath: "success.csv",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{
+				auth: tt.fields.auth,
+			}
+			if err := sf.SaveJobRecordResults("1234", tt.args.successPath, tt.args.failedPath, ""); (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.SaveJobRecordResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetJobResults(t *testing.T) {
+	jobResults := BulkJobResults{
+		Id:                  "1234",
+		State:               BulkJobStateOpen,
+		NumberRecordsFailed: 0,
+		ErrorMessage:        "",
+	}
+	server, sfAuth := setupTestServer(jobResults, http.StatusOK)
+	defer server.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	type args struct {
+		bulkJobId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    BulkJobResults
+		wantErr bool
+	}{
+		{
+			name: "get_job_results",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				bulkJobId: "1234",
+			},
+			want:    jobResults,
 			wantErr: false,
 		},
 		{
@@ -2510,7 +3201,7 @@ func TestSalesforce_InsertBulkFile(t *testing.T) {
 
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2588,7 +3279,7 @@ func TestSalesforce_UpdateBulkFile(t *testing.T) {
 
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2666,7 +3357,7 @@ func TestSalesforce_UpsertBulkFile(t *testing.T) {
 
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2747,7 +3438,7 @@ func TestSalesforce_DeleteBulkFile(t *testing.T) {
 
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateOpen,
+		State: BulkJobStateOpen,
 	}
 	server, sfAuth := setupTestServer(job, http.StatusOK)
 	defer server.Close()
@@ -2817,11 +3508,11 @@ func TestSalesforce_DeleteBulkFile(t *testing.T) {
 func TestSalesforce_QueryBulkExport(t *testing.T) {
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	jobResults := BulkJobResults{
 		Id:                  "1234",
-		State:               jobStateJobComplete,
+		State:               BulkJobStateJobComplete,
 		NumberRecordsFailed: 0,
 		ErrorMessage:        "",
 	}
@@ -2903,6 +3594,85 @@ func TestSalesforce_QueryBulkExport(t *testing.T) {
 	}
 }
 
+func TestSalesforce_QueryBulkExportJSON(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+
+	jobCreationRespBody, _ := json.Marshal(bulkJob{Id: "1234", State: BulkJobStateJobComplete})
+	jobResultsRespBody, _ := json.Marshal(BulkJobResults{Id: "1234", State: BulkJobStateJobComplete})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.RequestURI, "/query"):
+			if _, err := w.Write(jobCreationRespBody); err != nil {
+				t.Error(err.Error())
+			}
+		case strings.HasSuffix(r.RequestURI, "/1234"):
+			if _, err := w.Write(jobResultsRespBody); err != nil {
+				t.Error(err.Error())
+			}
+		case strings.HasSuffix(r.RequestURI, "/results"):
+			w.Header().Add("Sforce-Locator", "null")
+			w.Header().Add("Sforce-Numberofrecords", "1")
+			if _, err := w.Write([]byte("Id,Name\n001A,Stark Industries\n")); err != nil {
+				t.Error(err.Error())
+			}
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	tests := []struct {
+		name    string
+		opts    []BulkQueryOptions
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "json_array",
+			opts:    nil,
+			want:    "[\n{\"Id\":\"001A\",\"Name\":\"Stark Industries\"}\n]\n",
+			wantErr: false,
+		},
+		{
+			name:    "json_lines",
+			opts:    []BulkQueryOptions{{Format: ExportFormatJSONLines}},
+			want:    "{\"Id\":\"001A\",\"Name\":\"Stark Industries\"}\n",
+			wantErr: false,
+		},
+		{
+			name:    "invalid_format",
+			opts:    []BulkQueryOptions{{Format: "XML"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{auth: &sfAuth}
+			err := sf.QueryBulkExportJSON("SELECT Id, Name FROM Account", "data/export.json", tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Salesforce.QueryBulkExportJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := afero.ReadFile(appFs, "data/export.json")
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if string(got) != tt.want {
+				t.Errorf("Salesforce.QueryBulkExportJSON() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	sf := &Salesforce{}
+	if err := sf.QueryBulkExportJSON("SELECT Id FROM Account", "data/export.json"); err == nil {
+		t.Errorf("Salesforce.QueryBulkExportJSON() expected validation error")
+	}
+}
+
 func TestSalesforce_QueryStructBulkExport(t *testing.T) {
 	type account struct {
 		Id   string
@@ -2910,11 +3680,11 @@ func TestSalesforce_QueryStructBulkExport(t *testing.T) {
 	}
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	jobResults := BulkJobResults{
 		Id:                  "1234",
-		State:               jobStateJobComplete,
+		State:               BulkJobStateJobComplete,
 		NumberRecordsFailed: 0,
 		ErrorMessage:        "",
 	}
@@ -2999,11 +3769,11 @@ func TestSalesforce_QueryStructBulkExport(t *testing.T) {
 func TestSalesforce_CreateQueryBulkJob(t *testing.T) {
 	job := bulkJob{
 		Id:    "1234",
-		State: jobStateJobComplete,
+		State: BulkJobStateJobComplete,
 	}
 	jobResults := BulkJobResults{
 		Id:                  "1234",
-		State:               jobStateJobComplete,
+		State:               BulkJobStateJobComplete,
 		NumberRecordsFailed: 0,
 		ErrorMessage:        "",
 	}
@@ -3104,6 +3874,180 @@ func TestSalesforce_CreateQueryBulkJob(t *testing.T) {
 	}
 }
 
+func TestSalesforce_GetAllJobs(t *testing.T) {
+	jobs := allJobsResponse{
+		Done: true,
+		Records: []BulkJobSummary{
+			{Id: "1234", State: BulkJobStateJobComplete},
+		},
+	}
+	server, sfAuth := setupTestServer(jobs, http.StatusOK)
+	defer server.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	type args struct {
+		jobType string
+		opts    []BulkJobFilter
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []BulkJobSummary
+		wantErr bool
+	}{
+		{
+			name: "get_all_ingest_jobs",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				jobType: ingestJobType,
+				opts:    []BulkJobFilter{{ConcurrencyMode: ConcurrencyModeParallel, IsPkChunkingEnabled: true}},
+			},
+			want:    jobs.Records,
+			wantErr: false,
+		},
+		{
+			name: "invalid_job_type",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				jobType: "batch",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "validation_fail",
+			fields: fields{
+				auth: nil,
+			},
+			args: args{
+				jobType: ingestJobType,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{
+				auth: tt.fields.auth,
+			}
+			got, err := sf.GetAllJobs(tt.args.jobType, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.GetAllJobs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.GetAllJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_AbortJob(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	type args struct {
+		bulkJobId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "abort_job",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				bulkJobId: "1234",
+			},
+			wantErr: false,
+		},
+		{
+			name: "validation_fail",
+			fields: fields{
+				auth: nil,
+			},
+			args: args{
+				bulkJobId: "1234",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{
+				auth: tt.fields.auth,
+			}
+			if err := sf.AbortJob(tt.args.bulkJobId); (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.AbortJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSalesforce_DeleteJob(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusNoContent)
+	defer server.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	type args struct {
+		bulkJobId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "delete_job",
+			fields: fields{
+				auth: &sfAuth,
+			},
+			args: args{
+				bulkJobId: "1234",
+			},
+			wantErr: false,
+		},
+		{
+			name: "validation_fail",
+			fields: fields{
+				auth: nil,
+			},
+			args: args{
+				bulkJobId: "1234",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{
+				auth: tt.fields.auth,
+			}
+			if err := sf.DeleteJob(tt.args.bulkJobId); (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.DeleteJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGetAccessToken(t *testing.T) {
 	sfAuth := authentication{
 		AccessToken: "1234",
@@ -3140,3 +4084,634 @@ func TestGetAccessToken(t *testing.T) {
 		})
 	}
 }
+
+func TestSalesforce_Capabilities(t *testing.T) {
+	sf := &Salesforce{auth: &authentication{grantType: grantTypeUsernamePassword}}
+	got := sf.Capabilities()
+	if got.AuthFlow != AuthFlowUsernamePassword || !got.CanRefreshSession {
+		t.Errorf("Capabilities() = %v", got)
+	}
+}
+
+func TestSalesforce_CanUpdate(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"RecordId": "001000000000001AAA", "HasEditAccess": true}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+	sfAuth.Id = "https://login.salesforce.com/id/00D000000000001/005000000000001"
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.CanUpdate([]string{"001000000000001AAA"})
+	if err != nil {
+		t.Fatalf("CanUpdate() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("CanUpdate() = %v, want %v", got, []string{})
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.CanUpdate([]string{"001000000000001AAA"}); err == nil {
+		t.Errorf("CanUpdate() expected validation error")
+	}
+}
+
+func TestSalesforce_UserInfo(t *testing.T) {
+	resp := map[string]any{
+		"user_id":            "005000000000001",
+		"organization_id":    "00D000000000001",
+		"preferred_username": "tony@stark.com",
+		"name":               "Tony Stark",
+		"email":              "tony@stark.com",
+		"locale":             "en_US",
+		"zoneinfo":           "America/Los_Angeles",
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.UserInfo()
+	if err != nil {
+		t.Fatalf("UserInfo() error = %v", err)
+	}
+	want := UserInfo{
+		UserId:         "005000000000001",
+		OrganizationId: "00D000000000001",
+		Username:       "tony@stark.com",
+		Name:           "Tony Stark",
+		Email:          "tony@stark.com",
+		Locale:         "en_US",
+		Timezone:       "America/Los_Angeles",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UserInfo() = %v, want %v", got, want)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.UserInfo(); err == nil {
+		t.Errorf("UserInfo() expected validation error")
+	}
+}
+
+func TestSalesforce_ExecuteAnonymousApex(t *testing.T) {
+	resp := ExecuteAnonymousResult{
+		Line:     -1,
+		Column:   -1,
+		Compiled: true,
+		Success:  true,
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.ExecuteAnonymousApex("System.debug('hi');")
+	if err != nil {
+		t.Fatalf("ExecuteAnonymousApex() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, resp) {
+		t.Errorf("ExecuteAnonymousApex() = %v, want %v", got, resp)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.ExecuteAnonymousApex("System.debug('hi');"); err == nil {
+		t.Errorf("ExecuteAnonymousApex() expected validation error")
+	}
+}
+
+func TestSalesforce_PostFeedElement(t *testing.T) {
+	server, sfAuth := setupTestServer(feedElementResponse{Id: "0D5000000000001", FeedElementType: feedItemType}, http.StatusCreated)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.PostFeedElement("001000000000001", "Sync complete", nil)
+	if err != nil {
+		t.Fatalf("PostFeedElement() error = %v", err)
+	}
+	want := FeedElement{Id: "0D5000000000001", FeedElementType: feedItemType}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PostFeedElement() = %v, want %v", got, want)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.PostFeedElement("001000000000001", "Sync complete", nil); err == nil {
+		t.Errorf("PostFeedElement() expected validation error")
+	}
+}
+
+func TestSalesforce_PostFeedComment(t *testing.T) {
+	server, sfAuth := setupTestServer(feedCommentResponse{Id: "0D7000000000001"}, http.StatusCreated)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.PostFeedComment("0D5000000000001", "Retrying", nil)
+	if err != nil {
+		t.Fatalf("PostFeedComment() error = %v", err)
+	}
+	if got.Id != "0D7000000000001" {
+		t.Errorf("PostFeedComment() = %v, want id %v", got, "0D7000000000001")
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.PostFeedComment("0D5000000000001", "Retrying", nil); err == nil {
+		t.Errorf("PostFeedComment() expected validation error")
+	}
+}
+
+func TestSalesforce_GetFeedElements(t *testing.T) {
+	server, sfAuth := setupTestServer(feedElementPage{Elements: []feedElementResponse{{Id: "0D5000000000001"}}}, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.GetFeedElements("001000000000001")
+	if err != nil {
+		t.Fatalf("GetFeedElements() error = %v", err)
+	}
+	want := []FeedElement{{Id: "0D5000000000001"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFeedElements() = %v, want %v", got, want)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.GetFeedElements("001000000000001"); err == nil {
+		t.Errorf("GetFeedElements() expected validation error")
+	}
+}
+
+func TestSalesforce_DeleteFeedElement(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusNoContent)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	if err := sf.DeleteFeedElement("0D5000000000001"); err != nil {
+		t.Errorf("DeleteFeedElement() error = %v", err)
+	}
+
+	sf = &Salesforce{}
+	if err := sf.DeleteFeedElement("0D5000000000001"); err == nil {
+		t.Errorf("DeleteFeedElement() expected validation error")
+	}
+}
+
+func TestSalesforce_SendCustomNotification(t *testing.T) {
+	server, sfAuth := setupTestServer([]actionResult{{ActionName: customNotificationType, IsSuccess: true}}, http.StatusOK)
+	defer server.Close()
+
+	notification := CustomNotification{NotificationTypeId: "0ML000000000001", RecipientIds: []string{"005000000000001"}, Title: "Sync failed", Body: "3 records failed"}
+	sf := &Salesforce{auth: &sfAuth}
+	if err := sf.SendCustomNotification(notification); err != nil {
+		t.Errorf("SendCustomNotification() error = %v", err)
+	}
+
+	sf = &Salesforce{}
+	if err := sf.SendCustomNotification(notification); err == nil {
+		t.Errorf("SendCustomNotification() expected validation error")
+	}
+}
+
+func TestSalesforce_SendEmail(t *testing.T) {
+	server, sfAuth := setupTestServer([]actionResult{{ActionName: emailSimpleType, IsSuccess: true}}, http.StatusOK)
+	defer server.Close()
+
+	message := EmailMessage{To: []string{"tony@starkindustries.com"}, Subject: "Sync failed", Body: "3 records failed to sync"}
+	sf := &Salesforce{auth: &sfAuth}
+	if err := sf.SendEmail(message); err != nil {
+		t.Errorf("SendEmail() error = %v", err)
+	}
+
+	sf = &Salesforce{}
+	if err := sf.SendEmail(message); err == nil {
+		t.Errorf("SendEmail() expected validation error")
+	}
+}
+
+func TestSalesforce_ExecuteComposite(t *testing.T) {
+	resp := CompositeResults{
+		Results: []CompositeResult{
+			{
+				Body:           json.RawMessage(`{"totalSize":0,"done":true,"records":[]}`),
+				HttpHeaders:    map[string]string{},
+				HttpStatusCode: http.StatusOK,
+				ReferenceId:    "refQuery",
+			},
+		},
+		ResponseMetadata: ResponseMetadata{StatusCode: http.StatusOK},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	builder := NewCompositeBuilder(false).Add(http.MethodGet, "/query/?q=SELECT+Id+FROM+Account", nil, "refQuery")
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.ExecuteComposite(builder)
+	if err != nil {
+		t.Fatalf("ExecuteComposite() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, resp) {
+		t.Errorf("ExecuteComposite() = %v, want %v", got, resp)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.ExecuteComposite(builder); err == nil {
+		t.Errorf("ExecuteComposite() expected validation error")
+	}
+}
+
+func TestSalesforce_ByExternalId(t *testing.T) {
+	type account struct {
+		External_Id__c string
+		Name           string
+	}
+	server, sfAuth, gotRequests := setupExternalIdTestServer(t)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	record := account{External_Id__c: "acc-1", Name: "test account"}
+	if err := sf.UpdateByExternalId("Account", "External_Id__c", record, WithHeader("Sforce-Auto-Assign", "false")); err != nil {
+		t.Errorf("UpdateByExternalId() error = %v", err)
+	}
+	for _, subRequest := range *gotRequests {
+		if subRequest.HttpHeaders["Sforce-Auto-Assign"] != "false" {
+			t.Errorf("UpdateByExternalId() subrequest headers = %v", subRequest.HttpHeaders)
+		}
+	}
+	results, err := sf.UpdateCollectionByExternalId("Account", "External_Id__c", []account{record})
+	if err != nil || results.HasSalesforceErrors || len(results.Results) != 1 {
+		t.Errorf("UpdateCollectionByExternalId() = %v, %v", results, err)
+	}
+	results, err = sf.DeleteCollectionByExternalId("Account", "External_Id__c", []account{record})
+	if err != nil || results.HasSalesforceErrors || len(results.Results) != 1 {
+		t.Errorf("DeleteCollectionByExternalId() = %v, %v", results, err)
+	}
+
+	if err := sf.UpdateByExternalId("Account", "External_Id__c", 0); err == nil {
+		t.Errorf("UpdateByExternalId() expected validation error")
+	}
+	if err := sf.DeleteByExternalId("Account", "External_Id__c", 0); err == nil {
+		t.Errorf("DeleteByExternalId() expected validation error")
+	}
+	if _, err := sf.UpdateCollectionByExternalId("Account", "External_Id__c", record); err == nil {
+		t.Errorf("UpdateCollectionByExternalId() expected validation error")
+	}
+	sf = &Salesforce{}
+	if _, err := sf.DeleteCollectionByExternalId("Account", "External_Id__c", []account{record}); err == nil {
+		t.Errorf("DeleteCollectionByExternalId() expected validation error")
+	}
+}
+
+func TestSalesforce_GetPicklistValues(t *testing.T) {
+	resp := PicklistValues{
+		Values: []PicklistValue{{Label: "Hot", Value: "Hot", ValidFor: []int{}}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.GetPicklistValues("Account", "", "Rating")
+	if err != nil {
+		t.Fatalf("GetPicklistValues() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, resp) {
+		t.Errorf("GetPicklistValues() = %v, want %v", got, resp)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.GetPicklistValues("Account", "", "Rating"); err == nil {
+		t.Errorf("GetPicklistValues() expected validation error")
+	}
+}
+
+func TestSalesforce_DeployMetadata(t *testing.T) {
+	resp := deployRequestResponse{Id: "0Af000000000001", DeployResult: DeployResult{Status: "Pending"}}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.DeployMetadata([]byte("zipdata"), DeployOptions{})
+	if err != nil {
+		t.Fatalf("DeployMetadata() error = %v", err)
+	}
+	want := DeployResult{Id: "0Af000000000001", Status: "Pending"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeployMetadata() = %v, want %v", got, want)
+	}
+	got, err = sf.CheckDeployStatus("0Af000000000001")
+	if err != nil {
+		t.Fatalf("CheckDeployStatus() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckDeployStatus() = %v, want %v", got, want)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.DeployMetadata([]byte("zipdata"), DeployOptions{}); err == nil {
+		t.Errorf("DeployMetadata() expected validation error")
+	}
+	if _, err := sf.CheckDeployStatus("0Af000000000001"); err == nil {
+		t.Errorf("CheckDeployStatus() expected validation error")
+	}
+	if _, err := sf.WaitForDeploy("0Af000000000001", time.Millisecond, time.Second); err == nil {
+		t.Errorf("WaitForDeploy() expected validation error")
+	}
+}
+
+func TestSalesforce_RetrieveMetadata(t *testing.T) {
+	server := setupMetadataSoapServer(t, []byte("zipdata"))
+	defer server.Close()
+
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+	retrieval, err := sf.RetrieveMetadata(RetrieveRequest{Types: map[string][]string{"CustomObject": {"Account"}}})
+	if err != nil {
+		t.Fatalf("RetrieveMetadata() error = %v", err)
+	}
+	got, err := sf.CheckRetrieveStatus(retrieval.Id)
+	if err != nil {
+		t.Fatalf("CheckRetrieveStatus() error = %v", err)
+	}
+	if !got.Done || string(got.ZipFile) != "zipdata" {
+		t.Errorf("CheckRetrieveStatus() = %v", got)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.RetrieveMetadata(RetrieveRequest{}); err == nil {
+		t.Errorf("RetrieveMetadata() expected validation error")
+	}
+	if _, err := sf.CheckRetrieveStatus("09S000000000001"); err == nil {
+		t.Errorf("CheckRetrieveStatus() expected validation error")
+	}
+	if _, err := sf.WaitForRetrieve("09S000000000001", time.Millisecond, time.Second); err == nil {
+		t.Errorf("WaitForRetrieve() expected validation error")
+	}
+}
+
+func TestSalesforce_RunReport(t *testing.T) {
+	server, sfAuth := setupTestServer(json.RawMessage(tabularReportResponse), http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.RunReport("00O000000000001")
+	if err != nil {
+		t.Fatalf("RunReport() error = %v", err)
+	}
+	if got.ReportId != "00O000000000001" || len(got.Rows) != 2 {
+		t.Errorf("RunReport() = %v", got)
+	}
+	got, err = sf.GetReportData("00O000000000001", "0LG000000000001")
+	if err != nil {
+		t.Fatalf("GetReportData() error = %v", err)
+	}
+	if got.InstanceId != "0LG000000000001" {
+		t.Errorf("GetReportData() = %v", got)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.RunReport("00O000000000001"); err == nil {
+		t.Errorf("RunReport() expected validation error")
+	}
+	if _, err := sf.RunReportAsync("00O000000000001"); err == nil {
+		t.Errorf("RunReportAsync() expected validation error")
+	}
+	if _, err := sf.GetReportData("00O000000000001", "0LG000000000001"); err == nil {
+		t.Errorf("GetReportData() expected validation error")
+	}
+}
+
+func TestSalesforce_GetListViews(t *testing.T) {
+	resp := listViewsResponse{ListViews: []ListView{{Id: "00B000000000001", Label: "All Accounts"}}, Done: true}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.GetListViews("Account")
+	if err != nil {
+		t.Fatalf("GetListViews() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, resp.ListViews) {
+		t.Errorf("GetListViews() = %v, want %v", got, resp.ListViews)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.GetListViews("Account"); err == nil {
+		t.Errorf("GetListViews() expected validation error")
+	}
+}
+
+func TestSalesforce_GetListViewRecords(t *testing.T) {
+	server, sfAuth := setupTestServer(json.RawMessage(`{"done": true, "records": [{"columns": [{"fieldNameOrPath": "Name", "value": "Stark Industries"}]}]}`), http.StatusOK)
+	defer server.Close()
+
+	type account struct {
+		Name string
+	}
+	sf := &Salesforce{auth: &sfAuth}
+	got := []account{}
+	if err := sf.GetListViewRecords("Account", "00B000000000001", &got); err != nil {
+		t.Fatalf("GetListViewRecords() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []account{{Name: "Stark Industries"}}) {
+		t.Errorf("GetListViewRecords() = %v", got)
+	}
+	if err := sf.GetListViewRecords("Account", "00B000000000001", got); err == nil {
+		t.Errorf("GetListViewRecords() expected error for non-pointer result")
+	}
+
+	sf = &Salesforce{}
+	if err := sf.GetListViewRecords("Account", "00B000000000001", &got); err == nil {
+		t.Errorf("GetListViewRecords() expected validation error")
+	}
+}
+
+func TestSalesforce_RecentlyViewed(t *testing.T) {
+	server, sfAuth := setupTestServer(json.RawMessage(`[{"attributes": {"type": "Contact"}, "Id": "003000000000001", "Name": "Peter Parker"}]`), http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.RecentlyViewed(10)
+	if err != nil {
+		t.Fatalf("RecentlyViewed() error = %v", err)
+	}
+	want := []RecentlyViewedRecord{{Id: "003000000000001", Name: "Peter Parker", Type: "Contact"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentlyViewed() = %v, want %v", got, want)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.RecentlyViewed(10); err == nil {
+		t.Errorf("RecentlyViewed() expected validation error")
+	}
+}
+
+func TestSalesforce_Undelete(t *testing.T) {
+	var requests []string
+	server := setupRecycleBinTestServer(t, &requests)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+	got, err := sf.Undelete([]string{"001000000000001"})
+	if err != nil || len(got.Results) != 1 || !got.Results[0].Success {
+		t.Errorf("Undelete() = %v, %v", got, err)
+	}
+	got, err = sf.EmptyRecycleBin([]string{"001000000000001"})
+	if err != nil || len(got.Results) != 1 || !got.Results[0].Success {
+		t.Errorf("EmptyRecycleBin() = %v, %v", got, err)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.Undelete([]string{"001000000000001"}); err == nil {
+		t.Errorf("Undelete() expected validation error")
+	}
+	if _, err := sf.EmptyRecycleBin([]string{"001000000000001"}); err == nil {
+		t.Errorf("EmptyRecycleBin() expected validation error")
+	}
+}
+
+func TestSalesforce_DescribeSObject(t *testing.T) {
+	server, sfAuth := setupTestServer(accountDescribe, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.DescribeSObject("Account")
+	if err != nil {
+		t.Fatalf("DescribeSObject() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, accountDescribe) {
+		t.Errorf("DescribeSObject() = %v, want %v", got, accountDescribe)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.DescribeSObject("Account"); err == nil {
+		t.Errorf("DescribeSObject() expected validation error")
+	}
+}
+
+func TestSalesforce_InvalidateMetadataCache(t *testing.T) {
+	cache := NewMemoryMetadataCache()
+	key := newMetadataCacheKey(&authentication{}, "Account", "describe")
+	cache.Set(key, []byte("{}"), 0)
+	sf := &Salesforce{auth: &authentication{config: &configuration{metadataCache: cache}}}
+
+	sf.InvalidateMetadataCache("Account")
+	if _, ok := cache.Get(key); ok {
+		t.Errorf("InvalidateMetadataCache() kept a cached describe")
+	}
+
+	sf = &Salesforce{}
+	sf.InvalidateMetadataCache()
+}
+
+func TestSalesforce_ResumeBulkLoad(t *testing.T) {
+	server, sfAuth := setupBulkLoadTestServer(t, nil)
+	defer server.Close()
+	sf := &Salesforce{auth: &sfAuth}
+
+	plan := &BulkLoadPlan{
+		SObjectName: "Account",
+		Operation:   insertOperation,
+		Batches:     []BulkLoadBatch{{Index: 0, Size: 1, JobId: "job0", Status: BulkBatchUploaded}, {Index: 1, Size: 1, Status: BulkBatchPending}},
+	}
+	jobIds, err := sf.ResumeBulkLoad(plan, []map[string]any{{"Name": "a"}, {"Name": "b"}}, false)
+	if err != nil {
+		t.Fatalf("ResumeBulkLoad() error = %v", err)
+	}
+	if !reflect.DeepEqual(jobIds, []string{"job1"}) {
+		t.Errorf("ResumeBulkLoad() = %v, want [job1]", jobIds)
+	}
+
+	if _, err := sf.ResumeBulkLoad(plan, "not a slice", false); err == nil {
+		t.Errorf("ResumeBulkLoad() expected validation error")
+	}
+	if _, err := sf.ResumeBulkLoadFile(nil, "accounts.csv", false); err == nil {
+		t.Errorf("ResumeBulkLoadFile() expected error for nil plan")
+	}
+}
+
+func TestSalesforce_Ping(t *testing.T) {
+	server, sfAuth := setupTestServer(map[string]any{}, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	if err := sf.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+
+	sf = &Salesforce{}
+	if err := sf.Ping(context.Background()); err == nil {
+		t.Errorf("Ping() expected validation error")
+	}
+}
+
+func TestSalesforce_TokenInfo(t *testing.T) {
+	server, sfAuth := setupTestServer(map[string]any{"active": true}, http.StatusOK)
+	defer server.Close()
+	sfAuth.creds = Creds{ConsumerKey: "key"}
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := sf.TokenInfo()
+	if err != nil {
+		t.Fatalf("TokenInfo() error = %v", err)
+	}
+	if !got.Active {
+		t.Errorf("TokenInfo() = %v, want active token", got)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.TokenInfo(); err == nil {
+		t.Errorf("TokenInfo() expected validation error")
+	}
+}
+
+func TestSalesforce_PermissionChecks(t *testing.T) {
+	server, sfAuth := setupTestServer(accountDescribe, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	if got, err := sf.CanCreateObject("Account"); err != nil || !got {
+		t.Errorf("CanCreateObject() = %v, %v", got, err)
+	}
+	if got, err := sf.CanUpdateObject("Account"); err != nil || !got {
+		t.Errorf("CanUpdateObject() = %v, %v", got, err)
+	}
+	if got, err := sf.CanDeleteObject("Account"); err != nil || got {
+		t.Errorf("CanDeleteObject() = %v, %v", got, err)
+	}
+	if got, err := sf.FieldAccessible("Account", "Name"); err != nil || !got {
+		t.Errorf("FieldAccessible() = %v, %v", got, err)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.CanCreateObject("Account"); err == nil {
+		t.Errorf("CanCreateObject() expected validation error")
+	}
+	if _, err := sf.CanUpdateObject("Account"); err == nil {
+		t.Errorf("CanUpdateObject() expected validation error")
+	}
+	if _, err := sf.CanDeleteObject("Account"); err == nil {
+		t.Errorf("CanDeleteObject() expected validation error")
+	}
+	if _, err := sf.FieldAccessible("Account", "Name"); err == nil {
+		t.Errorf("FieldAccessible() expected validation error")
+	}
+}
+
+func TestSalesforce_WatchJob(t *testing.T) {
+	server, sfAuth := setupTestServer(BulkJobResults{Id: "750", State: BulkJobStateJobComplete}, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	events, err := sf.WatchJob(context.Background(), "750")
+	if err != nil {
+		t.Fatalf("WatchJob() error = %v", err)
+	}
+	var last BulkJobEvent
+	for event := range events {
+		last = event
+	}
+	if last.Type != BulkJobEventCompleted {
+		t.Errorf("WatchJob() last event = %v, want %v", last.Type, BulkJobEventCompleted)
+	}
+
+	sf = &Salesforce{}
+	if _, err := sf.WatchJob(context.Background(), "750"); err == nil {
+		t.Errorf("WatchJob() expected validation error")
+	}
+}
@@ -0,0 +1,90 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	snapshotReferenceId = "snapshot"
+	deleteReferenceId   = "delete"
+)
+
+// retrieves fields of each record in the same composite request that deletes it, so the snapshot can't race
+// the delete; the snapshots are returned in the results of DeleteCollection
+func WithDeleteSnapshot(fields ...string) DMLOption {
+	return func(options *dmlOptions) {
+		options.snapshotFields = append(options.snapshotFields, fields...)
+	}
+}
+
+// ids is a comma separated list of up to 200 record ids; the composite request is all or none, so nothing is
+// deleted when the snapshot fails
+func deleteWithSnapshot(auth *authentication, sObjectName string, ids string, options dmlOptions) ([]SalesforceResult, ResponseMetadata, error) {
+	builder := NewCompositeBuilder(true).
+		Add(http.MethodGet, "/composite/sobjects/"+sObjectName+"?ids="+ids+"&fields="+url.QueryEscape(strings.Join(options.snapshotFields, ",")), nil, snapshotReferenceId).
+		Add(http.MethodDelete, "/composite/sobjects?ids="+ids+"&allOrNone="+strconv.FormatBool(options.allOrNone), nil, deleteReferenceId)
+	compositeResults, err := doExecuteComposite(auth, builder, dmlOptions{headers: options.headers, timeout: options.timeout, ctx: options.ctx})
+	if err != nil {
+		return nil, ResponseMetadata{}, err
+	}
+
+	var snapshots []map[string]any
+	var results []SalesforceResult
+	for _, result := range compositeResults.Results {
+		if result.HttpStatusCode >= http.StatusBadRequest {
+			return nil, compositeResults.ResponseMetadata, errors.New(result.ReferenceId + ": " + string(result.Body))
+		}
+		switch result.ReferenceId {
+		case snapshotReferenceId:
+			err = result.Decode(&snapshots)
+		case deleteReferenceId:
+			err = result.Decode(&results)
+		}
+		if err != nil {
+			return nil, compositeResults.ResponseMetadata, err
+		}
+	}
+	// retrieved records are in the order of the ids, with null for records that weren't found
+	for i := range results {
+		if i < len(snapshots) && snapshots[i] != nil {
+			delete(snapshots[i], "attributes")
+			results[i].Snapshot = snapshots[i]
+		}
+	}
+	return results, compositeResults.ResponseMetadata, nil
+}
+
+func doDeleteOneWithSnapshot(auth *authentication, sObjectName string, record any, fields []string, options dmlOptions) (map[string]any, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("at least one snapshot field is required")
+	}
+	recordMap, err := convertToMap(record, options.nullNilPointers)
+	if err != nil {
+		return nil, err
+	}
+	recordId, ok := recordMap["Id"].(string)
+	if !ok || recordId == "" {
+		return nil, errors.New("salesforce id not found in object data")
+	}
+
+	options.snapshotFields = fields
+	results, _, err := deleteWithSnapshot(auth, sObjectName, recordId, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, errors.New("unexpected number of delete results: " + strconv.Itoa(len(results)))
+	}
+	if !results[0].Success {
+		messages := []string{}
+		for _, sfError := range results[0].Errors {
+			messages = append(messages, sfError.StatusCode+": "+sfError.Message)
+		}
+		return results[0].Snapshot, errors.New(strings.Join(messages, "; "))
+	}
+	return results[0].Snapshot, nil
+}
@@ -0,0 +1,152 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// answers composite snapshot and delete requests, treating ids starting with "missing" as records that don't exist
+func setupSnapshotServer(t *testing.T, requests *[]compositeBuilderRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/composite" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		compReq := compositeBuilderRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&compReq); err != nil {
+			t.Fatal(err.Error())
+		}
+		*requests = append(*requests, compReq)
+		results := CompositeResults{}
+		for _, subReq := range compReq.CompositeRequest {
+			subUrl, err := url.Parse(subReq.Url)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			ids := strings.Split(subUrl.Query().Get("ids"), ",")
+			fields := strings.Split(subUrl.Query().Get("fields"), ",")
+			var body any
+			status := http.StatusOK
+			switch subReq.ReferenceId {
+			case snapshotReferenceId:
+				if fields[0] == "Invalid__c" {
+					status = http.StatusBadRequest
+					body = []SalesforceErrorMessage{{ErrorCode: "INVALID_FIELD", Message: "No such column 'Invalid__c' on sobject of type Account"}}
+					break
+				}
+				records := []map[string]any{}
+				for _, id := range ids {
+					if strings.HasPrefix(id, "missing") {
+						records = append(records, nil)
+						continue
+					}
+					record := map[string]any{"attributes": map[string]any{"type": "Account"}}
+					for _, field := range fields {
+						record[field] = field + " of " + id
+					}
+					records = append(records, record)
+				}
+				body = records
+			case deleteReferenceId:
+				deleted := []SalesforceResult{}
+				for _, id := range ids {
+					if strings.HasPrefix(id, "missing") {
+						deleted = append(deleted, SalesforceResult{Errors: []SalesforceErrorMessage{{StatusCode: "ENTITY_IS_DELETED", Message: "entity is deleted"}}})
+						continue
+					}
+					deleted = append(deleted, SalesforceResult{Id: id, Success: true})
+				}
+				body = deleted
+			}
+			if status == http.StatusOK && len(results.Results) > 0 && results.Results[0].HttpStatusCode >= http.StatusBadRequest {
+				status = http.StatusBadRequest
+				body = []SalesforceErrorMessage{{ErrorCode: "PROCESSING_HALTED"}}
+			}
+			encoded, _ := json.Marshal(body)
+			results.Results = append(results.Results, CompositeResult{Body: encoded, HttpStatusCode: status, ReferenceId: subReq.ReferenceId})
+		}
+		if err := json.NewEncoder(w).Encode(results); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+}
+
+func Test_doDeleteCollection_snapshot(t *testing.T) {
+	requests := []compositeBuilderRequest{}
+	server := setupSnapshotServer(t, &requests)
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	records := []map[string]any{{"Id": "001A"}, {"Id": "missingB"}, {"Id": "001C"}}
+	results, err := doDeleteCollection(&sfAuth, "Account", records, 2, newDMLOptions(WithDeleteSnapshot("Name", "Phone")))
+	if err != nil {
+		t.Fatalf("doDeleteCollection() error = %v", err)
+	}
+	want := []SalesforceResult{
+		{Id: "001A", Success: true, Index: 0, Snapshot: map[string]any{"Name": "Name of 001A", "Phone": "Phone of 001A"}},
+		{Errors: []SalesforceErrorMessage{{StatusCode: "ENTITY_IS_DELETED", Message: "entity is deleted"}}, Index: 1},
+		{Id: "001C", Success: true, Index: 2, Snapshot: map[string]any{"Name": "Name of 001C", "Phone": "Phone of 001C"}},
+	}
+	for i := range results.Results {
+		results.Results[i].ResponseMetadata = ResponseMetadata{}
+	}
+	if !reflect.DeepEqual(results.Results, want) || !results.HasSalesforceErrors {
+		t.Errorf("doDeleteCollection() = %+v, want %+v", results.Results, want)
+	}
+	if len(requests) != 2 || !requests[0].AllOrNone {
+		t.Fatalf("composite requests = %+v, want 2 all or none requests", requests)
+	}
+	wantUrls := []string{
+		"/services/data/" + apiVersion + "/composite/sobjects/Account?ids=001A,missingB&fields=Name%2CPhone",
+		"/services/data/" + apiVersion + "/composite/sobjects?ids=001A,missingB&allOrNone=false",
+	}
+	for i, subReq := range requests[0].CompositeRequest {
+		if subReq.Url != wantUrls[i] {
+			t.Errorf("subrequest url = %s, want %s", subReq.Url, wantUrls[i])
+		}
+	}
+
+	if _, err := doDeleteCollection(&sfAuth, "Account", records, 2, newDMLOptions(WithDeleteSnapshot("Invalid__c"))); err == nil {
+		t.Errorf("doDeleteCollection() expected error when the snapshot fails")
+	}
+}
+
+func TestSalesforce_DeleteOneWithSnapshot(t *testing.T) {
+	requests := []compositeBuilderRequest{}
+	server := setupSnapshotServer(t, &requests)
+	defer server.Close()
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+
+	snapshot, err := sf.DeleteOneWithSnapshot("Account", map[string]any{"Id": "001A"}, []string{"Name"})
+	if err != nil {
+		t.Fatalf("DeleteOneWithSnapshot() error = %v", err)
+	}
+	if want := (map[string]any{"Name": "Name of 001A"}); !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("DeleteOneWithSnapshot() = %v, want %v", snapshot, want)
+	}
+
+	tests := []struct {
+		name   string
+		sf     *Salesforce
+		record any
+		fields []string
+	}{
+		{name: "record_not_deleted", sf: sf, record: map[string]any{"Id": "missingB"}, fields: []string{"Name"}},
+		{name: "snapshot_failed", sf: sf, record: map[string]any{"Id": "001A"}, fields: []string{"Invalid__c"}},
+		{name: "no_fields", sf: sf, record: map[string]any{"Id": "001A"}},
+		{name: "no_id", sf: sf, record: map[string]any{"Name": "test"}, fields: []string{"Name"}},
+		{name: "validation_fail", sf: &Salesforce{}, record: map[string]any{"Id": "001A"}, fields: []string{"Name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.sf.DeleteOneWithSnapshot("Account", tt.record, tt.fields); err == nil {
+				t.Errorf("DeleteOneWithSnapshot() expected error")
+			}
+		})
+	}
+}
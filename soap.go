@@ -0,0 +1,104 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type soapEnvelope struct {
+	XMLName xml.Name   `xml:"soapenv:Envelope"`
+	SoapNs  string     `xml:"xmlns:soapenv,attr"`
+	Header  soapHeader `xml:"soapenv:Header>SessionHeader"`
+	Body    soapAction `xml:"soapenv:Body"`
+}
+
+type soapHeader struct {
+	Namespace string `xml:"xmlns,attr"`
+	SessionId string `xml:"sessionId"`
+}
+
+type soapAction struct {
+	Retrieve            *soapRetrieve            `xml:"retrieve,omitempty"`
+	CheckRetrieveStatus *soapCheckRetrieveStatus `xml:"checkRetrieveStatus,omitempty"`
+	Undelete            *soapIds                 `xml:"undelete,omitempty"`
+	EmptyRecycleBin     *soapIds                 `xml:"emptyRecycleBin,omitempty"`
+}
+
+type soapResponse struct {
+	Fault struct {
+		FaultCode   string `xml:"faultcode"`
+		FaultString string `xml:"faultstring"`
+	} `xml:"Body>Fault"`
+	RetrieveResult            RetrieveResult `xml:"Body>retrieveResponse>result"`
+	CheckRetrieveStatusResult struct {
+		RetrieveResult
+		ZipFile string `xml:"zipFile"`
+	} `xml:"Body>checkRetrieveStatusResponse>result"`
+	UndeleteResults        []soapSaveResult `xml:"Body>undeleteResponse>result"`
+	EmptyRecycleBinResults []soapSaveResult `xml:"Body>emptyRecycleBinResponse>result"`
+}
+
+const (
+	soapEnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	xmlType               = "text/xml; charset=UTF-8"
+)
+
+// some operations, such as metadata retrieval and undelete, are only available through the soap api
+func doSoapRequest(auth *authentication, route string, namespace string, action string, body soapAction, polling bool) (soapResponse, error) {
+	instanceUrl, accessToken := auth.session()
+	envelope := soapEnvelope{
+		SoapNs: soapEnvelopeNamespace,
+		Header: soapHeader{Namespace: namespace, SessionId: accessToken},
+		Body:   body,
+	}
+	requestBody, err := xml.Marshal(envelope)
+	if err != nil {
+		return soapResponse{}, err
+	}
+
+	endpoint := instanceUrl + route + strings.TrimPrefix(apiVersion, "v")
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return soapResponse{}, err
+	}
+	req.Header.Set("User-Agent", "go-salesforce")
+	req.Header.Set("Content-Type", xmlType)
+	req.Header.Set("SOAPAction", action)
+	if err := auth.config.limiter(polling).wait(req.Context()); err != nil {
+		return soapResponse{}, err
+	}
+
+	req, endTelemetry := startRequestTelemetry(auth, req, TelemetryRequest{
+		Operation:  "POST soap/" + action,
+		Method:     http.MethodPost,
+		Path:       route,
+		IsPolling:  polling,
+		ApiVersion: apiVersion,
+	})
+	resp, err := auth.config.client().Do(req)
+	endTelemetry(resp, err)
+	if err != nil {
+		return soapResponse{}, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return soapResponse{}, err
+	}
+
+	result := soapResponse{}
+	if err := xml.Unmarshal(respBody, &result); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 300 {
+			return soapResponse{}, errors.New(resp.Status + ": " + string(respBody))
+		}
+		return soapResponse{}, err
+	}
+	if result.Fault.FaultString != "" || result.Fault.FaultCode != "" {
+		return soapResponse{}, errors.New(result.Fault.FaultCode + ": " + result.Fault.FaultString)
+	}
+	return result, nil
+}
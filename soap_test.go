@@ -0,0 +1,17 @@
+package salesforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_doSoapRequest(t *testing.T) {
+	server, sfAuth := setupTestServer("not xml", http.StatusInternalServerError)
+	defer server.Close()
+
+	_, err := doSoapRequest(&sfAuth, partnerSoapRoute, partnerNamespace, "undelete", soapAction{}, false)
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("doSoapRequest() error = %v, want status error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package salesforce
+
+// binds record operations to a single sObject, so that its name is only given (and checked) once
+type SObjectHandle struct {
+	sf   *Salesforce
+	name string
+	err  error
+}
+
+func (handle *SObjectHandle) Name() string {
+	return handle.name
+}
+
+// describes the sObject, returning an error if it doesn't exist or isn't accessible to the user
+func (handle *SObjectHandle) Validate() error {
+	if handle.err != nil {
+		return handle.err
+	}
+	_, err := handle.sf.DescribeSObject(handle.name)
+	return err
+}
+
+func (handle *SObjectHandle) Create(record any, opts ...DMLOption) (SalesforceResult, error) {
+	if handle.err != nil {
+		return SalesforceResult{}, handle.err
+	}
+	return handle.sf.InsertOne(handle.name, record, opts...)
+}
+
+func (handle *SObjectHandle) Update(record any, opts ...DMLOption) error {
+	if handle.err != nil {
+		return handle.err
+	}
+	return handle.sf.UpdateOne(handle.name, record, opts...)
+}
+
+func (handle *SObjectHandle) Upsert(externalIdFieldName string, record any, opts ...DMLOption) (SalesforceResult, error) {
+	if handle.err != nil {
+		return SalesforceResult{}, handle.err
+	}
+	return handle.sf.UpsertOne(handle.name, externalIdFieldName, record, opts...)
+}
+
+func (handle *SObjectHandle) Delete(record any, opts ...DMLOption) error {
+	if handle.err != nil {
+		return handle.err
+	}
+	return handle.sf.DeleteOne(handle.name, record, opts...)
+}
+
+func (handle *SObjectHandle) Get(recordId string, fields []string, result any) error {
+	if handle.err != nil {
+		return handle.err
+	}
+	return handle.sf.GetOne(handle.name, recordId, fields, result)
+}
+
+// starts a query builder on the sObject
+func (handle *SObjectHandle) Select(fields ...string) *SoqlBuilder {
+	builder := Select(fields...).From(handle.name)
+	builder.setErr(handle.err)
+	return builder
+}
+
+// queries the given fields of records matching the where condition, which can reference bind variables
+// in params as :name; an empty condition queries every record
+func (handle *SObjectHandle) Query(fields []string, where string, params map[string]any, result any, opts ...RequestOption) error {
+	builder := handle.Select(fields...)
+	if where != "" {
+		builder.Where(where)
+	}
+	for name, value := range params {
+		builder.Bind(name, value)
+	}
+	query, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	return handle.sf.Query(query, result, opts...)
+}
@@ -0,0 +1,109 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSalesforce_SObject(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		if r.URL.RawQuery != "" {
+			query, _ := url.QueryUnescape(r.URL.RawQuery)
+			request += "?" + query
+		}
+		requests = append(requests, request)
+		var resp string
+		switch {
+		case r.Method == http.MethodPost || (r.Method == http.MethodPatch && r.URL.Path != "/services/data/"+apiVersion+"/sobjects/Account/1234"):
+			w.WriteHeader(http.StatusCreated)
+			resp = `{"id": "1234", "success": true, "errors": []}`
+		case r.Method == http.MethodPatch || r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case r.URL.Path == "/services/data/"+apiVersion+"/query/":
+			resp = `{"totalSize": 1, "done": true, "records": [{"Id": "1234", "Name": "Acme"}]}`
+		case r.URL.Path == "/services/data/"+apiVersion+"/sobjects/Account/describe":
+			resp = `{"name": "Account"}`
+		case r.URL.Path == "/services/data/"+apiVersion+"/sobjects/Accout/describe":
+			w.WriteHeader(http.StatusNotFound)
+			resp = `[{"errorCode": "NOT_FOUND", "message": "The requested resource does not exist"}]`
+		default:
+			resp = `{"Id": "1234", "Name": "Acme"}`
+		}
+		if _, err := w.Write([]byte(resp)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+
+	type account struct {
+		Id   string
+		Name string
+	}
+	accounts := sf.SObject("Account")
+	if accounts.Name() != "Account" {
+		t.Errorf("Name() = %v, want Account", accounts.Name())
+	}
+	if err := accounts.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if _, err := accounts.Create(account{Name: "Acme"}); err != nil {
+		t.Errorf("Create() error = %v", err)
+	}
+	if err := accounts.Update(account{Id: "1234", Name: "Acme"}); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+	if _, err := accounts.Upsert("External_Id__c", map[string]any{"External_Id__c": "A-1", "Name": "Acme"}); err != nil {
+		t.Errorf("Upsert() error = %v", err)
+	}
+	if err := accounts.Delete(account{Id: "1234"}); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	got := account{}
+	if err := accounts.Get("1234", []string{"Name"}, &got); err != nil || got.Name != "Acme" {
+		t.Errorf("Get() = %v, error = %v", got, err)
+	}
+	results := []account{}
+	err := accounts.Query([]string{"Id", "Name"}, "Name = :name", map[string]any{"name": "Acme"}, &results)
+	if err != nil || len(results) != 1 {
+		t.Errorf("Query() = %v, error = %v", results, err)
+	}
+
+	want := []string{
+		"GET /services/data/" + apiVersion + "/sobjects/Account/describe",
+		"POST /services/data/" + apiVersion + "/sobjects/Account",
+		"PATCH /services/data/" + apiVersion + "/sobjects/Account/1234",
+		"PATCH /services/data/" + apiVersion + "/sobjects/Account/External_Id__c/A-1",
+		"DELETE /services/data/" + apiVersion + "/sobjects/Account/1234",
+		"GET /services/data/" + apiVersion + "/sobjects/Account/1234?fields=Name",
+		"GET /services/data/" + apiVersion + "/query/?q=SELECT Id, Name FROM Account WHERE Name = 'Acme'",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	if err := sf.SObject("Accout").Validate(); err == nil {
+		t.Errorf("Validate() expected error for unknown sObject")
+	}
+
+	requests = nil
+	invalid := sf.SObject("Account; DELETE")
+	if _, err := invalid.Create(account{Name: "Acme"}); err == nil {
+		t.Errorf("Create() expected error for invalid sObject name")
+	}
+	if err := invalid.Query([]string{"Id"}, "", nil, &results); err == nil {
+		t.Errorf("Query() expected error for invalid sObject name")
+	}
+	if err := sf.SObject("").Update(account{Id: "1234"}); err == nil {
+		t.Errorf("Update() expected error for empty sObject name")
+	}
+	if len(requests) != 0 {
+		t.Errorf("invalid handles sent requests: %v", requests)
+	}
+}
@@ -0,0 +1,127 @@
+package salesforce
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// field and object names may include relationship paths such as Account.Owner.Name
+var soqlNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)*$`)
+
+type SoqlBuilder struct {
+	fields     []string
+	sObject    string
+	conditions []string
+	params     map[string]any
+	orderBy    []string
+	limit      int
+	offset     int
+	err        error
+}
+
+// starts a query that selects the given fields, which can be chosen at runtime since they're validated as names
+func Select(fields ...string) *SoqlBuilder {
+	builder := &SoqlBuilder{}
+	for _, field := range fields {
+		builder.setErr(validateSoqlName(field))
+	}
+	builder.fields = append(builder.fields, fields...)
+	return builder
+}
+
+func (builder *SoqlBuilder) From(sObjectName string) *SoqlBuilder {
+	builder.setErr(validateSoqlName(sObjectName))
+	builder.sObject = sObjectName
+	return builder
+}
+
+// adds a condition that can reference bind variables as :name; multiple conditions are combined with AND
+func (builder *SoqlBuilder) Where(condition string) *SoqlBuilder {
+	if strings.TrimSpace(condition) == "" {
+		builder.setErr(errors.New("where condition must not be empty"))
+	}
+	builder.conditions = append(builder.conditions, condition)
+	return builder
+}
+
+// sets the value of a bind variable used in a where condition
+func (builder *SoqlBuilder) Bind(name string, value any) *SoqlBuilder {
+	if builder.params == nil {
+		builder.params = map[string]any{}
+	}
+	builder.params[name] = value
+	return builder
+}
+
+func (builder *SoqlBuilder) OrderBy(field string) *SoqlBuilder {
+	builder.setErr(validateSoqlName(field))
+	builder.orderBy = append(builder.orderBy, field+" ASC")
+	return builder
+}
+
+func (builder *SoqlBuilder) OrderByDesc(field string) *SoqlBuilder {
+	builder.setErr(validateSoqlName(field))
+	builder.orderBy = append(builder.orderBy, field+" DESC")
+	return builder
+}
+
+func (builder *SoqlBuilder) Limit(limit int) *SoqlBuilder {
+	if limit < 1 {
+		builder.setErr(errors.New("limit must be greater than 0"))
+	}
+	builder.limit = limit
+	return builder
+}
+
+func (builder *SoqlBuilder) Offset(offset int) *SoqlBuilder {
+	if offset < 0 {
+		builder.setErr(errors.New("offset must not be negative"))
+	}
+	builder.offset = offset
+	return builder
+}
+
+// returns the SOQL query, or the first error recorded while building it
+func (builder *SoqlBuilder) Build() (string, error) {
+	if builder.err != nil {
+		return "", builder.err
+	}
+	if len(builder.fields) == 0 {
+		return "", errors.New("at least one field is required")
+	}
+	if builder.sObject == "" {
+		return "", errors.New("sObject name is required")
+	}
+
+	query := "SELECT " + strings.Join(builder.fields, ", ") + " FROM " + builder.sObject
+	if len(builder.conditions) == 1 {
+		query += " WHERE " + builder.conditions[0]
+	} else if len(builder.conditions) > 1 {
+		query += " WHERE (" + strings.Join(builder.conditions, ") AND (") + ")"
+	}
+	if len(builder.orderBy) > 0 {
+		query += " ORDER BY " + strings.Join(builder.orderBy, ", ")
+	}
+	if builder.limit > 0 {
+		query += " LIMIT " + strconv.Itoa(builder.limit)
+	}
+	if builder.offset > 0 {
+		query += " OFFSET " + strconv.Itoa(builder.offset)
+	}
+	return bindQueryParams(query, builder.params)
+}
+
+func (builder *SoqlBuilder) setErr(err error) {
+	if builder.err == nil {
+		builder.err = err
+	}
+}
+
+func validateSoqlName(name string) error {
+	if !soqlNamePattern.MatchString(name) {
+		return errors.New("invalid field or object name: " + name)
+	}
+	return nil
+}
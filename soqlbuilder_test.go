@@ -0,0 +1,92 @@
+package salesforce
+
+import (
+	"testing"
+)
+
+func TestSoqlBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SoqlBuilder
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "select_from",
+			builder: Select("Id", "Name", "Owner.Name").From("Account"),
+			want:    "SELECT Id, Name, Owner.Name FROM Account",
+			wantErr: false,
+		},
+		{
+			name: "full_query",
+			builder: Select("Id", "LastName").From("Contact").
+				Where("LastName = :lastName").
+				Where("CreatedDate = LAST_N_DAYS:30 OR Email != null").
+				Bind("lastName", "O'Brien").
+				OrderBy("LastName").
+				OrderByDesc("CreatedDate").
+				Limit(10).
+				Offset(20),
+			want:    `SELECT Id, LastName FROM Contact WHERE (LastName = 'O\'Brien') AND (CreatedDate = LAST_N_DAYS:30 OR Email != null) ORDER BY LastName ASC, CreatedDate DESC LIMIT 10 OFFSET 20`,
+			wantErr: false,
+		},
+		{
+			name:    "in_clause",
+			builder: Select("Id").From("Account").Where("Id IN :ids").Bind("ids", []string{"001A", "001B"}),
+			want:    "SELECT Id FROM Account WHERE Id IN ('001A','001B')",
+			wantErr: false,
+		},
+		{
+			name:    "invalid_field",
+			builder: Select("Id FROM User --").From("Account"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid_object",
+			builder: Select("Id").From("Account WHERE"),
+			wantErr: true,
+		},
+		{
+			name:    "missing_fields",
+			builder: Select().From("Account"),
+			wantErr: true,
+		},
+		{
+			name:    "missing_object",
+			builder: Select("Id"),
+			wantErr: true,
+		},
+		{
+			name:    "missing_bind_variable",
+			builder: Select("Id").From("Account").Where("Name = :name"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid_limit",
+			builder: Select("Id").From("Account").Limit(0),
+			wantErr: true,
+		},
+		{
+			name:    "invalid_offset",
+			builder: Select("Id").From("Account").Offset(-1),
+			wantErr: true,
+		},
+		{
+			name:    "empty_condition",
+			builder: Select("Id").From("Account").Where(" "),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SoqlBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SoqlBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+)
+
+type StreamingEvent struct {
+	Channel  string
+	ReplayId int64
+	Payload  map[string]any
+	Raw      json.RawMessage
+}
+
+type bayeuxAdvice struct {
+	Reconnect string `json:"reconnect,omitempty"`
+	Interval  int    `json:"interval,omitempty"`
+	Timeout   int    `json:"timeout,omitempty"`
+}
+
+type bayeuxMessage struct {
+	Channel                  string          `json:"channel"`
+	Id                       string          `json:"id,omitempty"`
+	ClientId                 string          `json:"clientId,omitempty"`
+	Version                  string          `json:"version,omitempty"`
+	SupportedConnectionTypes []string        `json:"supportedConnectionTypes,omitempty"`
+	ConnectionType           string          `json:"connectionType,omitempty"`
+	Subscription             string          `json:"subscription,omitempty"`
+	Successful               bool            `json:"successful,omitempty"`
+	Error                    string          `json:"error,omitempty"`
+	Advice                   *bayeuxAdvice   `json:"advice,omitempty"`
+	Data                     json.RawMessage `json:"data,omitempty"`
+	Ext                      map[string]any  `json:"ext,omitempty"`
+}
+
+type streamingEventData struct {
+	Event struct {
+		ReplayId int64 `json:"replayId"`
+	} `json:"event"`
+	Payload map[string]any `json:"payload"`
+	SObject map[string]any `json:"sobject"`
+}
+
+type streamingClient struct {
+	auth     *authentication
+	client   *http.Client
+	clientId string
+	// the token of the last request, refreshed when salesforce rejects it
+	accessToken string
+}
+
+const (
+	ReplayNewEvents     = -1
+	ReplayAllEvents     = -2
+	metaHandshake       = "/meta/handshake"
+	metaSubscribe       = "/meta/subscribe"
+	metaConnect         = "/meta/connect"
+	metaDisconnect      = "/meta/disconnect"
+	longPolling         = "long-polling"
+	reconnectHandshake  = "handshake"
+	reconnectNone       = "none"
+	bayeuxVersion       = "1.0"
+	cometdEndpointRoute = "/cometd/"
+)
+
+// salesforce reports an expired session as a 401 response, or as a 401:: error in a bayeux message
+var errStreamingUnauthorized = errors.New("streaming session unauthorized")
+
+func isBayeuxUnauthorized(err string) bool {
+	return strings.HasPrefix(err, "401::")
+}
+
+func bayeuxError(message string, err string) error {
+	if isBayeuxUnauthorized(err) {
+		return fmt.Errorf("%w: %s", errStreamingUnauthorized, err)
+	}
+	return errors.New(message + err)
+}
+
+func newStreamingClient(auth *authentication) (*streamingClient, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	// the long-polling connection is held open by the server, so only the transport is shared with the configured client
+	client := &http.Client{
+		Transport: auth.config.client().Transport,
+		Jar:       jar,
+	}
+	return &streamingClient{auth: auth, client: client}, nil
+}
+
+func (sc *streamingClient) send(ctx context.Context, messages []bayeuxMessage) ([]bayeuxMessage, error) {
+	body, err := json.Marshal(messages)
+	if err != nil {
+		return nil, err
+	}
+	instanceUrl, accessToken := sc.auth.session()
+	sc.accessToken = accessToken
+	endpoint := instanceUrl + cometdEndpointRoute + strings.TrimPrefix(apiVersion, "v")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "go-salesforce")
+	req.Header.Set("Content-Type", jsonType)
+	req.Header.Set("Accept", jsonType)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("%w: %s", errStreamingUnauthorized, respBody)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 300 {
+		return nil, errors.New(resp.Status + ": " + string(respBody))
+	}
+
+	var results []bayeuxMessage
+	if err := json.Unmarshal(respBody, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (sc *streamingClient) handshake(ctx context.Context) error {
+	results, err := sc.send(ctx, []bayeuxMessage{{
+		Channel:                  metaHandshake,
+		Version:                  bayeuxVersion,
+		SupportedConnectionTypes: []string{longPolling},
+	}})
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Channel == metaHandshake {
+			if !result.Successful || result.ClientId == "" {
+				return bayeuxError("streaming handshake failed: ", result.Error)
+			}
+			sc.clientId = result.ClientId
+			return nil
+		}
+	}
+	return errors.New("streaming handshake failed: no response")
+}
+
+func (sc *streamingClient) subscribe(ctx context.Context, channel string, replayId int64) error {
+	results, err := sc.send(ctx, []bayeuxMessage{{
+		Channel:      metaSubscribe,
+		ClientId:     sc.clientId,
+		Subscription: channel,
+		Ext:          map[string]any{"replay": map[string]int64{channel: replayId}},
+	}})
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Channel == metaSubscribe && !result.Successful {
+			return bayeuxError("streaming subscription to "+channel+" failed: ", result.Error)
+		}
+	}
+	return nil
+}
+
+// refreshes an expired session once before giving up
+func (sc *streamingClient) handshakeAndSubscribe(ctx context.Context, channel string, replayId int64) error {
+	err := sc.handshake(ctx)
+	if err == nil {
+		err = sc.subscribe(ctx, channel, replayId)
+	}
+	if !errors.Is(err, errStreamingUnauthorized) {
+		return err
+	}
+	return sc.reauthenticate(ctx, channel, replayId)
+}
+
+func (sc *streamingClient) reauthenticate(ctx context.Context, channel string, replayId int64) error {
+	if err := refreshExpiredSession(sc.auth, sc.accessToken); err != nil {
+		return err
+	}
+	if err := sc.handshake(ctx); err != nil {
+		return err
+	}
+	return sc.subscribe(ctx, channel, replayId)
+}
+
+func (sc *streamingClient) disconnect() {
+	// best effort, the server expires unused clients on its own
+	_, _ = sc.send(context.Background(), []bayeuxMessage{{
+		Channel:  metaDisconnect,
+		ClientId: sc.clientId,
+	}})
+}
+
+func decodeStreamingEvent(message bayeuxMessage) (StreamingEvent, error) {
+	data := streamingEventData{}
+	if err := json.Unmarshal(message.Data, &data); err != nil {
+		return StreamingEvent{}, err
+	}
+	payload := data.Payload
+	if payload == nil {
+		payload = data.SObject
+	}
+	return StreamingEvent{
+		Channel:  message.Channel,
+		ReplayId: data.Event.ReplayId,
+		Payload:  payload,
+		Raw:      message.Data,
+	}, nil
+}
+
+func doSubscribe(ctx context.Context, auth *authentication, channel string, replayId int64, handler func(StreamingEvent) error) error {
+	sc, err := newStreamingClient(auth)
+	if err != nil {
+		return err
+	}
+	if err := sc.handshakeAndSubscribe(ctx, channel, replayId); err != nil {
+		return err
+	}
+	defer sc.disconnect()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		results, err := sc.send(ctx, []bayeuxMessage{{
+			Channel:        metaConnect,
+			ClientId:       sc.clientId,
+			ConnectionType: longPolling,
+		}})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if !errors.Is(err, errStreamingUnauthorized) {
+				return err
+			}
+			// resume from the last event that was handed to the caller
+			if err := sc.reauthenticate(ctx, channel, replayId); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, result := range results {
+			if result.Channel == metaConnect {
+				if result.Successful {
+					continue
+				}
+				if isBayeuxUnauthorized(result.Error) {
+					if err := sc.reauthenticate(ctx, channel, replayId); err != nil {
+						return err
+					}
+					continue
+				}
+				if result.Advice == nil || result.Advice.Reconnect == reconnectNone {
+					return errors.New("streaming connection closed: " + result.Error)
+				}
+				if result.Advice.Reconnect == reconnectHandshake {
+					// resume from the last event that was handed to the caller
+					if err := sc.handshakeAndSubscribe(ctx, channel, replayId); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if strings.HasPrefix(result.Channel, "/meta/") || len(result.Data) == 0 {
+				continue
+			}
+
+			event, err := decodeStreamingEvent(result)
+			if err != nil {
+				return err
+			}
+			if err := handler(event); err != nil {
+				return err
+			}
+			replayId = event.ReplayId
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func setupStreamingTestServer(t *testing.T, connectResponses [][]bayeuxMessage) (*httptest.Server, authentication) {
+	connects := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var messages []bayeuxMessage
+		if err := json.NewDecoder(r.Body).Decode(&messages); err != nil || len(messages) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var resp []bayeuxMessage
+		switch messages[0].Channel {
+		case metaHandshake:
+			resp = []bayeuxMessage{{Channel: metaHandshake, ClientId: "client1", Successful: true}}
+		case metaSubscribe:
+			resp = []bayeuxMessage{{Channel: metaSubscribe, Subscription: messages[0].Subscription, Successful: true}}
+		case metaConnect:
+			if connects < len(connectResponses) {
+				resp = connectResponses[connects]
+			} else {
+				resp = []bayeuxMessage{{Channel: metaConnect, Successful: false, Advice: &bayeuxAdvice{Reconnect: reconnectNone}}}
+			}
+			connects++
+		case metaDisconnect:
+			resp = []bayeuxMessage{{Channel: metaDisconnect, Successful: true}}
+		}
+		body, _ := json.Marshal(resp)
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	return server, sfAuth
+}
+
+func Test_decodeStreamingEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		message bayeuxMessage
+		want    StreamingEvent
+		wantErr bool
+	}{
+		{
+			name: "platform_event",
+			message: bayeuxMessage{
+				Channel: "/event/Test__e",
+				Data:    json.RawMessage(`{"event":{"replayId":5},"payload":{"Message__c":"hi"}}`),
+			},
+			want: StreamingEvent{
+				Channel:  "/event/Test__e",
+				ReplayId: 5,
+				Payload:  map[string]any{"Message__c": "hi"},
+				Raw:      json.RawMessage(`{"event":{"replayId":5},"payload":{"Message__c":"hi"}}`),
+			},
+			wantErr: false,
+		},
+		{
+			name: "push_topic",
+			message: bayeuxMessage{
+				Channel: "/topic/Accounts",
+				Data:    json.RawMessage(`{"event":{"replayId":1},"sobject":{"Id":"001"}}`),
+			},
+			want: StreamingEvent{
+				Channel:  "/topic/Accounts",
+				ReplayId: 1,
+				Payload:  map[string]any{"Id": "001"},
+				Raw:      json.RawMessage(`{"event":{"replayId":1},"sobject":{"Id":"001"}}`),
+			},
+			wantErr: false,
+		},
+		{
+			name: "bad_data",
+			message: bayeuxMessage{
+				Channel: "/event/Test__e",
+				Data:    json.RawMessage(`1`),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeStreamingEvent(tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeStreamingEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeStreamingEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doSubscribe(t *testing.T) {
+	eventData := json.RawMessage(`{"event":{"replayId":7},"payload":{"Message__c":"hi"}}`)
+	server, sfAuth := setupStreamingTestServer(t, [][]bayeuxMessage{
+		{{Channel: metaConnect, Successful: true}},
+		{{Channel: "/event/Test__e", Data: eventData}, {Channel: metaConnect, Successful: true}},
+	})
+	defer server.Close()
+
+	closedServer, closedAuth := setupStreamingTestServer(t, nil)
+	defer closedServer.Close()
+
+	badServer, badAuth := setupTestServer("", http.StatusUnauthorized)
+	defer badServer.Close()
+
+	errStop := errors.New("stop")
+
+	type args struct {
+		auth    *authentication
+		channel string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []StreamingEvent
+		wantErr error
+	}{
+		{
+			name: "receive_event",
+			args: args{
+				auth:    &sfAuth,
+				channel: "/event/Test__e",
+			},
+			want: []StreamingEvent{{
+				Channel:  "/event/Test__e",
+				ReplayId: 7,
+				Payload:  map[string]any{"Message__c": "hi"},
+				Raw:      eventData,
+			}},
+			wantErr: errStop,
+		},
+		{
+			name: "connection_closed",
+			args: args{
+				auth:    &closedAuth,
+				channel: "/event/Test__e",
+			},
+			want: nil,
+		},
+		{
+			name: "handshake_fail",
+			args: args{
+				auth:    &badAuth,
+				channel: "/event/Test__e",
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []StreamingEvent
+			err := doSubscribe(context.Background(), tt.args.auth, tt.args.channel, ReplayNewEvents, func(event StreamingEvent) error {
+				got = append(got, event)
+				return errStop
+			})
+			if err == nil {
+				t.Errorf("doSubscribe() expected error")
+				return
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("doSubscribe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doSubscribe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doSubscribe_cancelled(t *testing.T) {
+	server, sfAuth := setupStreamingTestServer(t, nil)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := doSubscribe(ctx, &sfAuth, "/event/Test__e", ReplayNewEvents, func(event StreamingEvent) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("doSubscribe() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_doSubscribe_expiredSession(t *testing.T) {
+	eventData := json.RawMessage(`{"event":{"replayId":7},"payload":{"Message__c":"hi"}}`)
+	tests := []struct {
+		name             string
+		token            string
+		connectResponses [][]bayeuxMessage
+	}{
+		{
+			name:  "handshake_unauthorized",
+			token: "expired",
+			connectResponses: [][]bayeuxMessage{
+				{{Channel: "/event/Test__e", Data: eventData}, {Channel: metaConnect, Successful: true}},
+			},
+		},
+		{
+			name:  "connect_unauthorized",
+			token: "fresh",
+			connectResponses: [][]bayeuxMessage{
+				{{Channel: metaConnect, Successful: false, Error: "401::Authentication invalid"}},
+				{{Channel: "/event/Test__e", Data: eventData}, {Channel: metaConnect, Successful: true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handshakes, connects := 0, 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer fresh" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				var messages []bayeuxMessage
+				if err := json.NewDecoder(r.Body).Decode(&messages); err != nil || len(messages) == 0 {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				var resp []bayeuxMessage
+				switch messages[0].Channel {
+				case metaHandshake:
+					handshakes++
+					resp = []bayeuxMessage{{Channel: metaHandshake, ClientId: "client1", Successful: true}}
+				case metaSubscribe:
+					resp = []bayeuxMessage{{Channel: metaSubscribe, Successful: true}}
+				case metaConnect:
+					resp = tt.connectResponses[min(connects, len(tt.connectResponses)-1)]
+					connects++
+				}
+				body, _ := json.Marshal(resp)
+				if _, err := w.Write(body); err != nil {
+					t.Fatal(err.Error())
+				}
+			}))
+			defer server.Close()
+
+			refreshes := 0
+			config, err := newConfiguration(WithTokenProvider(func(ctx context.Context) (string, string, error) {
+				refreshes++
+				return "fresh", "", nil
+			}))
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			sfAuth := authentication{InstanceUrl: server.URL, AccessToken: tt.token, config: config}
+
+			errStop := errors.New("stop")
+			var got []StreamingEvent
+			err = doSubscribe(context.Background(), &sfAuth, "/event/Test__e", ReplayNewEvents, func(event StreamingEvent) error {
+				got = append(got, event)
+				return errStop
+			})
+			if !errors.Is(err, errStop) {
+				t.Fatalf("doSubscribe() error = %v, want %v", err, errStop)
+			}
+			if len(got) != 1 || got[0].ReplayId != 7 {
+				t.Errorf("doSubscribe() = %v, want the event after reauthenticating", got)
+			}
+			if refreshes != 1 {
+				t.Errorf("doSubscribe() refreshed the session %d times, want 1", refreshes)
+			}
+			if tt.name == "connect_unauthorized" && handshakes != 2 {
+				t.Errorf("doSubscribe() sent %d handshakes, want 2", handshakes)
+			}
+		})
+	}
+}
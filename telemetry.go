@@ -0,0 +1,137 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// receives spans and metrics for api calls, adapters can forward them to OpenTelemetry or any other backend
+type Telemetry interface {
+	// called before each request, the returned context is attached to the request and end is called once it completes
+	StartRequest(ctx context.Context, request TelemetryRequest) (context.Context, func(TelemetryResponse))
+	// called before a request is sent again, such as after refreshing an expired session or when records are locked
+	RecordRetry(ctx context.Context, retry TelemetryRetry)
+	// called once a bulk job that is being waited on completes, fails, or is aborted
+	RecordBulkJob(ctx context.Context, job TelemetryBulkJob)
+}
+
+type TelemetryRequest struct {
+	// method and api resource, such as "POST composite/sobjects" or "GET query"
+	Operation  string
+	Method     string
+	Path       string
+	SObject    string
+	JobId      string
+	Batch      int // 1-based batch number, 0 when the request isn't part of a batched operation
+	IsRetry    bool
+	IsPolling  bool
+	ApiVersion string
+}
+
+type TelemetryResponse struct {
+	StatusCode int
+	Duration   time.Duration
+	Err        error // set when no response was received
+}
+
+type TelemetryRetry struct {
+	Operation string
+	Reason    string
+	Attempt   int
+	Records   int
+}
+
+type TelemetryBulkJob struct {
+	JobId            string
+	JobType          string
+	State            BulkJobState
+	RecordsProcessed int
+	RecordsFailed    int
+	Duration         time.Duration
+	Err              error
+}
+
+const (
+	retryReasonInvalidSession     = "invalid_session"
+	retryReasonLockedRecords      = "locked_records"
+	retryReasonServiceUnavailable = "service_unavailable"
+	retryReasonBulkJobLimit       = "bulk_job_limit"
+)
+
+func WithTelemetry(telemetry Telemetry) Option {
+	return func(config *configuration) error {
+		if telemetry == nil {
+			return errors.New("telemetry is nil")
+		}
+		config.telemetry = telemetry
+		return nil
+	}
+}
+
+// starts a request span, returning a function that ends it with the response and error of the request
+func startRequestTelemetry(auth *authentication, req *http.Request, request TelemetryRequest) (*http.Request, func(*http.Response, error)) {
+	if auth.config == nil || auth.config.telemetry == nil {
+		return req, func(*http.Response, error) {}
+	}
+	start := time.Now()
+	ctx, end := auth.config.telemetry.StartRequest(req.Context(), request)
+	return req.WithContext(ctx), func(resp *http.Response, err error) {
+		response := TelemetryResponse{Duration: time.Since(start), Err: err}
+		if resp != nil {
+			response.StatusCode = resp.StatusCode
+		}
+		end(response)
+	}
+}
+
+func recordRetryTelemetry(auth *authentication, retry TelemetryRetry) {
+	if auth.config == nil || auth.config.telemetry == nil {
+		return
+	}
+	auth.config.telemetry.RecordRetry(context.Background(), retry)
+}
+
+func recordBulkJobTelemetry(auth *authentication, job TelemetryBulkJob) {
+	if auth.config == nil || auth.config.telemetry == nil {
+		return
+	}
+	auth.config.telemetry.RecordBulkJob(context.Background(), job)
+}
+
+func newTelemetryRequest(payload requestPayload) TelemetryRequest {
+	path, _, _ := strings.Cut(payload.uri, "?")
+	request := TelemetryRequest{
+		Method:     payload.method,
+		Path:       path,
+		SObject:    payload.sObjectName,
+		Batch:      payload.batch,
+		IsRetry:    payload.retry,
+		IsPolling:  payload.polling,
+		ApiVersion: apiVersion,
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	resource := segments[0]
+	switch {
+	case resource == "sobjects" && len(segments) > 1:
+		request.SObject = segments[1]
+	case resource == "composite" && len(segments) > 1:
+		if segments[1] == "sobjects" && len(segments) > 2 {
+			request.SObject = segments[2]
+		}
+		resource += "/" + segments[1]
+	case resource == "jobs" && len(segments) > 1:
+		if len(segments) > 2 {
+			request.JobId = segments[2]
+		}
+		resource += "/" + segments[1]
+	case resource == "ui-api" && len(segments) > 2 && segments[1] == "object-info":
+		resource += "/" + segments[1]
+		request.SObject = segments[2]
+	}
+	request.Operation = payload.method + " " + resource
+	return request
+}
@@ -0,0 +1,194 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingTelemetry struct {
+	mu        sync.Mutex
+	requests  []TelemetryRequest
+	responses []TelemetryResponse
+	retries   []TelemetryRetry
+	jobs      []TelemetryBulkJob
+}
+
+type telemetryContextKey struct{}
+
+func (telemetry *recordingTelemetry) StartRequest(ctx context.Context, request TelemetryRequest) (context.Context, func(TelemetryResponse)) {
+	telemetry.mu.Lock()
+	defer telemetry.mu.Unlock()
+	telemetry.requests = append(telemetry.requests, request)
+	return context.WithValue(ctx, telemetryContextKey{}, request.Operation), func(response TelemetryResponse) {
+		telemetry.mu.Lock()
+		defer telemetry.mu.Unlock()
+		telemetry.responses = append(telemetry.responses, response)
+	}
+}
+
+func (telemetry *recordingTelemetry) RecordRetry(ctx context.Context, retry TelemetryRetry) {
+	telemetry.mu.Lock()
+	defer telemetry.mu.Unlock()
+	telemetry.retries = append(telemetry.retries, retry)
+}
+
+func (telemetry *recordingTelemetry) RecordBulkJob(ctx context.Context, job TelemetryBulkJob) {
+	telemetry.mu.Lock()
+	defer telemetry.mu.Unlock()
+	telemetry.jobs = append(telemetry.jobs, job)
+}
+
+func Test_newTelemetryRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload requestPayload
+		want    TelemetryRequest
+	}{
+		{
+			name:    "query",
+			payload: requestPayload{method: http.MethodGet, uri: "/query/?q=SELECT+Id+FROM+Account"},
+			want:    TelemetryRequest{Operation: "GET query", Method: http.MethodGet, Path: "/query/"},
+		},
+		{
+			name:    "single_record",
+			payload: requestPayload{method: http.MethodPatch, uri: "/sobjects/Account/001000000000001"},
+			want:    TelemetryRequest{Operation: "PATCH sobjects", Method: http.MethodPatch, Path: "/sobjects/Account/001000000000001", SObject: "Account"},
+		},
+		{
+			name:    "collection_batch",
+			payload: requestPayload{method: http.MethodPost, uri: "/composite/sobjects/", sObjectName: "Contact", batch: 2},
+			want:    TelemetryRequest{Operation: "POST composite/sobjects", Method: http.MethodPost, Path: "/composite/sobjects/", SObject: "Contact", Batch: 2},
+		},
+		{
+			name:    "collection_upsert",
+			payload: requestPayload{method: http.MethodPatch, uri: "/composite/sobjects/Account/External_Id__c"},
+			want:    TelemetryRequest{Operation: "PATCH composite/sobjects", Method: http.MethodPatch, Path: "/composite/sobjects/Account/External_Id__c", SObject: "Account"},
+		},
+		{
+			name:    "bulk_job",
+			payload: requestPayload{method: http.MethodGet, uri: "/jobs/ingest/7505e00000ABCDE", polling: true},
+			want:    TelemetryRequest{Operation: "GET jobs/ingest", Method: http.MethodGet, Path: "/jobs/ingest/7505e00000ABCDE", JobId: "7505e00000ABCDE", IsPolling: true},
+		},
+		{
+			name:    "object_info",
+			payload: requestPayload{method: http.MethodGet, uri: "/ui-api/object-info/Account/picklist-values/012000000000000AAA/Rating"},
+			want:    TelemetryRequest{Operation: "GET ui-api/object-info", Method: http.MethodGet, Path: "/ui-api/object-info/Account/picklist-values/012000000000000AAA/Rating", SObject: "Account"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.ApiVersion = apiVersion
+			if got := newTelemetryRequest(tt.payload); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newTelemetryRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTelemetry(t *testing.T) {
+	if _, err := newConfiguration(WithTelemetry(nil)); err == nil {
+		t.Errorf("WithTelemetry() expected error for nil telemetry")
+	}
+
+	telemetry := &recordingTelemetry{}
+	config, err := newConfiguration(WithTelemetry(telemetry), WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// the context returned by StartRequest is attached to the request
+			if req.Context().Value(telemetryContextKey{}) != "POST composite/sobjects" {
+				return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader("[]"))}, nil
+			}
+			return next.RoundTrip(req)
+		})
+	}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal([]SalesforceResult{{Id: "001000000000001", Success: true}})
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+
+	records := []map[string]any{{"Name": "Stark Industries"}, {"Name": "Wayne Enterprises"}}
+	if _, err := doInsertCollection(&sfAuth, "Account", records, 1, dmlOptions{}); err != nil {
+		t.Fatalf("doInsertCollection() error = %v", err)
+	}
+	want := []TelemetryRequest{
+		{Operation: "POST composite/sobjects", Method: http.MethodPost, Path: "/composite/sobjects/", SObject: "Account", Batch: 1, ApiVersion: apiVersion},
+		{Operation: "POST composite/sobjects", Method: http.MethodPost, Path: "/composite/sobjects/", SObject: "Account", Batch: 2, ApiVersion: apiVersion},
+	}
+	if !reflect.DeepEqual(telemetry.requests, want) {
+		t.Errorf("telemetry requests = %v, want %v", telemetry.requests, want)
+	}
+	if len(telemetry.responses) != 2 || telemetry.responses[0].StatusCode != http.StatusOK || telemetry.responses[0].Err != nil {
+		t.Errorf("telemetry responses = %v", telemetry.responses)
+	}
+}
+
+func Test_waitForJobResults_telemetry(t *testing.T) {
+	server, sfAuth := setupTestServer(BulkJobResults{Id: "1234", State: BulkJobStateJobComplete, NumberRecordsProcessed: 3, NumberRecordsFailed: 1}, http.StatusOK)
+	defer server.Close()
+	telemetry := &recordingTelemetry{}
+	sfAuth.config = &configuration{telemetry: telemetry}
+
+	if err := waitForJobResults(&sfAuth, "1234", ingestJobType, time.Nanosecond, nil); err != nil {
+		t.Fatalf("waitForJobResults() error = %v", err)
+	}
+	if len(telemetry.jobs) != 1 {
+		t.Fatalf("telemetry bulk jobs = %v, want 1", telemetry.jobs)
+	}
+	job := telemetry.jobs[0]
+	job.Duration = 0
+	want := TelemetryBulkJob{JobId: "1234", JobType: ingestJobType, State: BulkJobStateJobComplete, RecordsProcessed: 3, RecordsFailed: 1}
+	if !reflect.DeepEqual(job, want) {
+		t.Errorf("telemetry bulk job = %v, want %v", job, want)
+	}
+	if len(telemetry.requests) != 1 || !telemetry.requests[0].IsPolling || telemetry.requests[0].JobId != "1234" {
+		t.Errorf("telemetry requests = %v", telemetry.requests)
+	}
+}
+
+func Test_processSalesforceError_retryTelemetry(t *testing.T) {
+	serverRefreshed, sfAuthRefreshed := setupTestServer("", http.StatusOK)
+	defer serverRefreshed.Close()
+	serverInvalidSession, sfAuth := setupTestServer(sfAuthRefreshed, http.StatusOK)
+	defer serverInvalidSession.Close()
+	sfAuth.grantType = grantTypeClientCredentials
+	telemetry := &recordingTelemetry{}
+	sfAuth.config = &configuration{telemetry: telemetry}
+
+	body, _ := json.Marshal([]SalesforceErrorMessage{{
+		Message:    "error message",
+		StatusCode: strconv.Itoa(http.StatusUnauthorized),
+		ErrorCode:  invalidSessionIdError,
+	}})
+	resp := http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(strings.NewReader(string(body)))}
+	payload := requestPayload{method: http.MethodGet, uri: "/query/?q=SELECT+Id+FROM+Account", content: jsonType}
+	if _, err := processSalesforceError(resp, &sfAuth, payload, sfAuth.AccessToken); err != nil {
+		t.Fatalf("processSalesforceError() error = %v", err)
+	}
+
+	wantRetries := []TelemetryRetry{{Operation: "GET query", Reason: retryReasonInvalidSession, Attempt: 1}}
+	if !reflect.DeepEqual(telemetry.retries, wantRetries) {
+		t.Errorf("telemetry retries = %v, want %v", telemetry.retries, wantRetries)
+	}
+	if len(telemetry.requests) != 1 || !telemetry.requests[0].IsRetry {
+		t.Errorf("telemetry requests = %v", telemetry.requests)
+	}
+}
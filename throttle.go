@@ -0,0 +1,75 @@
+package salesforce
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type ThrottleProfile struct {
+	MaxBatchSize    int
+	MaxParallelJobs int
+	InterBatchDelay time.Duration
+}
+
+func WithThrottleProfile(sObjectName string, profile ThrottleProfile) Option {
+	return func(config *configuration) error {
+		if sObjectName == "" {
+			return errors.New("throttle profile requires an sObject name")
+		}
+		if profile.MaxBatchSize < 0 || profile.MaxParallelJobs < 0 || profile.InterBatchDelay < 0 {
+			return errors.New("throttle profile values must not be negative")
+		}
+		if config.throttleProfiles == nil {
+			config.throttleProfiles = map[string]ThrottleProfile{}
+		}
+		config.throttleProfiles[strings.ToLower(sObjectName)] = profile
+		return nil
+	}
+}
+
+func (config *configuration) throttleProfile(sObjectName string) ThrottleProfile {
+	if config == nil {
+		return ThrottleProfile{}
+	}
+	return config.throttleProfiles[strings.ToLower(sObjectName)]
+}
+
+func (profile ThrottleProfile) batchSize(batchSize int) int {
+	if profile.MaxBatchSize > 0 && batchSize > profile.MaxBatchSize {
+		return profile.MaxBatchSize
+	}
+	return batchSize
+}
+
+// sleeps between batches, skipping the first one
+func (profile ThrottleProfile) wait(batchNumber int) {
+	if batchNumber > 0 && profile.InterBatchDelay > 0 {
+		time.Sleep(profile.InterBatchDelay)
+	}
+}
+
+// blocks until a new bulk job can be created without exceeding the profile's parallel job limit
+func throttleBulkJob(auth *authentication, profile ThrottleProfile, jobIds []string) error {
+	profile.wait(len(jobIds))
+	if profile.MaxParallelJobs == 0 || len(jobIds) < profile.MaxParallelJobs {
+		return nil
+	}
+	// jobs are waited on in order, so every job before this one is already done
+	return waitForJobTerminal(auth, jobIds[len(jobIds)-profile.MaxParallelJobs], ingestJobType, (time.Second / 2))
+}
+
+// polls without a timeout until the job stops processing; failed and aborted jobs free up a slot just like completed ones,
+// and their errors are left for whoever collects the results
+func waitForJobTerminal(auth *authentication, bulkJobId string, jobType string, interval time.Duration) error {
+	for {
+		job, err := getJobResults(auth, jobType, bulkJobId, true)
+		if err != nil {
+			return err
+		}
+		if job.State.IsTerminal() {
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,228 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithThrottleProfile(t *testing.T) {
+	type args struct {
+		sObjectName string
+		profile     ThrottleProfile
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]ThrottleProfile
+		wantErr bool
+	}{
+		{
+			name: "add_profile",
+			args: args{
+				sObjectName: "Case",
+				profile:     ThrottleProfile{MaxBatchSize: 50, MaxParallelJobs: 1, InterBatchDelay: time.Second},
+			},
+			want:    map[string]ThrottleProfile{"case": {MaxBatchSize: 50, MaxParallelJobs: 1, InterBatchDelay: time.Second}},
+			wantErr: false,
+		},
+		{
+			name: "no_sobject",
+			args: args{
+				sObjectName: "",
+				profile:     ThrottleProfile{MaxBatchSize: 50},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative_value",
+			args: args{
+				sObjectName: "Case",
+				profile:     ThrottleProfile{MaxBatchSize: -1},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newConfiguration(WithThrottleProfile(tt.args.sObjectName, tt.args.profile))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithThrottleProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(config.throttleProfiles, tt.want) {
+				t.Errorf("WithThrottleProfile() = %v, want %v", config.throttleProfiles, tt.want)
+			}
+		})
+	}
+}
+
+func Test_configuration_throttleProfile(t *testing.T) {
+	config, err := newConfiguration(WithThrottleProfile("Case", ThrottleProfile{MaxBatchSize: 50}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := config.throttleProfile("CASE"); got.MaxBatchSize != 50 {
+		t.Errorf("throttleProfile() = %v, want MaxBatchSize 50", got)
+	}
+	if got := config.throttleProfile("Account"); got != (ThrottleProfile{}) {
+		t.Errorf("throttleProfile() = %v, want empty profile", got)
+	}
+	var nilConfig *configuration
+	if got := nilConfig.throttleProfile("Case"); got != (ThrottleProfile{}) {
+		t.Errorf("throttleProfile() = %v, want empty profile", got)
+	}
+}
+
+func TestThrottleProfile_batchSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   ThrottleProfile
+		batchSize int
+		want      int
+	}{
+		{
+			name:      "no_limit",
+			profile:   ThrottleProfile{},
+			batchSize: 200,
+			want:      200,
+		},
+		{
+			name:      "capped",
+			profile:   ThrottleProfile{MaxBatchSize: 50},
+			batchSize: 200,
+			want:      50,
+		},
+		{
+			name:      "under_limit",
+			profile:   ThrottleProfile{MaxBatchSize: 50},
+			batchSize: 10,
+			want:      10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.batchSize(tt.batchSize); got != tt.want {
+				t.Errorf("ThrottleProfile.batchSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_throttleBulkJob(t *testing.T) {
+	jobResults := BulkJobResults{
+		Id:    "1234",
+		State: BulkJobStateJobComplete,
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := json.Marshal(jobResults)
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	profile := ThrottleProfile{MaxParallelJobs: 2}
+	if err := throttleBulkJob(&sfAuth, profile, []string{"1234"}); err != nil {
+		t.Fatalf("throttleBulkJob() error = %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("throttleBulkJob() made %d requests under the limit, want 0", requests)
+	}
+	if err := throttleBulkJob(&sfAuth, profile, []string{"1234", "5678"}); err != nil {
+		t.Fatalf("throttleBulkJob() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("throttleBulkJob() made %d requests at the limit, want 1", requests)
+	}
+}
+
+func Test_waitForJobTerminal(t *testing.T) {
+	polls := []BulkJobState{BulkJobStateInProgress, BulkJobStateInProgress, BulkJobStateFailed}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal(BulkJobResults{Id: "1234", State: polls[min(requests, len(polls)-1)], ErrorMessage: "InvalidBatch"})
+		requests++
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	// a failed job no longer counts towards the limit, so it doesn't stop later batches
+	if err := waitForJobTerminal(&sfAuth, "1234", ingestJobType, time.Nanosecond); err != nil {
+		t.Fatalf("waitForJobTerminal() error = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("waitForJobTerminal() made %d requests, want 3", requests)
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+	if err := waitForJobTerminal(&badAuth, "1234", ingestJobType, time.Nanosecond); err == nil {
+		t.Errorf("waitForJobTerminal() expected error when polling fails")
+	}
+}
+
+func Test_throttleBulkJob_noTelemetry(t *testing.T) {
+	server, sfAuth := setupTestServer(BulkJobResults{Id: "1234", State: BulkJobStateJobComplete}, http.StatusOK)
+	defer server.Close()
+	telemetry := &recordingTelemetry{}
+	config, err := newConfiguration(WithTelemetry(telemetry))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth.config = config
+
+	if err := throttleBulkJob(&sfAuth, ThrottleProfile{MaxParallelJobs: 1}, []string{"1234"}); err != nil {
+		t.Fatalf("throttleBulkJob() error = %v", err)
+	}
+	if len(telemetry.jobs) != 0 {
+		t.Errorf("throttleBulkJob() reported %d bulk jobs, want 0", len(telemetry.jobs))
+	}
+}
+
+func Test_doInsertCollection_throttled(t *testing.T) {
+	type account struct {
+		Name string
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := json.Marshal([]SalesforceResult{{Id: "1234", Success: true}})
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	config, err := newConfiguration(WithThrottleProfile("Account", ThrottleProfile{MaxBatchSize: 1}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+		config:      config,
+	}
+
+	records := []account{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if _, err := doInsertCollection(&sfAuth, "Account", records, 200, dmlOptions{}); err != nil {
+		t.Fatalf("doInsertCollection() error = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("doInsertCollection() made %d requests, want 3", requests)
+	}
+}
@@ -0,0 +1,78 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// returns a current access token and the instance url it is valid for, for platforms that mint
+// salesforce tokens centrally, such as a vault or a sidecar
+type TokenProvider func(ctx context.Context) (token string, instanceUrl string, err error)
+
+const grantTypeTokenProvider = "token_provider"
+
+// the provider is called instead of the library's own refresh flows when the session expires,
+// and by Init to get the first token when no creds are given
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(config *configuration) error {
+		if provider == nil {
+			return errors.New("token provider is nil")
+		}
+		config.tokenProvider = provider
+		return nil
+	}
+}
+
+func (config *configuration) hasTokenProvider() bool {
+	return config != nil && config.tokenProvider != nil
+}
+
+func provideToken(ctx context.Context, config *configuration) (string, string, error) {
+	token, instanceUrl, err := config.tokenProvider(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("token provider: %w", err)
+	}
+	if token == "" {
+		return "", "", errors.New("token provider returned an empty access token")
+	}
+	instanceUrl, err = normalizeDomain(instanceUrl)
+	if err != nil {
+		return "", "", fmt.Errorf("token provider: %w", err)
+	}
+	return token, instanceUrl, nil
+}
+
+func tokenProviderFlow(ctx context.Context, config *configuration) (*authentication, error) {
+	token, instanceUrl, err := provideToken(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if instanceUrl == "" {
+		return nil, errors.New("token provider returned an empty instance url")
+	}
+	auth, err := setAccessToken(instanceUrl, token, config)
+	if err != nil {
+		return nil, err
+	}
+	auth.grantType = grantTypeTokenProvider
+	auth.creds = Creds{Domain: instanceUrl}
+	return auth, nil
+}
+
+// an empty instance url keeps the current one
+func refreshFromTokenProvider(auth *authentication) error {
+	token, instanceUrl, err := provideToken(context.Background(), auth.config)
+	if err != nil {
+		return err
+	}
+
+	lock := auth.sessionLock()
+	lock.Lock()
+	defer lock.Unlock()
+	auth.AccessToken = token
+	if instanceUrl != "" {
+		auth.InstanceUrl = instanceUrl
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWithTokenProvider(t *testing.T) {
+	if _, err := newConfiguration(WithTokenProvider(nil)); err == nil {
+		t.Errorf("WithTokenProvider() expected error for nil provider")
+	}
+	provider := func(ctx context.Context) (string, string, error) { return "token", "example.my.salesforce.com", nil }
+	config, err := newConfiguration(WithTokenProvider(provider))
+	if err != nil {
+		t.Fatalf("WithTokenProvider() error = %v", err)
+	}
+	if !config.hasTokenProvider() {
+		t.Errorf("WithTokenProvider() provider was not set")
+	}
+	var nilConfig *configuration
+	if nilConfig.hasTokenProvider() {
+		t.Errorf("hasTokenProvider() = true for nil configuration")
+	}
+}
+
+func Test_tokenProviderFlow(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider TokenProvider
+		want     *authentication
+		wantErr  bool
+	}{
+		{
+			name: "success",
+			provider: func(ctx context.Context) (string, string, error) {
+				return "token", "example.my.salesforce.com/", nil
+			},
+			want: &authentication{
+				AccessToken: "token",
+				InstanceUrl: "https://example.my.salesforce.com",
+			},
+		},
+		{
+			name: "provider_error",
+			provider: func(ctx context.Context) (string, string, error) {
+				return "", "", errors.New("vault unavailable")
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty_token",
+			provider: func(ctx context.Context) (string, string, error) {
+				return "", "example.my.salesforce.com", nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty_instance_url",
+			provider: func(ctx context.Context) (string, string, error) {
+				return "token", "", nil
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newConfiguration(WithTokenProvider(tt.provider), WithoutSessionValidation())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tokenProviderFlow(context.Background(), config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tokenProviderFlow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.AccessToken != tt.want.AccessToken || got.InstanceUrl != tt.want.InstanceUrl {
+				t.Errorf("tokenProviderFlow() = %v, want %v", got, tt.want)
+			}
+			if got.grantType != grantTypeTokenProvider {
+				t.Errorf("tokenProviderFlow() grantType = %v, want %v", got.grantType, grantTypeTokenProvider)
+			}
+		})
+	}
+}
+
+func TestInit_tokenProvider(t *testing.T) {
+	provider := func(ctx context.Context) (string, string, error) { return "token", "example.my.salesforce.com", nil }
+	sf, err := Init(Creds{}, WithTokenProvider(provider), WithoutSessionValidation())
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if sf.GetAccessToken() != "token" {
+		t.Errorf("Init() access token = %v, want token", sf.GetAccessToken())
+	}
+
+	if _, err := Init(Creds{}); err == nil {
+		t.Errorf("Init() expected error for empty creds without a token provider")
+	}
+}
+
+func Test_refreshSession_tokenProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			if _, err := w.Write([]byte(`[{"message": "Session expired or invalid", "errorCode": "INVALID_SESSION_ID"}]`)); err != nil {
+				panic(err.Error())
+			}
+			return
+		}
+		if _, err := w.Write([]byte(`{}`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	calls := 0
+	provider := func(ctx context.Context) (string, string, error) {
+		calls++
+		return "fresh", "", nil
+	}
+	config, err := newConfiguration(WithTokenProvider(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "expired",
+		grantType:   grantTypeClientCredentials,
+		config:      config,
+	}
+
+	resp, err := doRequest(auth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Errorf("token provider called %d times, want 1", calls)
+	}
+	if auth.AccessToken != "fresh" || auth.InstanceUrl != server.URL {
+		t.Errorf("refreshSession() auth = %v, want fresh token on %v", auth, server.URL)
+	}
+
+	failing := func(ctx context.Context) (string, string, error) { return "", "", errors.New("vault unavailable") }
+	config, _ = newConfiguration(WithTokenProvider(failing))
+	auth = &authentication{InstanceUrl: server.URL, AccessToken: "expired", config: config}
+	if err := refreshSession(auth); err == nil {
+		t.Errorf("refreshSession() expected error from token provider")
+	}
+}
+
+func TestSalesforce_WithToken_tokenProvider(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+		if _, err := w.Write([]byte(`[{"message": "Session expired or invalid", "errorCode": "INVALID_SESSION_ID"}]`)); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	calls := 0
+	provider := func(ctx context.Context) (string, string, error) {
+		calls++
+		return "service", "", nil
+	}
+	config, err := newConfiguration(WithTokenProvider(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf := &Salesforce{auth: &authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "service",
+		grantType:   grantTypeTokenProvider,
+		config:      config,
+	}}
+
+	userSf := sf.WithToken("user")
+	if _, err := userSf.DoRequest(http.MethodGet, "/limits", nil); err == nil {
+		t.Errorf("DoRequest() expected error for an expired user token")
+	}
+	if calls != 0 || !reflect.DeepEqual(tokens, []string{"Bearer user"}) {
+		t.Errorf("token provider called %d times with requests %v, want 0 calls and only the user token", calls, tokens)
+	}
+	if got := userSf.GetAccessToken(); got != "user" {
+		t.Errorf("WithToken() client token = %v, want user", got)
+	}
+	if !sf.auth.config.hasTokenProvider() {
+		t.Errorf("WithToken() removed the token provider from the parent")
+	}
+}
+
+// run with -race: refreshing the session must not race with requests that are being built
+func Test_refreshFromTokenProvider_concurrentRequests(t *testing.T) {
+	server, _ := setupTestServer(map[string]any{}, http.StatusOK)
+	defer server.Close()
+
+	provider := func(ctx context.Context) (string, string, error) {
+		return "fresh", server.URL, nil
+	}
+	config, err := newConfiguration(WithTokenProvider(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &authentication{InstanceUrl: server.URL, AccessToken: "expired", config: config}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := refreshFromTokenProvider(auth); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			resp, err := doRequest(auth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if instanceUrl, accessToken := auth.session(); instanceUrl != server.URL || accessToken != "fresh" {
+		t.Errorf("session() = %v, %v, want %v, fresh", instanceUrl, accessToken, server.URL)
+	}
+}
@@ -0,0 +1,173 @@
+package salesforce
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	highThroughputMaxIdleConns        = 200
+	highThroughputMaxIdleConnsPerHost = 100
+	highThroughputIdleConnTimeout     = 90 * time.Second
+	highThroughputTLSSessionCacheSize = 64
+)
+
+// how a request got its connection, reported to the hook passed to WithConnectionStats
+type ConnectionEvent struct {
+	Host string
+	// the connection was reused from an earlier request rather than dialed
+	Reused   bool
+	WasIdle  bool
+	IdleTime time.Duration
+	// time spent dialing and in the tls handshake, zero for reused connections
+	DialDuration time.Duration
+	// the tls handshake resumed an earlier session instead of doing a full handshake
+	TLSResumed bool
+}
+
+// keeps more connections open per host and reuses tls sessions, for clients making many concurrent bulk
+// and composite calls to the same instance
+func WithHighThroughputTransport() Option {
+	return func(config *configuration) error {
+		config.highThroughput = true
+		return nil
+	}
+}
+
+// calls hook for every request with how its connection was obtained; the hook must be safe for concurrent use
+func WithConnectionStats(hook func(ConnectionEvent)) Option {
+	return func(config *configuration) error {
+		if hook == nil {
+			return errors.New("connection stats hook is nil")
+		}
+		config.connectionStats = hook
+		return nil
+	}
+}
+
+// presents a client certificate in the tls handshake of every request, including authentication, for orgs
+// whose api security policies require mutual tls
+func WithClientCertificate(certPEM []byte, keyPEM []byte) Option {
+	return func(config *configuration) error {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.clientCertificates = append(config.clientCertificates, cert)
+		return nil
+	}
+}
+
+// like middleware, the configured client's transport is replaced once every option is applied; settings for the
+// base *http.Transport are applied before it's wrapped for connection stats or a recorder, so options work in any order
+func (config *configuration) applyTransport() error {
+	if !config.highThroughput && config.connectionStats == nil && len(config.clientCertificates) == 0 &&
+		!config.hasProxy() && config.recorder == nil {
+		return nil
+	}
+	client := *config.client()
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if len(config.clientCertificates) > 0 {
+		base, ok := transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("client certificates require an *http.Transport, got %T", transport)
+		}
+		transport = withClientCertificates(base, config.clientCertificates)
+	}
+	if config.hasProxy() {
+		base, ok := transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("proxy options require an *http.Transport, got %T", transport)
+		}
+		transport = withProxy(base, config.proxy, config.proxyHeaders)
+	}
+	if config.highThroughput {
+		base, ok := transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("high throughput transport requires an *http.Transport, got %T", transport)
+		}
+		transport = tuneTransport(base)
+	}
+	if config.connectionStats != nil {
+		transport = connectionStatsTransport{next: transport, hook: config.connectionStats}
+	}
+	if config.recorder != nil {
+		transport = recorderTransport{recorder: config.recorder, next: transport}
+	}
+	client.Transport = transport
+	config.httpClient = &client
+	return nil
+}
+
+// the given transport is cloned, so it isn't modified
+func withClientCertificates(base *http.Transport, certs []tls.Certificate) *http.Transport {
+	transport := base.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.Certificates = slices.Concat(transport.TLSClientConfig.Certificates, certs)
+	return transport
+}
+
+// the given transport is cloned, so it isn't modified
+func tuneTransport(base *http.Transport) *http.Transport {
+	tuned := base.Clone()
+	tuned.ForceAttemptHTTP2 = true
+	tuned.MaxIdleConns = highThroughputMaxIdleConns
+	tuned.MaxIdleConnsPerHost = highThroughputMaxIdleConnsPerHost
+	tuned.IdleConnTimeout = highThroughputIdleConnTimeout
+	if tuned.TLSClientConfig == nil {
+		tuned.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if tuned.TLSClientConfig.ClientSessionCache == nil {
+		tuned.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(highThroughputTLSSessionCacheSize)
+	}
+	return tuned
+}
+
+type connectionStatsTransport struct {
+	next http.RoundTripper
+	hook func(ConnectionEvent)
+}
+
+func (transport connectionStatsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var mu sync.Mutex
+	var dialStart time.Time
+	event := ConnectionEvent{Host: req.URL.Host}
+	trace := &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			defer mu.Unlock()
+			if dialStart.IsZero() {
+				dialStart = time.Now()
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			event.TLSResumed = err == nil && state.DidResume
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			event.Reused = info.Reused
+			event.WasIdle = info.WasIdle
+			event.IdleTime = info.IdleTime
+			if !info.Reused && !dialStart.IsZero() {
+				event.DialDuration = time.Since(dialStart)
+			}
+			got := event
+			mu.Unlock()
+			transport.hook(got)
+		},
+	}
+	return transport.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
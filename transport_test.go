@@ -0,0 +1,175 @@
+package salesforce
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithHighThroughputTransport(t *testing.T) {
+	base := &http.Transport{MaxIdleConnsPerHost: 2}
+	client := &http.Client{Transport: base, Timeout: time.Minute}
+	config, err := newConfiguration(WithHighThroughputTransport(), WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	tuned, ok := config.client().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("client transport = %T, want *http.Transport", config.client().Transport)
+	}
+	if tuned.MaxIdleConnsPerHost != highThroughputMaxIdleConnsPerHost || tuned.MaxIdleConns != highThroughputMaxIdleConns {
+		t.Errorf("idle connections = %d per host, %d total", tuned.MaxIdleConnsPerHost, tuned.MaxIdleConns)
+	}
+	if !tuned.ForceAttemptHTTP2 || tuned.TLSClientConfig == nil || tuned.TLSClientConfig.ClientSessionCache == nil {
+		t.Errorf("transport does not attempt http/2 or reuse tls sessions")
+	}
+	if config.client().Timeout != time.Minute {
+		t.Errorf("client timeout = %v, want %v", config.client().Timeout, time.Minute)
+	}
+	if base.MaxIdleConnsPerHost != 2 || client.Transport != base {
+		t.Errorf("WithHighThroughputTransport() modified the http client passed to WithHTTPClient")
+	}
+
+	config, err = newConfiguration(WithHighThroughputTransport())
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	if _, ok := config.client().Transport.(*http.Transport); !ok || config.client() == http.DefaultClient {
+		t.Errorf("WithHighThroughputTransport() did not tune the default transport")
+	}
+
+	custom := &http.Client{Transport: RoundTripperFunc(http.DefaultTransport.RoundTrip)}
+	if _, err := newConfiguration(WithHTTPClient(custom), WithHighThroughputTransport()); err == nil {
+		t.Errorf("WithHighThroughputTransport() expected error for a transport that isn't an *http.Transport")
+	}
+}
+
+func TestWithConnectionStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	events := []ConnectionEvent{}
+	config, err := newConfiguration(
+		WithHTTPClient(&http.Client{Transport: &http.Transport{}}),
+		WithHighThroughputTransport(),
+		WithConnectionStats(func(event ConnectionEvent) {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", config: config}
+	for i := 0; i < 2; i++ {
+		resp, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+		if err != nil {
+			t.Fatalf("doRequest() error = %v", err)
+		}
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			t.Fatalf("error reading response body: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("connection events = %v, want 2", events)
+	}
+	if events[0].Reused || events[0].DialDuration == 0 || events[0].Host != server.Listener.Addr().String() {
+		t.Errorf("first request = %+v, want a new connection to %s", events[0], server.Listener.Addr())
+	}
+	if !events[1].Reused || !events[1].WasIdle || events[1].DialDuration != 0 {
+		t.Errorf("second request = %+v, want a reused idle connection", events[1])
+	}
+
+	if _, err := newConfiguration(WithConnectionStats(nil)); err == nil {
+		t.Errorf("WithConnectionStats() expected error for nil hook")
+	}
+}
+
+func generateClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go-salesforce"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error marshaling key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certPEM)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) != 1 || r.TLS.PeerCertificates[0].Subject.CommonName != "go-salesforce" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	config, err := newConfiguration(WithHTTPClient(server.Client()), WithClientCertificate(certPEM, keyPEM))
+	if err != nil {
+		t.Fatalf("newConfiguration() error = %v", err)
+	}
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue", config: config}
+	resp, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType})
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+	if len(server.Client().Transport.(*http.Transport).TLSClientConfig.Certificates) != 0 {
+		t.Errorf("WithClientCertificate() modified the http client passed to WithHTTPClient")
+	}
+
+	// the server rejects the handshake without a certificate
+	sfAuth.config = &configuration{httpClient: server.Client()}
+	if _, err := doRequest(&sfAuth, requestPayload{method: http.MethodGet, uri: "/limits", content: jsonType}); err == nil {
+		t.Errorf("doRequest() expected error without a client certificate")
+	}
+
+	if _, err := newConfiguration(WithClientCertificate(certPEM, []byte("not a key"))); err == nil {
+		t.Errorf("WithClientCertificate() expected error for an invalid key")
+	}
+	custom := &http.Client{Transport: RoundTripperFunc(http.DefaultTransport.RoundTrip)}
+	if _, err := newConfiguration(WithHTTPClient(custom), WithClientCertificate(certPEM, keyPEM)); err == nil {
+		t.Errorf("WithClientCertificate() expected error for a transport that isn't an *http.Transport")
+	}
+}
@@ -0,0 +1,122 @@
+package salesforce
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+type TypedIterator[T any] struct {
+	it IteratorJob
+}
+
+func QueryTyped[T any](sf *Salesforce, query string, opts ...RequestOption) ([]T, error) {
+	records := []T{}
+	if err := sf.Query(query, &records, opts...); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func QueryStructTyped[T any](sf *Salesforce, soqlStruct any, opts ...RequestOption) ([]T, error) {
+	records := []T{}
+	if err := sf.QueryStruct(soqlStruct, &records, opts...); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// like DoRequest, but encodes body as json, unless it's already encoded as []byte, and decodes the response
+// into T; responses without content return the zero value of T
+func Do[T any](sf *Salesforce, method string, uri string, body any, opts ...RequestOption) (T, error) {
+	var result T
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return result, authErr
+	}
+
+	codec := sf.auth.config.codec()
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		payload = b
+	default:
+		var err error
+		if payload, err = codec.Marshal(body); err != nil {
+			return result, err
+		}
+	}
+	options := newDMLOptions(opts...)
+	resp, err := doRequest(sf.auth, requestPayload{
+		method:  method,
+		uri:     uri,
+		content: jsonType,
+		body:    string(payload),
+		headers: options.headers,
+		timeout: options.timeout,
+		ctx:     options.ctx,
+	})
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 {
+		return result, nil
+	}
+	if err := decodeBody(codec, resp.Body, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func QueryBulkIteratorTyped[T any](sf *Salesforce, query string, opts ...BulkQueryOptions) (*TypedIterator[T], error) {
+	it, err := sf.QueryBulkIterator(query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedIterator[T]{it: it}, nil
+}
+
+func QueryBulkTyped[T any](sf *Salesforce, query string, opts ...BulkQueryOptions) ([]T, error) {
+	it, err := QueryBulkIteratorTyped[T](sf, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	records := []T{}
+	for it.Next() {
+		batch, err := it.Decode()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, batch...)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (it *TypedIterator[T]) Next() bool {
+	return it.it.Next()
+}
+
+func (it *TypedIterator[T]) Error() error {
+	return it.it.Error()
+}
+
+func (it *TypedIterator[T]) Decode() ([]T, error) {
+	records := []T{}
+	if err := it.it.Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (it *TypedIterator[T]) WriteTo(w io.Writer) (int64, error) {
+	writer, ok := it.it.(io.WriterTo)
+	if !ok {
+		return 0, errors.New("iterator does not support WriteTo")
+	}
+	return writer.WriteTo(w)
+}
@@ -0,0 +1,308 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type typedAccount struct {
+	Id   string
+	Name string
+}
+
+func setupBulkQueryTestServer(t *testing.T, csvData string) (*httptest.Server, authentication) {
+	job := bulkJob{
+		Id:    "1234",
+		State: BulkJobStateJobComplete,
+	}
+	jobResults := BulkJobResults{
+		Id:    "1234",
+		State: BulkJobStateJobComplete,
+	}
+	jobCreationRespBody, _ := json.Marshal(job)
+	jobResultsRespBody, _ := json.Marshal(jobResults)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.RequestURI, "/query"):
+			if _, err := w.Write(jobCreationRespBody); err != nil {
+				t.Fatal(err.Error())
+			}
+		case strings.HasSuffix(r.RequestURI, "/1234"):
+			if _, err := w.Write(jobResultsRespBody); err != nil {
+				t.Fatal(err.Error())
+			}
+		case strings.HasSuffix(r.RequestURI, "/results"):
+			w.Header().Add("Sforce-Locator", "null")
+			w.Header().Add("Sforce-Numberofrecords", "2")
+			if _, err := w.Write([]byte(csvData)); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+	}))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	return server, sfAuth
+}
+
+func TestQueryTyped(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"Id":   "123abc",
+			"Name": "test account",
+		}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    []typedAccount
+		wantErr bool
+	}{
+		{
+			name:    "query_successfully",
+			auth:    &sfAuth,
+			want:    []typedAccount{{Id: "123abc", Name: "test account"}},
+			wantErr: false,
+		},
+		{
+			name:    "bad_request",
+			auth:    &badSfAuth,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "validation_fail",
+			auth:    nil,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{auth: tt.auth}
+			got, err := QueryTyped[typedAccount](sf, "SELECT Id, Name FROM Account")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryTyped() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryTyped() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryStructTyped(t *testing.T) {
+	type accountSoql struct {
+		Id   string `soql:"selectColumn,fieldName=Id" json:"Id"`
+		Name string `soql:"selectColumn,fieldName=Name" json:"Name"`
+	}
+	type accountSoqlQuery struct {
+		SelectClause accountSoql `soql:"selectClause,tableName=Account"`
+	}
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"Id":   "123abc",
+			"Name": "test account",
+		}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	got, err := QueryStructTyped[typedAccount](sf, accountSoqlQuery{})
+	if err != nil {
+		t.Fatalf("QueryStructTyped() error = %v", err)
+	}
+	want := []typedAccount{{Id: "123abc", Name: "test account"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryStructTyped() = %v, want %v", got, want)
+	}
+
+	if _, err := QueryStructTyped[typedAccount](sf, "invalid"); err == nil {
+		t.Errorf("QueryStructTyped() expected error")
+	}
+}
+
+func TestQueryBulkTyped(t *testing.T) {
+	server, sfAuth := setupBulkQueryTestServer(t, "Id,Name\n001,test account\n002,other account\n")
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    []typedAccount
+		wantErr bool
+	}{
+		{
+			name:    "query_successfully",
+			auth:    &sfAuth,
+			want:    []typedAccount{{Id: "001", Name: "test account"}, {Id: "002", Name: "other account"}},
+			wantErr: false,
+		},
+		{
+			name:    "bad_request",
+			auth:    &badSfAuth,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := &Salesforce{auth: tt.auth}
+			got, err := QueryBulkTyped[typedAccount](sf, "SELECT Id, Name FROM Account")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryBulkTyped() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryBulkTyped() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryBulkIteratorTyped(t *testing.T) {
+	server, sfAuth := setupBulkQueryTestServer(t, "Id,Name\n001,test account\n")
+	defer server.Close()
+
+	sf := &Salesforce{auth: &sfAuth}
+	it, err := QueryBulkIteratorTyped[typedAccount](sf, "SELECT Id, Name FROM Account")
+	if err != nil {
+		t.Fatalf("QueryBulkIteratorTyped() error = %v", err)
+	}
+	got := []typedAccount{}
+	for it.Next() {
+		batch, err := it.Decode()
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		got = append(got, batch...)
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("Error() = %v", err)
+	}
+	want := []typedAccount{{Id: "001", Name: "test account"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryBulkIteratorTyped() = %v, want %v", got, want)
+	}
+
+	if _, err := QueryBulkIteratorTyped[typedAccount](&Salesforce{}, "SELECT Id FROM Account"); err == nil {
+		t.Errorf("QueryBulkIteratorTyped() expected error")
+	}
+}
+
+func TestTypedIterator_WriteTo(t *testing.T) {
+	server, sfAuth := setupBulkQueryTestServer(t, "Id,Name\n001,test account\n")
+	defer server.Close()
+
+	it, err := QueryBulkIteratorTyped[typedAccount](&Salesforce{auth: &sfAuth}, "SELECT Id, Name FROM Account")
+	if err != nil {
+		t.Fatalf("QueryBulkIteratorTyped() error = %v", err)
+	}
+	var buf bytes.Buffer
+	for it.Next() {
+		if _, err := it.WriteTo(&buf); err != nil {
+			t.Fatalf("WriteTo() error = %v", err)
+		}
+	}
+	if buf.String() != "Id,Name\n001,test account\n" {
+		t.Errorf("WriteTo() = %q", buf.String())
+	}
+
+	// IteratorJob implementations outside this package don't have to support WriteTo
+	custom := TypedIterator[typedAccount]{it: decodeOnlyIterator{}}
+	if _, err := custom.WriteTo(&buf); err == nil {
+		t.Errorf("WriteTo() expected error for an iterator without WriteTo")
+	}
+}
+
+type decodeOnlyIterator struct{}
+
+func (decodeOnlyIterator) Next() bool       { return false }
+func (decodeOnlyIterator) Error() error     { return nil }
+func (decodeOnlyIterator) Decode(any) error { return nil }
+
+func TestDo(t *testing.T) {
+	type limit struct {
+		Max       int
+		Remaining int
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/" + apiVersion + "/limits":
+			if _, err := w.Write([]byte(`{"DailyApiRequests":{"Max":15000,"Remaining":14998}}`)); err != nil {
+				t.Fatal(err.Error())
+			}
+		case "/services/data/" + apiVersion + "/apexrest/echo":
+			body := map[string]any{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body["Header"] = r.Header.Get("X-Custom")
+			if err := json.NewEncoder(w).Encode(body); err != nil {
+				t.Fatal(err.Error())
+			}
+		case "/services/data/" + apiVersion + "/sobjects/Account/123abc":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	sf := &Salesforce{auth: &authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}}
+
+	limits, err := Do[map[string]limit](sf, http.MethodGet, "/limits", nil)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if want := (map[string]limit{"DailyApiRequests": {Max: 15000, Remaining: 14998}}); !reflect.DeepEqual(limits, want) {
+		t.Errorf("Do() = %v, want %v", limits, want)
+	}
+
+	echo, err := Do[map[string]string](sf, http.MethodPost, "/apexrest/echo", map[string]string{"Name": "test"},
+		WithHeaders(map[string]string{"X-Custom": "value"}))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if want := (map[string]string{"Name": "test", "Header": "value"}); !reflect.DeepEqual(echo, want) {
+		t.Errorf("Do() = %v, want %v", echo, want)
+	}
+
+	raw, err := Do[map[string]string](sf, http.MethodPost, "/apexrest/echo", []byte(`{"Name":"raw"}`))
+	if err != nil || raw["Name"] != "raw" {
+		t.Errorf("Do() = %v, %v, want the []byte body sent as is", raw, err)
+	}
+
+	empty, err := Do[*typedAccount](sf, http.MethodPatch, "/sobjects/Account/123abc", typedAccount{Name: "test"})
+	if err != nil || empty != nil {
+		t.Errorf("Do() = %v, %v, want the zero value for no content", empty, err)
+	}
+
+	if _, err := Do[map[string]any](sf, http.MethodGet, "/missing", nil); err == nil {
+		t.Errorf("Do() expected error for a failed request")
+	}
+	if _, err := Do[map[string]any](&Salesforce{}, http.MethodGet, "/limits", nil); err == nil {
+		t.Errorf("Do() expected validation error")
+	}
+}